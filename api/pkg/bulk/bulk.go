@@ -0,0 +1,271 @@
+// Package bulk reads the rows of bulk SMS CSV and Excel files one row at a time so that files with hundreds of
+// thousands of rows don't need to be loaded into memory.
+package bulk
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jszwec/csvutil"
+	"github.com/palantir/stacktrace"
+	"github.com/xuri/excelize/v2"
+)
+
+// Format is the file format of a bulk SMS file
+type Format string
+
+const (
+	// FormatCSV is a comma separated values file with a header row
+	FormatCSV = Format("csv")
+
+	// FormatXLSX is an Excel workbook where the first sheet contains the messages
+	FormatXLSX = Format("xlsx")
+)
+
+// String converts the Format to a string
+func (format Format) String() string {
+	return string(format)
+}
+
+// DetectFormat returns the Format of an uploaded file from its name and content type
+func DetectFormat(filename string, contentType string) (Format, bool) {
+	if contentType == "text/csv" || strings.HasSuffix(filename, ".csv") {
+		return FormatCSV, true
+	}
+	if contentType == "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet" || strings.HasSuffix(filename, ".xlsx") {
+		return FormatXLSX, true
+	}
+	return "", false
+}
+
+// excelTimeLayout is the format of the SendTime column in an Excel file
+const excelTimeLayout = "2006-01-02T15:04:05"
+
+// Row is a single message in a bulk SMS file
+type Row struct {
+	// Number is the line number of the row in the file
+	Number          int
+	FromPhoneNumber string
+	ToPhoneNumber   string
+	Content         string
+	SendTime        *time.Time
+
+	// Variables are the values of the extra columns in the row keyed by the column header
+	Variables map[string]string
+}
+
+// RowError is returned by Reader.Next when a single row cannot be read, the next rows can still be read
+type RowError struct {
+	Number  int
+	Message string
+}
+
+// Error returns the description of the invalid row
+func (err *RowError) Error() string {
+	return fmt.Sprintf("Row [%d]: %s", err.Number, err.Message)
+}
+
+// Reader reads the rows of a bulk SMS file
+type Reader interface {
+	// Next returns the next row in the file or io.EOF when there are no more rows
+	Next() (*Row, error)
+
+	// Close releases the resources used by the Reader
+	Close() error
+}
+
+// NewReader creates a Reader for the Format. The SendTime of an Excel file is parsed in the location.
+func NewReader(format Format, content io.Reader, location *time.Location) (Reader, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVReader(content)
+	case FormatXLSX:
+		return newXLSXReader(content, location)
+	default:
+		return nil, stacktrace.NewError(fmt.Sprintf("unsupported bulk file format [%s]", format))
+	}
+}
+
+type csvRecord struct {
+	FromPhoneNumber string     `csv:"FromPhoneNumber"`
+	ToPhoneNumber   string     `csv:"ToPhoneNumber"`
+	Content         string     `csv:"Content"`
+	SendTime        *time.Time `csv:"SendTime(optional)"`
+}
+
+type csvReader struct {
+	decoder *csvutil.Decoder
+	header  []string
+	line    int
+}
+
+func newCSVReader(content io.Reader) (*csvReader, error) {
+	decoder, err := csvutil.NewDecoder(csv.NewReader(content))
+	if errors.Is(err, io.EOF) {
+		return nil, stacktrace.Propagate(err, "the CSV file is empty")
+	}
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "cannot read the header of the CSV file")
+	}
+	return &csvReader{decoder: decoder, header: decoder.Header(), line: 1}, nil
+}
+
+func (reader *csvReader) Next() (*Row, error) {
+	record := new(csvRecord)
+	err := reader.decoder.Decode(record)
+	if errors.Is(err, io.EOF) {
+		return nil, io.EOF
+	}
+
+	reader.line++
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot parse line [%d] of the CSV file", reader.line))
+	}
+	if err != nil {
+		return nil, &RowError{Number: reader.line, Message: fmt.Sprintf("The row cannot be read: %s.", err.Error())}
+	}
+
+	row := &Row{
+		Number:          reader.line,
+		FromPhoneNumber: record.FromPhoneNumber,
+		ToPhoneNumber:   record.ToPhoneNumber,
+		Content:         record.Content,
+		SendTime:        record.SendTime,
+		Variables:       map[string]string{},
+	}
+
+	for _, column := range reader.decoder.Unused() {
+		row.Variables[strings.TrimSpace(reader.header[column])] = reader.decoder.Record()[column]
+	}
+
+	return row, nil
+}
+
+func (reader *csvReader) Close() error {
+	return nil
+}
+
+// the headers of the columns of an Excel file are the same as the csv tags of csvRecord
+const (
+	xlsxColumnFromPhoneNumber = "FromPhoneNumber"
+	xlsxColumnToPhoneNumber   = "ToPhoneNumber"
+	xlsxColumnContent         = "Content"
+	xlsxColumnSendTime        = "SendTime(optional)"
+)
+
+type xlsxReader struct {
+	file     *excelize.File
+	rows     *excelize.Rows
+	header   []string
+	columns  map[string]int
+	line     int
+	location *time.Location
+}
+
+// newXLSXReader opens the workbook and streams the rows of the first sheet. Large sheets are extracted to a
+// temporary file by excelize instead of being kept in memory.
+func newXLSXReader(content io.Reader, location *time.Location) (*xlsxReader, error) {
+	file, err := excelize.OpenReader(content)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "cannot open the excel file")
+	}
+
+	rows, err := file.Rows(file.GetSheetName(0))
+	if err != nil {
+		_ = file.Close()
+		return nil, stacktrace.Propagate(err, "cannot read the rows of the excel file")
+	}
+
+	return &xlsxReader{file: file, rows: rows, location: location}, nil
+}
+
+func (reader *xlsxReader) Next() (*Row, error) {
+	for reader.rows.Next() {
+		reader.line++
+		columns, err := reader.rows.Columns()
+		if err != nil {
+			return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot read row [%d] of the excel file", reader.line))
+		}
+
+		if reader.header == nil {
+			reader.setHeader(columns)
+			continue
+		}
+
+		if strings.TrimSpace(strings.Join(columns, "")) == "" {
+			continue
+		}
+
+		return reader.toRow(columns)
+	}
+
+	if err := reader.rows.Error(); err != nil {
+		return nil, stacktrace.Propagate(err, "cannot read the rows of the excel file")
+	}
+	return nil, io.EOF
+}
+
+// setHeader maps the columns by their header so the columns can be in any order like in a CSV file
+func (reader *xlsxReader) setHeader(header []string) {
+	reader.header = make([]string, len(header))
+	reader.columns = map[string]int{}
+	for index, name := range header {
+		reader.header[index] = strings.TrimSpace(name)
+		if _, ok := reader.columns[reader.header[index]]; !ok {
+			reader.columns[reader.header[index]] = index
+		}
+	}
+}
+
+// value returns the cell of the column with the header name, rows can be shorter than the header when the last cells are empty
+func (reader *xlsxReader) value(columns []string, name string) string {
+	index, ok := reader.columns[name]
+	if !ok || index >= len(columns) {
+		return ""
+	}
+	return columns[index]
+}
+
+func (reader *xlsxReader) toRow(columns []string) (*Row, error) {
+	row := &Row{
+		Number:          reader.line,
+		FromPhoneNumber: strings.TrimSpace(reader.value(columns, xlsxColumnFromPhoneNumber)),
+		ToPhoneNumber:   strings.TrimSpace(reader.value(columns, xlsxColumnToPhoneNumber)),
+		Content:         reader.value(columns, xlsxColumnContent),
+		Variables:       map[string]string{},
+	}
+
+	if value := strings.TrimSpace(reader.value(columns, xlsxColumnSendTime)); value != "" {
+		sendTime, err := time.ParseInLocation(excelTimeLayout, value, reader.location)
+		if err != nil {
+			return nil, &RowError{
+				Number:  reader.line,
+				Message: fmt.Sprintf("The SendTime [%s] is not in the correct format e.g [2006-01-02T15:04:05] where 2006 is the year, 01 is January, 02 is the second day of the month and the time is 15:04:05", value),
+			}
+		}
+		row.SendTime = &sendTime
+	}
+
+	for column := 0; column < len(columns) && column < len(reader.header); column++ {
+		switch name := reader.header[column]; name {
+		case "", xlsxColumnFromPhoneNumber, xlsxColumnToPhoneNumber, xlsxColumnContent, xlsxColumnSendTime:
+			continue
+		default:
+			row.Variables[name] = columns[column]
+		}
+	}
+
+	return row, nil
+}
+
+func (reader *xlsxReader) Close() error {
+	if err := reader.rows.Close(); err != nil {
+		return stacktrace.Propagate(err, "cannot close the rows of the excel file")
+	}
+	return reader.file.Close()
+}
@@ -0,0 +1,171 @@
+package bulk
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func readAll(t *testing.T, reader Reader) ([]*Row, []*RowError) {
+	var rows []*Row
+	var rowErrors []*RowError
+	for {
+		row, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var rowErr *RowError
+		if errors.As(err, &rowErr) {
+			rowErrors = append(rowErrors, rowErr)
+			continue
+		}
+
+		require.NoError(t, err)
+		rows = append(rows, row)
+	}
+	require.NoError(t, reader.Close())
+	return rows, rowErrors
+}
+
+func TestDetectFormat(t *testing.T) {
+	t.Run("it detects the format from the file name or content type", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Act
+		csvFormat, csvOK := DetectFormat("campaign.csv", "")
+		xlsxFormat, xlsxOK := DetectFormat("upload", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		_, pdfOK := DetectFormat("campaign.pdf", "application/pdf")
+
+		// Assert
+		assert.True(t, csvOK)
+		assert.Equal(t, FormatCSV, csvFormat)
+		assert.True(t, xlsxOK)
+		assert.Equal(t, FormatXLSX, xlsxFormat)
+		assert.False(t, pdfOK)
+	})
+}
+
+func TestNewReader(t *testing.T) {
+	t.Run("it reads CSV rows with extra columns as variables", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		content := "FromPhoneNumber,ToPhoneNumber,Content,SendTime(optional), FirstName \n" +
+			"+18005550199,+18005550100,Hello {{FirstName}},,John\n" +
+			"+18005550199,+18005550101,Hello {{FirstName}},2022-06-05T14:26:09Z,Jane\n"
+
+		// Act
+		reader, err := NewReader(FormatCSV, strings.NewReader(content), time.UTC)
+		require.NoError(t, err)
+		rows, rowErrors := readAll(t, reader)
+
+		// Assert
+		assert.Empty(t, rowErrors)
+		require.Len(t, rows, 2)
+		assert.Equal(t, 2, rows[0].Number)
+		assert.Equal(t, "+18005550100", rows[0].ToPhoneNumber)
+		assert.Nil(t, rows[0].SendTime)
+		assert.Equal(t, map[string]string{"FirstName": "John"}, rows[0].Variables)
+		assert.Equal(t, 3, rows[1].Number)
+		assert.Equal(t, time.Date(2022, 6, 5, 14, 26, 9, 0, time.UTC), rows[1].SendTime.UTC())
+	})
+
+	t.Run("it reports an invalid CSV row and continues reading", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		content := "FromPhoneNumber,ToPhoneNumber,Content,SendTime(optional)\n" +
+			"+18005550199,+18005550100,Hello,tomorrow\n" +
+			"+18005550199,+18005550101,Hello,\n"
+
+		// Act
+		reader, err := NewReader(FormatCSV, strings.NewReader(content), time.UTC)
+		require.NoError(t, err)
+		rows, rowErrors := readAll(t, reader)
+
+		// Assert
+		require.Len(t, rowErrors, 1)
+		assert.Equal(t, 2, rowErrors[0].Number)
+		require.Len(t, rows, 1)
+		assert.Equal(t, 3, rows[0].Number)
+	})
+
+	t.Run("it reads excel rows and parses the send time in the location", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		file := excelize.NewFile()
+		sheet := file.GetSheetName(0)
+		require.NoError(t, file.SetSheetRow(sheet, "A1", &[]any{"FromPhoneNumber", "ToPhoneNumber", "Content", "SendTime(optional)", "FirstName"}))
+		require.NoError(t, file.SetSheetRow(sheet, "A2", &[]any{"+18005550199", "+18005550100", "Hello {{FirstName}}", "2022-06-05T14:26:09", "John"}))
+		require.NoError(t, file.SetSheetRow(sheet, "A4", &[]any{"+18005550199", "+18005550101", "Hello", "", ""}))
+		content := new(bytes.Buffer)
+		require.NoError(t, file.Write(content))
+		location, err := time.LoadLocation("Africa/Douala")
+		require.NoError(t, err)
+
+		// Act
+		reader, err := NewReader(FormatXLSX, content, location)
+		require.NoError(t, err)
+		rows, rowErrors := readAll(t, reader)
+
+		// Assert
+		assert.Empty(t, rowErrors)
+		require.Len(t, rows, 2)
+		assert.Equal(t, 2, rows[0].Number)
+		assert.Equal(t, time.Date(2022, 6, 5, 13, 26, 9, 0, time.UTC), rows[0].SendTime.UTC())
+		assert.Equal(t, map[string]string{"FirstName": "John"}, rows[0].Variables)
+		assert.Equal(t, 4, rows[1].Number)
+		assert.Nil(t, rows[1].SendTime)
+	})
+
+	t.Run("it maps excel columns by their header when there is no send time column", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		file := excelize.NewFile()
+		sheet := file.GetSheetName(0)
+		require.NoError(t, file.SetSheetRow(sheet, "A1", &[]any{"Content", "ToPhoneNumber", "FromPhoneNumber", "FirstName", "LastName"}))
+		require.NoError(t, file.SetSheetRow(sheet, "A2", &[]any{"Hello {{FirstName}}", "+18005550100", "+18005550199", "John", "Doe"}))
+		content := new(bytes.Buffer)
+		require.NoError(t, file.Write(content))
+
+		// Act
+		reader, err := NewReader(FormatXLSX, content, time.UTC)
+		require.NoError(t, err)
+		rows, rowErrors := readAll(t, reader)
+
+		// Assert
+		assert.Empty(t, rowErrors)
+		require.Len(t, rows, 1)
+		assert.Equal(t, "+18005550199", rows[0].FromPhoneNumber)
+		assert.Equal(t, "+18005550100", rows[0].ToPhoneNumber)
+		assert.Equal(t, "Hello {{FirstName}}", rows[0].Content)
+		assert.Nil(t, rows[0].SendTime)
+		assert.Equal(t, map[string]string{"FirstName": "John", "LastName": "Doe"}, rows[0].Variables)
+	})
+
+	t.Run("it returns an error for an unsupported format", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Act
+		_, err := NewReader(Format("pdf"), strings.NewReader(""), time.UTC)
+
+		// Assert
+		assert.Error(t, err)
+	})
+}
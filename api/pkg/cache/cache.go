@@ -9,4 +9,9 @@ import (
 type Cache interface {
 	Set(ctx context.Context, key string, value string, ttl time.Duration) error
 	Get(ctx context.Context, key string) (value string, err error)
+	// Add stores the item only if the key does not exist. It returns false when the key already exists.
+	Add(ctx context.Context, key string, value string, ttl time.Duration) (bool, error)
+	Delete(ctx context.Context, key string) error
+	// DeleteIfEqual deletes the item only if it still has the value. It returns false when the item has another value or does not exist.
+	DeleteIfEqual(ctx context.Context, key string, value string) (bool, error)
 }
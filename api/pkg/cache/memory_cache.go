@@ -3,6 +3,7 @@ package cache
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
@@ -14,6 +15,8 @@ import (
 type memoryCache struct {
 	tracer telemetry.Tracer
 	store  *ttlCache.Cache
+	// mutex makes DeleteIfEqual atomic with the writes to the store
+	mutex sync.Mutex
 }
 
 // NewMemoryCache creates a new instance of memoryCache
@@ -42,6 +45,48 @@ func (cache *memoryCache) Set(ctx context.Context, key string, value string, ttl
 	ctx, span := cache.tracer.Start(ctx)
 	defer span.End()
 
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
 	cache.store.Set(key, value, ttl)
 	return nil
 }
+
+// Add an item in the memory cache if the key does not exist
+func (cache *memoryCache) Add(ctx context.Context, key string, value string, ttl time.Duration) (bool, error) {
+	ctx, span := cache.tracer.Start(ctx)
+	defer span.End()
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if err := cache.store.Add(key, value, ttl); err != nil {
+		return false, nil
+	}
+	return true, nil
+}
+
+// Delete an item from the memory cache
+func (cache *memoryCache) Delete(ctx context.Context, key string) error {
+	ctx, span := cache.tracer.Start(ctx)
+	defer span.End()
+
+	cache.store.Delete(key)
+	return nil
+}
+
+// DeleteIfEqual deletes an item from the memory cache only if it still has the value
+func (cache *memoryCache) DeleteIfEqual(ctx context.Context, key string, value string) (bool, error) {
+	ctx, span := cache.tracer.Start(ctx)
+	defer span.End()
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if current, ok := cache.store.Get(key); !ok || current.(string) != value {
+		return false, nil
+	}
+
+	cache.store.Delete(key)
+	return true, nil
+}
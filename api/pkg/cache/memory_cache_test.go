@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	ttlCache "github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryCache_Add(t *testing.T) {
+	t.Run("it adds the item only once for concurrent requests with the same key", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+		cache := NewMemoryCache(telemetry.NewOtelLogger("", nil), ttlCache.New(time.Minute, time.Minute))
+
+		// Arrange
+		const requests = 100
+		added := make([]bool, requests)
+		wg := sync.WaitGroup{}
+
+		// Act
+		for i := 0; i < requests; i++ {
+			wg.Add(1)
+			go func(index int) {
+				defer wg.Done()
+				result, err := cache.Add(context.Background(), "idempotency:key", fmt.Sprintf("message-%d", index), time.Minute)
+				assert.NoError(t, err)
+				added[index] = result
+			}(i)
+		}
+		wg.Wait()
+
+		// Assert
+		winner := -1
+		for index, result := range added {
+			if result {
+				assert.Equal(t, -1, winner)
+				winner = index
+			}
+		}
+		require.NotEqual(t, -1, winner)
+
+		value, err := cache.Get(context.Background(), "idempotency:key")
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("message-%d", winner), value)
+	})
+
+	t.Run("it adds the item again after the key is deleted", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+		cache := NewMemoryCache(telemetry.NewOtelLogger("", nil), ttlCache.New(time.Minute, time.Minute))
+
+		// Arrange
+		added, err := cache.Add(context.Background(), "idempotency:key", "first", time.Minute)
+		require.NoError(t, err)
+		require.True(t, added)
+
+		// Act
+		require.NoError(t, cache.Delete(context.Background(), "idempotency:key"))
+		added, err = cache.Add(context.Background(), "idempotency:key", "second", time.Minute)
+
+		// Assert
+		require.NoError(t, err)
+		assert.True(t, added)
+
+		value, err := cache.Get(context.Background(), "idempotency:key")
+		require.NoError(t, err)
+		assert.Equal(t, "second", value)
+	})
+}
+
+func TestMemoryCache_DeleteIfEqual(t *testing.T) {
+	t.Run("it only deletes the item when it has the value", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+		cache := NewMemoryCache(telemetry.NewOtelLogger("", nil), ttlCache.New(time.Minute, time.Minute))
+
+		// Arrange
+		require.NoError(t, cache.Set(context.Background(), "sender-pool:lock", "second-holder", time.Minute))
+
+		// Act
+		deletedOther, err := cache.DeleteIfEqual(context.Background(), "sender-pool:lock", "first-holder")
+		require.NoError(t, err)
+		deletedOwn, err := cache.DeleteIfEqual(context.Background(), "sender-pool:lock", "second-holder")
+		require.NoError(t, err)
+
+		// Assert
+		assert.False(t, deletedOther)
+		assert.True(t, deletedOwn)
+
+		_, err = cache.Get(context.Background(), "sender-pool:lock")
+		assert.Error(t, err)
+	})
+}
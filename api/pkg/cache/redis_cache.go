@@ -11,6 +11,9 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+// deleteIfEqualScript deletes the key in a single step only if it still has the value
+var deleteIfEqualScript = redis.NewScript(`if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) else return 0 end`)
+
 // redisCache is the Cache implementation in redis
 type redisCache struct {
 	tracer telemetry.Tracer
@@ -51,3 +54,38 @@ func (cache *redisCache) Set(ctx context.Context, key string, value string, ttl
 	}
 	return nil
 }
+
+// Add an item in the redis cache if the key does not exist
+func (cache *redisCache) Add(ctx context.Context, key string, value string, ttl time.Duration) (bool, error) {
+	ctx, span := cache.tracer.Start(ctx)
+	defer span.End()
+
+	added, err := cache.client.SetNX(ctx, key, value, ttl).Result()
+	if err != nil {
+		return false, cache.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot add item in redis with key [%s]", key)))
+	}
+	return added, nil
+}
+
+// Delete an item from the redis cache
+func (cache *redisCache) Delete(ctx context.Context, key string) error {
+	ctx, span := cache.tracer.Start(ctx)
+	defer span.End()
+
+	if err := cache.client.Del(ctx, key).Err(); err != nil {
+		return cache.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot delete item in redis with key [%s]", key)))
+	}
+	return nil
+}
+
+// DeleteIfEqual deletes an item from the redis cache only if it still has the value
+func (cache *redisCache) DeleteIfEqual(ctx context.Context, key string, value string) (bool, error) {
+	ctx, span := cache.tracer.Start(ctx)
+	defer span.End()
+
+	deleted, err := deleteIfEqualScript.Run(ctx, cache.client, []string{key}, value).Int()
+	if err != nil {
+		return false, cache.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot delete item in redis with key [%s] and value [%s]", key, value)))
+	}
+	return deleted == 1, nil
+}
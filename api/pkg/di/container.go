@@ -57,6 +57,8 @@ import (
 	"github.com/NdoleStudio/httpsms/pkg/listeners"
 	"github.com/NdoleStudio/httpsms/pkg/repositories"
 	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/storage"
+	"github.com/dustin/go-humanize"
 	"github.com/gofiber/fiber/v2"
 	fiberLogger "github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/swagger"
@@ -67,6 +69,7 @@ import (
 	"github.com/NdoleStudio/httpsms/pkg/handlers"
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
 	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/NdoleStudio/httpsms/pkg/webhooks"
 	"gorm.io/driver/postgres"
 	gormLogger "gorm.io/gorm/logger"
 )
@@ -112,6 +115,19 @@ func NewContainer(projectID string, version string) (container *Container) {
 	container.RegisterMessageListeners()
 	container.RegisterMessageRoutes()
 	container.RegisterBulkMessageRoutes()
+	container.RegisterBulkMessageJobListeners()
+	container.RegisterMessageTemplateRoutes()
+	container.RegisterContactRoutes()
+	container.RegisterContactGroupRoutes()
+	container.RegisterSenderPoolRoutes()
+	container.RegisterSuppressionRoutes()
+	container.RegisterAutoReplyRoutes()
+	container.RegisterAutoReplyListeners()
+	container.RegisterScheduledMessageRoutes()
+	container.RegisterScheduledMessageListeners()
+	container.RegisterAttachmentRoutes()
+	container.RegisterMessageExportRoutes()
+	container.RegisterMessageExportListeners()
 
 	container.RegisterMessageThreadRoutes()
 	container.RegisterMessageThreadListeners()
@@ -157,7 +173,11 @@ func (container *Container) App() (app *fiber.App) {
 
 	container.logger.Debug(fmt.Sprintf("creating %T", app))
 
-	app = fiber.New()
+	// request bodies are streamed so that the body limit can be raised for the bulk SMS upload route only
+	app = fiber.New(fiber.Config{StreamRequestBody: true, DisablePreParseMultipartForm: true})
+
+	// the bulk SMS upload route has a higher limit which is applied after the request is authenticated
+	app.Use(middlewares.BodyLimit(container.Tracer(), container.Logger(), fiber.DefaultBodyLimit, "/v1/bulk-messages"))
 
 	if os.Getenv("USE_HTTP_LOGGER") == "true" {
 		app.Use(fiberLogger.New())
@@ -280,6 +300,23 @@ ALTER TABLE discords ADD CONSTRAINT IF NOT EXISTS uni_discords_server_id CHECK (
 		container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T", &entities.Message{})))
 	}
 
+	// an interrupted CREATE INDEX CONCURRENTLY leaves an invalid index which is never rebuilt by IF NOT EXISTS
+	var invalidIndex bool
+	if err = db.Raw(`SELECT EXISTS (SELECT 1 FROM pg_index JOIN pg_class ON pg_class.oid = pg_index.indexrelid WHERE pg_class.relname = 'idx_messages_content_search' AND NOT pg_index.indisvalid)`).Scan(&invalidIndex).Error; err != nil {
+		container.logger.Fatal(stacktrace.Propagate(err, "cannot check if the full-text search index for messages is valid"))
+	}
+
+	if invalidIndex {
+		if err = db.Exec(`DROP INDEX CONCURRENTLY IF EXISTS idx_messages_content_search`).Error; err != nil {
+			container.logger.Fatal(stacktrace.Propagate(err, "cannot drop the invalid full-text search index for messages"))
+		}
+	}
+
+	// GIN index for the full-text search of messages which are not encrypted
+	if err = db.Exec(`CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_messages_content_search ON messages USING GIN (to_tsvector('simple', content)) WHERE encrypted = false`).Error; err != nil {
+		container.logger.Fatal(stacktrace.Propagate(err, "cannot create the full-text search index for messages"))
+	}
+
 	if err = db.AutoMigrate(&entities.MessageThread{}); err != nil {
 		container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T", &entities.MessageThread{})))
 	}
@@ -304,6 +341,18 @@ ALTER TABLE discords ADD CONSTRAINT IF NOT EXISTS uni_discords_server_id CHECK (
 		container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T", &entities.Webhook{})))
 	}
 
+	if err = db.AutoMigrate(&entities.WebhookDelivery{}); err != nil {
+		container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T", &entities.WebhookDelivery{})))
+	}
+
+	if err = db.AutoMigrate(&entities.WebhookDeliveryAttempt{}); err != nil {
+		container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T", &entities.WebhookDeliveryAttempt{})))
+	}
+
+	if err = db.AutoMigrate(&entities.WebhookDeadLetter{}); err != nil {
+		container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T", &entities.WebhookDeadLetter{})))
+	}
+
 	if err = db.AutoMigrate(&entities.Discord{}); err != nil {
 		container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T", &entities.Discord{})))
 	}
@@ -312,6 +361,50 @@ ALTER TABLE discords ADD CONSTRAINT IF NOT EXISTS uni_discords_server_id CHECK (
 		container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T", &entities.Integration3CX{})))
 	}
 
+	if err = db.AutoMigrate(&entities.MessageTemplate{}); err != nil {
+		container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T", &entities.MessageTemplate{})))
+	}
+
+	if err = db.AutoMigrate(&entities.Contact{}); err != nil {
+		container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T", &entities.Contact{})))
+	}
+
+	if err = db.AutoMigrate(&entities.ContactGroup{}); err != nil {
+		container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T", &entities.ContactGroup{})))
+	}
+
+	if err = db.AutoMigrate(&entities.Suppression{}); err != nil {
+		container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T", &entities.Suppression{})))
+	}
+
+	if err = db.AutoMigrate(&entities.AutoReply{}); err != nil {
+		container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T", &entities.AutoReply{})))
+	}
+
+	if err = db.AutoMigrate(&entities.ScheduledMessage{}); err != nil {
+		container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T", &entities.ScheduledMessage{})))
+	}
+
+	if err = db.AutoMigrate(&entities.Attachment{}); err != nil {
+		container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T", &entities.Attachment{})))
+	}
+
+	if err = db.AutoMigrate(&entities.MessageExport{}); err != nil {
+		container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T", &entities.MessageExport{})))
+	}
+
+	if err = db.AutoMigrate(&entities.BulkMessageJob{}); err != nil {
+		container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T", &entities.BulkMessageJob{})))
+	}
+
+	if err = db.AutoMigrate(&entities.BulkMessageJobRow{}); err != nil {
+		container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T", &entities.BulkMessageJobRow{})))
+	}
+
+	if err = db.AutoMigrate(&entities.SenderPool{}); err != nil {
+		container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T", &entities.SenderPool{})))
+	}
+
 	return container.db
 }
 
@@ -333,6 +426,27 @@ func (container *Container) InMemoryCache() cache.Cache {
 	return cache.NewMemoryCache(container.Tracer(), c)
 }
 
+// Storage creates a new instance of storage.Storage
+func (container *Container) Storage() storage.Storage {
+	if os.Getenv("STORAGE_BUCKET") == "" {
+		container.logger.Debug("creating local storage.Storage")
+		return storage.NewLocalStorage(container.Tracer(), os.Getenv("STORAGE_PATH"))
+	}
+
+	container.logger.Debug("creating GCS storage.Storage")
+	client, err := container.FirebaseApp().Storage(context.Background())
+	if err != nil {
+		container.logger.Fatal(stacktrace.Propagate(err, "cannot initialize firebase storage client"))
+	}
+
+	bucket, err := client.Bucket(os.Getenv("STORAGE_BUCKET"))
+	if err != nil {
+		container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot open storage bucket [%s]", os.Getenv("STORAGE_BUCKET"))))
+	}
+
+	return storage.NewGCSStorage(container.Tracer(), bucket)
+}
+
 // Cache creates a new instance of cache.Cache
 func (container *Container) Cache() cache.Cache {
 	container.logger.Debug("creating cache.Cache")
@@ -460,6 +574,12 @@ func (container *Container) MessageHandlerValidator() (validator *validators.Mes
 		container.Logger(),
 		container.Tracer(),
 		container.PhoneService(),
+		container.MessageTemplateService(),
+		container.ContactService(),
+		container.ContactGroupService(),
+		container.SuppressionService(),
+		container.AttachmentService(),
+		container.SenderPoolService(),
 	)
 }
 
@@ -469,8 +589,7 @@ func (container *Container) BulkMessageHandlerValidator() (validator *validators
 	return validators.NewBulkMessageHandlerValidator(
 		container.Logger(),
 		container.Tracer(),
-		container.PhoneService(),
-		container.UserService(),
+		container.MessageTemplateService(),
 	)
 }
 
@@ -525,6 +644,92 @@ func (container *Container) BillingHandlerValidator() (validator *validators.Bil
 	)
 }
 
+// ContactHandlerValidator creates a new instance of validators.ContactHandlerValidator
+func (container *Container) ContactHandlerValidator() (validator *validators.ContactHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewContactHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+	)
+}
+
+// ContactGroupHandlerValidator creates a new instance of validators.ContactGroupHandlerValidator
+func (container *Container) ContactGroupHandlerValidator() (validator *validators.ContactGroupHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewContactGroupHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+	)
+}
+
+// SuppressionHandlerValidator creates a new instance of validators.SuppressionHandlerValidator
+func (container *Container) SuppressionHandlerValidator() (validator *validators.SuppressionHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewSuppressionHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+	)
+}
+
+// AutoReplyHandlerValidator creates a new instance of validators.AutoReplyHandlerValidator
+func (container *Container) AutoReplyHandlerValidator() (validator *validators.AutoReplyHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewAutoReplyHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+		container.PhoneService(),
+		container.MessageTemplateService(),
+	)
+}
+
+// ScheduledMessageHandlerValidator creates a new instance of validators.ScheduledMessageHandlerValidator
+func (container *Container) ScheduledMessageHandlerValidator() (validator *validators.ScheduledMessageHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewScheduledMessageHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+		container.PhoneService(),
+		container.SenderPoolService(),
+	)
+}
+
+// MessageExportHandlerValidator creates a new instance of validators.MessageExportHandlerValidator
+func (container *Container) MessageExportHandlerValidator() (validator *validators.MessageExportHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewMessageExportHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+	)
+}
+
+// SenderPoolHandlerValidator creates a new instance of validators.SenderPoolHandlerValidator
+func (container *Container) SenderPoolHandlerValidator() (validator *validators.SenderPoolHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewSenderPoolHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+		container.PhoneService(),
+	)
+}
+
+// AttachmentHandlerValidator creates a new instance of validators.AttachmentHandlerValidator
+func (container *Container) AttachmentHandlerValidator() (validator *validators.AttachmentHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewAttachmentHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+	)
+}
+
+// MessageTemplateHandlerValidator creates a new instance of validators.MessageTemplateHandlerValidator
+func (container *Container) MessageTemplateHandlerValidator() (validator *validators.MessageTemplateHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewMessageTemplateHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+	)
+}
+
 // DiscordHandlerValidator creates a new instance of validators.DiscordHandlerValidator
 func (container *Container) DiscordHandlerValidator() (validator *validators.DiscordHandlerValidator) {
 	container.logger.Debug(fmt.Sprintf("creating %T", validator))
@@ -666,6 +871,106 @@ func (container *Container) DiscordRepository() (repository repositories.Discord
 	)
 }
 
+// ContactRepository creates a new instance of repositories.ContactRepository
+func (container *Container) ContactRepository() (repository repositories.ContactRepository) {
+	container.logger.Debug("creating GORM repositories.ContactRepository")
+	return repositories.NewGormContactRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// ContactGroupRepository creates a new instance of repositories.ContactGroupRepository
+func (container *Container) ContactGroupRepository() (repository repositories.ContactGroupRepository) {
+	container.logger.Debug("creating GORM repositories.ContactGroupRepository")
+	return repositories.NewGormContactGroupRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// SuppressionRepository creates a new instance of repositories.SuppressionRepository
+func (container *Container) SuppressionRepository() (repository repositories.SuppressionRepository) {
+	container.logger.Debug("creating GORM repositories.SuppressionRepository")
+	return repositories.NewGormSuppressionRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// AutoReplyRepository creates a new instance of repositories.AutoReplyRepository
+func (container *Container) AutoReplyRepository() (repository repositories.AutoReplyRepository) {
+	container.logger.Debug("creating GORM repositories.AutoReplyRepository")
+	return repositories.NewGormAutoReplyRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// ScheduledMessageRepository creates a new instance of repositories.ScheduledMessageRepository
+func (container *Container) ScheduledMessageRepository() (repository repositories.ScheduledMessageRepository) {
+	container.logger.Debug("creating GORM repositories.ScheduledMessageRepository")
+	return repositories.NewGormScheduledMessageRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// MessageExportRepository creates a new instance of repositories.MessageExportRepository
+func (container *Container) MessageExportRepository() (repository repositories.MessageExportRepository) {
+	container.logger.Debug("creating GORM repositories.MessageExportRepository")
+	return repositories.NewGormMessageExportRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// BulkMessageJobRepository creates a new instance of repositories.BulkMessageJobRepository
+func (container *Container) BulkMessageJobRepository() (repository repositories.BulkMessageJobRepository) {
+	container.logger.Debug("creating GORM repositories.BulkMessageJobRepository")
+	return repositories.NewGormBulkMessageJobRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// SenderPoolRepository creates a new instance of repositories.SenderPoolRepository
+func (container *Container) SenderPoolRepository() (repository repositories.SenderPoolRepository) {
+	container.logger.Debug("creating GORM repositories.SenderPoolRepository")
+	return repositories.NewGormSenderPoolRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// AttachmentRepository creates a new instance of repositories.AttachmentRepository
+func (container *Container) AttachmentRepository() (repository repositories.AttachmentRepository) {
+	container.logger.Debug("creating GORM repositories.AttachmentRepository")
+	return repositories.NewGormAttachmentRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// MessageTemplateRepository creates a new instance of repositories.MessageTemplateRepository
+func (container *Container) MessageTemplateRepository() (repository repositories.MessageTemplateRepository) {
+	container.logger.Debug("creating GORM repositories.MessageTemplateRepository")
+	return repositories.NewGormMessageTemplateRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
 // WebhookRepository creates a new instance of repositories.WebhookRepository
 func (container *Container) WebhookRepository() (repository repositories.WebhookRepository) {
 	container.logger.Debug("creating GORM repositories.WebhookRepository")
@@ -676,6 +981,36 @@ func (container *Container) WebhookRepository() (repository repositories.Webhook
 	)
 }
 
+// WebhookDeliveryRepository creates a new instance of repositories.WebhookDeliveryRepository
+func (container *Container) WebhookDeliveryRepository() (repository repositories.WebhookDeliveryRepository) {
+	container.logger.Debug("creating GORM repositories.WebhookDeliveryRepository")
+	return repositories.NewGormWebhookDeliveryRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// WebhookDeliveryAttemptRepository creates a new instance of repositories.WebhookDeliveryAttemptRepository
+func (container *Container) WebhookDeliveryAttemptRepository() (repository repositories.WebhookDeliveryAttemptRepository) {
+	container.logger.Debug("creating GORM repositories.WebhookDeliveryAttemptRepository")
+	return repositories.NewGormWebhookDeliveryAttemptRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// WebhookDeadLetterRepository creates a new instance of repositories.WebhookDeadLetterRepository
+func (container *Container) WebhookDeadLetterRepository() (repository repositories.WebhookDeadLetterRepository) {
+	container.logger.Debug("creating GORM repositories.WebhookDeadLetterRepository")
+	return repositories.NewGormWebhookDeadLetterRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
 // PhoneNotificationRepository creates a new instance of repositories.PhoneNotificationRepository
 func (container *Container) PhoneNotificationRepository() (repository repositories.PhoneNotificationRepository) {
 	container.logger.Debug("creating GORM repositories.PhoneNotificationRepository")
@@ -752,6 +1087,142 @@ func (container *Container) BillingService() (service *services.BillingService)
 	)
 }
 
+// ContactService creates a new instance of services.ContactService
+func (container *Container) ContactService() (service *services.ContactService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewContactService(
+		container.Logger(),
+		container.Tracer(),
+		container.ContactRepository(),
+	)
+}
+
+// ContactGroupService creates a new instance of services.ContactGroupService
+func (container *Container) ContactGroupService() (service *services.ContactGroupService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewContactGroupService(
+		container.Logger(),
+		container.Tracer(),
+		container.ContactGroupRepository(),
+	)
+}
+
+// SuppressionService creates a new instance of services.SuppressionService
+func (container *Container) SuppressionService() (service *services.SuppressionService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewSuppressionService(
+		container.Logger(),
+		container.Tracer(),
+		container.SuppressionRepository(),
+		container.EventDispatcher(),
+	)
+}
+
+// AutoReplyService creates a new instance of services.AutoReplyService
+func (container *Container) AutoReplyService() (service *services.AutoReplyService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewAutoReplyService(
+		container.Logger(),
+		container.Tracer(),
+		container.Cache(),
+		container.AutoReplyRepository(),
+		container.UserRepository(),
+		container.PhoneService(),
+		container.MessageService(),
+		container.MessageTemplateService(),
+		container.SuppressionService(),
+		container.EventDispatcher(),
+	)
+}
+
+// ScheduledMessageService creates a new instance of services.ScheduledMessageService
+func (container *Container) ScheduledMessageService() (service *services.ScheduledMessageService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewScheduledMessageService(
+		container.Logger(),
+		container.Tracer(),
+		container.ScheduledMessageRepository(),
+		container.UserRepository(),
+		container.MessageService(),
+		container.BillingService(),
+		container.SuppressionService(),
+		container.EventDispatcher(),
+	)
+}
+
+// MessageExportService creates a new instance of services.MessageExportService
+func (container *Container) MessageExportService() (service *services.MessageExportService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewMessageExportService(
+		container.Logger(),
+		container.Tracer(),
+		container.MessageRepository(),
+		container.MessageExportRepository(),
+		container.UserRepository(),
+		container.Storage(),
+		container.Mailer(),
+		container.UserEmailFactory(),
+		container.EventDispatcher(),
+		os.Getenv("API_URL"),
+	)
+}
+
+// BulkMessageJobService creates a new instance of services.BulkMessageJobService
+func (container *Container) BulkMessageJobService() (service *services.BulkMessageJobService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewBulkMessageJobService(
+		container.Logger(),
+		container.Tracer(),
+		container.BulkMessageJobRepository(),
+		container.UserRepository(),
+		container.MessageService(),
+		container.PhoneService(),
+		container.MessageTemplateService(),
+		container.SuppressionService(),
+		container.BillingService(),
+		container.SenderPoolService(),
+		container.Storage(),
+		container.EventDispatcher(),
+	)
+}
+
+// SenderPoolService creates a new instance of services.SenderPoolService
+func (container *Container) SenderPoolService() (service *services.SenderPoolService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewSenderPoolService(
+		container.Logger(),
+		container.Tracer(),
+		container.SenderPoolRepository(),
+		container.PhoneRepository(),
+		container.HeartbeatMonitorRepository(),
+		container.MessageThreadRepository(),
+		container.PhoneNotificationRepository(),
+		container.Cache(),
+	)
+}
+
+// AttachmentService creates a new instance of services.AttachmentService
+func (container *Container) AttachmentService() (service *services.AttachmentService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewAttachmentService(
+		container.Logger(),
+		container.Tracer(),
+		container.AttachmentRepository(),
+		container.Storage(),
+		os.Getenv("API_URL"),
+	)
+}
+
+// MessageTemplateService creates a new instance of services.MessageTemplateService
+func (container *Container) MessageTemplateService() (service *services.MessageTemplateService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewMessageTemplateService(
+		container.Logger(),
+		container.Tracer(),
+		container.MessageTemplateRepository(),
+	)
+}
+
 // DiscordService creates a new instance of services.DiscordService
 func (container *Container) DiscordService() (service *services.DiscordService) {
 	container.logger.Debug(fmt.Sprintf("creating %T", service))
@@ -772,10 +1243,30 @@ func (container *Container) WebhookService() (service *services.WebhookService)
 		container.Tracer(),
 		container.HTTPClient("webhook"),
 		container.WebhookRepository(),
+		container.WebhookDeliveryRepository(),
+		container.WebhookDeliveryAttemptRepository(),
+		container.WebhookDeadLetterRepository(),
 		container.EventDispatcher(),
+		container.WebhookRenderers(),
+		container.WebhookMaxDeliveryAttempts(),
 	)
 }
 
+// WebhookRenderers creates a new instance of webhooks.Registry
+func (container *Container) WebhookRenderers() (registry *webhooks.Registry) {
+	container.logger.Debug(fmt.Sprintf("creating %T", registry))
+	return webhooks.NewRegistry()
+}
+
+// WebhookMaxDeliveryAttempts is the number of times we try to send an event to a webhook before giving up
+func (container *Container) WebhookMaxDeliveryAttempts() uint {
+	attempts, err := strconv.ParseUint(os.Getenv("WEBHOOK_MAX_DELIVERY_ATTEMPTS"), 10, 32)
+	if err != nil || attempts == 0 {
+		return 5
+	}
+	return uint(attempts)
+}
+
 // Integration3CXService creates a new instance of services.Integration3CXService
 func (container *Container) Integration3CXService() (service *services.Integration3CXService) {
 	container.logger.Debug(fmt.Sprintf("creating %T", service))
@@ -907,6 +1398,7 @@ func (container *Container) MessageThreadService() (service *services.MessageThr
 		container.Logger(),
 		container.Tracer(),
 		container.MessageThreadRepository(),
+		container.ContactRepository(),
 		container.EventDispatcher(),
 	)
 }
@@ -933,6 +1425,12 @@ func (container *Container) MessageHandler() (handler *handlers.MessageHandler)
 		container.MessageHandlerValidator(),
 		container.BillingService(),
 		container.MessageService(),
+		container.MessageTemplateService(),
+		container.ContactService(),
+		container.ContactGroupService(),
+		container.SuppressionService(),
+		container.AttachmentService(),
+		container.BulkMessageJobService(),
 	)
 }
 
@@ -943,8 +1441,7 @@ func (container *Container) BulkMessageHandler() (handler *handlers.BulkMessageH
 		container.Logger(),
 		container.Tracer(),
 		container.BulkMessageHandlerValidator(),
-		container.BillingService(),
-		container.MessageService(),
+		container.BulkMessageJobService(),
 	)
 }
 
@@ -1031,6 +1528,105 @@ func (container *Container) Integration3CXHandler() (handler *handlers.Integrati
 	)
 }
 
+// ContactHandler creates a new instance of handlers.ContactHandler
+func (container *Container) ContactHandler() (handler *handlers.ContactHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", handler))
+	return handlers.NewContactHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.ContactHandlerValidator(),
+		container.ContactService(),
+	)
+}
+
+// ContactGroupHandler creates a new instance of handlers.ContactGroupHandler
+func (container *Container) ContactGroupHandler() (handler *handlers.ContactGroupHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", handler))
+	return handlers.NewContactGroupHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.ContactGroupHandlerValidator(),
+		container.ContactGroupService(),
+	)
+}
+
+// SuppressionHandler creates a new instance of handlers.SuppressionHandler
+func (container *Container) SuppressionHandler() (handler *handlers.SuppressionHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", handler))
+	return handlers.NewSuppressionHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.SuppressionHandlerValidator(),
+		container.SuppressionService(),
+	)
+}
+
+// AutoReplyHandler creates a new instance of handlers.AutoReplyHandler
+func (container *Container) AutoReplyHandler() (handler *handlers.AutoReplyHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", handler))
+	return handlers.NewAutoReplyHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.AutoReplyHandlerValidator(),
+		container.AutoReplyService(),
+	)
+}
+
+// ScheduledMessageHandler creates a new instance of handlers.ScheduledMessageHandler
+func (container *Container) ScheduledMessageHandler() (handler *handlers.ScheduledMessageHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", handler))
+	return handlers.NewScheduledMessageHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.ScheduledMessageHandlerValidator(),
+		container.ScheduledMessageService(),
+	)
+}
+
+// MessageExportHandler creates a new instance of handlers.MessageExportHandler
+func (container *Container) MessageExportHandler() (handler *handlers.MessageExportHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", handler))
+	return handlers.NewMessageExportHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.MessageExportHandlerValidator(),
+		container.MessageExportService(),
+	)
+}
+
+// SenderPoolHandler creates a new instance of handlers.SenderPoolHandler
+func (container *Container) SenderPoolHandler() (handler *handlers.SenderPoolHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", handler))
+	return handlers.NewSenderPoolHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.SenderPoolHandlerValidator(),
+		container.SenderPoolService(),
+	)
+}
+
+// AttachmentHandler creates a new instance of handlers.AttachmentHandler
+func (container *Container) AttachmentHandler() (handler *handlers.AttachmentHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", handler))
+	return handlers.NewAttachmentHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.AttachmentHandlerValidator(),
+		container.AttachmentService(),
+	)
+}
+
+// MessageTemplateHandler creates a new instance of handlers.MessageTemplateHandler
+func (container *Container) MessageTemplateHandler() (handler *handlers.MessageTemplateHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", handler))
+	return handlers.NewMessageTemplateHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.MessageTemplateHandlerValidator(),
+		container.MessageTemplateService(),
+	)
+}
+
 // DiscordHandler creates a new instance of handlers.DiscordHandler
 func (container *Container) DiscordHandler() (handler *handlers.DiscordHandler) {
 	container.logger.Debug(fmt.Sprintf("creating %T", handler))
@@ -1229,6 +1825,10 @@ func (container *Container) MessageService() (service *services.MessageService)
 		container.MessageRepository(),
 		container.EventDispatcher(),
 		container.PhoneService(),
+		container.SuppressionService(),
+		container.SenderPoolService(),
+		container.UserRepository(),
+		container.Cache(),
 	)
 }
 
@@ -1242,6 +1842,8 @@ func (container *Container) NotificationService() (service *services.PhoneNotifi
 		container.PhoneRepository(),
 		container.PhoneNotificationRepository(),
 		container.EventDispatcher(),
+		container.UserRepository(),
+		container.MessageRepository(),
 	)
 }
 
@@ -1254,7 +1856,121 @@ func (container *Container) RegisterMessageRoutes() {
 // RegisterBulkMessageRoutes registers routes for the /bulk-messages prefix
 func (container *Container) RegisterBulkMessageRoutes() {
 	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.BulkMessageHandler{}))
-	container.BulkMessageHandler().RegisterRoutes(container.AuthRouter())
+	router := container.AuthRouter()
+
+	// bulk SMS files of up to 50 MB are uploaded in a single request, the extra 1 MB is for the rest of the multipart form
+	router.Use("/bulk-messages", middlewares.BodyLimit(container.Tracer(), container.Logger(), validators.BulkMessageMaxFileSize+humanize.MByte))
+	container.BulkMessageHandler().RegisterRoutes(router)
+}
+
+// RegisterContactRoutes registers routes for the /contacts prefix
+func (container *Container) RegisterContactRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.ContactHandler{}))
+	container.ContactHandler().RegisterRoutes(container.AuthRouter())
+}
+
+// RegisterContactGroupRoutes registers routes for the /contact-groups prefix
+func (container *Container) RegisterContactGroupRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.ContactGroupHandler{}))
+	container.ContactGroupHandler().RegisterRoutes(container.AuthRouter())
+}
+
+// RegisterSuppressionRoutes registers routes for the /suppressions prefix
+func (container *Container) RegisterSuppressionRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.SuppressionHandler{}))
+	container.SuppressionHandler().RegisterRoutes(container.AuthRouter())
+}
+
+// RegisterAutoReplyRoutes registers routes for the /auto-replies prefix
+func (container *Container) RegisterAutoReplyRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.AutoReplyHandler{}))
+	container.AutoReplyHandler().RegisterRoutes(container.AuthRouter())
+}
+
+// RegisterAutoReplyListeners registers event listeners for listeners.AutoReplyListener
+func (container *Container) RegisterAutoReplyListeners() {
+	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.AutoReplyListener{}))
+	_, routes := listeners.NewAutoReplyListener(
+		container.Logger(),
+		container.Tracer(),
+		container.AutoReplyService(),
+	)
+
+	for event, handler := range routes {
+		container.EventDispatcher().Subscribe(event, handler)
+	}
+}
+
+// RegisterScheduledMessageRoutes registers routes for the /scheduled-messages prefix
+func (container *Container) RegisterScheduledMessageRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.ScheduledMessageHandler{}))
+	container.ScheduledMessageHandler().RegisterRoutes(container.AuthRouter())
+}
+
+// RegisterScheduledMessageListeners registers event listeners for listeners.ScheduledMessageListener
+func (container *Container) RegisterScheduledMessageListeners() {
+	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.ScheduledMessageListener{}))
+	_, routes := listeners.NewScheduledMessageListener(
+		container.Logger(),
+		container.Tracer(),
+		container.ScheduledMessageService(),
+	)
+
+	for event, handler := range routes {
+		container.EventDispatcher().Subscribe(event, handler)
+	}
+}
+
+// RegisterMessageExportRoutes registers routes for the /message-exports prefix
+func (container *Container) RegisterMessageExportRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.MessageExportHandler{}))
+	container.MessageExportHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware())
+}
+
+// RegisterBulkMessageJobListeners registers event listeners for listeners.BulkMessageJobListener
+func (container *Container) RegisterBulkMessageJobListeners() {
+	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.BulkMessageJobListener{}))
+	_, routes := listeners.NewBulkMessageJobListener(
+		container.Logger(),
+		container.Tracer(),
+		container.BulkMessageJobService(),
+	)
+
+	for event, handler := range routes {
+		container.EventDispatcher().Subscribe(event, handler)
+	}
+}
+
+// RegisterMessageExportListeners registers event listeners for listeners.MessageExportListener
+func (container *Container) RegisterMessageExportListeners() {
+	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.MessageExportListener{}))
+	_, routes := listeners.NewMessageExportListener(
+		container.Logger(),
+		container.Tracer(),
+		container.MessageExportService(),
+	)
+
+	for event, handler := range routes {
+		container.EventDispatcher().Subscribe(event, handler)
+	}
+}
+
+// RegisterSenderPoolRoutes registers routes for the /sender-pools prefix
+func (container *Container) RegisterSenderPoolRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.SenderPoolHandler{}))
+	container.SenderPoolHandler().RegisterRoutes(container.AuthRouter())
+}
+
+// RegisterAttachmentRoutes registers routes for the /attachments prefix
+func (container *Container) RegisterAttachmentRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.AttachmentHandler{}))
+	container.AttachmentHandler().RegisterRoutes(container.AuthRouter())
+}
+
+// RegisterMessageTemplateRoutes registers routes for the /message-templates prefix
+func (container *Container) RegisterMessageTemplateRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.MessageTemplateHandler{}))
+	container.MessageTemplateHandler().RegisterRoutes(container.AuthRouter())
 }
 
 // RegisterMessageThreadRoutes registers routes for the /message-threads prefix
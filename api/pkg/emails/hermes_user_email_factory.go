@@ -2,6 +2,7 @@ package emails
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
@@ -153,6 +154,55 @@ func (factory *hermesUserEmailFactory) UsageLimitAlert(user *entities.User, usag
 	}, nil
 }
 
+// MessageExportCompleted is the email sent when a large message export is ready to download
+func (factory *hermesUserEmailFactory) MessageExportCompleted(user *entities.User, export *entities.MessageExport, link string) (*Email, error) {
+	location, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	email := hermes.Email{
+		Body: hermes.Body{
+			Intros: []string{
+				fmt.Sprintf("Your export of %d messages in the %s format is ready.", export.Count, strings.ToUpper(export.Format)),
+			},
+			Actions: []hermes.Action{
+				{
+					Instructions: fmt.Sprintf("Click the button below to download the file. The link expires on %s.", export.ExpiresAt.In(location).Format(time.RFC1123)),
+					Button: hermes.Button{
+						Color:     "#329ef4",
+						TextColor: "#FFFFFF",
+						Text:      "DOWNLOAD EXPORT",
+						Link:      link,
+					},
+				},
+			},
+			Title:     "Hey,",
+			Signature: "Cheers",
+			Outros: []string{
+				"Anyone with this link can download your messages so don't share it.",
+			},
+		},
+	}
+
+	html, err := factory.generator.GenerateHTML(email)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "cannot generate html email")
+	}
+
+	text, err := factory.generator.GeneratePlainText(email)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "cannot generate text email")
+	}
+
+	return &Email{
+		ToEmail: user.Email,
+		Subject: "Your httpSMS message export is ready",
+		HTML:    html,
+		Text:    text,
+	}, nil
+}
+
 // NewHermesUserEmailFactory creates a new instance of the UserEmailFactory
 func NewHermesUserEmailFactory(config *HermesGeneratorConfig) UserEmailFactory {
 	return &hermesUserEmailFactory{
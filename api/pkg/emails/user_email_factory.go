@@ -19,4 +19,7 @@ type UserEmailFactory interface {
 
 	// APIKeyRotated sends an email when the API key is rotated
 	APIKeyRotated(email string, timestamp time.Time, timezone string) (*Email, error)
+
+	// MessageExportCompleted sends an email with the download link of a large entities.MessageExport
+	MessageExportCompleted(user *entities.User, export *entities.MessageExport, link string) (*Email, error)
 }
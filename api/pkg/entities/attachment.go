@@ -0,0 +1,21 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Attachment is a media file like a picture which is sent or received with an MMS message
+type Attachment struct {
+	ID          uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID      UserID    `json:"user_id" gorm:"index" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Name        string    `json:"name" example:"invoice.png"`
+	ContentType string    `json:"content_type" example:"image/png"`
+	Size        int64     `json:"size" example:"102400"`
+	// URL is the link used to download the attachment with your API key
+	URL string `json:"url" example:"https://api.httpsms.com/v1/attachments/32343a19-da5e-4b1b-a767-3298a73703cb/download"`
+	// StorageKey is the location of the content in the storage.Storage
+	StorageKey string    `json:"-"`
+	CreatedAt  time.Time `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+}
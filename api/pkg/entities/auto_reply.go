@@ -0,0 +1,127 @@
+package entities
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AutoReplyMatch is the strategy used to match an inbound message to an AutoReply
+type AutoReplyMatch string
+
+const (
+	// AutoReplyMatchKeyword matches messages which start with the keyword
+	AutoReplyMatchKeyword = AutoReplyMatch("keyword")
+
+	// AutoReplyMatchRegex matches messages with the regular expression
+	AutoReplyMatchRegex = AutoReplyMatch("regex")
+
+	// AutoReplyMatchContact matches every message sent by the contact
+	AutoReplyMatchContact = AutoReplyMatch("contact")
+)
+
+// String converts the AutoReplyMatch to a string
+func (match AutoReplyMatch) String() string {
+	return string(match)
+}
+
+// AutoReplyAction is what happens when an AutoReply matches an inbound message
+type AutoReplyAction string
+
+const (
+	// AutoReplyActionReply replies to the contact with a text message
+	AutoReplyActionReply = AutoReplyAction("reply")
+
+	// AutoReplyActionTemplate replies to the contact with a MessageTemplate
+	AutoReplyActionTemplate = AutoReplyAction("template")
+
+	// AutoReplyActionForward forwards the message to another phone number
+	AutoReplyActionForward = AutoReplyAction("forward")
+
+	// AutoReplyActionTag adds a tag to the MessageThread
+	AutoReplyActionTag = AutoReplyAction("tag")
+
+	// AutoReplyActionWebhook sends the auto-reply.triggered event to the webhooks of the user
+	AutoReplyActionWebhook = AutoReplyAction("webhook")
+)
+
+// String converts the AutoReplyAction to a string
+func (action AutoReplyAction) String() string {
+	return string(action)
+}
+
+// AutoReply is a rule which is evaluated for every message received by a phone
+type AutoReply struct {
+	ID     uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID UserID    `json:"user_id" gorm:"index:idx_auto_replies_user_id_owner" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Owner  string    `json:"owner" gorm:"index:idx_auto_replies_user_id_owner" example:"+18005550199"`
+	Name   string    `json:"name" example:"Opening hours"`
+
+	Match   AutoReplyMatch `json:"match" example:"keyword"`
+	Pattern string         `json:"pattern" example:"HOURS"`
+
+	// StartTime and EndTime are an optional daily window in the timezone of the user e.g 09:00 to 17:00
+	StartTime *string `json:"start_time" example:"09:00"`
+	EndTime   *string `json:"end_time" example:"17:00"`
+
+	Action     AutoReplyAction `json:"action" example:"reply"`
+	Content    *string         `json:"content" example:"We are open from 9am to 5pm, Monday to Friday"`
+	TemplateID *uuid.UUID      `json:"template_id" gorm:"type:uuid" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	ForwardTo  *string         `json:"forward_to" example:"+18005550100"`
+	Tag        *string         `json:"tag" example:"support"`
+
+	// CooldownSeconds is the minimum duration between 2 executions of the rule for the same contact, it does not apply to forwards
+	CooldownSeconds uint `json:"cooldown_seconds" example:"300"`
+
+	IsActive  bool      `json:"is_active" example:"true"`
+	Priority  uint      `json:"priority" example:"1"`
+	CreatedAt time.Time `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt time.Time `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
+
+// Cooldown is the duration between 2 executions of the rule for the same contact
+func (reply *AutoReply) Cooldown() time.Duration {
+	return time.Duration(reply.CooldownSeconds) * time.Second
+}
+
+// IsRateLimited checks if the executions of the AutoReply for a contact are limited by the cooldown, forwarded messages are never dropped
+func (reply *AutoReply) IsRateLimited() bool {
+	return reply.Action != AutoReplyActionForward && reply.CooldownSeconds > 0
+}
+
+// Matches checks if a message from the contact matches the AutoReply
+func (reply *AutoReply) Matches(contact string, content string) bool {
+	switch reply.Match {
+	case AutoReplyMatchKeyword:
+		fields := strings.Fields(content)
+		return len(fields) > 0 && strings.EqualFold(strings.TrimFunc(fields[0], reply.isPunctuation), reply.Pattern)
+	case AutoReplyMatchRegex:
+		expression, err := regexp.Compile(reply.Pattern)
+		return err == nil && expression.MatchString(content)
+	case AutoReplyMatchContact:
+		return contact == reply.Pattern
+	default:
+		return false
+	}
+}
+
+// IsActiveAt checks if the timestamp is within the daily window of the AutoReply
+func (reply *AutoReply) IsActiveAt(timestamp time.Time) bool {
+	if reply.StartTime == nil || reply.EndTime == nil {
+		return true
+	}
+
+	now := timestamp.Format("15:04")
+	if *reply.StartTime <= *reply.EndTime {
+		return now >= *reply.StartTime && now < *reply.EndTime
+	}
+
+	// the window spans midnight e.g 22:00 to 06:00
+	return now >= *reply.StartTime || now < *reply.EndTime
+}
+
+func (reply *AutoReply) isPunctuation(r rune) bool {
+	return strings.ContainsRune(".,!?;:'\"", r)
+}
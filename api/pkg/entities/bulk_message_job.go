@@ -0,0 +1,130 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// BulkMessageJobStatus is the status of a BulkMessageJob
+type BulkMessageJobStatus string
+
+const (
+	// BulkMessageJobStatusValidating means the rows of the uploaded file are being validated
+	BulkMessageJobStatusValidating = BulkMessageJobStatus("validating")
+
+	// BulkMessageJobStatusInvalid means the uploaded file contains invalid rows and no message was sent
+	BulkMessageJobStatusInvalid = BulkMessageJobStatus("invalid")
+
+	// BulkMessageJobStatusSending means the valid rows are being queued in batches
+	BulkMessageJobStatusSending = BulkMessageJobStatus("sending")
+
+	// BulkMessageJobStatusCompleted means every row of the uploaded file has been queued or skipped
+	BulkMessageJobStatusCompleted = BulkMessageJobStatus("completed")
+
+	// BulkMessageJobStatusFailed means the job was stopped e.g. because the user is not entitled to send the messages
+	BulkMessageJobStatusFailed = BulkMessageJobStatus("failed")
+)
+
+// String converts the BulkMessageJobStatus to a string
+func (status BulkMessageJobStatus) String() string {
+	return string(status)
+}
+
+// BulkMessageJobRowStatus is the outcome of a row in the uploaded file before a Message is created
+type BulkMessageJobRowStatus string
+
+const (
+	// BulkMessageJobRowStatusPending means the row is valid and waiting for its batch to be queued
+	BulkMessageJobRowStatusPending = BulkMessageJobRowStatus("pending")
+
+	// BulkMessageJobRowStatusQueued means a Message was created for the row, the outcome is the MessageStatus
+	BulkMessageJobRowStatusQueued = BulkMessageJobRowStatus("queued")
+
+	// BulkMessageJobRowStatusSkipped means the row was not sent e.g. because the recipient opted out
+	BulkMessageJobRowStatusSkipped = BulkMessageJobRowStatus("skipped")
+
+	// BulkMessageJobRowStatusFailed means a Message could not be created for the row
+	BulkMessageJobRowStatusFailed = BulkMessageJobRowStatus("failed")
+)
+
+// String converts the BulkMessageJobRowStatus to a string
+func (status BulkMessageJobRowStatus) String() string {
+	return string(status)
+}
+
+// BulkMessageJob tracks the messages sent from a bulk SMS file
+type BulkMessageJob struct {
+	ID       uuid.UUID            `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID   UserID               `json:"user_id" gorm:"index" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Filename string               `json:"filename" example:"campaign.csv"`
+	Status   BulkMessageJobStatus `json:"status" example:"sending"`
+	// TemplateID is the entities.MessageTemplate used as the content of every row
+	TemplateID *uuid.UUID `json:"template_id" gorm:"type:uuid" example:"8f9c71b8-b84e-4417-8408-a62274f65a08"`
+	// Source is the origin of the upload which is used as the source of the queued messages
+	Source string `json:"-"`
+	// StorageKey is the location of the uploaded file in the storage.Storage until it is validated
+	StorageKey string `json:"-"`
+	// ContactGroupID is the ContactGroup of a broadcast, the rows of a broadcast are the phone numbers in the group
+	ContactGroupID *uuid.UUID `json:"contact_group_id" gorm:"type:uuid" example:"6c29c2f2-d1a4-4f53-bb6e-ee4d1d7e8b87"`
+	// Encrypted is true when the content of the messages is end-to-end encrypted
+	Encrypted bool `json:"-"`
+	// MessageRequestID is the Message.RequestID chosen by the user for the messages of a broadcast
+	MessageRequestID *string `json:"-"`
+
+	// Total is the number of rows in the uploaded file
+	Total int `json:"total" example:"250"`
+	// Valid is the number of rows which will be sent
+	Valid int `json:"valid" example:"242"`
+	// Processed is the number of valid rows which have been queued so far
+	Processed int `json:"processed" example:"200"`
+	// Errors are the validation errors of an invalid file or the reason a job failed
+	Errors pq.StringArray `json:"errors" gorm:"type:text[]" swaggertype:"array,string" example:"Row [3]: The ToPhoneNumber [+1800] is not a valid E.164 phone number"`
+
+	// Counts is the number of rows by the BulkMessageJobRowStatus or MessageStatus of the row
+	Counts map[string]int `json:"counts" gorm:"-" example:"delivered:240,failed:2,skipped:8"`
+	// Rows are the outcomes of the rows in the uploaded file
+	Rows []*BulkMessageJobRow `json:"rows" gorm:"-"`
+
+	CreatedAt time.Time `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt time.Time `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
+
+// Fail stops the BulkMessageJob with the reasons
+func (job *BulkMessageJob) Fail(status BulkMessageJobStatus, reasons ...string) *BulkMessageJob {
+	job.Status = status
+	job.Errors = reasons
+	job.UpdatedAt = time.Now().UTC()
+	return job
+}
+
+// RequestID is the Message.RequestID of every message sent by the BulkMessageJob
+func (job *BulkMessageJob) RequestID() string {
+	if job.MessageRequestID != nil {
+		return *job.MessageRequestID
+	}
+	return fmt.Sprintf("bulk-%s", job.ID)
+}
+
+// BulkMessageJobRow is the outcome of a single row in the file of a BulkMessageJob
+type BulkMessageJobRow struct {
+	ID    uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;" example:"e5c1a3b4-3f7b-4a5e-9a0e-6c8c4e4a1f1b"`
+	JobID uuid.UUID `json:"job_id" gorm:"type:uuid;uniqueIndex:idx_bulk_message_job_rows_job_id_row" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	// Row is the line number of the row in the uploaded file
+	Row       int        `json:"row" gorm:"column:row_number;uniqueIndex:idx_bulk_message_job_rows_job_id_row" example:"2"`
+	Owner     string     `json:"owner" example:"+18005550199"`
+	Contact   string     `json:"contact" example:"+18005550100"`
+	MessageID *uuid.UUID `json:"message_id" gorm:"type:uuid" example:"ea8a0b36-ab33-4c5a-bf2c-fa4f5a0ad3c4"`
+
+	// Status is the BulkMessageJobRowStatus of the row or the MessageStatus once the message is queued
+	Status string  `json:"status" example:"delivered"`
+	Reason *string `json:"reason" example:"the recipient opted out of receiving messages"`
+
+	// Content is the rendered content of the message which is sent for the row
+	Content string     `json:"-"`
+	SendAt  *time.Time `json:"-"`
+
+	CreatedAt time.Time `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+}
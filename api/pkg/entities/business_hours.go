@@ -0,0 +1,28 @@
+package entities
+
+import (
+	"time"
+)
+
+// BusinessHours are the opening hours of a phone on a day of the week in the timezone of the user
+type BusinessHours struct {
+	// Day of the week where 0 is Sunday and 6 is Saturday
+	Day   time.Weekday `json:"day" swaggertype:"integer" example:"1"`
+	Start string       `json:"start" example:"09:00"`
+	End   string       `json:"end" example:"17:00"`
+}
+
+// IsOpenAt checks if the timestamp is within the BusinessHours
+func (hours BusinessHours) IsOpenAt(timestamp time.Time) bool {
+	clock := timestamp.Format("15:04")
+	return timestamp.Weekday() == hours.Day && clock >= hours.Start && clock < hours.End
+}
+
+// OpensOn returns the opening time of the BusinessHours on the date
+func (hours BusinessHours) OpensOn(date time.Time) time.Time {
+	start, err := time.Parse("15:04", hours.Start)
+	if err != nil {
+		return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), start.Hour(), start.Minute(), 0, 0, date.Location())
+}
@@ -0,0 +1,29 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Contact is a person in the address book of a user
+type Contact struct {
+	ID           uuid.UUID         `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID       UserID            `json:"user_id" gorm:"index" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Name         string            `json:"name" example:"John Doe"`
+	PhoneNumbers pq.StringArray    `json:"phone_numbers" gorm:"type:text[];index:,type:gin" swaggertype:"array,string" example:"+18005550100"`
+	Tags         pq.StringArray    `json:"tags" gorm:"type:text[];index:,type:gin" swaggertype:"array,string" example:"customers,vip"`
+	CustomFields map[string]string `json:"custom_fields" gorm:"type:jsonb;serializer:json" swaggertype:"object,string" example:"company:Acme"`
+	Notes        string            `json:"notes" example:"Prefers to be contacted in the evening"`
+	CreatedAt    time.Time         `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt    time.Time         `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
+
+// PhoneNumber returns the phone number used when sending a message to the contact
+func (contact *Contact) PhoneNumber() string {
+	if len(contact.PhoneNumbers) == 0 {
+		return ""
+	}
+	return contact.PhoneNumbers[0]
+}
@@ -0,0 +1,24 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ContactGroup is a named audience of phone numbers which can receive a broadcast message
+type ContactGroup struct {
+	ID           uuid.UUID      `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID       UserID         `json:"user_id" gorm:"index" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Name         string         `json:"name" example:"Newsletter Subscribers"`
+	Description  string         `json:"description" example:"Customers who opted in to the weekly newsletter"`
+	PhoneNumbers pq.StringArray `json:"phone_numbers" gorm:"type:text[]" swaggertype:"array,string" example:"+18005550100,+18005550199"`
+	CreatedAt    time.Time      `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt    time.Time      `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
+
+// Size returns the number of phone numbers in the group
+func (group *ContactGroup) Size() int {
+	return len(group.PhoneNumbers)
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // MessageType is the type of message if it is incoming or outgoing
@@ -50,6 +51,9 @@ const (
 
 	// MessageStatusDeleted is for deleted messages and threads
 	MessageStatusDeleted = "deleted"
+
+	// MessageStatusCancelled means the message was cancelled before a phone picked it up
+	MessageStatusCancelled = "cancelled"
 )
 
 // MessageEventName is the type of event generated by the mobile phone for a message
@@ -98,6 +102,14 @@ type Message struct {
 	// * DEFAULT: used the default communication SIM card
 	SIM SIM `json:"sim" example:"DEFAULT"`
 
+	// Encoding is the character set used to send the content, either GSM-7 or UCS-2. It is empty for encrypted messages.
+	Encoding string `json:"encoding" example:"GSM-7"`
+	// Segments is the number of SMS messages needed to send the content. It is 0 for encrypted messages.
+	Segments uint `json:"segments" example:"1"`
+
+	// Attachments are the download URLs of the pictures and other media files of an MMS message
+	Attachments pq.StringArray `json:"attachments" gorm:"type:text[]" swaggertype:"array,string" example:"https://api.httpsms.com/v1/attachments/32343a19-da5e-4b1b-a767-3298a73703cb/download"`
+
 	// SendDuration is the number of nanoseconds from when the request was received until when the mobile phone send the message
 	SendDuration *int64 `json:"send_time" example:"133414"`
 
@@ -144,6 +156,31 @@ func (message *Message) IsExpired() bool {
 	return message.Status == MessageStatusExpired
 }
 
+// IsCancelled checks if a message has been cancelled
+func (message *Message) IsCancelled() bool {
+	return message.Status == MessageStatusCancelled
+}
+
+// CanBeCancelled checks if a phone has not picked up the message yet
+func (message *Message) CanBeCancelled() bool {
+	return message.IsPending() || message.IsScheduled()
+}
+
+// Cancelled registers a message as cancelled
+func (message *Message) Cancelled(timestamp time.Time) *Message {
+	message.Status = MessageStatusCancelled
+	message.updateOrderTimestamp(timestamp)
+	return message
+}
+
+// Rescheduled changes the time when the message will be sent
+func (message *Message) Rescheduled(sendAt time.Time) *Message {
+	message.ScheduledSendTime = &sendAt
+	message.Status = MessageStatusPending
+	message.NotificationScheduledAt = nil
+	return message
+}
+
 // CanBeRescheduled checks if a message can be rescheduled
 func (message *Message) CanBeRescheduled() bool {
 	return message.SendAttemptCount < message.MaxSendAttempts
@@ -214,6 +251,12 @@ func (message *Message) NotificationScheduled(timestamp time.Time) *Message {
 	return message
 }
 
+// NotificationDeferred configures a Message when the notification is deferred until the end of the quiet hours
+func (message *Message) NotificationDeferred(timestamp time.Time) *Message {
+	message.ScheduledSendTime = &timestamp
+	return message.NotificationScheduled(timestamp)
+}
+
 // AddSendAttempt configures a Message for sending
 func (message *Message) AddSendAttempt(timestamp time.Time) *Message {
 	message.Status = MessageStatusSending
@@ -0,0 +1,17 @@
+package entities
+
+// MessageAnalysis is the encoding and the number of SMS segments needed to send the content of a message
+type MessageAnalysis struct {
+	// Encoding is either GSM-7 or UCS-2 when the content has characters which are not in the GSM 03.38 alphabet
+	Encoding   string `json:"encoding" example:"GSM-7"`
+	Characters uint   `json:"characters" example:"29"`
+	// Length is the number of GSM-7 septets or UCS-2 code units in the content
+	Length   uint `json:"length" example:"29"`
+	Segments uint `json:"segments" example:"1"`
+	// Remaining is the number of GSM-7 septets or UCS-2 code units which can still be added to the last segment
+	Remaining uint `json:"remaining" example:"131"`
+	// UnicodeCharacters are the characters which force the UCS-2 encoding
+	UnicodeCharacters []string `json:"unicode_characters" example:"’"`
+	// MaxSegments is the maximum number of segments allowed by the phone. It is null when there is no limit.
+	MaxSegments *uint `json:"max_segments" example:"3"`
+}
@@ -0,0 +1,56 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// MessageExportStatus is the status of a MessageExport
+type MessageExportStatus string
+
+const (
+	// MessageExportStatusPending means the export file is being created or its download link is being emailed
+	MessageExportStatusPending = MessageExportStatus("pending")
+
+	// MessageExportStatusCompleted means the export file can be downloaded
+	MessageExportStatusCompleted = MessageExportStatus("completed")
+
+	// MessageExportStatusFailed means the export file could not be created
+	MessageExportStatusFailed = MessageExportStatus("failed")
+)
+
+// MessageExport is a large export of messages which is created in the background and sent to the user by email
+type MessageExport struct {
+	ID        uuid.UUID      `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID    UserID         `json:"user_id" gorm:"index" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Format    string         `json:"format" example:"csv"`
+	Owner     string         `json:"owner" example:"+18005550199"`
+	Contacts  pq.StringArray `json:"contacts" gorm:"type:text[]" swaggertype:"array,string" example:"+18005550100"`
+	StartDate *time.Time     `json:"start_date" example:"2022-06-01T00:00:00Z"`
+	EndDate   *time.Time     `json:"end_date" example:"2022-07-01T00:00:00Z"`
+
+	Status MessageExportStatus `json:"status" example:"completed"`
+	// Count is the number of messages in the export file
+	Count int64 `json:"count" example:"250000"`
+
+	// StorageKey is the location of the export file in the storage.Storage
+	StorageKey string `json:"-"`
+	// Token authorizes the download link which is sent by email
+	Token     string     `json:"-"`
+	ExpiresAt *time.Time `json:"expires_at" example:"2022-06-12T14:26:02.302718+03:00"`
+
+	CreatedAt time.Time `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt time.Time `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
+
+// IsCompleted checks if the export file can be downloaded
+func (export *MessageExport) IsCompleted() bool {
+	return export.Status == MessageExportStatusCompleted
+}
+
+// IsExpired checks if the download link of the MessageExport has expired
+func (export *MessageExport) IsExpired(timestamp time.Time) bool {
+	return export.ExpiresAt != nil && timestamp.After(*export.ExpiresAt)
+}
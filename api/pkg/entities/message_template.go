@@ -0,0 +1,19 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// MessageTemplate is reusable message content with {{variable}} placeholders
+type MessageTemplate struct {
+	ID        uuid.UUID      `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID    UserID         `json:"user_id" gorm:"index" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Name      string         `json:"name" example:"Appointment Reminder"`
+	Content   string         `json:"content" example:"Hello {{name}}, your appointment is on {{date}}"`
+	Variables pq.StringArray `json:"variables" gorm:"type:text[]" swaggertype:"array,string" example:"name,date"`
+	CreatedAt time.Time      `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt time.Time      `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
@@ -4,22 +4,25 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // MessageThread represents a message thread between 2 phone numbers
 type MessageThread struct {
-	ID                 uuid.UUID     `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703ca"`
-	Owner              string        `json:"owner" example:"+18005550199"`
-	Contact            string        `json:"contact" example:"+18005550100"`
-	IsArchived         bool          `json:"is_archived" example:"false"`
-	UserID             UserID        `json:"user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
-	Color              string        `json:"color" example:"indigo"`
-	Status             MessageStatus `json:"status" example:"PENDING"`
-	LastMessageContent *string       `json:"last_message_content" example:"This is a sample message content"`
-	LastMessageID      *uuid.UUID    `json:"last_message_id" example:"32343a19-da5e-4b1b-a767-3298a73703ca"`
-	CreatedAt          time.Time     `json:"created_at" example:"2022-06-05T14:26:09.527976+03:00"`
-	UpdatedAt          time.Time     `json:"updated_at" example:"2022-06-05T14:26:09.527976+03:00"`
-	OrderTimestamp     time.Time     `json:"order_timestamp" example:"2022-06-05T14:26:09.527976+03:00"`
+	ID                 uuid.UUID      `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703ca"`
+	Owner              string         `json:"owner" example:"+18005550199"`
+	Contact            string         `json:"contact" example:"+18005550100"`
+	IsArchived         bool           `json:"is_archived" example:"false"`
+	UserID             UserID         `json:"user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Color              string         `json:"color" example:"indigo"`
+	Status             MessageStatus  `json:"status" example:"PENDING"`
+	LastMessageContent *string        `json:"last_message_content" example:"This is a sample message content"`
+	LastMessageID      *uuid.UUID     `json:"last_message_id" example:"32343a19-da5e-4b1b-a767-3298a73703ca"`
+	ContactName        *string        `json:"contact_name" gorm:"-" example:"John Doe"`
+	Tags               pq.StringArray `json:"tags" gorm:"type:text[]" swaggertype:"array,string" example:"support"`
+	CreatedAt          time.Time      `json:"created_at" example:"2022-06-05T14:26:09.527976+03:00"`
+	UpdatedAt          time.Time      `json:"updated_at" example:"2022-06-05T14:26:09.527976+03:00"`
+	OrderTimestamp     time.Time      `json:"order_timestamp" example:"2022-06-05T14:26:09.527976+03:00"`
 }
 
 // Update a message thread after a message event
@@ -37,6 +40,17 @@ func (thread *MessageThread) UpdateArchive(isArchived bool) *MessageThread {
 	return thread
 }
 
+// AddTag adds a tag to the message thread if it is not already present
+func (thread *MessageThread) AddTag(tag string) *MessageThread {
+	for _, value := range thread.Tags {
+		if value == tag {
+			return thread
+		}
+	}
+	thread.Tags = append(thread.Tags, tag)
+	return thread
+}
+
 // HasLastMessage checks the last message in a thread by ID
 func (thread *MessageThread) HasLastMessage(id uuid.UUID) bool {
 	if thread.LastMessageID == nil {
@@ -1,9 +1,12 @@
 package entities
 
 import (
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // Phone represents an android phone which has installed the http sms app
@@ -17,11 +20,33 @@ type Phone struct {
 	// MaxSendAttempts determines how many times to retry sending an SMS message
 	MaxSendAttempts uint `json:"max_send_attempts" example:"2"`
 
+	// MaxSegments is the maximum number of SMS segments of an outgoing message. 0 means there is no limit.
+	MaxSegments uint `json:"max_segments" example:"3"`
+
 	// MessageExpirationSeconds is the duration in seconds after sending a message when it is considered to be expired.
 	MessageExpirationSeconds uint `json:"message_expiration_seconds"`
 
 	MissedCallAutoReply *string `json:"missed_call_auto_reply" example:"This phone cannot receive calls. Please send an SMS instead."`
 
+	// OptOutKeywords are the replies which add the contact to the suppression list. Defaults to STOP and UNSUBSCRIBE when empty.
+	OptOutKeywords pq.StringArray `json:"opt_out_keywords" gorm:"type:text[]" swaggertype:"array,string" example:"STOP,UNSUBSCRIBE"`
+
+	// OptInKeywords are the replies which remove the contact from the suppression list. Defaults to START when empty.
+	OptInKeywords pq.StringArray `json:"opt_in_keywords" gorm:"type:text[]" swaggertype:"array,string" example:"START"`
+
+	// BusinessHours are the weekly opening hours of the phone in the timezone of the user
+	BusinessHours []BusinessHours `json:"business_hours" gorm:"serializer:json;type:jsonb"`
+
+	// Holidays are the dates in the YYYY-MM-DD format when the phone is out of office for the whole day
+	Holidays pq.StringArray `json:"holidays" gorm:"type:text[]" swaggertype:"array,string" example:"2024-12-25"`
+
+	// OutOfOfficeReply is sent once per contact when an SMS or a missed call arrives outside the business hours
+	OutOfOfficeReply *string `json:"out_of_office_reply" example:"We are closed. Our business hours are 9am to 5pm, Monday to Friday."`
+
+	// QuietHoursStart and QuietHoursEnd are a daily window in the timezone of the user when outgoing messages are deferred e.g 21:00 to 08:00
+	QuietHoursStart *string `json:"quiet_hours_start" example:"21:00"`
+	QuietHoursEnd   *string `json:"quiet_hours_end" example:"08:00"`
+
 	CreatedAt time.Time `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
 	UpdatedAt time.Time `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
 }
@@ -46,3 +71,138 @@ func (phone *Phone) MaxSendAttemptsSanitized() uint {
 	}
 	return phone.MaxSendAttempts
 }
+
+// BacklogDuration returns the time needed to send the pending notifications at the MessagesPerMinute rate limit of the phone
+func (phone *Phone) BacklogDuration(pending int) time.Duration {
+	if phone.MessagesPerMinute == 0 {
+		return 0
+	}
+	return time.Duration(pending) * time.Minute / time.Duration(phone.MessagesPerMinute)
+}
+
+// HasQuietHours checks if the phone defers outgoing messages during quiet hours
+func (phone *Phone) HasQuietHours() bool {
+	return phone.QuietHoursStart != nil && phone.QuietHoursEnd != nil && *phone.QuietHoursStart != *phone.QuietHoursEnd
+}
+
+// QuietHoursDeferral returns the end of the quiet hours when the timestamp in the timezone of the user is within the quiet hours
+func (phone *Phone) QuietHoursDeferral(timestamp time.Time) *time.Time {
+	if !phone.HasQuietHours() {
+		return nil
+	}
+
+	end, err := time.Parse("15:04", *phone.QuietHoursEnd)
+	if err != nil {
+		return nil
+	}
+
+	clock := timestamp.Format("15:04")
+	endsAt := time.Date(timestamp.Year(), timestamp.Month(), timestamp.Day(), end.Hour(), end.Minute(), 0, 0, timestamp.Location())
+
+	switch {
+	case *phone.QuietHoursStart < *phone.QuietHoursEnd && clock >= *phone.QuietHoursStart && clock < *phone.QuietHoursEnd:
+		return &endsAt
+	case *phone.QuietHoursStart > *phone.QuietHoursEnd && clock >= *phone.QuietHoursStart:
+		// the quiet hours span midnight e.g 21:00 to 08:00
+		endsAt = endsAt.AddDate(0, 0, 1)
+		return &endsAt
+	case *phone.QuietHoursStart > *phone.QuietHoursEnd && clock < *phone.QuietHoursEnd:
+		return &endsAt
+	default:
+		return nil
+	}
+}
+
+// HasOutOfOfficeReply checks if the phone has business hours or holidays and an out-of-office reply
+func (phone *Phone) HasOutOfOfficeReply() bool {
+	if phone.OutOfOfficeReply == nil || strings.TrimSpace(*phone.OutOfOfficeReply) == "" {
+		return false
+	}
+	return len(phone.BusinessHours) > 0 || len(phone.Holidays) > 0
+}
+
+// IsOutOfOffice checks if the timestamp in the timezone of the user is on a holiday or outside the business hours
+func (phone *Phone) IsOutOfOffice(timestamp time.Time) bool {
+	if phone.isHoliday(timestamp) {
+		return true
+	}
+
+	if len(phone.BusinessHours) == 0 {
+		return false
+	}
+
+	for _, hours := range phone.BusinessHours {
+		if hours.IsOpenAt(timestamp) {
+			return false
+		}
+	}
+	return true
+}
+
+// NextOpening returns the time after the timestamp when the phone is back in office
+func (phone *Phone) NextOpening(timestamp time.Time) time.Time {
+	today := time.Date(timestamp.Year(), timestamp.Month(), timestamp.Day(), 0, 0, 0, 0, timestamp.Location())
+	for days := 0; days <= 366; days++ {
+		date := today.AddDate(0, 0, days)
+		if phone.isHoliday(date) {
+			continue
+		}
+
+		if len(phone.BusinessHours) == 0 && days > 0 {
+			return date
+		}
+
+		var opening *time.Time
+		for _, hours := range phone.BusinessHours {
+			if hours.Day != date.Weekday() {
+				continue
+			}
+			if start := hours.OpensOn(date); start.After(timestamp) && (opening == nil || start.Before(*opening)) {
+				opening = &start
+			}
+		}
+
+		if opening != nil {
+			return *opening
+		}
+	}
+	return timestamp.Add(24 * time.Hour)
+}
+
+func (phone *Phone) isHoliday(timestamp time.Time) bool {
+	date := timestamp.Format(time.DateOnly)
+	for _, holiday := range phone.Holidays {
+		if holiday == date {
+			return true
+		}
+	}
+	return false
+}
+
+// OptOutKeyword returns the opt-out keyword which matches the content of a received message
+func (phone *Phone) OptOutKeyword(content string) *string {
+	return phone.matchKeyword(content, phone.OptOutKeywords, []string{"STOP", "UNSUBSCRIBE"})
+}
+
+// OptInKeyword returns the opt-in keyword which matches the content of a received message
+func (phone *Phone) OptInKeyword(content string) *string {
+	return phone.matchKeyword(content, phone.OptInKeywords, []string{"START"})
+}
+
+func (phone *Phone) matchKeyword(content string, keywords []string, defaults []string) *string {
+	if len(keywords) == 0 {
+		keywords = defaults
+	}
+
+	content = strings.TrimFunc(content, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for _, keyword := range keywords {
+		keyword = strings.TrimSpace(keyword)
+		if keyword != "" && strings.EqualFold(content, keyword) {
+			return &keyword
+		}
+	}
+	return nil
+}
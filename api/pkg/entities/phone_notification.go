@@ -13,6 +13,8 @@ const (
 	PhoneNotificationStatusSent = "sent"
 	// PhoneNotificationStatusFailed is the status when a notification could not be sent.
 	PhoneNotificationStatusFailed = "failed"
+	// PhoneNotificationStatusCancelled is the status when the message was cancelled or rescheduled before the notification was sent
+	PhoneNotificationStatusCancelled = "cancelled"
 )
 
 // PhoneNotificationStatus is the status of a phone notification
@@ -21,7 +23,7 @@ type PhoneNotificationStatus string
 // PhoneNotification represents an FCM notification to a mobile phone
 type PhoneNotification struct {
 	ID          uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;"`
-	MessageID   uuid.UUID `json:"message_id"`
+	MessageID   uuid.UUID `json:"message_id" gorm:"index"`
 	UserID      UserID    `json:"user_id"`
 	PhoneID     uuid.UUID `json:"phone_id"`
 	Status      string    `json:"status"`
@@ -29,3 +31,8 @@ type PhoneNotification struct {
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
+
+// IsPending checks if the PhoneNotification has not been sent yet
+func (notification *PhoneNotification) IsPending() bool {
+	return notification.Status == PhoneNotificationStatusPending
+}
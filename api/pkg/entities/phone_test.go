@@ -0,0 +1,218 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPhone_QuietHoursDeferral(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		start     *string
+		end       *string
+		timestamp time.Time
+		expected  *time.Time
+	}{
+		{
+			name:      "it defers to the end of quiet hours within the same day",
+			start:     phoneClock("12:00"),
+			end:       phoneClock("14:00"),
+			timestamp: time.Date(2024, 3, 6, 13, 15, 0, 0, newYork),
+			expected:  phoneTime(time.Date(2024, 3, 6, 14, 0, 0, 0, newYork)),
+		},
+		{
+			name:      "it defers from the start of the quiet hours",
+			start:     phoneClock("12:00"),
+			end:       phoneClock("14:00"),
+			timestamp: time.Date(2024, 3, 6, 12, 0, 0, 0, newYork),
+			expected:  phoneTime(time.Date(2024, 3, 6, 14, 0, 0, 0, newYork)),
+		},
+		{
+			name:      "it does not defer at the end of the quiet hours",
+			start:     phoneClock("12:00"),
+			end:       phoneClock("14:00"),
+			timestamp: time.Date(2024, 3, 6, 14, 0, 0, 0, newYork),
+			expected:  nil,
+		},
+		{
+			name:      "it defers to the next day when quiet hours cross midnight before midnight",
+			start:     phoneClock("21:00"),
+			end:       phoneClock("08:00"),
+			timestamp: time.Date(2024, 3, 6, 23, 30, 0, 0, newYork),
+			expected:  phoneTime(time.Date(2024, 3, 7, 8, 0, 0, 0, newYork)),
+		},
+		{
+			name:      "it defers to the same day when quiet hours cross midnight after midnight",
+			start:     phoneClock("21:00"),
+			end:       phoneClock("08:00"),
+			timestamp: time.Date(2024, 3, 7, 2, 0, 0, 0, newYork),
+			expected:  phoneTime(time.Date(2024, 3, 7, 8, 0, 0, 0, newYork)),
+		},
+		{
+			name:      "it defers to the next month when quiet hours cross midnight at the end of the month",
+			start:     phoneClock("21:00"),
+			end:       phoneClock("08:00"),
+			timestamp: time.Date(2024, 2, 29, 22, 0, 0, 0, newYork),
+			expected:  phoneTime(time.Date(2024, 3, 1, 8, 0, 0, 0, newYork)),
+		},
+		{
+			name:      "it does not defer outside of quiet hours which cross midnight",
+			start:     phoneClock("21:00"),
+			end:       phoneClock("08:00"),
+			timestamp: time.Date(2024, 3, 7, 12, 0, 0, 0, newYork),
+			expected:  nil,
+		},
+		{
+			name:      "it defers to the local end of quiet hours when the night crosses a DST change",
+			start:     phoneClock("21:00"),
+			end:       phoneClock("08:00"),
+			timestamp: time.Date(2024, 3, 9, 22, 0, 0, 0, newYork),
+			expected:  phoneTime(time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)),
+		},
+		{
+			name:      "it does not defer when the start and end are the same",
+			start:     phoneClock("08:00"),
+			end:       phoneClock("08:00"),
+			timestamp: time.Date(2024, 3, 7, 8, 0, 0, 0, newYork),
+			expected:  nil,
+		},
+		{
+			name:      "it does not defer without quiet hours",
+			start:     nil,
+			end:       nil,
+			timestamp: time.Date(2024, 3, 7, 2, 0, 0, 0, newYork),
+			expected:  nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Setup
+			t.Parallel()
+
+			// Arrange
+			phone := &Phone{QuietHoursStart: test.start, QuietHoursEnd: test.end}
+
+			// Act
+			deferredAt := phone.QuietHoursDeferral(test.timestamp)
+
+			// Assert
+			if test.expected == nil {
+				assert.Nil(t, deferredAt)
+				return
+			}
+			require.NotNil(t, deferredAt)
+			assert.True(t, test.expected.Equal(*deferredAt), "expected [%s] but got [%s]", test.expected, deferredAt)
+		})
+	}
+}
+
+func phoneClock(value string) *string {
+	return &value
+}
+
+func phoneTime(timestamp time.Time) *time.Time {
+	return &timestamp
+}
+
+func TestPhone_NextOpening(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	weekdays := []BusinessHours{
+		{Day: time.Monday, Start: "09:00", End: "17:00"},
+		{Day: time.Tuesday, Start: "09:00", End: "17:00"},
+		{Day: time.Wednesday, Start: "09:00", End: "17:00"},
+		{Day: time.Thursday, Start: "09:00", End: "17:00"},
+		{Day: time.Friday, Start: "09:00", End: "17:00"},
+	}
+
+	tests := []struct {
+		name          string
+		businessHours []BusinessHours
+		holidays      []string
+		timestamp     time.Time
+		expected      time.Time
+	}{
+		{
+			name:          "it opens on the same day before the business hours",
+			businessHours: weekdays,
+			timestamp:     time.Date(2024, 3, 6, 7, 0, 0, 0, newYork),
+			expected:      time.Date(2024, 3, 6, 9, 0, 0, 0, newYork),
+		},
+		{
+			name:          "it opens on the next day after the business hours close",
+			businessHours: weekdays,
+			timestamp:     time.Date(2024, 3, 6, 18, 0, 0, 0, newYork),
+			expected:      time.Date(2024, 3, 7, 9, 0, 0, 0, newYork),
+		},
+		{
+			name:          "it opens on monday after the business hours close on friday",
+			businessHours: weekdays,
+			timestamp:     time.Date(2024, 3, 1, 18, 0, 0, 0, newYork),
+			expected:      time.Date(2024, 3, 4, 9, 0, 0, 0, newYork),
+		},
+		{
+			name:          "it opens on monday during the weekend",
+			businessHours: weekdays,
+			timestamp:     time.Date(2024, 3, 2, 12, 0, 0, 0, newYork),
+			expected:      time.Date(2024, 3, 4, 9, 0, 0, 0, newYork),
+		},
+		{
+			name:          "it skips a holiday after the weekend",
+			businessHours: weekdays,
+			holidays:      []string{"2024-03-04"},
+			timestamp:     time.Date(2024, 3, 2, 12, 0, 0, 0, newYork),
+			expected:      time.Date(2024, 3, 5, 9, 0, 0, 0, newYork),
+		},
+		{
+			name:          "it opens at the local time when the weekend crosses a DST change",
+			businessHours: weekdays,
+			timestamp:     time.Date(2024, 3, 8, 18, 0, 0, 0, newYork),
+			expected:      time.Date(2024, 3, 11, 13, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "it uses the timezone of the timestamp when the day is different in UTC",
+			businessHours: weekdays,
+			timestamp:     time.Date(2024, 3, 6, 23, 0, 0, 0, time.UTC).In(tokyo),
+			expected:      time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "it opens after the business hours close in UTC at the same instant",
+			businessHours: weekdays,
+			timestamp:     time.Date(2024, 3, 6, 23, 0, 0, 0, time.UTC),
+			expected:      time.Date(2024, 3, 7, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "it opens at midnight after a holiday without business hours",
+			holidays:  []string{"2024-03-06"},
+			timestamp: time.Date(2024, 3, 6, 10, 0, 0, 0, newYork),
+			expected:  time.Date(2024, 3, 7, 0, 0, 0, 0, newYork),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Setup
+			t.Parallel()
+
+			// Arrange
+			phone := &Phone{BusinessHours: test.businessHours, Holidays: test.holidays}
+
+			// Act
+			opening := phone.NextOpening(test.timestamp)
+
+			// Assert
+			assert.True(t, test.expected.Equal(opening), "expected [%s] but got [%s]", test.expected, opening)
+		})
+	}
+}
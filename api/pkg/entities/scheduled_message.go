@@ -0,0 +1,155 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/palantir/stacktrace"
+	"github.com/robfig/cron/v3"
+	"github.com/teambition/rrule-go"
+)
+
+// ScheduledMessageFormat is the syntax of the recurrence expression of a ScheduledMessage
+type ScheduledMessageFormat string
+
+const (
+	// ScheduledMessageFormatCron is a standard 5 field cron expression e.g 0 9 * * MON-FRI
+	ScheduledMessageFormatCron = ScheduledMessageFormat("cron")
+
+	// ScheduledMessageFormatRRule is an RFC 5545 recurrence rule e.g FREQ=WEEKLY;BYDAY=MO;BYHOUR=9;BYMINUTE=0
+	ScheduledMessageFormatRRule = ScheduledMessageFormat("rrule")
+)
+
+// String converts the ScheduledMessageFormat to a string
+func (format ScheduledMessageFormat) String() string {
+	return string(format)
+}
+
+// scheduledMessageMaxMessageIDs is the number of messages kept in the history of a ScheduledMessage
+const scheduledMessageMaxMessageIDs = 100
+
+// ScheduledMessageStatus is the status of a ScheduledMessage
+type ScheduledMessageStatus string
+
+const (
+	// ScheduledMessageStatusActive means the next occurrence will be sent
+	ScheduledMessageStatusActive = ScheduledMessageStatus("active")
+
+	// ScheduledMessageStatusPaused means no occurrence is sent until the schedule is resumed
+	ScheduledMessageStatusPaused = ScheduledMessageStatus("paused")
+
+	// ScheduledMessageStatusCompleted means the recurrence rule has no more occurrences
+	ScheduledMessageStatusCompleted = ScheduledMessageStatus("completed")
+)
+
+// String converts the ScheduledMessageStatus to a string
+func (status ScheduledMessageStatus) String() string {
+	return string(status)
+}
+
+// ScheduledMessage is a message which is sent repeatedly on a recurring schedule
+type ScheduledMessage struct {
+	ID      uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID  UserID    `json:"user_id" gorm:"index:idx_scheduled_messages_user_id_owner" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Owner   string    `json:"owner" gorm:"index:idx_scheduled_messages_user_id_owner" example:"+18005550199"`
+	Contact string    `json:"contact" example:"+18005550100"`
+	Content string    `json:"content" example:"Your weekly reminder to submit your timesheet"`
+
+	// Expression is evaluated in the timezone of the user
+	Format     ScheduledMessageFormat `json:"format" example:"cron"`
+	Expression string                 `json:"expression" example:"0 9 * * MON"`
+
+	Status    ScheduledMessageStatus `json:"status" example:"active"`
+	NextRunAt *time.Time             `json:"next_run_at" example:"2022-06-06T09:00:00+03:00"`
+	LastRunAt *time.Time             `json:"last_run_at" example:"2022-05-30T09:00:00+03:00"`
+	RunCount  uint                   `json:"run_count" example:"3"`
+
+	// MessageIDs is the history of the last 100 messages produced by the schedule
+	MessageIDs pq.StringArray `json:"message_ids" gorm:"type:text[]" swaggertype:"array,string" example:"ea8a0b36-ab33-4c5a-bf2c-fa4f5a0ad3c4"`
+
+	CreatedAt time.Time `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt time.Time `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
+
+// IsActive checks if the ScheduledMessage will send the next occurrence
+func (schedule *ScheduledMessage) IsActive() bool {
+	return schedule.Status == ScheduledMessageStatusActive
+}
+
+// IsDue checks if the occurrence is the next run of an active ScheduledMessage.
+// A later occurrence is also due when the next run failed so that the schedule keeps running.
+func (schedule *ScheduledMessage) IsDue(occurrence time.Time) bool {
+	return schedule.IsActive() && schedule.NextRunAt != nil && !schedule.NextRunAt.After(occurrence)
+}
+
+// NextOccurrence returns the first occurrence strictly after the timestamp or nil when there are no more occurrences.
+// The timestamp must be in the timezone of the user.
+func (schedule *ScheduledMessage) NextOccurrence(after time.Time) (*time.Time, error) {
+	var next time.Time
+	switch schedule.Format {
+	case ScheduledMessageFormatCron:
+		expression, err := cron.ParseStandard(schedule.Expression)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "cannot parse cron expression [%s]", schedule.Expression)
+		}
+		next = expression.Next(after)
+	case ScheduledMessageFormatRRule:
+		options, err := rrule.StrToROptionInLocation(schedule.Expression, after.Location())
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "cannot parse recurrence rule [%s]", schedule.Expression)
+		}
+		if options.Dtstart.IsZero() {
+			options.Dtstart = schedule.CreatedAt.In(after.Location()).Truncate(time.Minute)
+		}
+		rule, err := rrule.NewRRule(*options)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "cannot create recurrence rule [%s]", schedule.Expression)
+		}
+		next = rule.After(after, false)
+	default:
+		return nil, stacktrace.NewError("unsupported schedule format [%s]", schedule.Format)
+	}
+
+	if next.IsZero() {
+		return nil, nil
+	}
+
+	next = next.UTC()
+	return &next, nil
+}
+
+// Occurred records the message which was sent for an occurrence and moves the ScheduledMessage to the next occurrence
+func (schedule *ScheduledMessage) Occurred(messageID uuid.UUID, occurrence time.Time, next *time.Time) *ScheduledMessage {
+	schedule.MessageIDs = append(schedule.MessageIDs, messageID.String())
+	if len(schedule.MessageIDs) > scheduledMessageMaxMessageIDs {
+		schedule.MessageIDs = schedule.MessageIDs[len(schedule.MessageIDs)-scheduledMessageMaxMessageIDs:]
+	}
+	schedule.LastRunAt = &occurrence
+	schedule.RunCount++
+	return schedule.Reschedule(next)
+}
+
+// Reschedule sets the next occurrence of the ScheduledMessage and completes it when there are no more occurrences
+func (schedule *ScheduledMessage) Reschedule(next *time.Time) *ScheduledMessage {
+	schedule.NextRunAt = next
+	if next == nil {
+		schedule.Status = ScheduledMessageStatusCompleted
+	}
+	schedule.UpdatedAt = time.Now().UTC()
+	return schedule
+}
+
+// Pause stops sending occurrences of the ScheduledMessage
+func (schedule *ScheduledMessage) Pause() *ScheduledMessage {
+	schedule.Status = ScheduledMessageStatusPaused
+	schedule.NextRunAt = nil
+	schedule.UpdatedAt = time.Now().UTC()
+	return schedule
+}
+
+// Resume sends occurrences of a paused ScheduledMessage again starting from the next occurrence
+func (schedule *ScheduledMessage) Resume(next *time.Time) *ScheduledMessage {
+	schedule.Status = ScheduledMessageStatusActive
+	return schedule.Reschedule(next)
+}
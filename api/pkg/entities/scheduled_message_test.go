@@ -0,0 +1,160 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduledMessage_NextOccurrence(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		format     ScheduledMessageFormat
+		expression string
+		after      time.Time
+		expected   *time.Time
+	}{
+		{
+			name:       "it keeps a cron occurrence at the same local time when DST starts",
+			format:     ScheduledMessageFormatCron,
+			expression: "0 9 * * *",
+			after:      time.Date(2024, 3, 9, 10, 0, 0, 0, newYork),
+			expected:   scheduledMessageTime(time.Date(2024, 3, 10, 13, 0, 0, 0, time.UTC)),
+		},
+		{
+			name:       "it keeps a cron occurrence at the same local time when DST ends",
+			format:     ScheduledMessageFormatCron,
+			expression: "0 9 * * *",
+			after:      time.Date(2024, 11, 2, 10, 0, 0, 0, newYork),
+			expected:   scheduledMessageTime(time.Date(2024, 11, 3, 14, 0, 0, 0, time.UTC)),
+		},
+		{
+			name:       "it skips a cron occurrence which doesn't exist when DST starts",
+			format:     ScheduledMessageFormatCron,
+			expression: "30 2 * * *",
+			after:      time.Date(2024, 3, 9, 10, 0, 0, 0, newYork),
+			expected:   scheduledMessageTime(time.Date(2024, 3, 11, 6, 30, 0, 0, time.UTC)),
+		},
+		{
+			name:       "it skips months without the day of a cron expression",
+			format:     ScheduledMessageFormatCron,
+			expression: "0 9 31 * *",
+			after:      time.Date(2024, 4, 1, 0, 0, 0, 0, newYork),
+			expected:   scheduledMessageTime(time.Date(2024, 5, 31, 13, 0, 0, 0, time.UTC)),
+		},
+		{
+			name:       "it keeps a recurrence rule occurrence at the same local time when DST starts",
+			format:     ScheduledMessageFormatRRule,
+			expression: "FREQ=DAILY;BYHOUR=9;BYMINUTE=0;BYSECOND=0",
+			after:      time.Date(2024, 3, 9, 10, 0, 0, 0, newYork),
+			expected:   scheduledMessageTime(time.Date(2024, 3, 10, 13, 0, 0, 0, time.UTC)),
+		},
+		{
+			name:       "it uses the last day of february in a leap year for a recurrence rule",
+			format:     ScheduledMessageFormatRRule,
+			expression: "FREQ=MONTHLY;BYMONTHDAY=-1;BYHOUR=9;BYMINUTE=0;BYSECOND=0",
+			after:      time.Date(2024, 2, 1, 0, 0, 0, 0, newYork),
+			expected:   scheduledMessageTime(time.Date(2024, 2, 29, 14, 0, 0, 0, time.UTC)),
+		},
+		{
+			name:       "it skips months without the day of a recurrence rule",
+			format:     ScheduledMessageFormatRRule,
+			expression: "FREQ=MONTHLY;BYMONTHDAY=31;BYHOUR=9;BYMINUTE=0;BYSECOND=0",
+			after:      time.Date(2024, 4, 1, 0, 0, 0, 0, newYork),
+			expected:   scheduledMessageTime(time.Date(2024, 5, 31, 13, 0, 0, 0, time.UTC)),
+		},
+		{
+			name:       "it returns nil when a recurrence rule has no more occurrences",
+			format:     ScheduledMessageFormatRRule,
+			expression: "FREQ=DAILY;COUNT=2;BYHOUR=9;BYMINUTE=0;BYSECOND=0",
+			after:      time.Date(2024, 3, 9, 10, 0, 0, 0, newYork),
+			expected:   nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Setup
+			t.Parallel()
+
+			// Arrange
+			schedule := &ScheduledMessage{
+				Format:     test.format,
+				Expression: test.expression,
+				CreatedAt:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			}
+
+			// Act
+			next, err := schedule.NextOccurrence(test.after)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, next)
+		})
+	}
+
+	t.Run("it returns an error for an invalid expression", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		schedule := &ScheduledMessage{Format: ScheduledMessageFormatCron, Expression: "0 9 * *"}
+
+		// Act
+		_, err := schedule.NextOccurrence(time.Now())
+
+		// Assert
+		assert.Error(t, err)
+	})
+}
+
+func TestScheduledMessage_IsDue(t *testing.T) {
+	t.Run("it is due for the next run and for a later run when the next run failed", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		next := time.Date(2024, 3, 10, 13, 0, 0, 0, time.UTC)
+		schedule := &ScheduledMessage{Status: ScheduledMessageStatusActive, NextRunAt: &next}
+
+		// Assert
+		assert.True(t, schedule.IsDue(next))
+		assert.True(t, schedule.IsDue(next.Add(24*time.Hour)))
+		assert.False(t, schedule.IsDue(next.Add(-24*time.Hour)))
+		assert.False(t, schedule.Pause().IsDue(next))
+	})
+}
+
+func TestScheduledMessage_Occurred(t *testing.T) {
+	t.Run("it keeps the history of the last messages", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		schedule := &ScheduledMessage{Status: ScheduledMessageStatusActive}
+		next := time.Now().UTC()
+		messageIDs := make([]uuid.UUID, scheduledMessageMaxMessageIDs+5)
+
+		// Act
+		for i := range messageIDs {
+			messageIDs[i] = uuid.New()
+			schedule.Occurred(messageIDs[i], next, &next)
+		}
+
+		// Assert
+		assert.Len(t, schedule.MessageIDs, scheduledMessageMaxMessageIDs)
+		assert.Equal(t, messageIDs[5].String(), schedule.MessageIDs[0])
+		assert.Equal(t, messageIDs[len(messageIDs)-1].String(), schedule.MessageIDs[scheduledMessageMaxMessageIDs-1])
+		assert.Equal(t, uint(len(messageIDs)), schedule.RunCount)
+	})
+}
+
+func scheduledMessageTime(timestamp time.Time) *time.Time {
+	return &timestamp
+}
@@ -0,0 +1,70 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// SenderPool is a group of phones which share the outgoing messages sent to the ID of the pool
+type SenderPool struct {
+	ID     uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID UserID    `json:"user_id" gorm:"index" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Name   string    `json:"name" example:"Customer Support"`
+	// PhoneNumbers are the numbers of the phones in the pool, the order is used to break ties between phones with the same load
+	PhoneNumbers pq.StringArray `json:"phone_numbers" gorm:"type:text[]" swaggertype:"array,string" example:"+18005550199,+18005550100"`
+	CreatedAt    time.Time      `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt    time.Time      `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
+
+// Size returns the number of phones in the pool
+func (pool *SenderPool) Size() int {
+	return len(pool.PhoneNumbers)
+}
+
+// SenderPoolReservation is a message which was routed to a phone of a SenderPool and whose notification may not be scheduled yet
+type SenderPoolReservation struct {
+	PhoneID   uuid.UUID `json:"phone_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// SenderPoolReservations are the recent SenderPoolReservation of a SenderPool
+type SenderPoolReservations []SenderPoolReservation
+
+// Active removes the reservations which expired before the timestamp
+func (reservations SenderPoolReservations) Active(timestamp time.Time) SenderPoolReservations {
+	result := make(SenderPoolReservations, 0, len(reservations))
+	for _, reservation := range reservations {
+		if reservation.ExpiresAt.After(timestamp) {
+			result = append(result, reservation)
+		}
+	}
+	return result
+}
+
+// LeastLoadedPhone returns the phone which needs the least time to send its pending and reserved messages, ties are broken by the
+// number of messages and then by the order of the phones
+func (reservations SenderPoolReservations) LeastLoadedPhone(phones []*Phone, pending map[uuid.UUID]int) *Phone {
+	if len(phones) == 0 {
+		return nil
+	}
+
+	load := make(map[uuid.UUID]int, len(phones))
+	for phoneID, count := range pending {
+		load[phoneID] = count
+	}
+	for _, reservation := range reservations {
+		load[reservation.PhoneID]++
+	}
+
+	result := phones[0]
+	for _, phone := range phones[1:] {
+		backlog, best := phone.BacklogDuration(load[phone.ID]), result.BacklogDuration(load[result.ID])
+		if backlog < best || (backlog == best && load[phone.ID] < load[result.ID]) {
+			result = phone
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,113 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSenderPoolReservations_LeastLoadedPhone(t *testing.T) {
+	slow := &Phone{ID: uuid.New(), MessagesPerMinute: 1}
+	fast := &Phone{ID: uuid.New(), MessagesPerMinute: 10}
+	unlimited := &Phone{ID: uuid.New(), MessagesPerMinute: 0}
+
+	tests := []struct {
+		name         string
+		phones       []*Phone
+		pending      map[uuid.UUID]int
+		reservations SenderPoolReservations
+		expected     *Phone
+	}{
+		{
+			name:     "it returns nil without phones",
+			phones:   []*Phone{},
+			expected: nil,
+		},
+		{
+			name:     "it uses the order of the phones when they have the same load",
+			phones:   []*Phone{slow, fast},
+			pending:  map[uuid.UUID]int{},
+			expected: slow,
+		},
+		{
+			name:     "it uses the phone which sends its pending messages the fastest",
+			phones:   []*Phone{slow, fast},
+			pending:  map[uuid.UUID]int{slow.ID: 1, fast.ID: 5},
+			expected: fast,
+		},
+		{
+			name:     "it uses the phone with less pending messages when the backlogs are the same",
+			phones:   []*Phone{unlimited, fast},
+			pending:  map[uuid.UUID]int{unlimited.ID: 3},
+			expected: fast,
+		},
+		{
+			name:    "it counts the reservations of messages which were routed before their notifications are scheduled",
+			phones:  []*Phone{slow, fast},
+			pending: map[uuid.UUID]int{},
+			reservations: SenderPoolReservations{
+				{PhoneID: slow.ID, ExpiresAt: time.Now().Add(time.Minute)},
+			},
+			expected: fast,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Setup
+			t.Parallel()
+
+			// Act
+			phone := test.reservations.LeastLoadedPhone(test.phones, test.pending)
+
+			// Assert
+			assert.Equal(t, test.expected, phone)
+		})
+	}
+
+	t.Run("it spreads consecutive routes over the phones of the pool", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		first := &Phone{ID: uuid.New(), MessagesPerMinute: 10}
+		second := &Phone{ID: uuid.New(), MessagesPerMinute: 10}
+		reservations := SenderPoolReservations{}
+		counts := map[uuid.UUID]int{}
+
+		// Act
+		for i := 0; i < 10; i++ {
+			phone := reservations.LeastLoadedPhone([]*Phone{first, second}, map[uuid.UUID]int{})
+			reservations = append(reservations, SenderPoolReservation{PhoneID: phone.ID, ExpiresAt: time.Now().Add(time.Minute)})
+			counts[phone.ID]++
+		}
+
+		// Assert
+		assert.Equal(t, 5, counts[first.ID])
+		assert.Equal(t, 5, counts[second.ID])
+	})
+}
+
+func TestSenderPoolReservations_Active(t *testing.T) {
+	t.Run("it removes the expired reservations", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		now := time.Date(2024, 3, 7, 12, 0, 0, 0, time.UTC)
+		active := SenderPoolReservation{PhoneID: uuid.New(), ExpiresAt: now.Add(time.Second)}
+		reservations := SenderPoolReservations{
+			{PhoneID: uuid.New(), ExpiresAt: now.Add(-time.Second)},
+			{PhoneID: uuid.New(), ExpiresAt: now},
+			active,
+		}
+
+		// Act
+		result := reservations.Active(now)
+
+		// Assert
+		assert.Equal(t, SenderPoolReservations{active}, result)
+	})
+}
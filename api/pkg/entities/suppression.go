@@ -0,0 +1,31 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SuppressionSource is the reason why a phone number was added to the suppression list
+type SuppressionSource string
+
+const (
+	// SuppressionSourceKeyword is used when the contact replied with an opt-out keyword e.g. STOP
+	SuppressionSourceKeyword = SuppressionSource("keyword")
+
+	// SuppressionSourceAPI is used when the phone number was added through the API
+	SuppressionSourceAPI = SuppressionSource("api")
+)
+
+// Suppression is a phone number which opted out of receiving messages from a user
+type Suppression struct {
+	ID          uuid.UUID         `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID      UserID            `json:"user_id" gorm:"uniqueIndex:idx_suppressions_user_id_phone_number" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	PhoneNumber string            `json:"phone_number" gorm:"uniqueIndex:idx_suppressions_user_id_phone_number" example:"+18005550100"`
+	Owner       *string           `json:"owner" example:"+18005550199"`
+	Source      SuppressionSource `json:"source" example:"keyword"`
+	Keyword     *string           `json:"keyword" example:"STOP"`
+	Reason      *string           `json:"reason" example:"The customer asked not to be contacted"`
+	CreatedAt   time.Time         `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt   time.Time         `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
@@ -7,14 +7,44 @@ import (
 	"github.com/lib/pq"
 )
 
+// WebhookSigningScheme is the method used to sign requests sent to a webhook
+type WebhookSigningScheme string
+
+const (
+	// WebhookSigningSchemeJWT sends an HS256 JWT signed with the signing key in the Authorization header
+	WebhookSigningSchemeJWT = WebhookSigningScheme("jwt")
+	// WebhookSigningSchemeHMAC sends a timestamped HMAC-SHA256 of the request body in the X-Httpsms-Signature header
+	WebhookSigningSchemeHMAC = WebhookSigningScheme("hmac-sha256")
+)
+
+// WebhookFormat is the shape of the request body sent to a webhook
+type WebhookFormat string
+
+const (
+	// WebhookFormatCloudEventsStructured sends the whole cloudevent as the JSON body
+	WebhookFormatCloudEventsStructured = WebhookFormat("cloudevents-structured")
+	// WebhookFormatCloudEventsBinary sends the event data as the body and the event attributes as ce-* headers
+	WebhookFormatCloudEventsBinary = WebhookFormat("cloudevents-binary")
+	// WebhookFormatDiscord sends a Discord webhook message with an embed
+	WebhookFormatDiscord = WebhookFormat("discord")
+	// WebhookFormatSlack sends a Slack incoming webhook message using blocks
+	WebhookFormatSlack = WebhookFormat("slack")
+	// WebhookFormatTeams sends a Microsoft Teams message with an adaptive card
+	WebhookFormatTeams = WebhookFormat("teams")
+	// WebhookFormatGoogleChat sends a Google Chat message with a card
+	WebhookFormatGoogleChat = WebhookFormat("google-chat")
+)
+
 // Webhook stores the webhooks of a user
 type Webhook struct {
-	ID           uuid.UUID      `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
-	UserID       UserID         `json:"user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
-	URL          string         `json:"url" example:"https://example.com"`
-	SigningKey   string         `json:"signing_key" example:"DGW8NwQp7mxKaSZ72Xq9v67SLqSbWQvckzzmK8D6rvd7NywSEkdMJtuxKyEkYnCY"`
-	PhoneNumbers pq.StringArray `json:"phone_numbers" example:"[+18005550199,+18005550100]" gorm:"type:text[]" swaggertype:"array,string"`
-	Events       pq.StringArray `json:"events" example:"[message.phone.received]" gorm:"type:text[]" swaggertype:"array,string"`
-	CreatedAt    time.Time      `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
-	UpdatedAt    time.Time      `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+	ID            uuid.UUID            `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID        UserID               `json:"user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	URL           string               `json:"url" example:"https://example.com"`
+	SigningKey    string               `json:"signing_key" example:"DGW8NwQp7mxKaSZ72Xq9v67SLqSbWQvckzzmK8D6rvd7NywSEkdMJtuxKyEkYnCY"`
+	SigningScheme WebhookSigningScheme `json:"signing_scheme" gorm:"default:jwt" example:"hmac-sha256"`
+	Format        WebhookFormat        `json:"format" example:"cloudevents-structured"`
+	PhoneNumbers  pq.StringArray       `json:"phone_numbers" example:"[+18005550199,+18005550100]" gorm:"type:text[]" swaggertype:"array,string"`
+	Events        pq.StringArray       `json:"events" example:"[message.phone.received]" gorm:"type:text[]" swaggertype:"array,string"`
+	CreatedAt     time.Time            `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt     time.Time            `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
 }
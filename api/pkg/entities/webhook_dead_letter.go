@@ -0,0 +1,23 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookDeadLetter is an event which could not be delivered to an entities.Webhook after all attempts
+type WebhookDeadLetter struct {
+	ID                     uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	WebhookID              uuid.UUID `json:"webhook_id" gorm:"index" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	DeliveryID             uuid.UUID `json:"delivery_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID                 UserID    `json:"user_id" gorm:"index" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Owner                  string    `json:"owner" example:"+18005550199"`
+	EventID                string    `json:"event_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	EventType              string    `json:"event_type" example:"message.phone.received"`
+	Event                  string    `json:"event" gorm:"type:text" example:"{\"specversion\":\"1.0\",\"type\":\"message.phone.received\"}"`
+	Attempts               uint      `json:"attempts" example:"5"`
+	HTTPResponseStatusCode *int      `json:"http_response_status_code" example:"500"`
+	ErrorMessage           string    `json:"error_message" example:"Internal Server Error"`
+	CreatedAt              time.Time `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+}
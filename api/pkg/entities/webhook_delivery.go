@@ -0,0 +1,50 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookDeliveryStatus is the status of a webhook delivery
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryStatusPending means the event has not been sent yet
+	WebhookDeliveryStatusPending = WebhookDeliveryStatus("pending")
+	// WebhookDeliveryStatusRetrying means the last attempt failed and another attempt is scheduled
+	WebhookDeliveryStatusRetrying = WebhookDeliveryStatus("retrying")
+	// WebhookDeliveryStatusSucceeded means the event was accepted by the webhook
+	WebhookDeliveryStatusSucceeded = WebhookDeliveryStatus("succeeded")
+	// WebhookDeliveryStatusFailed means all attempts failed and the event was moved to the dead letters
+	WebhookDeliveryStatusFailed = WebhookDeliveryStatus("failed")
+)
+
+// WebhookDelivery tracks the delivery of a single event to an entities.Webhook
+type WebhookDelivery struct {
+	ID                     uuid.UUID             `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	WebhookID              uuid.UUID             `json:"webhook_id" gorm:"index" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID                 UserID                `json:"user_id" gorm:"index" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Owner                  string                `json:"owner" example:"+18005550199"`
+	EventID                string                `json:"event_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	EventType              string                `json:"event_type" example:"message.phone.received"`
+	Event                  string                `json:"-" gorm:"type:text"`
+	Status                 WebhookDeliveryStatus `json:"status" example:"retrying"`
+	Attempts               uint                  `json:"attempts" example:"1"`
+	MaxAttempts            uint                  `json:"max_attempts" example:"5"`
+	NextAttemptAt          *time.Time            `json:"next_attempt_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	HTTPResponseStatusCode *int                  `json:"http_response_status_code" example:"500"`
+	ErrorMessage           *string               `json:"error_message" example:"Internal Server Error"`
+	CreatedAt              time.Time             `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt              time.Time             `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
+
+// IsFinal returns true when no further attempts will be made for the delivery
+func (delivery *WebhookDelivery) IsFinal() bool {
+	return delivery.Status == WebhookDeliveryStatusSucceeded || delivery.Status == WebhookDeliveryStatusFailed
+}
+
+// CanRetry returns true if the delivery has attempts left
+func (delivery *WebhookDelivery) CanRetry() bool {
+	return delivery.Attempts < delivery.MaxAttempts
+}
@@ -0,0 +1,25 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookDeliveryAttempt is a single HTTP request made while delivering an entities.WebhookDelivery
+type WebhookDeliveryAttempt struct {
+	ID                     uuid.UUID             `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	DeliveryID             uuid.UUID             `json:"delivery_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	WebhookID              uuid.UUID             `json:"webhook_id" gorm:"index:idx_webhook_delivery_attempts_webhook_id_created_at" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID                 UserID                `json:"user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	EventID                string                `json:"event_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	EventType              string                `json:"event_type" example:"message.phone.received"`
+	URL                    string                `json:"url" example:"https://example.com/webhook"`
+	Attempt                uint                  `json:"attempt" example:"1"`
+	Status                 WebhookDeliveryStatus `json:"status" example:"failed"`
+	HTTPResponseStatusCode *int                  `json:"http_response_status_code" example:"500"`
+	LatencyMilliseconds    int64                 `json:"latency_ms" example:"230"`
+	ResponseBody           *string               `json:"response_body" gorm:"type:text" example:"Internal Server Error"`
+	ErrorMessage           *string               `json:"error_message" example:"Internal Server Error"`
+	CreatedAt              time.Time             `json:"created_at" gorm:"index:idx_webhook_delivery_attempts_webhook_id_created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+}
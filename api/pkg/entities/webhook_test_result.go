@@ -0,0 +1,13 @@
+package entities
+
+// WebhookTestResult is the response of a webhook to a sample event
+type WebhookTestResult struct {
+	EventID                string              `json:"event_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	EventType              string              `json:"event_type" example:"message.phone.received"`
+	URL                    string              `json:"url" example:"https://example.com/webhook"`
+	HTTPResponseStatusCode *int                `json:"http_response_status_code" example:"200"`
+	HTTPResponseHeaders    map[string][]string `json:"http_response_headers" swaggertype:"object"`
+	HTTPResponseBody       *string             `json:"http_response_body" example:"OK"`
+	LatencyMilliseconds    int64               `json:"latency_ms" example:"230"`
+	ErrorMessage           *string             `json:"error_message" example:"TIMOUT after 10 seconds"`
+}
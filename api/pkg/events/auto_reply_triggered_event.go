@@ -0,0 +1,25 @@
+package events
+
+import (
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// EventTypeAutoReplyTriggered is emitted when an entities.AutoReply matches a received message
+const EventTypeAutoReplyTriggered = "auto-reply.triggered"
+
+// AutoReplyTriggeredPayload is the payload of the EventTypeAutoReplyTriggered event
+type AutoReplyTriggeredPayload struct {
+	AutoReplyID    uuid.UUID                `json:"auto_reply_id"`
+	UserID         entities.UserID          `json:"user_id"`
+	Owner          string                   `json:"owner"`
+	Contact        string                   `json:"contact"`
+	MessageID      uuid.UUID                `json:"message_id"`
+	Content        string                   `json:"content"`
+	Action         entities.AutoReplyAction `json:"action"`
+	Tag            *string                  `json:"tag"`
+	ReplyMessageID *uuid.UUID               `json:"reply_message_id"`
+	Timestamp      time.Time                `json:"timestamp"`
+}
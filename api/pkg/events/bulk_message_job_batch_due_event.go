@@ -0,0 +1,17 @@
+package events
+
+import (
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// EventTypeBulkMessageJobBatchDue is emitted when the next batch of rows of an entities.BulkMessageJob should be queued
+const EventTypeBulkMessageJobBatchDue = "bulk-message-job.batch-due"
+
+// BulkMessageJobBatchDuePayload is the payload of the EventTypeBulkMessageJobBatchDue event
+type BulkMessageJobBatchDuePayload struct {
+	BulkMessageJobID uuid.UUID       `json:"bulk_message_job_id"`
+	UserID           entities.UserID `json:"user_id"`
+	// Attempt is the number of times the batch has been retried after an error
+	Attempt uint `json:"attempt"`
+}
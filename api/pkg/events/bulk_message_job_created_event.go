@@ -0,0 +1,15 @@
+package events
+
+import (
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// EventTypeBulkMessageJobCreated is emitted when the uploaded file of an entities.BulkMessageJob should be validated
+const EventTypeBulkMessageJobCreated = "bulk-message-job.created"
+
+// BulkMessageJobCreatedPayload is the payload of the EventTypeBulkMessageJobCreated event
+type BulkMessageJobCreatedPayload struct {
+	BulkMessageJobID uuid.UUID       `json:"bulk_message_job_id"`
+	UserID           entities.UserID `json:"user_id"`
+}
@@ -0,0 +1,20 @@
+package events
+
+import (
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+)
+
+// EventTypeContactOptIn is emitted when a phone number is removed from the suppression list of a user
+const EventTypeContactOptIn = "contact.opt_in"
+
+// ContactOptInPayload is the payload of the EventTypeContactOptIn event
+type ContactOptInPayload struct {
+	UserID    entities.UserID            `json:"user_id"`
+	Owner     string                     `json:"owner"`
+	Contact   string                     `json:"contact"`
+	Source    entities.SuppressionSource `json:"source"`
+	Keyword   *string                    `json:"keyword"`
+	Timestamp time.Time                  `json:"timestamp"`
+}
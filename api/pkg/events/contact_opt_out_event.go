@@ -0,0 +1,22 @@
+package events
+
+import (
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// EventTypeContactOptOut is emitted when a phone number is added to the suppression list of a user
+const EventTypeContactOptOut = "contact.opt_out"
+
+// ContactOptOutPayload is the payload of the EventTypeContactOptOut event
+type ContactOptOutPayload struct {
+	SuppressionID uuid.UUID                  `json:"suppression_id"`
+	UserID        entities.UserID            `json:"user_id"`
+	Owner         string                     `json:"owner"`
+	Contact       string                     `json:"contact"`
+	Source        entities.SuppressionSource `json:"source"`
+	Keyword       *string                    `json:"keyword"`
+	Timestamp     time.Time                  `json:"timestamp"`
+}
@@ -0,0 +1,25 @@
+package events
+
+import (
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+
+	"github.com/google/uuid"
+)
+
+// EventTypeMessageAPICancelled is emitted when a message is cancelled before a phone picks it up
+const EventTypeMessageAPICancelled = "message.api.cancelled"
+
+// MessageAPICancelledPayload is the payload of the EventTypeMessageAPICancelled event
+type MessageAPICancelledPayload struct {
+	MessageID uuid.UUID       `json:"message_id"`
+	UserID    entities.UserID `json:"user_id"`
+	Owner     string          `json:"owner"`
+	RequestID *string         `json:"request_id"`
+	Contact   string          `json:"contact"`
+	Timestamp time.Time       `json:"timestamp"`
+	Content   string          `json:"content"`
+	Encrypted bool            `json:"encrypted"`
+	SIM       entities.SIM    `json:"sim"`
+}
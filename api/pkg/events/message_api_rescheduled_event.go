@@ -0,0 +1,26 @@
+package events
+
+import (
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+
+	"github.com/google/uuid"
+)
+
+// EventTypeMessageAPIRescheduled is emitted when the send time of a message is changed before a phone picks it up
+const EventTypeMessageAPIRescheduled = "message.api.rescheduled"
+
+// MessageAPIRescheduledPayload is the payload of the EventTypeMessageAPIRescheduled event
+type MessageAPIRescheduledPayload struct {
+	MessageID         uuid.UUID       `json:"message_id"`
+	UserID            entities.UserID `json:"user_id"`
+	Owner             string          `json:"owner"`
+	RequestID         *string         `json:"request_id"`
+	Contact           string          `json:"contact"`
+	Content           string          `json:"content"`
+	Encrypted         bool            `json:"encrypted"`
+	SIM               entities.SIM    `json:"sim"`
+	ScheduledSendTime time.Time       `json:"scheduled_send_time"`
+	Timestamp         time.Time       `json:"timestamp"`
+}
@@ -23,5 +23,8 @@ type MessageAPISentPayload struct {
 	RequestReceivedAt time.Time       `json:"request_received_at"`
 	Content           string          `json:"content"`
 	Encrypted         bool            `json:"encrypted"`
+	Encoding          string          `json:"encoding"`
+	Segments          uint            `json:"segments"`
+	Attachments       []string        `json:"attachments"`
 	SIM               entities.SIM    `json:"sim"`
 }
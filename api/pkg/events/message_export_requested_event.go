@@ -0,0 +1,15 @@
+package events
+
+import (
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// EventTypeMessageExportRequested is emitted when a large entities.MessageExport should be created in the background
+const EventTypeMessageExportRequested = "message-export.requested"
+
+// MessageExportRequestedPayload is the payload of the EventTypeMessageExportRequested event
+type MessageExportRequestedPayload struct {
+	MessageExportID uuid.UUID       `json:"message_export_id"`
+	UserID          entities.UserID `json:"user_id"`
+}
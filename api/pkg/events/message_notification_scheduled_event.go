@@ -22,5 +22,6 @@ type MessageNotificationScheduledPayload struct {
 	UserID         entities.UserID `json:"user_id"`
 	PhoneID        uuid.UUID       `json:"phone_id"`
 	ScheduledAt    time.Time       `json:"scheduled_at"`
+	Deferred       bool            `json:"deferred"`
 	NotificationID uuid.UUID       `json:"notification_id"`
 }
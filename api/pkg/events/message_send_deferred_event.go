@@ -0,0 +1,26 @@
+package events
+
+import (
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// EventTypeMessageSendDeferred is emitted when an outgoing message is deferred until the end of the quiet hours of the phone
+const EventTypeMessageSendDeferred = "message.send.deferred"
+
+// MessageSendDeferredPayload is the payload of the EventTypeMessageSendDeferred event
+type MessageSendDeferredPayload struct {
+	MessageID           uuid.UUID       `json:"message_id"`
+	UserID              entities.UserID `json:"user_id"`
+	PhoneID             uuid.UUID       `json:"phone_id"`
+	Owner               string          `json:"owner"`
+	Contact             string          `json:"contact"`
+	NotificationID      uuid.UUID       `json:"notification_id"`
+	QuietHoursStart     string          `json:"quiet_hours_start"`
+	QuietHoursEnd       string          `json:"quiet_hours_end"`
+	OriginalScheduledAt time.Time       `json:"original_scheduled_at"`
+	ScheduledAt         time.Time       `json:"scheduled_at"`
+	Timestamp           time.Time       `json:"timestamp"`
+}
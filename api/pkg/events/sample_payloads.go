@@ -0,0 +1,145 @@
+package events
+
+import (
+	"sort"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+const (
+	sampleContact = "+18005550100"
+	sampleContent = "This is a sample text message"
+)
+
+// samplePayloadFactories is the registry of events which users can subscribe to with an entities.Webhook.
+// The timer events used internally for scheduling and the webhook.* events are not in the registry so a failing webhook cannot trigger itself.
+var samplePayloadFactories = map[string]func(userID entities.UserID, owner string, timestamp time.Time) any{
+	EventTypeMessagePhoneReceived: func(userID entities.UserID, owner string, timestamp time.Time) any {
+		return &MessagePhoneReceivedPayload{MessageID: uuid.New(), UserID: userID, Owner: owner, Contact: sampleContact, Timestamp: timestamp, Content: sampleContent, SIM: entities.SIM1}
+	},
+	EventTypeMessagePhoneSending: func(userID entities.UserID, owner string, timestamp time.Time) any {
+		return &MessagePhoneSendingPayload{ID: uuid.New(), UserID: userID, RequestID: sampleRequestID(), Timestamp: timestamp, Owner: owner, Contact: sampleContact, Content: sampleContent, SIM: entities.SIM1}
+	},
+	EventTypeMessagePhoneSent: func(userID entities.UserID, owner string, timestamp time.Time) any {
+		return &MessagePhoneSentPayload{ID: uuid.New(), UserID: userID, RequestID: sampleRequestID(), Owner: owner, Contact: sampleContact, Timestamp: timestamp, Content: sampleContent, SIM: entities.SIM1}
+	},
+	EventTypeMessagePhoneDelivered: func(userID entities.UserID, owner string, timestamp time.Time) any {
+		return &MessagePhoneDeliveredPayload{ID: uuid.New(), Owner: owner, Contact: sampleContact, RequestID: sampleRequestID(), UserID: userID, Timestamp: timestamp, Content: sampleContent, SIM: entities.SIM1}
+	},
+	EventTypeMessageSendFailed: func(userID entities.UserID, owner string, timestamp time.Time) any {
+		return &MessageSendFailedPayload{ID: uuid.New(), ErrorMessage: "RESULT_ERROR_GENERIC_FAILURE", UserID: userID, Owner: owner, RequestID: sampleRequestID(), Contact: sampleContact, Timestamp: timestamp, Content: sampleContent, SIM: entities.SIM1}
+	},
+	EventTypeMessageSendExpired: func(userID entities.UserID, owner string, timestamp time.Time) any {
+		return &MessageSendExpiredPayload{MessageID: uuid.New(), Owner: owner, SendAttemptCount: 2, IsFinal: true, RequestID: sampleRequestID(), Contact: sampleContact, UserID: userID, Timestamp: timestamp, Content: sampleContent, SIM: entities.SIM1}
+	},
+	EventTypeMessageSendRetry: func(userID entities.UserID, owner string, timestamp time.Time) any {
+		return &MessageSendRetryPayload{MessageID: uuid.New(), Owner: owner, Contact: sampleContact, UserID: userID, Timestamp: timestamp, Content: sampleContent, SIM: entities.SIM1}
+	},
+	EventTypeMessageAPISent: func(userID entities.UserID, owner string, timestamp time.Time) any {
+		return &MessageAPISentPayload{MessageID: uuid.New(), UserID: userID, Owner: owner, RequestID: sampleRequestID(), MaxSendAttempts: 2, Contact: sampleContact, RequestReceivedAt: timestamp, Content: sampleContent, Encoding: "GSM-7", Segments: 1, SIM: entities.SIM1}
+	},
+	EventTypeMessageAPICancelled: func(userID entities.UserID, owner string, timestamp time.Time) any {
+		return &MessageAPICancelledPayload{MessageID: uuid.New(), UserID: userID, Owner: owner, RequestID: sampleRequestID(), Contact: sampleContact, Timestamp: timestamp, Content: sampleContent, SIM: entities.SIM1}
+	},
+	EventTypeMessageAPIRescheduled: func(userID entities.UserID, owner string, timestamp time.Time) any {
+		return &MessageAPIRescheduledPayload{MessageID: uuid.New(), UserID: userID, Owner: owner, RequestID: sampleRequestID(), Contact: sampleContact, Content: sampleContent, SIM: entities.SIM1, ScheduledSendTime: timestamp.Add(time.Hour), Timestamp: timestamp}
+	},
+	MessageAPIDeleted: func(userID entities.UserID, owner string, timestamp time.Time) any {
+		return &MessageAPIDeletedPayload{MessageID: uuid.New(), UserID: userID, Owner: owner, RequestID: sampleRequestID(), Contact: sampleContact, Timestamp: timestamp, Content: sampleContent, SIM: entities.SIM1}
+	},
+	EventTypeMessageNotificationScheduled: func(userID entities.UserID, owner string, timestamp time.Time) any {
+		return &MessageNotificationScheduledPayload{MessageID: uuid.New(), Owner: owner, Contact: sampleContact, Content: sampleContent, SIM: entities.SIM1, UserID: userID, PhoneID: uuid.New(), ScheduledAt: timestamp, NotificationID: uuid.New()}
+	},
+	EventTypeMessageNotificationSent: func(userID entities.UserID, _ string, timestamp time.Time) any {
+		return &MessageNotificationSentPayload{MessageID: uuid.New(), UserID: userID, PhoneID: uuid.New(), ScheduledAt: timestamp, FcmMessageID: "projects/httpsms/messages/0:1667153566424578%e9b8d3d2f9fd7ecd", MessageExpirationDuration: 10 * time.Minute, NotificationSentAt: timestamp, NotificationID: uuid.New()}
+	},
+	EventTypeMessageNotificationFailed: func(userID entities.UserID, _ string, timestamp time.Time) any {
+		return &MessageNotificationFailedPayload{MessageID: uuid.New(), UserID: userID, NotificationID: uuid.New(), PhoneID: uuid.New(), ErrorMessage: "Requested entity was not found.", NotificationFailedAt: timestamp}
+	},
+	MessageCallMissed: func(userID entities.UserID, owner string, timestamp time.Time) any {
+		return &MessageCallMissedPayload{MessageID: uuid.New(), UserID: userID, Owner: owner, Contact: sampleContact, Timestamp: timestamp, SIM: entities.SIM1}
+	},
+	MessageThreadAPIDeleted: func(userID entities.UserID, owner string, timestamp time.Time) any {
+		return &MessageThreadAPIDeletedPayload{MessageThreadID: uuid.New(), UserID: userID, Owner: owner, Contact: sampleContact, Color: "indigo", Status: entities.MessageStatusDelivered, Timestamp: timestamp}
+	},
+	EventTypePhoneHeartbeatOnline: func(userID entities.UserID, owner string, timestamp time.Time) any {
+		return &PhoneHeartbeatOnlinePayload{PhoneID: uuid.New(), UserID: userID, LastHeartbeatTimestamp: timestamp.Add(-15 * time.Minute), Timestamp: timestamp, MonitorID: uuid.New(), Owner: owner}
+	},
+	EventTypePhoneHeartbeatOffline: func(userID entities.UserID, owner string, timestamp time.Time) any {
+		return &PhoneHeartbeatOfflinePayload{PhoneID: uuid.New(), UserID: userID, LastHeartbeatTimestamp: timestamp.Add(-1 * time.Hour), Timestamp: timestamp, MonitorID: uuid.New(), Owner: owner}
+	},
+	PhoneHeartbeatMissed: func(userID entities.UserID, owner string, timestamp time.Time) any {
+		return &PhoneHeartbeatMissedPayload{PhoneID: uuid.New(), UserID: userID, LastHeartbeatTimestamp: timestamp.Add(-1 * time.Hour), Timestamp: timestamp, MonitorID: uuid.New(), Owner: owner}
+	},
+	EventTypeDiscordSendFailed: func(userID entities.UserID, owner string, _ time.Time) any {
+		statusCode := 403
+		return &DiscordSendFailedPayload{DiscordID: uuid.New(), UserID: userID, MessageID: uuid.New(), EventType: EventTypeMessagePhoneReceived, Owner: owner, HTTPResponseStatusCode: &statusCode, ErrorMessage: "Missing Access", DiscordChannelID: "1095780203256627291"}
+	},
+	EventTypeContactOptOut: func(userID entities.UserID, owner string, timestamp time.Time) any {
+		keyword := "STOP"
+		return &ContactOptOutPayload{SuppressionID: uuid.New(), UserID: userID, Owner: owner, Contact: sampleContact, Source: entities.SuppressionSourceKeyword, Keyword: &keyword, Timestamp: timestamp}
+	},
+	EventTypeContactOptIn: func(userID entities.UserID, owner string, timestamp time.Time) any {
+		keyword := "START"
+		return &ContactOptInPayload{UserID: userID, Owner: owner, Contact: sampleContact, Source: entities.SuppressionSourceKeyword, Keyword: &keyword, Timestamp: timestamp}
+	},
+	EventTypeAutoReplyTriggered: func(userID entities.UserID, owner string, timestamp time.Time) any {
+		return &AutoReplyTriggeredPayload{AutoReplyID: uuid.New(), UserID: userID, Owner: owner, Contact: sampleContact, MessageID: uuid.New(), Content: "HOURS", Action: entities.AutoReplyActionWebhook, Timestamp: timestamp}
+	},
+	EventTypeMessageSendDeferred: func(userID entities.UserID, owner string, timestamp time.Time) any {
+		return &MessageSendDeferredPayload{MessageID: uuid.New(), UserID: userID, PhoneID: uuid.New(), Owner: owner, Contact: sampleContact, NotificationID: uuid.New(), QuietHoursStart: "21:00", QuietHoursEnd: "08:00", OriginalScheduledAt: timestamp, ScheduledAt: timestamp.Add(8 * time.Hour), Timestamp: timestamp}
+	},
+	EventTypePhoneUpdated: func(userID entities.UserID, owner string, timestamp time.Time) any {
+		return &PhoneUpdatedPayload{PhoneID: uuid.New(), UserID: userID, Timestamp: timestamp, Owner: owner, SIM: entities.SIM1}
+	},
+	EventTypePhoneDeleted: func(userID entities.UserID, owner string, timestamp time.Time) any {
+		return &PhoneDeletedPayload{PhoneID: uuid.New(), UserID: userID, Timestamp: timestamp, Owner: owner, SIM: entities.SIM1}
+	},
+	UserAPIKeyRotated: func(userID entities.UserID, _ string, timestamp time.Time) any {
+		return &UserAPIKeyRotatedPayload{UserID: userID, Email: "name@email.com", Timestamp: timestamp, Timezone: "Europe/Tallinn"}
+	},
+	UserSubscriptionCreated: func(userID entities.UserID, _ string, timestamp time.Time) any {
+		return &UserSubscriptionCreatedPayload{UserID: userID, SubscriptionCreatedAt: timestamp, SubscriptionID: "8f51121", SubscriptionName: entities.SubscriptionNameProMonthly, SubscriptionRenewsAt: timestamp.AddDate(0, 1, 0), SubscriptionStatus: "active"}
+	},
+	UserSubscriptionUpdated: func(userID entities.UserID, _ string, timestamp time.Time) any {
+		return &UserSubscriptionUpdatedPayload{UserID: userID, SubscriptionUpdatedAt: timestamp, SubscriptionRenewsAt: timestamp.AddDate(0, 1, 0), SubscriptionID: "8f51121", SubscriptionName: entities.SubscriptionNameProMonthly, SubscriptionStatus: "active"}
+	},
+	UserSubscriptionCancelled: func(userID entities.UserID, _ string, timestamp time.Time) any {
+		return &UserSubscriptionCancelledPayload{UserID: userID, SubscriptionCancelledAt: timestamp, SubscriptionEndsAt: timestamp.AddDate(0, 1, 0), SubscriptionID: "8f51121", SubscriptionName: entities.SubscriptionNameProMonthly, SubscriptionStatus: "cancelled"}
+	},
+	UserSubscriptionExpired: func(userID entities.UserID, _ string, timestamp time.Time) any {
+		return &UserSubscriptionExpiredPayload{UserID: userID, SubscriptionExpiredAt: timestamp, SubscriptionEndsAt: timestamp, IsCancelled: true, SubscriptionID: "8f51121", SubscriptionName: entities.SubscriptionNameProMonthly, SubscriptionStatus: "expired"}
+	},
+}
+
+// SamplePayload returns a realistic payload for an event type which is used to test webhooks
+func SamplePayload(eventType string, userID entities.UserID, owner string) (any, bool) {
+	factory, ok := samplePayloadFactories[eventType]
+	if !ok {
+		return nil, false
+	}
+	return factory(userID, owner, time.Now().UTC()), true
+}
+
+// WebhookEventTypes returns the sorted list of event types which users can subscribe to with an entities.Webhook
+func WebhookEventTypes() []string {
+	eventTypes := make([]string, 0, len(samplePayloadFactories))
+	for eventType := range samplePayloadFactories {
+		eventTypes = append(eventTypes, eventType)
+	}
+	sort.Strings(eventTypes)
+	return eventTypes
+}
+
+// IsWebhookEventType checks if users can subscribe to an event type with an entities.Webhook
+func IsWebhookEventType(eventType string) bool {
+	_, ok := samplePayloadFactories[eventType]
+	return ok
+}
+
+func sampleRequestID() *string {
+	requestID := "153554b5-ae44-44a0-8f4f-7bbac5657ad4"
+	return &requestID
+}
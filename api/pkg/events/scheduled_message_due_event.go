@@ -0,0 +1,18 @@
+package events
+
+import (
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// EventTypeScheduledMessageDue is emitted when the next occurrence of an entities.ScheduledMessage should be sent
+const EventTypeScheduledMessageDue = "scheduled-message.due"
+
+// ScheduledMessageDuePayload is the payload of the EventTypeScheduledMessageDue event
+type ScheduledMessageDuePayload struct {
+	ScheduledMessageID uuid.UUID       `json:"scheduled_message_id"`
+	UserID             entities.UserID `json:"user_id"`
+	OccurrenceAt       time.Time       `json:"occurrence_at"`
+}
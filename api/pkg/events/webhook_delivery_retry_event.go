@@ -0,0 +1,20 @@
+package events
+
+import (
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// EventTypeWebhookDeliveryRetry is emitted when a webhook event should be sent again
+const EventTypeWebhookDeliveryRetry = "webhook.delivery.retry"
+
+// WebhookDeliveryRetryPayload is the payload of the EventTypeWebhookDeliveryRetry event
+type WebhookDeliveryRetryPayload struct {
+	DeliveryID  uuid.UUID       `json:"delivery_id"`
+	WebhookID   uuid.UUID       `json:"webhook_id"`
+	UserID      entities.UserID `json:"user_id"`
+	Attempt     uint            `json:"attempt"`
+	ScheduledAt time.Time       `json:"scheduled_at"`
+}
@@ -0,0 +1,242 @@
+// Package exports writes entities.Message to CSV, XLSX and JSON Lines files one message at a time so that large
+// exports don't need to be loaded into memory.
+package exports
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/palantir/stacktrace"
+	"github.com/xuri/excelize/v2"
+)
+
+// Format is the file format of an export
+type Format string
+
+const (
+	// FormatCSV is a comma separated values file with a header row
+	FormatCSV = Format("csv")
+
+	// FormatXLSX is an Excel workbook with a single sheet
+	FormatXLSX = Format("xlsx")
+
+	// FormatJSONL is a JSON Lines file with one JSON encoded message per line
+	FormatJSONL = Format("jsonl")
+)
+
+// String converts the Format to a string
+func (format Format) String() string {
+	return string(format)
+}
+
+// ContentType is the MIME type of the Format
+func (format Format) ContentType() string {
+	switch format {
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatJSONL:
+		return "application/jsonl"
+	default:
+		return "text/csv"
+	}
+}
+
+// XLSXMaxMessages is the maximum number of messages in an XLSX export, a sheet has 1,048,576 rows including the header row
+const XLSXMaxMessages = int64(excelize.TotalRows - 1)
+
+// CanExport checks if the number of messages fits in a file of the Format
+func (format Format) CanExport(count int64) bool {
+	return format != FormatXLSX || count <= XLSXMaxMessages
+}
+
+// Formats are all the supported export formats
+func Formats() []string {
+	return []string{FormatCSV.String(), FormatXLSX.String(), FormatJSONL.String()}
+}
+
+// attachmentSeparator separates multiple attachment URLs in a single column
+const attachmentSeparator = ";"
+
+var columns = []string{
+	"ID",
+	"Owner",
+	"Contact",
+	"Type",
+	"Status",
+	"Content",
+	"Encrypted",
+	"SIM",
+	"Encoding",
+	"Segments",
+	"Attachments",
+	"RequestID",
+	"RequestReceivedAt",
+	"OrderTimestamp",
+	"SentAt",
+	"DeliveredAt",
+	"ReceivedAt",
+	"FailedAt",
+	"FailureReason",
+}
+
+// Writer writes entities.Message to an export file
+type Writer interface {
+	// Write adds a message to the export
+	Write(message *entities.Message) error
+
+	// Close flushes the export. It does not close the underlying io.Writer
+	Close() error
+}
+
+// NewWriter creates a Writer for the Format
+func NewWriter(format Format, writer io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(writer)
+	case FormatXLSX:
+		return newXLSXWriter(writer)
+	case FormatJSONL:
+		return &jsonlWriter{encoder: json.NewEncoder(writer)}, nil
+	default:
+		return nil, stacktrace.NewError("unsupported export format [%s]", format)
+	}
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+func newCSVWriter(writer io.Writer) (*csvWriter, error) {
+	result := &csvWriter{writer: csv.NewWriter(writer)}
+	if err := result.writer.Write(columns); err != nil {
+		return nil, stacktrace.Propagate(err, "cannot write CSV header")
+	}
+	return result, nil
+}
+
+func (writer *csvWriter) Write(message *entities.Message) error {
+	if err := writer.writer.Write(row(message)); err != nil {
+		return stacktrace.Propagate(err, "cannot write CSV record for message [%s]", message.ID)
+	}
+	return nil
+}
+
+func (writer *csvWriter) Close() error {
+	if writer.writer.Flush(); writer.writer.Error() != nil {
+		return stacktrace.Propagate(writer.writer.Error(), "cannot flush CSV writer")
+	}
+	return nil
+}
+
+type xlsxWriter struct {
+	writer io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	rows   int
+}
+
+func newXLSXWriter(writer io.Writer) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+	stream, err := file.NewStreamWriter(file.GetSheetName(0))
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "cannot create XLSX stream writer")
+	}
+
+	result := &xlsxWriter{writer: writer, file: file, stream: stream}
+	if err = result.setRow(columns); err != nil {
+		return nil, stacktrace.Propagate(err, "cannot write XLSX header")
+	}
+	return result, nil
+}
+
+func (writer *xlsxWriter) Write(message *entities.Message) error {
+	if err := writer.setRow(row(message)); err != nil {
+		return stacktrace.Propagate(err, "cannot write XLSX row for message [%s]", message.ID)
+	}
+	return nil
+}
+
+func (writer *xlsxWriter) setRow(values []string) error {
+	writer.rows++
+	cell, err := excelize.CoordinatesToCellName(1, writer.rows)
+	if err != nil {
+		return stacktrace.Propagate(err, "cannot get the cell name of row [%d]", writer.rows)
+	}
+
+	cells := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		cells = append(cells, value)
+	}
+	return writer.stream.SetRow(cell, cells)
+}
+
+func (writer *xlsxWriter) Close() error {
+	defer func() { _ = writer.file.Close() }()
+
+	if err := writer.stream.Flush(); err != nil {
+		return stacktrace.Propagate(err, "cannot flush XLSX stream writer")
+	}
+
+	if err := writer.file.Write(writer.writer); err != nil {
+		return stacktrace.Propagate(err, "cannot write XLSX file")
+	}
+	return nil
+}
+
+type jsonlWriter struct {
+	encoder *json.Encoder
+}
+
+func (writer *jsonlWriter) Write(message *entities.Message) error {
+	if err := writer.encoder.Encode(message); err != nil {
+		return stacktrace.Propagate(err, "cannot encode message [%s] as JSON", message.ID)
+	}
+	return nil
+}
+
+func (writer *jsonlWriter) Close() error {
+	return nil
+}
+
+func row(message *entities.Message) []string {
+	return []string{
+		message.ID.String(),
+		message.Owner,
+		message.Contact,
+		string(message.Type),
+		string(message.Status),
+		message.Content,
+		strconv.FormatBool(message.Encrypted),
+		message.SIM.String(),
+		message.Encoding,
+		strconv.FormatUint(uint64(message.Segments), 10),
+		strings.Join(message.Attachments, attachmentSeparator),
+		stringValue(message.RequestID),
+		timeValue(&message.RequestReceivedAt),
+		timeValue(&message.OrderTimestamp),
+		timeValue(message.SentAt),
+		timeValue(message.DeliveredAt),
+		timeValue(message.ReceivedAt),
+		timeValue(message.FailedAt),
+		stringValue(message.FailureReason),
+	}
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func timeValue(value *time.Time) string {
+	if value == nil || value.IsZero() {
+		return ""
+	}
+	return value.UTC().Format(time.RFC3339)
+}
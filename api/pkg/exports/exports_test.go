@@ -0,0 +1,139 @@
+package exports
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func sampleMessages() []*entities.Message {
+	timestamp := time.Date(2022, 6, 5, 14, 26, 9, 0, time.UTC)
+	return []*entities.Message{
+		{
+			ID:                uuid.MustParse("32343a19-da5e-4b1b-a767-3298a73703cb"),
+			Owner:             "+18005550199",
+			Contact:           "+18005550100",
+			Content:           "Hello, world",
+			Type:              entities.MessageTypeMobileTerminated,
+			Status:            entities.MessageStatusDelivered,
+			SIM:               entities.SIM1,
+			Encoding:          "GSM-7",
+			Segments:          1,
+			Attachments:       []string{"https://example.com/a.png", "https://example.com/b.png"},
+			RequestReceivedAt: timestamp,
+			OrderTimestamp:    timestamp,
+			DeliveredAt:       &timestamp,
+		},
+		{
+			ID:                uuid.MustParse("ea8a0b36-ab33-4c5a-bf2c-fa4f5a0ad3c4"),
+			Owner:             "+18005550199",
+			Contact:           "+18005550100",
+			Content:           "Thanks",
+			Type:              entities.MessageTypeMobileOriginated,
+			Status:            entities.MessageStatusReceived,
+			SIM:               entities.SIM2,
+			RequestReceivedAt: timestamp,
+			OrderTimestamp:    timestamp,
+			ReceivedAt:        &timestamp,
+		},
+	}
+}
+
+func write(t *testing.T, format Format) *bytes.Buffer {
+	buffer := new(bytes.Buffer)
+	writer, err := NewWriter(format, buffer)
+	require.NoError(t, err)
+
+	for _, message := range sampleMessages() {
+		require.NoError(t, writer.Write(message))
+	}
+	require.NoError(t, writer.Close())
+
+	return buffer
+}
+
+func TestFormat_CanExport(t *testing.T) {
+	t.Run("it limits the number of messages of an XLSX export to the rows of a sheet", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Assert
+		assert.True(t, FormatXLSX.CanExport(XLSXMaxMessages))
+		assert.False(t, FormatXLSX.CanExport(XLSXMaxMessages+1))
+		assert.True(t, FormatCSV.CanExport(XLSXMaxMessages+1))
+		assert.True(t, FormatJSONL.CanExport(XLSXMaxMessages+1))
+	})
+}
+
+func TestNewWriter(t *testing.T) {
+	t.Run("it writes a CSV file with a header row", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Act
+		buffer := write(t, FormatCSV)
+
+		// Assert
+		records, err := csv.NewReader(buffer).ReadAll()
+		require.NoError(t, err)
+		assert.Equal(t, 3, len(records))
+		assert.Equal(t, columns, records[0])
+		assert.Equal(t, "Hello, world", records[1][5])
+		assert.Equal(t, "https://example.com/a.png;https://example.com/b.png", records[1][10])
+		assert.Equal(t, "2022-06-05T14:26:09Z", records[1][15])
+		assert.Equal(t, "", records[2][15])
+	})
+
+	t.Run("it writes one JSON object per line", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Act
+		buffer := write(t, FormatJSONL)
+
+		// Assert
+		lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+		assert.Equal(t, 2, len(lines))
+
+		message := new(entities.Message)
+		require.NoError(t, json.Unmarshal([]byte(lines[1]), message))
+		assert.Equal(t, "ea8a0b36-ab33-4c5a-bf2c-fa4f5a0ad3c4", message.ID.String())
+	})
+
+	t.Run("it writes an XLSX workbook", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Act
+		buffer := write(t, FormatXLSX)
+
+		// Assert
+		file, err := excelize.OpenReader(buffer)
+		require.NoError(t, err)
+		rows, err := file.GetRows(file.GetSheetName(0))
+		require.NoError(t, err)
+		assert.Equal(t, 3, len(rows))
+		assert.Equal(t, "Contact", rows[0][2])
+		assert.Equal(t, "Thanks", rows[2][5])
+	})
+
+	t.Run("it does not support other formats", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Act
+		_, err := NewWriter(Format("pdf"), new(bytes.Buffer))
+
+		// Assert
+		assert.Error(t, err)
+	})
+}
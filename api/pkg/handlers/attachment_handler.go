@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"fmt"
+	"mime"
+	"path/filepath"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// AttachmentHandler handles attachment http requests
+type AttachmentHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	validator *validators.AttachmentHandlerValidator
+	service   *services.AttachmentService
+}
+
+// NewAttachmentHandler creates a new AttachmentHandler
+func NewAttachmentHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	validator *validators.AttachmentHandlerValidator,
+	service *services.AttachmentService,
+) (h *AttachmentHandler) {
+	return &AttachmentHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		validator: validator,
+		service:   service,
+	}
+}
+
+// RegisterRoutes registers the routes for the AttachmentHandler
+func (h *AttachmentHandler) RegisterRoutes(router fiber.Router) {
+	router.Post("/attachments", h.Store)
+	router.Get("/attachments/:attachmentID", h.Show)
+	router.Get("/attachments/:attachmentID/download", h.Download)
+	router.Delete("/attachments/:attachmentID", h.Delete)
+}
+
+// Store an entities.Attachment
+// @Summary      Upload an attachment
+// @Description  Upload a picture, video, audio file or contact card which can be sent as an MMS message. The file must be less than 3 MB.
+// @Security	 ApiKeyAuth
+// @Tags         Attachments
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file		formData	file	true	"The media file to upload"
+// @Success      200 		{object}	responses.AttachmentResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /attachments [post]
+func (h *AttachmentHandler) Store(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch file with name [%s] from request", "file")
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	contentType, errors := h.validator.ValidateStore(ctx, header)
+	if len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while uploading attachment [%s] for [%s]", spew.Sdump(errors), header.Filename, h.userIDFomContext(c))
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while uploading attachment")
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot open file [%s] for user [%s]", header.Filename, h.userIDFomContext(c))))
+		return h.responseInternalServerError(c)
+	}
+	defer func() {
+		if e := file.Close(); e != nil {
+			ctxLogger.Error(stacktrace.Propagate(e, fmt.Sprintf("cannot close file [%s] for user [%s]", header.Filename, h.userIDFomContext(c))))
+		}
+	}()
+
+	attachment, err := h.service.Store(ctx, &services.AttachmentStoreParams{
+		UserID:      h.userIDFomContext(c),
+		Name:        filepath.Base(header.Filename),
+		ContentType: contentType,
+		Size:        header.Size,
+		Content:     file,
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot store attachment [%s] for user [%s]", header.Filename, h.userIDFomContext(c))
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "attachment uploaded successfully", attachment)
+}
+
+// Show returns an attachment
+// @Summary      Get an attachment
+// @Description  Get the details of an attachment of the authenticated user by ID
+// @Security	 ApiKeyAuth
+// @Tags         Attachments
+// @Accept       json
+// @Produce      json
+// @Param 		 attachmentID 	path		string 				true 	"ID of the attachment"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{object}	responses.AttachmentResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /attachments/{attachmentID} [get]
+func (h *AttachmentHandler) Show(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	attachmentID := c.Params("attachmentID")
+	if errors := h.validator.ValidateUUID(ctx, attachmentID, "attachmentID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching attachment with ID [%s]", spew.Sdump(errors), attachmentID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching attachment")
+	}
+
+	attachment, err := h.service.Load(ctx, h.userIDFomContext(c), uuid.MustParse(attachmentID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find attachment with ID [%s]", attachmentID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load attachment with ID [%s]", attachmentID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "attachment fetched successfully", attachment)
+}
+
+// Download the content of an attachment
+// @Summary      Download an attachment
+// @Description  Download the content of an attachment of the authenticated user. The httpSMS app uses this endpoint to fetch the pictures of an MMS message.
+// @Security	 ApiKeyAuth
+// @Tags         Attachments
+// @Produce      octet-stream
+// @Param 		 attachmentID 	path		string 				true 	"ID of the attachment"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{file}		file
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /attachments/{attachmentID}/download [get]
+func (h *AttachmentHandler) Download(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	attachmentID := c.Params("attachmentID")
+	if errors := h.validator.ValidateUUID(ctx, attachmentID, "attachmentID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while downloading attachment with ID [%s]", spew.Sdump(errors), attachmentID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while downloading attachment")
+	}
+
+	attachment, err := h.service.Load(ctx, h.userIDFomContext(c), uuid.MustParse(attachmentID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find attachment with ID [%s]", attachmentID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load attachment with ID [%s]", attachmentID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	content, err := h.service.Download(ctx, attachment)
+	if err != nil {
+		msg := fmt.Sprintf("cannot download content of attachment with ID [%s]", attachmentID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	// user uploaded content is downloaded instead of being rendered by the browser on the API domain
+	c.Set(fiber.HeaderContentType, attachment.ContentType)
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name}))
+	return c.SendStream(content, int(attachment.Size))
+}
+
+// Delete an attachment
+// @Summary      Delete an attachment
+// @Description  Delete an attachment and its content for the authenticated user
+// @Security	 ApiKeyAuth
+// @Tags         Attachments
+// @Accept       json
+// @Produce      json
+// @Param 		 attachmentID 	path		string 				true 	"ID of the attachment"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      204 		{object}	responses.NoContent
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /attachments/{attachmentID} [delete]
+func (h *AttachmentHandler) Delete(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	attachmentID := c.Params("attachmentID")
+	if errors := h.validator.ValidateUUID(ctx, attachmentID, "attachmentID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while deleting attachment with ID [%s]", spew.Sdump(errors), attachmentID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while deleting attachment")
+	}
+
+	err := h.service.Delete(ctx, h.userIDFomContext(c), uuid.MustParse(attachmentID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find attachment with ID [%s]", attachmentID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete attachment with ID [%s]", attachmentID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseNoContent(c, "attachment deleted successfully")
+}
@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// AutoReplyHandler handles auto reply http requests
+type AutoReplyHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	validator *validators.AutoReplyHandlerValidator
+	service   *services.AutoReplyService
+}
+
+// NewAutoReplyHandler creates a new AutoReplyHandler
+func NewAutoReplyHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	validator *validators.AutoReplyHandlerValidator,
+	service *services.AutoReplyService,
+) (h *AutoReplyHandler) {
+	return &AutoReplyHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		validator: validator,
+		service:   service,
+	}
+}
+
+// RegisterRoutes registers the routes for the AutoReplyHandler
+func (h *AutoReplyHandler) RegisterRoutes(router fiber.Router) {
+	router.Get("/auto-replies", h.Index)
+	router.Post("/auto-replies", h.Store)
+	router.Get("/auto-replies/:autoReplyID", h.Show)
+	router.Put("/auto-replies/:autoReplyID", h.Update)
+	router.Delete("/auto-replies/:autoReplyID", h.Delete)
+}
+
+// Index returns the auto replies of a user
+// @Summary      Get auto replies of a user
+// @Description  Get the auto reply rules which are evaluated when the phones of a user receive a message
+// @Security	 ApiKeyAuth
+// @Tags         AutoReplies
+// @Accept       json
+// @Produce      json
+// @Param        owner		query  string  	false 	"filter auto replies of a phone number"	default(+18005550199)
+// @Param        skip		query  int  	false	"number of auto replies to skip"		minimum(0)
+// @Param        query		query  string  	false 	"filter auto replies containing query"
+// @Param        limit		query  int  	false	"number of auto replies to return"	minimum(1)	maximum(100)
+// @Success      200 		{object}	responses.AutoRepliesResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /auto-replies 	[get]
+func (h *AutoReplyHandler) Index(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.AutoReplyIndex
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall URL [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateIndex(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching auto replies [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching auto replies")
+	}
+
+	autoReplies, err := h.service.Index(ctx, h.userIDFomContext(c), request.Owner, request.ToIndexParams())
+	if err != nil {
+		msg := fmt.Sprintf("cannot get auto replies with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d auto %s", len(autoReplies), h.pluralize("reply", len(autoReplies))), autoReplies)
+}
+
+// Show returns an auto reply
+// @Summary      Get an auto reply
+// @Description  Get an auto reply of the authenticated user by ID
+// @Security	 ApiKeyAuth
+// @Tags         AutoReplies
+// @Accept       json
+// @Produce      json
+// @Param 		 autoReplyID 	path		string 				true 	"ID of the auto reply"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{object}	responses.AutoReplyResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /auto-replies/{autoReplyID} [get]
+func (h *AutoReplyHandler) Show(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	autoReplyID := c.Params("autoReplyID")
+	if errors := h.validator.ValidateUUID(ctx, autoReplyID, "autoReplyID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching auto reply with ID [%s]", spew.Sdump(errors), autoReplyID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching auto reply")
+	}
+
+	autoReply, err := h.service.Load(ctx, h.userIDFomContext(c), uuid.MustParse(autoReplyID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find auto reply with ID [%s]", autoReplyID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load auto reply with ID [%s]", autoReplyID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "auto reply fetched successfully", autoReply)
+}
+
+// Store an entities.AutoReply
+// @Summary      Store an auto reply
+// @Description  Store an auto reply rule which matches received messages by keyword, regex or contact and replies, forwards, tags the thread or triggers a webhook
+// @Security	 ApiKeyAuth
+// @Tags         AutoReplies
+// @Accept       json
+// @Produce      json
+// @Param        payload   	body 		requests.AutoReplyStore  		true "Payload of the auto reply"
+// @Success      201 		{object}	responses.AutoReplyResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /auto-replies [post]
+func (h *AutoReplyHandler) Store(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.AutoReplyStore
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall body [%s] into [%T]", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateStore(ctx, h.userIDFomContext(c), request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while storing auto reply [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while storing auto reply")
+	}
+
+	autoReply, err := h.service.Store(ctx, request.ToStoreParams(h.userFromContext(c)))
+	if err != nil {
+		msg := fmt.Sprintf("cannot store auto reply with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseCreated(c, "auto reply created successfully", autoReply)
+}
+
+// Update an entities.AutoReply
+// @Summary      Update an auto reply
+// @Description  Update an auto reply of the authenticated user
+// @Security	 ApiKeyAuth
+// @Tags         AutoReplies
+// @Accept       json
+// @Produce      json
+// @Param 		 autoReplyID	path		string 							true 	"ID of the auto reply" 	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        payload   	body 		requests.AutoReplyUpdate  	true 	"Payload of auto reply to update"
+// @Success      200 		{object}	responses.AutoReplyResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /auto-replies/{autoReplyID} 	[put]
+func (h *AutoReplyHandler) Update(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.AutoReplyUpdate
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params [%s] into [%T]", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.AutoReplyID = c.Params("autoReplyID")
+	if errors := h.validator.ValidateUpdate(ctx, h.userIDFomContext(c), request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while updating auto reply [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while updating auto reply")
+	}
+
+	autoReply, err := h.service.Update(ctx, request.ToUpdateParams(h.userFromContext(c)))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find auto reply with ID [%s]", request.AutoReplyID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot update auto reply with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "auto reply updated successfully", autoReply)
+}
+
+// Delete an entities.AutoReply
+// @Summary      Delete an auto reply
+// @Description  Delete an auto reply of the authenticated user
+// @Security	 ApiKeyAuth
+// @Tags         AutoReplies
+// @Accept       json
+// @Produce      json
+// @Param 		 autoReplyID 	path		string 				true 	"ID of the auto reply"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      204		{object}    responses.NoContent
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /auto-replies/{autoReplyID} [delete]
+func (h *AutoReplyHandler) Delete(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	autoReplyID := c.Params("autoReplyID")
+	if errors := h.validator.ValidateUUID(ctx, autoReplyID, "autoReplyID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while deleting auto reply with ID [%s]", spew.Sdump(errors), autoReplyID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while deleting auto reply")
+	}
+
+	err := h.service.Delete(ctx, h.userIDFomContext(c), uuid.MustParse(autoReplyID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find auto reply with ID [%s]", autoReplyID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete auto reply with ID [%s]", autoReplyID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseNoContent(c, "auto reply deleted successfully")
+}
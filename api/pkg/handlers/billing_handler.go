@@ -3,6 +3,8 @@ package handlers
 import (
 	"fmt"
 
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
 	"github.com/NdoleStudio/httpsms/pkg/requests"
 	"github.com/NdoleStudio/httpsms/pkg/services"
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
@@ -50,8 +52,9 @@ func (h *BillingHandler) RegisterRoutes(router fiber.Router) {
 // @Accept       json
 // @Produce      json
 // @Param        skip		query  int  	false	"number of heartbeats to skip"		minimum(0)
+// @Param        cursor		query  string  	false	"the next_cursor of the previous page, it is used instead of skip"
 // @Param        limit		query  int  	false	"number of heartbeats to return"	minimum(1)	maximum(100)
-// @Success      200 		{object}	responses.BillingUsagesResponse
+// @Success      200 		{object}	responses.BillingUsagesCursorResponse
 // @Failure      400		{object}	responses.BadRequest
 // @Failure 	 401	    {object}	responses.Unauthorized
 // @Failure      422		{object}	responses.UnprocessableEntity
@@ -76,14 +79,22 @@ func (h *BillingHandler) UsageHistory(c *fiber.Ctx) error {
 		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching usage history")
 	}
 
-	heartbeats, err := h.service.GetUsageHistory(ctx, h.userIDFomContext(c), request.ToIndexParams())
+	params := request.ToIndexParams()
+	limit := params.Limit
+	// fetch one more item than the limit to know if there is a next page
+	params.Limit++
+	heartbeats, err := h.service.GetUsageHistory(ctx, h.userIDFomContext(c), params)
 	if err != nil {
 		msg := fmt.Sprintf("cannot get billing usage history with params [%+#v]", request)
 		ctxLogger.Error(stacktrace.Propagate(err, msg))
 		return h.responseInternalServerError(c)
 	}
 
-	return h.responseOK(c, fmt.Sprintf("fetched %d billing usage %s", len(*heartbeats), h.pluralize("record", len(*heartbeats))), heartbeats)
+	var cursor *repositories.Cursor
+	*heartbeats, cursor = nextCursor(*heartbeats, limit, func(usage entities.BillingUsage) *repositories.Cursor {
+		return repositories.NewCursor(usage.StartTimestamp, usage.ID.String())
+	})
+	return h.responseOKWithCursor(c, fmt.Sprintf("fetched %d billing usage %s", len(*heartbeats), h.pluralize("record", len(*heartbeats))), heartbeats, cursor)
 }
 
 // Usage returns the current usage history of a user
@@ -1,9 +1,13 @@
 package handlers
 
 import (
+	"bufio"
+	"context"
 	"fmt"
-	"sync"
+	"strings"
 
+	"github.com/NdoleStudio/httpsms/pkg/bulk"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
 	"github.com/NdoleStudio/httpsms/pkg/requests"
 	"github.com/google/uuid"
 
@@ -18,11 +22,10 @@ import (
 // BulkMessageHandler handles bulk SMS http requests
 type BulkMessageHandler struct {
 	handler
-	logger         telemetry.Logger
-	tracer         telemetry.Tracer
-	validator      *validators.BulkMessageHandlerValidator
-	messageService *services.MessageService
-	billingService *services.BillingService
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	validator  *validators.BulkMessageHandlerValidator
+	jobService *services.BulkMessageJobService
 }
 
 // NewBulkMessageHandler creates a new BulkMessageHandler
@@ -30,31 +33,33 @@ func NewBulkMessageHandler(
 	logger telemetry.Logger,
 	tracer telemetry.Tracer,
 	validator *validators.BulkMessageHandlerValidator,
-	billingService *services.BillingService,
-	messageService *services.MessageService,
+	jobService *services.BulkMessageJobService,
 ) (h *BulkMessageHandler) {
 	return &BulkMessageHandler{
-		logger:         logger.WithService(fmt.Sprintf("%T", h)),
-		tracer:         tracer,
-		validator:      validator,
-		messageService: messageService,
-		billingService: billingService,
+		logger:     logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:     tracer,
+		validator:  validator,
+		jobService: jobService,
 	}
 }
 
 // RegisterRoutes registers the routes for the MessageHandler
 func (h *BulkMessageHandler) RegisterRoutes(router fiber.Router) {
 	router.Post("/bulk-messages", h.Store)
+	router.Get("/bulk-messages/:jobID", h.Show)
+	router.Get("/bulk-messages/:jobID/results", h.Results)
 }
 
 // Store sends bulk SMS messages from a CSV file.
 // @Summary      Store bulk SMS file
-// @Description  Sends bulk SMS messages to multiple users from a CSV file.
+// @Description  Sends bulk SMS messages to multiple users from a CSV or Excel file of up to 50 MB. The file is validated in the background and the messages are queued in batches, use the ID of the bulk message job to follow its progress. Extra columns can be used as {{column}} placeholders in the Content of each row.
 // @Security	 ApiKeyAuth
 // @Tags         BulkSMS
-// @Accept       json
+// @Accept       multipart/form-data
 // @Produce      json
-// @Success      202 		{object}	responses.NoContent
+// @Param        document		formData	file	true	"CSV or Excel file with the messages"
+// @Param        template_id	formData	string	false	"ID of the message template used as the content of every row. Extra columns are used as the template variables"
+// @Success      202 		{object}	responses.BulkMessageJobResponse
 // @Failure      400		{object}	responses.BadRequest
 // @Failure 	 401	    {object}	responses.Unauthorized
 // @Failure      422		{object}	responses.UnprocessableEntity
@@ -71,36 +76,139 @@ func (h *BulkMessageHandler) Store(c *fiber.Ctx) error {
 		return h.responseBadRequest(c, err)
 	}
 
-	messages, validationErrors := h.validator.ValidateStore(ctx, h.userIDFomContext(c), file)
-	if len(validationErrors) != 0 {
-		msg := fmt.Sprintf("validation errors [%s], while sending bulk sms from CSV file [%s] for [%s]", spew.Sdump(validationErrors), file.Filename, h.userIDFomContext(c))
+	templateID := strings.TrimSpace(c.FormValue("template_id"))
+	if errors := h.validator.ValidateStore(ctx, h.userIDFomContext(c), templateID, file); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while sending bulk sms from file [%s] for [%s]", spew.Sdump(errors), file.Filename, h.userIDFomContext(c))
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while sending bulk SMS")
+	}
+
+	content, err := file.Open()
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot open file [%s] for reading for user [%s]", file.Filename, h.userIDFomContext(c))))
+		return h.responseInternalServerError(c)
+	}
+	defer func() {
+		if err = content.Close(); err != nil {
+			ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot close file [%s] for user [%s]", file.Filename, h.userIDFomContext(c))))
+		}
+	}()
+
+	format, _ := bulk.DetectFormat(file.Filename, file.Header.Get("Content-Type"))
+	params := &services.BulkMessageJobStoreParams{
+		UserID:   h.userIDFomContext(c),
+		Filename: file.Filename,
+		Format:   format,
+		Source:   c.OriginalURL(),
+		Content:  content,
+	}
+	if templateID != "" {
+		id := uuid.MustParse(templateID)
+		params.TemplateID = &id
+	}
+
+	job, err := h.jobService.Store(ctx, params)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot store bulk message file [%s] for [%s]", file.Filename, h.userIDFomContext(c))))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseAcceptedWithData(c, fmt.Sprintf("The file [%s] is being validated, the messages will be queued once every row is valid", file.Filename), job)
+}
+
+// Show returns a bulk message job
+// @Summary      Get a bulk message job
+// @Description  Get the aggregate counts and the outcome of each row of a bulk SMS file. The status of a row is the status of its message once it is queued.
+// @Security	 ApiKeyAuth
+// @Tags         BulkSMS
+// @Accept       json
+// @Produce      json
+// @Param 		 jobID 		path		string 	true 	"ID of the bulk message job"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        skip		query  		int  	false	"number of rows to skip"		minimum(0)
+// @Param        limit		query  		int  	false	"number of rows to return"		minimum(1)	maximum(1000)
+// @Success      200 		{object}	responses.BulkMessageJobResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /bulk-messages/{jobID} [get]
+func (h *BulkMessageHandler) Show(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.BulkMessageJobShow
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall URL [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.JobID = c.Params("jobID")
+	if errors := h.validator.ValidateShow(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching bulk message job [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching bulk message job")
+	}
+
+	job, err := h.jobService.Load(ctx, h.userIDFomContext(c), uuid.MustParse(request.JobID), request.ToIndexParams())
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find bulk message job with ID [%s]", request.JobID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load bulk message job with ID [%s] for user [%s]", request.JobID, h.userIDFomContext(c))
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "bulk message job fetched successfully", job)
+}
+
+// Results downloads the outcome of every row of a bulk message job as a CSV file
+// @Summary      Download the results of a bulk message job
+// @Description  Download a CSV file with the message ID, status and failure reason of every row of a bulk SMS file
+// @Security	 ApiKeyAuth
+// @Tags         BulkSMS
+// @Produce      text/csv
+// @Param 		 jobID 		path		string 	true 	"ID of the bulk message job"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{file}		file
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /bulk-messages/{jobID}/results [get]
+func (h *BulkMessageHandler) Results(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	jobID := c.Params("jobID")
+	if errors := h.validator.ValidateUUID(ctx, jobID, "jobID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while downloading results of bulk message job with ID [%s]", spew.Sdump(errors), jobID)
 		ctxLogger.Warn(stacktrace.NewError(msg))
-		return h.responseUnprocessableEntity(c, validationErrors, "validation errors while sending bulk SMS")
+		return h.responseUnprocessableEntity(c, errors, "validation errors while downloading bulk message job results")
 	}
 
-	if msg := h.billingService.IsEntitledWithCount(ctx, h.userIDFomContext(c), uint(len(messages))); msg != nil {
-		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("user with ID [%s] is not entitled to send [%d] messages", h.userIDFomContext(c), len(messages))))
-		return h.responsePaymentRequired(c, *msg)
+	job, err := h.jobService.Load(ctx, h.userIDFomContext(c), uuid.MustParse(jobID), repositories.IndexParams{})
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find bulk message job with ID [%s]", jobID))
 	}
 
-	requestID := uuid.New()
-	wg := sync.WaitGroup{}
-	for _, message := range messages {
-		wg.Add(1)
-		go func(message *requests.BulkMessage) {
-			_, err = h.messageService.SendMessage(
-				ctx,
-				message.ToMessageSendParams(h.userIDFomContext(c), requestID, c.OriginalURL()),
-			)
-
-			if err != nil {
-				msg := fmt.Sprintf("cannot send message with paylod [%s]", c.Body())
-				ctxLogger.Error(stacktrace.Propagate(err, msg))
-			}
-			wg.Done()
-		}(message)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load bulk message job with ID [%s] for user [%s]", jobID, h.userIDFomContext(c))
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
 	}
 
-	wg.Wait()
-	return h.responseAccepted(c, fmt.Sprintf("Added %d messages to the queue", len(messages)))
+	// the body is written after the handler returns so the results must not be cancelled with the request context
+	streamCtx := context.WithoutCancel(ctx)
+	c.Attachment(fmt.Sprintf("httpsms-bulk-results-%s.csv", job.ID))
+	c.Set(fiber.HeaderContentType, "text/csv")
+	c.Status(fiber.StatusOK).Context().SetBodyStreamWriter(func(writer *bufio.Writer) {
+		if err := h.jobService.Results(streamCtx, job, writer); err != nil {
+			h.logger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot write results of bulk message job [%s]", job.ID)))
+		}
+	})
+
+	return nil
 }
@@ -0,0 +1,341 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// ContactGroupHandler handles contact group http requests
+type ContactGroupHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	validator *validators.ContactGroupHandlerValidator
+	service   *services.ContactGroupService
+}
+
+// NewContactGroupHandler creates a new ContactGroupHandler
+func NewContactGroupHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	validator *validators.ContactGroupHandlerValidator,
+	service *services.ContactGroupService,
+) (h *ContactGroupHandler) {
+	return &ContactGroupHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		validator: validator,
+		service:   service,
+	}
+}
+
+// RegisterRoutes registers the routes for the ContactGroupHandler
+func (h *ContactGroupHandler) RegisterRoutes(router fiber.Router) {
+	router.Get("/contact-groups", h.Index)
+	router.Post("/contact-groups", h.Store)
+	router.Post("/contact-groups/:contactGroupID/members", h.AddMembers)
+	router.Delete("/contact-groups/:contactGroupID/members", h.RemoveMembers)
+	router.Get("/contact-groups/:contactGroupID", h.Show)
+	router.Put("/contact-groups/:contactGroupID", h.Update)
+	router.Delete("/contact-groups/:contactGroupID", h.Delete)
+}
+
+// Index returns the contact groups of a user
+// @Summary      Get contact groups of a user
+// @Description  Get the contact groups of a user
+// @Security	 ApiKeyAuth
+// @Tags         ContactGroups
+// @Accept       json
+// @Produce      json
+// @Param        skip		query  int  	false	"number of contact groups to skip"		minimum(0)
+// @Param        query		query  string  	false 	"filter contact groups with a name or description containing query"
+// @Param        limit		query  int  	false	"number of contact groups to return"	minimum(1)	maximum(100)
+// @Success      200 		{object}	responses.ContactGroupsResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /contact-groups 	[get]
+func (h *ContactGroupHandler) Index(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.ContactGroupIndex
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall URL [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateIndex(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching contact groups [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching contact groups")
+	}
+
+	groups, err := h.service.Index(ctx, h.userIDFomContext(c), request.ToIndexParams())
+	if err != nil {
+		msg := fmt.Sprintf("cannot get contact groups with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d %s", len(groups), h.pluralize("contact group", len(groups))), groups)
+}
+
+// Show returns a contact group
+// @Summary      Get a contact group
+// @Description  Get a contact group of the authenticated user by ID
+// @Security	 ApiKeyAuth
+// @Tags         ContactGroups
+// @Accept       json
+// @Produce      json
+// @Param 		 contactGroupID 	path		string 				true 	"ID of the contact group"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{object}	responses.ContactGroupResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /contact-groups/{contactGroupID} [get]
+func (h *ContactGroupHandler) Show(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	contactGroupID := c.Params("contactGroupID")
+	if errors := h.validator.ValidateUUID(ctx, contactGroupID, "contactGroupID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching contact group with ID [%s]", spew.Sdump(errors), contactGroupID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching contact group")
+	}
+
+	group, err := h.service.Load(ctx, h.userIDFomContext(c), uuid.MustParse(contactGroupID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find contact group with ID [%s]", contactGroupID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load contact group with ID [%s]", contactGroupID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "contact group fetched successfully", group)
+}
+
+// Store an entities.ContactGroup
+// @Summary      Store a contact group
+// @Description  Store a named group of phone numbers which can receive broadcast messages
+// @Security	 ApiKeyAuth
+// @Tags         ContactGroups
+// @Accept       json
+// @Produce      json
+// @Param        payload   	body 		requests.ContactGroupStore  		true "Payload of the contact group"
+// @Success      201 		{object}	responses.ContactGroupResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /contact-groups [post]
+func (h *ContactGroupHandler) Store(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.ContactGroupStore
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall body [%s] into [%T]", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateStore(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while storing contact group [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while storing contact group")
+	}
+
+	group, err := h.service.Store(ctx, request.ToStoreParams(h.userFromContext(c)))
+	if err != nil {
+		msg := fmt.Sprintf("cannot store contact group with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseCreated(c, "contact group created successfully", group)
+}
+
+// Update an entities.ContactGroup
+// @Summary      Update a contact group
+// @Description  Update a contact group of the authenticated user. The phone numbers replace the existing members of the group
+// @Security	 ApiKeyAuth
+// @Tags         ContactGroups
+// @Accept       json
+// @Produce      json
+// @Param 		 contactGroupID	path		string 					true 	"ID of the contact group" 	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        payload   	body 		requests.ContactGroupUpdate  true 	"Payload of contact group to update"
+// @Success      200 		{object}	responses.ContactGroupResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /contact-groups/{contactGroupID} 	[put]
+func (h *ContactGroupHandler) Update(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.ContactGroupUpdate
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params [%s] into [%T]", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.ContactGroupID = c.Params("contactGroupID")
+	if errors := h.validator.ValidateUpdate(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while updating contact group [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while updating contact group")
+	}
+
+	group, err := h.service.Update(ctx, request.ToUpdateParams(h.userFromContext(c)))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find contact group with ID [%s]", request.ContactGroupID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot update contact group with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "contact group updated successfully", group)
+}
+
+// Delete an entities.ContactGroup
+// @Summary      Delete a contact group
+// @Description  Delete a contact group of the authenticated user
+// @Security	 ApiKeyAuth
+// @Tags         ContactGroups
+// @Accept       json
+// @Produce      json
+// @Param 		 contactGroupID 	path		string 				true 	"ID of the contact group"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      204		{object}    responses.NoContent
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /contact-groups/{contactGroupID} [delete]
+func (h *ContactGroupHandler) Delete(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	contactGroupID := c.Params("contactGroupID")
+	if errors := h.validator.ValidateUUID(ctx, contactGroupID, "contactGroupID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while deleting contact group with ID [%s]", spew.Sdump(errors), contactGroupID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while deleting contact group")
+	}
+
+	err := h.service.Delete(ctx, h.userIDFomContext(c), uuid.MustParse(contactGroupID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find contact group with ID [%s]", contactGroupID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete contact group with ID [%s]", contactGroupID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseNoContent(c, "contact group deleted successfully")
+}
+
+// AddMembers adds phone numbers to an entities.ContactGroup
+// @Summary      Add phone numbers to a contact group
+// @Description  Add phone numbers to a contact group of the authenticated user. Phone numbers which are already in the group are ignored.
+// @Security	 ApiKeyAuth
+// @Tags         ContactGroups
+// @Accept       json
+// @Produce      json
+// @Param 		 contactGroupID	path		string 							true 	"ID of the contact group" 	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        payload   		body 		requests.ContactGroupMembers  	true 	"Phone numbers to add to the contact group"
+// @Success      200 		{object}	responses.ContactGroupResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /contact-groups/{contactGroupID}/members 	[post]
+func (h *ContactGroupHandler) AddMembers(c *fiber.Ctx) error {
+	return h.updateMembers(c, "adding", h.service.AddMembers)
+}
+
+// RemoveMembers removes phone numbers from an entities.ContactGroup
+// @Summary      Remove phone numbers from a contact group
+// @Description  Remove phone numbers from a contact group of the authenticated user
+// @Security	 ApiKeyAuth
+// @Tags         ContactGroups
+// @Accept       json
+// @Produce      json
+// @Param 		 contactGroupID	path		string 							true 	"ID of the contact group" 	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        payload   		body 		requests.ContactGroupMembers  	true 	"Phone numbers to remove from the contact group"
+// @Success      200 		{object}	responses.ContactGroupResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /contact-groups/{contactGroupID}/members 	[delete]
+func (h *ContactGroupHandler) RemoveMembers(c *fiber.Ctx) error {
+	return h.updateMembers(c, "removing", h.service.RemoveMembers)
+}
+
+func (h *ContactGroupHandler) updateMembers(
+	c *fiber.Ctx,
+	action string,
+	update func(ctx context.Context, params *services.ContactGroupMembersParams) (*entities.ContactGroup, error),
+) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.ContactGroupMembers
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params [%s] into [%T]", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.ContactGroupID = c.Params("contactGroupID")
+	if errors := h.validator.ValidateMembers(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while %s contact group members [%+#v]", spew.Sdump(errors), action, request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, fmt.Sprintf("validation errors while %s contact group members", action))
+	}
+
+	group, err := update(ctx, request.ToMembersParams(h.userIDFomContext(c)))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find contact group with ID [%s]", request.ContactGroupID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("error %s members with params [%+#v]", action, request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("contact group now has %d phone %s", group.Size(), h.pluralize("number", group.Size())), group)
+}
@@ -0,0 +1,337 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// ContactHandler handles contact http requests
+type ContactHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	validator *validators.ContactHandlerValidator
+	service   *services.ContactService
+}
+
+// NewContactHandler creates a new ContactHandler
+func NewContactHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	validator *validators.ContactHandlerValidator,
+	service *services.ContactService,
+) (h *ContactHandler) {
+	return &ContactHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		validator: validator,
+		service:   service,
+	}
+}
+
+// RegisterRoutes registers the routes for the ContactHandler
+func (h *ContactHandler) RegisterRoutes(router fiber.Router) {
+	router.Get("/contacts", h.Index)
+	router.Post("/contacts", h.Store)
+	router.Post("/contacts/import", h.Import)
+	router.Get("/contacts/export", h.Export)
+	router.Get("/contacts/:contactID", h.Show)
+	router.Put("/contacts/:contactID", h.Update)
+	router.Delete("/contacts/:contactID", h.Delete)
+}
+
+// Index returns the contacts of a user
+// @Summary      Get contacts of a user
+// @Description  Get the contacts in the address book of a user
+// @Security	 ApiKeyAuth
+// @Tags         Contacts
+// @Accept       json
+// @Produce      json
+// @Param        skip		query  int  	false	"number of contacts to skip"		minimum(0)
+// @Param        query		query  string  	false 	"filter contacts with a name, phone number or notes containing query"
+// @Param        tag		query  string  	false 	"filter contacts having the tag"
+// @Param        limit		query  int  	false	"number of contacts to return"	minimum(1)	maximum(100)
+// @Success      200 		{object}	responses.ContactsResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /contacts 	[get]
+func (h *ContactHandler) Index(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.ContactIndex
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall URL [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateIndex(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching contacts [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching contacts")
+	}
+
+	contacts, err := h.service.Index(ctx, h.userIDFomContext(c), request.Tag, request.ToIndexParams())
+	if err != nil {
+		msg := fmt.Sprintf("cannot get contacts with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d %s", len(contacts), h.pluralize("contact", len(contacts))), contacts)
+}
+
+// Show returns a contact
+// @Summary      Get a contact
+// @Description  Get a contact of the authenticated user by ID
+// @Security	 ApiKeyAuth
+// @Tags         Contacts
+// @Accept       json
+// @Produce      json
+// @Param 		 contactID 	path		string 				true 	"ID of the contact"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{object}	responses.ContactResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /contacts/{contactID} [get]
+func (h *ContactHandler) Show(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	contactID := c.Params("contactID")
+	if errors := h.validator.ValidateUUID(ctx, contactID, "contactID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching contact with ID [%s]", spew.Sdump(errors), contactID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching contact")
+	}
+
+	contact, err := h.service.Load(ctx, h.userIDFomContext(c), uuid.MustParse(contactID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find contact with ID [%s]", contactID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load contact with ID [%s]", contactID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "contact fetched successfully", contact)
+}
+
+// Store an entities.Contact
+// @Summary      Store a contact
+// @Description  Add a new contact to the address book of the authenticated user
+// @Security	 ApiKeyAuth
+// @Tags         Contacts
+// @Accept       json
+// @Produce      json
+// @Param        payload   	body 		requests.ContactStore  		true "Payload of the contact"
+// @Success      201 		{object}	responses.ContactResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /contacts [post]
+func (h *ContactHandler) Store(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.ContactStore
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall body [%s] into [%T]", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateStore(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while storing contact [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while storing contact")
+	}
+
+	contact, err := h.service.Store(ctx, request.ToStoreParams(h.userFromContext(c)))
+	if err != nil {
+		msg := fmt.Sprintf("cannot store contact with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseCreated(c, "contact created successfully", contact)
+}
+
+// Update an entities.Contact
+// @Summary      Update a contact
+// @Description  Update a contact in the address book of the authenticated user
+// @Security	 ApiKeyAuth
+// @Tags         Contacts
+// @Accept       json
+// @Produce      json
+// @Param 		 contactID	path		string 					true 	"ID of the contact" 	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        payload   	body 		requests.ContactUpdate  true 	"Payload of contact to update"
+// @Success      200 		{object}	responses.ContactResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /contacts/{contactID} 	[put]
+func (h *ContactHandler) Update(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.ContactUpdate
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params [%s] into [%T]", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.ContactID = c.Params("contactID")
+	if errors := h.validator.ValidateUpdate(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while updating contact [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while updating contact")
+	}
+
+	contact, err := h.service.Update(ctx, request.ToUpdateParams(h.userFromContext(c)))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find contact with ID [%s]", request.ContactID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot update contact with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "contact updated successfully", contact)
+}
+
+// Delete an entities.Contact
+// @Summary      Delete a contact
+// @Description  Delete a contact from the address book of the authenticated user
+// @Security	 ApiKeyAuth
+// @Tags         Contacts
+// @Accept       json
+// @Produce      json
+// @Param 		 contactID 	path		string 				true 	"ID of the contact"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      204		{object}    responses.NoContent
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /contacts/{contactID} [delete]
+func (h *ContactHandler) Delete(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	contactID := c.Params("contactID")
+	if errors := h.validator.ValidateUUID(ctx, contactID, "contactID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while deleting contact with ID [%s]", spew.Sdump(errors), contactID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while deleting contact")
+	}
+
+	err := h.service.Delete(ctx, h.userIDFomContext(c), uuid.MustParse(contactID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find contact with ID [%s]", contactID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete contact with ID [%s]", contactID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseNoContent(c, "contact deleted successfully")
+}
+
+// Import contacts from a CSV file
+// @Summary      Import contacts from a CSV file
+// @Description  Import contacts from a CSV file with the columns Name, PhoneNumbers, Tags(optional) and Notes(optional). Multiple phone numbers or tags are separated by ";" and extra columns are stored as custom fields. A contact with any of the phone numbers of a row is updated and a failed import does not save any contact.
+// @Security	 ApiKeyAuth
+// @Tags         Contacts
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        document	formData	file	true	"The CSV file containing the contacts"
+// @Success      200 		{object}	responses.ContactsResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /contacts/import [post]
+func (h *ContactHandler) Import(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	file, err := c.FormFile("document")
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch file with name [%s] from request", "document")
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	rows, errors := h.validator.ValidateImport(ctx, h.userIDFomContext(c), file)
+	if len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while importing contacts from file [%s]", spew.Sdump(errors), file.Filename)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while importing contacts")
+	}
+
+	params := make([]*services.ContactStoreParams, 0, len(rows))
+	for _, row := range rows {
+		params = append(params, row.ToStoreParams(h.userIDFomContext(c)))
+	}
+
+	contacts, err := h.service.Import(ctx, h.userIDFomContext(c), params)
+	if err != nil {
+		msg := fmt.Sprintf("cannot import [%d] contacts from file [%s]", len(params), file.Filename)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("imported %d %s successfully", len(contacts), h.pluralize("contact", len(contacts))), contacts)
+}
+
+// Export contacts as a CSV file
+// @Summary      Export contacts as a CSV file
+// @Description  Download all the contacts of the authenticated user as a CSV file which can be imported again
+// @Security	 ApiKeyAuth
+// @Tags         Contacts
+// @Produce      text/csv
+// @Success      200 		{file}		file
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /contacts/export [get]
+func (h *ContactHandler) Export(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	content, err := h.service.Export(ctx, h.userIDFomContext(c))
+	if err != nil {
+		msg := fmt.Sprintf("cannot export contacts for user [%s]", h.userIDFomContext(c))
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	c.Attachment(fmt.Sprintf("httpsms-contacts-%s.csv", time.Now().UTC().Format("2006-01-02")))
+	c.Set(fiber.HeaderContentType, "text/csv")
+	return c.Status(fiber.StatusOK).Send(content)
+}
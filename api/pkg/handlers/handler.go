@@ -5,6 +5,7 @@ import (
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
 	"github.com/NdoleStudio/httpsms/pkg/middlewares"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -57,6 +58,13 @@ func (h *handler) responseNotFound(c *fiber.Ctx, message string) error {
 	})
 }
 
+func (h *handler) responseConflict(c *fiber.Ctx, message string) error {
+	return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+		"status":  "error",
+		"message": message,
+	})
+}
+
 func (h *handler) responsePaymentRequired(c *fiber.Ctx, message string) error {
 	return c.Status(fiber.StatusPaymentRequired).JSON(fiber.Map{
 		"status":  "error",
@@ -78,6 +86,14 @@ func (h *handler) responseAccepted(c *fiber.Ctx, message string) error {
 	})
 }
 
+func (h *handler) responseAcceptedWithData(c *fiber.Ctx, message string, data interface{}) error {
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"status":  "success",
+		"message": message,
+		"data":    data,
+	})
+}
+
 func (h *handler) responseOK(c *fiber.Ctx, message string, data interface{}) error {
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"status":  "success",
@@ -86,6 +102,21 @@ func (h *handler) responseOK(c *fiber.Ctx, message string, data interface{}) err
 	})
 }
 
+func (h *handler) responseOKWithCursor(c *fiber.Ctx, message string, data interface{}, cursor *repositories.Cursor) error {
+	var nextCursor *string
+	if cursor != nil {
+		value := cursor.String()
+		nextCursor = &value
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":      "success",
+		"message":     message,
+		"data":        data,
+		"next_cursor": nextCursor,
+	})
+}
+
 func (h *handler) responseCreated(c *fiber.Ctx, message string, data interface{}) error {
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
 		"status":  "success",
@@ -94,6 +125,16 @@ func (h *handler) responseCreated(c *fiber.Ctx, message string, data interface{}
 	})
 }
 
+// nextCursor trims the items which were fetched with limit+1 to the limit and returns the cursor of the last item
+// when there are more items, there is no cursor on the last page even when it is full.
+func nextCursor[T any](items []T, limit int, cursor func(item T) *repositories.Cursor) ([]T, *repositories.Cursor) {
+	if len(items) <= limit {
+		return items, nil
+	}
+	items = items[:limit]
+	return items, cursor(items[limit-1])
+}
+
 func (h *handler) pluralize(value string, count int) string {
 	if count == 1 {
 		return value
@@ -3,6 +3,8 @@ package handlers
 import (
 	"fmt"
 
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
 	"github.com/NdoleStudio/httpsms/pkg/requests"
 	"github.com/NdoleStudio/httpsms/pkg/services"
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
@@ -51,9 +53,10 @@ func (h *HeartbeatHandler) RegisterRoutes(router fiber.Router) {
 // @Produce      json
 // @Param        owner		query  string  	true 	"the owner's phone number" 			default(+18005550199)
 // @Param        skip		query  int  	false	"number of heartbeats to skip"		minimum(0)
+// @Param        cursor		query  string  	false	"the next_cursor of the previous page, it is used instead of skip"
 // @Param        query		query  string  	false 	"filter containing query"
 // @Param        limit		query  int  	false	"number of heartbeats to return"	minimum(1)	maximum(20)
-// @Success      200 		{object}	responses.HeartbeatsResponse
+// @Success      200 		{object}	responses.HeartbeatsCursorResponse
 // @Failure      400		{object}	responses.BadRequest
 // @Failure 	 401	    {object}	responses.Unauthorized
 // @Failure      422		{object}	responses.UnprocessableEntity
@@ -78,14 +81,22 @@ func (h *HeartbeatHandler) Index(c *fiber.Ctx) error {
 		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching heartbeats")
 	}
 
-	heartbeats, err := h.service.Index(ctx, h.userIDFomContext(c), request.Owner, request.ToIndexParams())
+	params := request.ToIndexParams()
+	limit := params.Limit
+	// fetch one more item than the limit to know if there is a next page
+	params.Limit++
+	heartbeats, err := h.service.Index(ctx, h.userIDFomContext(c), request.Owner, params)
 	if err != nil {
 		msg := fmt.Sprintf("cannot get messgaes with params [%+#v]", request)
 		ctxLogger.Error(stacktrace.Propagate(err, msg))
 		return h.responseInternalServerError(c)
 	}
 
-	return h.responseOK(c, fmt.Sprintf("fetched %d %s", len(*heartbeats), h.pluralize("heartbeat", len(*heartbeats))), heartbeats)
+	var cursor *repositories.Cursor
+	*heartbeats, cursor = nextCursor(*heartbeats, limit, func(heartbeat entities.Heartbeat) *repositories.Cursor {
+		return repositories.NewCursor(heartbeat.Timestamp, heartbeat.ID.String())
+	})
+	return h.responseOKWithCursor(c, fmt.Sprintf("fetched %d %s", len(*heartbeats), h.pluralize("heartbeat", len(*heartbeats))), heartbeats, cursor)
 }
 
 // Store the heartbeat of a phone number
@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/exports"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// MessageExportHandler handles message export http requests
+type MessageExportHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	validator *validators.MessageExportHandlerValidator
+	service   *services.MessageExportService
+}
+
+// NewMessageExportHandler creates a new MessageExportHandler
+func NewMessageExportHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	validator *validators.MessageExportHandlerValidator,
+	service *services.MessageExportService,
+) (h *MessageExportHandler) {
+	return &MessageExportHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		validator: validator,
+		service:   service,
+	}
+}
+
+// RegisterRoutes registers the routes for the MessageExportHandler
+func (h *MessageExportHandler) RegisterRoutes(app *fiber.App, authMiddleware fiber.Handler, middlewares ...fiber.Handler) {
+	router := app.Group("message-exports")
+	router.Get("/:exportID/download", h.computeRoute(middlewares, h.Download)...)
+
+	app.Get("v1/messages/export", h.computeRoute(append(middlewares, authMiddleware), h.Export)...)
+
+	authRouter := app.Group("v1/message-exports")
+	authRouter.Get("/:exportID", h.computeRoute(append(middlewares, authMiddleware), h.Show)...)
+}
+
+// Export the messages of a user
+// @Summary      Export messages
+// @Description  Download the messages of the authenticated user as a CSV, XLSX or JSON Lines file. Large exports are created in the background and a download link is sent by email. An XLSX export can contain at most 1,048,575 messages.
+// @Security	 ApiKeyAuth
+// @Tags         Messages
+// @Produce      text/csv
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce      application/jsonl
+// @Produce      json
+// @Param        format		query  string  	false 	"file format of the export"							Enums(csv, xlsx, jsonl)	default(csv)
+// @Param        owner		query  string  	false 	"export messages of a phone number"					default(+18005550199)
+// @Param        contacts	query  string  	false 	"comma separated contact phone numbers"				default(+18005550100)
+// @Param        start_date	query  string  	false 	"export messages at or after this RFC3339 timestamp"	default(2022-06-01T00:00:00Z)
+// @Param        end_date	query  string  	false 	"export messages at or before this RFC3339 timestamp"		default(2022-07-01T00:00:00Z)
+// @Param        async		query  bool  	false 	"create the export in the background and email the download link"
+// @Success      200 		{file}		file
+// @Success      202 		{object}	responses.MessageExportResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /messages/export [get]
+func (h *MessageExportHandler) Export(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.MessageExport
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall URL [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateExport(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while exporting messages [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while exporting messages")
+	}
+
+	params := request.ToExportParams(h.userIDFomContext(c), c.OriginalURL())
+	count, err := h.service.Count(ctx, params)
+	if err != nil {
+		msg := fmt.Sprintf("cannot count messages to export with params [%+#v]", params)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	if !params.Format.CanExport(count) {
+		msg := fmt.Sprintf("cannot export [%d] messages as [%s] with params [%+#v]", count, params.Format, params)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseBadRequest(c, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("The export of %d messages is larger than the %d rows of an XLSX file, use the CSV format instead.", count, exports.XLSXMaxMessages)))
+	}
+
+	if request.IsAsync() || h.service.RequiresAsync(count) {
+		export, err := h.service.Schedule(ctx, params)
+		if err != nil {
+			msg := fmt.Sprintf("cannot schedule export of [%d] messages with params [%+#v]", count, params)
+			ctxLogger.Error(stacktrace.Propagate(err, msg))
+			return h.responseInternalServerError(c)
+		}
+		return h.responseAcceptedWithData(c, fmt.Sprintf("the export of %d %s is being created, the download link will be sent to your email address", count, h.pluralize("message", int(count))), export)
+	}
+
+	// the body is written after the handler returns so the export must not be cancelled with the request context
+	streamCtx := context.WithoutCancel(ctx)
+	c.Attachment(h.filename(params.Format))
+	c.Set(fiber.HeaderContentType, params.Format.ContentType())
+	c.Status(fiber.StatusOK).Context().SetBodyStreamWriter(func(writer *bufio.Writer) {
+		if _, err := h.service.Export(streamCtx, writer, params); err != nil {
+			msg := fmt.Sprintf("cannot stream export of [%d] messages with params [%+#v]", count, params)
+			h.logger.Error(stacktrace.Propagate(err, msg))
+		}
+	})
+
+	return nil
+}
+
+// Show returns a message export
+// @Summary      Get a message export
+// @Description  Get the status of a message export which is created in the background
+// @Security	 ApiKeyAuth
+// @Tags         Messages
+// @Accept       json
+// @Produce      json
+// @Param 		 exportID 	path		string 		true 	"ID of the message export"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{object}	responses.MessageExportResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /message-exports/{exportID} [get]
+func (h *MessageExportHandler) Show(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	exportID := c.Params("exportID")
+	if errors := h.validator.ValidateUUID(ctx, exportID, "exportID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching message export with ID [%s]", spew.Sdump(errors), exportID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching message export")
+	}
+
+	export, err := h.service.Load(ctx, h.userIDFomContext(c), uuid.MustParse(exportID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message export with ID [%s]", exportID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load message export with ID [%s] for user [%s]", exportID, h.userIDFomContext(c))
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "message export fetched successfully", export)
+}
+
+// Download the file of a message export with the link which was sent by email
+// @Summary      Download a message export
+// @Description  Download the file of a completed message export. The link is sent by email and expires after 7 days.
+// @Tags         Messages
+// @Produce      text/csv
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce      application/jsonl
+// @Param 		 exportID 	path		string 		true 	"ID of the message export"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param 		 token 		query		string 		true 	"token of the download link"
+// @Success      200 		{file}		file
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /message-exports/{exportID}/download [get]
+func (h *MessageExportHandler) Download(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	exportID := c.Params("exportID")
+	if errors := h.validator.ValidateUUID(ctx, exportID, "exportID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while downloading message export with ID [%s]", spew.Sdump(errors), exportID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while downloading message export")
+	}
+
+	export, content, err := h.service.Download(ctx, uuid.MustParse(exportID), c.Query("token"))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("invalid download link for message export [%s]", exportID)))
+		return h.responseNotFound(c, "the download link is invalid or has expired")
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot download message export with ID [%s]", exportID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	format := exports.Format(export.Format)
+	c.Attachment(h.filename(format))
+	c.Set(fiber.HeaderContentType, format.ContentType())
+	return c.Status(fiber.StatusOK).SendStream(content)
+}
+
+func (h *MessageExportHandler) filename(format exports.Format) string {
+	return fmt.Sprintf("httpsms-messages-%s.%s", time.Now().UTC().Format("2006-01-02"), format)
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 	"sync"
 	"time"
@@ -23,11 +24,17 @@ import (
 // MessageHandler handles message http requests.
 type MessageHandler struct {
 	handler
-	logger         telemetry.Logger
-	tracer         telemetry.Tracer
-	billingService *services.BillingService
-	validator      *validators.MessageHandlerValidator
-	service        *services.MessageService
+	logger             telemetry.Logger
+	tracer             telemetry.Tracer
+	billingService     *services.BillingService
+	validator          *validators.MessageHandlerValidator
+	service            *services.MessageService
+	templateService    *services.MessageTemplateService
+	contactService     *services.ContactService
+	groupService       *services.ContactGroupService
+	suppressionService *services.SuppressionService
+	attachmentService  *services.AttachmentService
+	jobService         *services.BulkMessageJobService
 }
 
 // NewMessageHandler creates a new MessageHandler
@@ -37,39 +44,61 @@ func NewMessageHandler(
 	validator *validators.MessageHandlerValidator,
 	billingService *services.BillingService,
 	service *services.MessageService,
+	templateService *services.MessageTemplateService,
+	contactService *services.ContactService,
+	groupService *services.ContactGroupService,
+	suppressionService *services.SuppressionService,
+	attachmentService *services.AttachmentService,
+	jobService *services.BulkMessageJobService,
 ) (h *MessageHandler) {
 	return &MessageHandler{
-		logger:         logger.WithService(fmt.Sprintf("%T", h)),
-		tracer:         tracer,
-		validator:      validator,
-		billingService: billingService,
-		service:        service,
+		logger:             logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:             tracer,
+		validator:          validator,
+		billingService:     billingService,
+		service:            service,
+		templateService:    templateService,
+		contactService:     contactService,
+		groupService:       groupService,
+		suppressionService: suppressionService,
+		attachmentService:  attachmentService,
+		jobService:         jobService,
 	}
 }
 
+// idempotencyKeyHeader is the header used to safely retry sending messages
+const idempotencyKeyHeader = "Idempotency-Key"
+
 // RegisterRoutes registers the routes for the MessageHandler
 func (h *MessageHandler) RegisterRoutes(router fiber.Router) {
 	router.Post("/messages/send", h.PostSend)
 	router.Post("/messages/bulk-send", h.BulkSend)
+	router.Post("/messages/broadcast", h.Broadcast)
+	router.Post("/messages/analyze", h.Analyze)
 	router.Post("/messages/receive", h.PostReceive)
 	router.Post("/messages/calls/missed", h.PostCallMissed)
 	router.Get("/messages/outstanding", h.GetOutstanding)
 	router.Get("/messages", h.Index)
+	router.Get("/messages/search", h.Search)
 	router.Post("/messages/:messageID/events", h.PostEvent)
+	router.Post("/messages/:messageID/cancel", h.Cancel)
+	router.Put("/messages/:messageID/schedule", h.Reschedule)
 	router.Delete("/messages/:messageID", h.Delete)
 }
 
 // PostSend a new entities.Message
 // @Summary      Send a new SMS message
-// @Description  Add a new SMS message to be sent by the android phone
+// @Description  Add a new SMS message to be sent by the android phone. Set the "from" field to the ID of a sender pool to send the message with the phone of the pool which last talked to the recipient or with the least busy online phone of the pool
 // @Security	 ApiKeyAuth
 // @Tags         Messages
 // @Accept       json
 // @Produce      json
 // @Param        payload   body requests.MessageSend  true  "PostSend message request payload"
+// @Param        Idempotency-Key  header  string  false  "Repeated requests with the same key within 24 hours return the original message instead of sending a new one. Defaults to the request_id"
 // @Success      200  {object}  responses.MessageResponse
 // @Failure      400  {object}  responses.BadRequest
 // @Failure 	 401  {object}	responses.Unauthorized
+// @Failure      409  {object}  responses.Conflict
 // @Failure      422  {object}  responses.UnprocessableEntity
 // @Failure      500  {object}  responses.InternalServerError
 // @Router       /messages/send [post]
@@ -86,6 +115,8 @@ func (h *MessageHandler) PostSend(c *fiber.Ctx) error {
 		return h.responseBadRequest(c, err)
 	}
 
+	request.IdempotencyKey = c.Get(idempotencyKeyHeader)
+
 	if errors := h.validator.ValidateMessageSend(ctx, h.userIDFomContext(c), request.Sanitize()); len(errors) != 0 {
 		msg := fmt.Sprintf("validation errors [%s], while sending payload [%s]", spew.Sdump(errors), c.Body())
 		ctxLogger.Warn(stacktrace.NewError(msg))
@@ -97,7 +128,39 @@ func (h *MessageHandler) PostSend(c *fiber.Ctx) error {
 		return h.responsePaymentRequired(c, *msg)
 	}
 
-	message, err := h.service.SendMessage(ctx, request.ToMessageSendParams(h.userIDFomContext(c), c.OriginalURL()))
+	if request.TemplateID != "" {
+		content, err := h.templateService.Render(ctx, h.userIDFomContext(c), uuid.MustParse(request.TemplateID), request.Variables)
+		if err != nil {
+			ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot render message template [%s] with paylod [%s]", request.TemplateID, c.Body())))
+			return h.responseInternalServerError(c)
+		}
+		request.Content = content
+	}
+
+	if request.To == "" {
+		contact, err := h.contactService.Load(ctx, h.userIDFomContext(c), uuid.MustParse(request.ContactID))
+		if err != nil {
+			ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot load contact [%s] with paylod [%s]", request.ContactID, c.Body())))
+			return h.responseInternalServerError(c)
+		}
+		request.To = contact.PhoneNumber()
+	}
+
+	params := request.ToMessageSendParams(h.userIDFomContext(c), c.OriginalURL())
+	if len(request.Attachments) > 0 {
+		attachments, err := h.attachmentService.URLs(ctx, h.userIDFomContext(c), request.AttachmentIDs())
+		if err != nil {
+			ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot load attachments with paylod [%s]", c.Body())))
+			return h.responseInternalServerError(c)
+		}
+		params.Attachments = attachments
+	}
+
+	message, err := h.service.SendMessage(ctx, params)
+	if stacktrace.GetCode(err) == services.ErrCodeIdempotencyKeyInUse {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("idempotency key [%s] is in use for paylod [%s]", request.IdempotencyKey, c.Body())))
+		return h.responseConflict(c, fmt.Sprintf("A message with the same %s is still being sent, retry the request later.", idempotencyKeyHeader))
+	}
 	if err != nil {
 		msg := fmt.Sprintf("cannot send message with paylod [%s]", c.Body())
 		ctxLogger.Error(stacktrace.Propagate(err, msg))
@@ -115,6 +178,7 @@ func (h *MessageHandler) PostSend(c *fiber.Ctx) error {
 // @Accept       json
 // @Produce      json
 // @Param        payload   body requests.MessageBulkSend  true  "Bulk send message request payload"
+// @Param        Idempotency-Key  header  string  false  "Repeated requests with the same key within 24 hours return the original message instead of sending a new one. Defaults to the request_id"
 // @Success      200  {object}  []responses.MessagesResponse
 // @Failure      400  {object}  responses.BadRequest
 // @Failure 	 401  {object}	responses.Unauthorized
@@ -134,17 +198,49 @@ func (h *MessageHandler) BulkSend(c *fiber.Ctx) error {
 		return h.responseBadRequest(c, err)
 	}
 
+	request.IdempotencyKey = c.Get(idempotencyKeyHeader)
+
 	if errors := h.validator.ValidateMessageBulkSend(ctx, h.userIDFomContext(c), request.Sanitize()); len(errors) != 0 {
 		msg := fmt.Sprintf("validation errors [%s], while sending payload [%s]", spew.Sdump(errors), c.Body())
 		ctxLogger.Warn(stacktrace.NewError(msg))
 		return h.responseUnprocessableEntity(c, errors, "validation errors while sending messages")
 	}
 
+	if request.HasContactTargets() {
+		contacts, err := h.contactService.Targets(ctx, request.ToContactTargetParams(h.userIDFomContext(c)))
+		if err != nil {
+			ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot fetch contacts with paylod [%s]", c.Body())))
+			return h.responseInternalServerError(c)
+		}
+		request.AddContacts(contacts)
+	}
+
+	suppressed, err := h.suppressionService.Suppressed(ctx, h.userIDFomContext(c), request.To)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot fetch suppressed recipients with paylod [%s]", c.Body())))
+		return h.responseInternalServerError(c)
+	}
+
+	skipped := request.RemoveRecipients(suppressed)
+	if len(request.To) == 0 {
+		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("all [%d] recipients opted out of receiving messages from user [%s]", len(skipped), h.userIDFomContext(c))))
+		return h.responseUnprocessableEntity(c, url.Values{"to": []string{"all the recipients opted out of receiving messages from you"}}, "validation errors while sending messages")
+	}
+
 	if msg := h.billingService.IsEntitledWithCount(ctx, h.userIDFomContext(c), uint(len(request.To))); msg != nil {
 		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("user with ID [%s] is not entitled to send [%d] messages", h.userIDFomContext(c), len(request.To))))
 		return h.responsePaymentRequired(c, *msg)
 	}
 
+	if request.TemplateID != "" {
+		content, err := h.templateService.Render(ctx, h.userIDFomContext(c), uuid.MustParse(request.TemplateID), request.Variables)
+		if err != nil {
+			ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot render message template [%s] with paylod [%s]", request.TemplateID, c.Body())))
+			return h.responseInternalServerError(c)
+		}
+		request.Content = content
+	}
+
 	wg := sync.WaitGroup{}
 	params := request.ToMessageSendParams(h.userIDFomContext(c), c.OriginalURL())
 	responses := make([]*entities.Message, len(params))
@@ -163,9 +259,118 @@ func (h *MessageHandler) BulkSend(c *fiber.Ctx) error {
 	}
 
 	wg.Wait()
+	if len(skipped) != 0 {
+		return h.responseOK(c, fmt.Sprintf("[%d] messages processed successfully, skipped [%s] because they opted out of receiving messages", len(responses), strings.Join(skipped, ", ")), responses)
+	}
 	return h.responseOK(c, fmt.Sprintf("[%d] messages processed successfully", len(responses)), responses)
 }
 
+// Broadcast an entities.Message to an entities.ContactGroup
+// @Summary      Broadcast an SMS message to a contact group
+// @Description  Send the same SMS message to every phone number in a contact group. The messages are sent in the background by a bulk message job, use the ID of the job to track the result for each recipient.
+// @Security	 ApiKeyAuth
+// @Tags         Messages
+// @Accept       json
+// @Produce      json
+// @Param        payload   body requests.MessageBroadcast  true  "Broadcast message request payload"
+// @Success      202  {object}  responses.BulkMessageJobResponse
+// @Failure      400  {object}  responses.BadRequest
+// @Failure 	 401  {object}	responses.Unauthorized
+// @Failure      422  {object}  responses.UnprocessableEntity
+// @Failure      500  {object}  responses.InternalServerError
+// @Router       /messages/broadcast [post]
+func (h *MessageHandler) Broadcast(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.MessageBroadcast
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall [%s] into %T", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateMessageBroadcast(ctx, h.userIDFomContext(c), request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while broadcasting payload [%s]", spew.Sdump(errors), c.Body())
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while broadcasting message")
+	}
+
+	group, err := h.groupService.Load(ctx, h.userIDFomContext(c), uuid.MustParse(request.ContactGroupID))
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot load contact group [%s] with paylod [%s]", request.ContactGroupID, c.Body())))
+		return h.responseInternalServerError(c)
+	}
+
+	if msg := h.billingService.IsEntitledWithCount(ctx, h.userIDFomContext(c), uint(group.Size())); msg != nil {
+		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("user with ID [%s] is not entitled to broadcast [%d] messages", h.userIDFomContext(c), group.Size())))
+		return h.responsePaymentRequired(c, *msg)
+	}
+
+	if request.TemplateID != "" {
+		content, err := h.templateService.Render(ctx, h.userIDFomContext(c), uuid.MustParse(request.TemplateID), request.Variables)
+		if err != nil {
+			ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot render message template [%s] with paylod [%s]", request.TemplateID, c.Body())))
+			return h.responseInternalServerError(c)
+		}
+		request.Content = content
+	}
+
+	job, err := h.jobService.Broadcast(ctx, request.ToBulkMessageJobBroadcastParams(h.userIDFomContext(c), c.OriginalURL(), group))
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot broadcast message to contact group [%s] with paylod [%s]", group.ID, c.Body())))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseAcceptedWithData(c, fmt.Sprintf("[%d] %s will be sent in the background by the bulk message job", job.Valid, h.pluralize("message", int(job.Valid))), job)
+}
+
+// Analyze the content of an entities.Message
+// @Summary      Analyze the content of an SMS message
+// @Description  Detect the GSM-7 or UCS-2 encoding of the content and count the number of SMS segments needed to send it without sending a message.
+// @Security	 ApiKeyAuth
+// @Tags         Messages
+// @Accept       json
+// @Produce      json
+// @Param        payload   body requests.MessageAnalyze  true  "Analyze message request payload"
+// @Success      200  {object}  responses.MessageAnalysisResponse
+// @Failure      400  {object}  responses.BadRequest
+// @Failure 	 401  {object}	responses.Unauthorized
+// @Failure 	 404  {object}	responses.NotFound
+// @Failure      422  {object}  responses.UnprocessableEntity
+// @Failure      500  {object}  responses.InternalServerError
+// @Router       /messages/analyze [post]
+func (h *MessageHandler) Analyze(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.MessageAnalyze
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall [%s] into %T", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateMessageAnalyze(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while analyzing payload [%s]", spew.Sdump(errors), c.Body())
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while analyzing message")
+	}
+
+	analysis, err := h.service.Analyze(ctx, h.userIDFomContext(c), request.From, request.Content)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find phone with number [%s]", request.From))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot analyze message with paylod [%s]", c.Body())
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("message content needs %d %s", analysis.Segments, h.pluralize("segment", int(analysis.Segments))), analysis)
+}
+
 // GetOutstanding returns an entities.Message which is still to be sent by the mobile phone
 // @Summary      Get an outstanding message
 // @Description  Get an outstanding message to be sent by an android phone
@@ -226,9 +431,10 @@ func (h *MessageHandler) GetOutstanding(c *fiber.Ctx) error {
 // @Param        owner		query  string  	true 	"the owner's phone number" 			default(+18005550199)
 // @Param        contact	query  string  	true 	"the contact's phone number" 		default(+18005550100)
 // @Param        skip		query  int  	false	"number of messages to skip"		minimum(0)
+// @Param        cursor		query  string  	false	"the next_cursor of the previous page, it is used instead of skip"
 // @Param        query		query  string  	false 	"filter messages containing query"
 // @Param        limit		query  int  	false	"number of messages to return"		minimum(1)	maximum(20)
-// @Success      200 		{object}	responses.MessagesResponse
+// @Success      200 		{object}	responses.MessagesCursorResponse
 // @Failure      400		{object}	responses.BadRequest
 // @Failure 	 401    	{object}	responses.Unauthorized
 // @Failure      422		{object}	responses.UnprocessableEntity
@@ -253,14 +459,72 @@ func (h *MessageHandler) Index(c *fiber.Ctx) error {
 		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching messages")
 	}
 
-	messages, err := h.service.GetMessages(ctx, request.ToGetParams(h.userIDFomContext(c)))
+	params := request.ToGetParams(h.userIDFomContext(c))
+	limit := params.Limit
+	// fetch one more item than the limit to know if there is a next page
+	params.Limit++
+	messages, err := h.service.GetMessages(ctx, params)
 	if err != nil {
 		msg := fmt.Sprintf("cannot get messgaes with params [%+#v]", request)
 		ctxLogger.Error(stacktrace.Propagate(err, msg))
 		return h.responseInternalServerError(c)
 	}
 
-	return h.responseOK(c, fmt.Sprintf("fetched %d %s", len(*messages), h.pluralize("message", len(*messages))), messages)
+	var cursor *repositories.Cursor
+	*messages, cursor = nextCursor(*messages, limit, func(message entities.Message) *repositories.Cursor {
+		return repositories.NewCursor(message.OrderTimestamp, message.ID.String())
+	})
+	return h.responseOKWithCursor(c, fmt.Sprintf("fetched %d %s", len(*messages), h.pluralize("message", len(*messages))), messages, cursor)
+}
+
+// Search all the messages of a user
+// @Summary      Search messages
+// @Description  Full-text search of all the messages of the user, sorted by timestamp in descending order. Encrypted messages are never matched by the query. Use the next_cursor in the response to fetch the next page.
+// @Security	 ApiKeyAuth
+// @Tags         Messages
+// @Accept       json
+// @Produce      json
+// @Param        query		query  string  	false 	"words to search for in the message content, supports quoted phrases and -excluded words"
+// @Param        owner		query  string  	false 	"the owner's phone number" 			default(+18005550199)
+// @Param        contact	query  string  	false 	"the contact's phone number" 		default(+18005550100)
+// @Param        status		query  string  	false 	"status of the messages"			Enums(pending,scheduled,sending,sent,received,failed,delivered,expired,cancelled)
+// @Param        type		query  string  	false 	"type of the messages"				Enums(mobile-terminated,mobile-originated,call/missed)
+// @Param        encrypted	query  bool  	false 	"filter encrypted or unencrypted messages"
+// @Param        start_date	query  string  	false 	"only messages on or after this RFC3339 timestamp"	default(2022-06-05T00:00:00Z)
+// @Param        end_date	query  string  	false 	"only messages on or before this RFC3339 timestamp"	default(2022-06-06T00:00:00Z)
+// @Param        cursor		query  string  	false 	"the next_cursor of the previous page"
+// @Param        limit		query  int  	false	"number of messages to return"		minimum(1)	maximum(100)
+// @Success      200 		{object}	responses.MessagesCursorResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /messages/search [get]
+func (h *MessageHandler) Search(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.MessageSearch
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateMessageSearch(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while searching messages [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while searching messages")
+	}
+
+	messages, cursor, err := h.service.SearchMessages(ctx, h.userIDFomContext(c), request.ToSearchParams())
+	if err != nil {
+		msg := fmt.Sprintf("cannot search messages with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOKWithCursor(c, fmt.Sprintf("found %d %s", len(*messages), h.pluralize("message", len(*messages))), messages, cursor)
 }
 
 // PostEvent registers an event on a message
@@ -350,7 +614,7 @@ func (h *MessageHandler) PostReceive(c *fiber.Ctx) error {
 		return h.responseBadRequest(c, err)
 	}
 
-	if errors := h.validator.ValidateMessageReceive(ctx, request.Sanitize()); len(errors) != 0 {
+	if errors := h.validator.ValidateMessageReceive(ctx, h.userIDFomContext(c), request.Sanitize()); len(errors) != 0 {
 		msg := fmt.Sprintf("validation errors [%s], while sending payload [%s]", spew.Sdump(errors), c.Body())
 		ctxLogger.Warn(stacktrace.NewError(msg))
 		return h.responseUnprocessableEntity(c, errors, "validation errors while receiving message")
@@ -361,7 +625,17 @@ func (h *MessageHandler) PostReceive(c *fiber.Ctx) error {
 		return h.responsePaymentRequired(c, *msg)
 	}
 
-	message, err := h.service.ReceiveMessage(ctx, request.ToMessageReceiveParams(h.userIDFomContext(c), c.OriginalURL()))
+	params := request.ToMessageReceiveParams(h.userIDFomContext(c), c.OriginalURL())
+	if len(request.Attachments) > 0 {
+		attachments, err := h.attachmentService.URLs(ctx, h.userIDFomContext(c), request.AttachmentIDs())
+		if err != nil {
+			ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot load attachments with paylod [%s]", c.Body())))
+			return h.responseInternalServerError(c)
+		}
+		params.Attachments = attachments
+	}
+
+	message, err := h.service.ReceiveMessage(ctx, params)
 	if err != nil {
 		msg := fmt.Sprintf("cannot receive message with paylod [%s]", c.Body())
 		ctxLogger.Error(stacktrace.Propagate(err, msg))
@@ -419,6 +693,130 @@ func (h *MessageHandler) Delete(c *fiber.Ctx) error {
 	return h.responseNoContent(c, "message deleted successfully")
 }
 
+// Cancel a message
+// @Summary      Cancel a message before it is sent
+// @Description  Cancel a pending or scheduled message which has not yet been picked up by the mobile phone.
+// @Security	 ApiKeyAuth
+// @Tags         Messages
+// @Accept       json
+// @Produce      json
+// @Param 		 messageID 	path		string 							true 	"ID of the message" 			default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200  		{object} 	responses.MessageResponse
+// @Failure      400  		{object}  	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404		{object}	responses.NotFound
+// @Failure      409  		{object} 	responses.Conflict
+// @Failure      422  		{object} 	responses.UnprocessableEntity
+// @Failure      500  		{object}  	responses.InternalServerError
+// @Router       /messages/{messageID}/cancel [post]
+func (h *MessageHandler) Cancel(c *fiber.Ctx) error {
+	ctx, span := h.tracer.StartFromFiberCtx(c)
+	defer span.End()
+
+	ctxLogger := h.tracer.CtxLogger(h.logger, span)
+
+	messageID := c.Params("messageID")
+	if errors := h.validator.ValidateUUID(ctx, messageID, "messageID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while cancelling a message with ID [%s]", spew.Sdump(errors), messageID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while cancelling message")
+	}
+
+	message, err := h.service.GetMessage(ctx, h.userIDFomContext(c), uuid.MustParse(messageID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message with ID [%s]", messageID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot find message with id [%s]", messageID)
+		ctxLogger.Error(h.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		return h.responseInternalServerError(c)
+	}
+
+	if !message.CanBeCancelled() {
+		return h.responseUnprocessableEntity(c, url.Values{"status": []string{fmt.Sprintf("cannot cancel message with status [%s]", message.Status)}}, "message cannot be cancelled")
+	}
+
+	message, err = h.service.CancelMessage(ctx, c.OriginalURL(), message)
+	if stacktrace.GetCode(err) == services.ErrCodeMessagePickedUp {
+		return h.responseConflict(c, fmt.Sprintf("cannot cancel message with ID [%s] because it has already been picked up by the phone", message.ID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot cancel message with ID [%s] for user with ID [%s]", messageID, h.userIDFomContext(c))
+		ctxLogger.Error(h.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "message cancelled successfully", message)
+}
+
+// Reschedule a message
+// @Summary      Change the send time of a message
+// @Description  Change the send time of a pending or scheduled message which has not yet been picked up by the mobile phone.
+// @Security	 ApiKeyAuth
+// @Tags         Messages
+// @Accept       json
+// @Produce      json
+// @Param 		 messageID 	path		string 							true 	"ID of the message" 			default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        payload   	body 		requests.MessageReschedule  	true 	"New send time of the message"
+// @Success      200  		{object} 	responses.MessageResponse
+// @Failure      400  		{object}  	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404		{object}	responses.NotFound
+// @Failure      409  		{object} 	responses.Conflict
+// @Failure      422  		{object} 	responses.UnprocessableEntity
+// @Failure      500  		{object}  	responses.InternalServerError
+// @Router       /messages/{messageID}/schedule [put]
+func (h *MessageHandler) Reschedule(c *fiber.Ctx) error {
+	ctx, span := h.tracer.StartFromFiberCtx(c)
+	defer span.End()
+
+	ctxLogger := h.tracer.CtxLogger(h.logger, span)
+
+	var request requests.MessageReschedule
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall [%s] into %T", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.MessageID = c.Params("messageID")
+	if errors := h.validator.ValidateMessageReschedule(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while rescheduling message [%s] with request [%s]", spew.Sdump(errors), request.MessageID, c.Body())
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while rescheduling message")
+	}
+
+	message, err := h.service.GetMessage(ctx, h.userIDFomContext(c), uuid.MustParse(request.MessageID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message with ID [%s]", request.MessageID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot find message with id [%s]", request.MessageID)
+		ctxLogger.Error(h.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		return h.responseInternalServerError(c)
+	}
+
+	if !message.CanBeCancelled() {
+		return h.responseUnprocessableEntity(c, url.Values{"status": []string{fmt.Sprintf("cannot reschedule message with status [%s]", message.Status)}}, "message cannot be rescheduled")
+	}
+
+	message, err = h.service.RescheduleMessage(ctx, c.OriginalURL(), message, request.SendAt)
+	if stacktrace.GetCode(err) == services.ErrCodeMessagePickedUp {
+		return h.responseConflict(c, fmt.Sprintf("cannot reschedule message with ID [%s] because it has already been picked up by the phone", message.ID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot reschedule message with ID [%s] to [%s]", request.MessageID, request.SendAt)
+		ctxLogger.Error(h.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "message rescheduled successfully", message)
+}
+
 // PostCallMissed registers a missed phone call
 // @Summary      Register a missed call event on the mobile phone
 // @Description  This endpoint is called by the httpSMS android app to register a missed call event on the mobile phone.
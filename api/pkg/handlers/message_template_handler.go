@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// MessageTemplateHandler handles message template http requests
+type MessageTemplateHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	validator *validators.MessageTemplateHandlerValidator
+	service   *services.MessageTemplateService
+}
+
+// NewMessageTemplateHandler creates a new MessageTemplateHandler
+func NewMessageTemplateHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	validator *validators.MessageTemplateHandlerValidator,
+	service *services.MessageTemplateService,
+) (h *MessageTemplateHandler) {
+	return &MessageTemplateHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		validator: validator,
+		service:   service,
+	}
+}
+
+// RegisterRoutes registers the routes for the MessageTemplateHandler
+func (h *MessageTemplateHandler) RegisterRoutes(router fiber.Router) {
+	router.Get("/message-templates", h.Index)
+	router.Post("/message-templates", h.Store)
+	router.Get("/message-templates/:messageTemplateID", h.Show)
+	router.Put("/message-templates/:messageTemplateID", h.Update)
+	router.Delete("/message-templates/:messageTemplateID", h.Delete)
+}
+
+// Index returns the message templates of a user
+// @Summary      Get message templates of a user
+// @Description  Get the message templates of a user
+// @Security	 ApiKeyAuth
+// @Tags         MessageTemplates
+// @Accept       json
+// @Produce      json
+// @Param        skip		query  int  	false	"number of message templates to skip"		minimum(0)
+// @Param        query		query  string  	false 	"filter message templates containing query"
+// @Param        limit		query  int  	false	"number of message templates to return"	minimum(1)	maximum(100)
+// @Success      200 		{object}	responses.MessageTemplatesResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /message-templates 	[get]
+func (h *MessageTemplateHandler) Index(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.MessageTemplateIndex
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall URL [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateIndex(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching message templates [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching message templates")
+	}
+
+	messageTemplates, err := h.service.Index(ctx, h.userIDFomContext(c), request.ToIndexParams())
+	if err != nil {
+		msg := fmt.Sprintf("cannot get message templates with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d message %s", len(messageTemplates), h.pluralize("template", len(messageTemplates))), messageTemplates)
+}
+
+// Show returns a message template
+// @Summary      Get a message template
+// @Description  Get a message template of the authenticated user by ID
+// @Security	 ApiKeyAuth
+// @Tags         MessageTemplates
+// @Accept       json
+// @Produce      json
+// @Param 		 messageTemplateID 	path		string 				true 	"ID of the message template"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{object}	responses.MessageTemplateResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /message-templates/{messageTemplateID} [get]
+func (h *MessageTemplateHandler) Show(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	messageTemplateID := c.Params("messageTemplateID")
+	if errors := h.validator.ValidateUUID(ctx, messageTemplateID, "messageTemplateID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching message template with ID [%s]", spew.Sdump(errors), messageTemplateID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching message template")
+	}
+
+	messageTemplate, err := h.service.Load(ctx, h.userIDFomContext(c), uuid.MustParse(messageTemplateID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message template with ID [%s]", messageTemplateID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load message template with ID [%s]", messageTemplateID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "message template fetched successfully", messageTemplate)
+}
+
+// Store an entities.MessageTemplate
+// @Summary      Store a message template
+// @Description  Store a message template with {{variable}} placeholders for the authenticated user
+// @Security	 ApiKeyAuth
+// @Tags         MessageTemplates
+// @Accept       json
+// @Produce      json
+// @Param        payload   	body 		requests.MessageTemplateStore  		true "Payload of the message template"
+// @Success      201 		{object}	responses.MessageTemplateResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /message-templates [post]
+func (h *MessageTemplateHandler) Store(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.MessageTemplateStore
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall body [%s] into [%T]", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateStore(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while storing message template [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while storing message template")
+	}
+
+	messageTemplate, err := h.service.Store(ctx, request.ToStoreParams(h.userFromContext(c)))
+	if err != nil {
+		msg := fmt.Sprintf("cannot store message template with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseCreated(c, "message template created successfully", messageTemplate)
+}
+
+// Update an entities.MessageTemplate
+// @Summary      Update a message template
+// @Description  Update a message template of the authenticated user
+// @Security	 ApiKeyAuth
+// @Tags         MessageTemplates
+// @Accept       json
+// @Produce      json
+// @Param 		 messageTemplateID	path		string 							true 	"ID of the message template" 	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        payload   			body 		requests.MessageTemplateUpdate  true 	"Payload of message template to update"
+// @Success      200 		{object}	responses.MessageTemplateResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /message-templates/{messageTemplateID} 	[put]
+func (h *MessageTemplateHandler) Update(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.MessageTemplateUpdate
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params [%s] into [%T]", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.MessageTemplateID = c.Params("messageTemplateID")
+	if errors := h.validator.ValidateUpdate(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while updating message template [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while updating message template")
+	}
+
+	messageTemplate, err := h.service.Update(ctx, request.ToUpdateParams(h.userFromContext(c)))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message template with ID [%s]", request.MessageTemplateID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot update message template with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "message template updated successfully", messageTemplate)
+}
+
+// Delete an entities.MessageTemplate
+// @Summary      Delete a message template
+// @Description  Delete a message template of the authenticated user
+// @Security	 ApiKeyAuth
+// @Tags         MessageTemplates
+// @Accept       json
+// @Produce      json
+// @Param 		 messageTemplateID 	path		string 				true 	"ID of the message template"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      204		{object}    responses.NoContent
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /message-templates/{messageTemplateID} [delete]
+func (h *MessageTemplateHandler) Delete(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	messageTemplateID := c.Params("messageTemplateID")
+	if errors := h.validator.ValidateUUID(ctx, messageTemplateID, "messageTemplateID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while deleting message template with ID [%s]", spew.Sdump(errors), messageTemplateID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while deleting message template")
+	}
+
+	err := h.service.Delete(ctx, h.userIDFomContext(c), uuid.MustParse(messageTemplateID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message template with ID [%s]", messageTemplateID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete message template with ID [%s]", messageTemplateID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseNoContent(c, "message template deleted successfully")
+}
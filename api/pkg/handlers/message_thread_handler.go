@@ -3,6 +3,7 @@ package handlers
 import (
 	"fmt"
 
+	"github.com/NdoleStudio/httpsms/pkg/entities"
 	"github.com/NdoleStudio/httpsms/pkg/repositories"
 	"github.com/google/uuid"
 
@@ -55,9 +56,10 @@ func (h *MessageThreadHandler) RegisterRoutes(router fiber.Router) {
 // @Produce      json
 // @Param        owner	query  string  	true 	"owner phone number" 						default(+18005550199)
 // @Param        skip	query  int  	false	"number of messages to skip"				minimum(0)
+// @Param        cursor	query  string  	false	"the next_cursor of the previous page, it is used instead of skip"
 // @Param        query	query  string  	false 	"filter message threads containing query"
 // @Param        limit	query  int  	false	"number of messages to return"				minimum(1)	maximum(20)
-// @Success      200 	{object}	responses.MessageThreadsResponse
+// @Success      200 	{object}	responses.MessageThreadsCursorResponse
 // @Failure      400	{object}	responses.BadRequest
 // @Failure 	 401    {object}	responses.Unauthorized
 // @Failure      422	{object}	responses.UnprocessableEntity
@@ -84,14 +86,22 @@ func (h *MessageThreadHandler) Index(c *fiber.Ctx) error {
 		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching message threads")
 	}
 
-	threads, err := h.service.GetThreads(ctx, request.ToGetParams(h.userIDFomContext(c)))
+	params := request.ToGetParams(h.userIDFomContext(c))
+	limit := params.Limit
+	// fetch one more item than the limit to know if there is a next page
+	params.Limit++
+	threads, err := h.service.GetThreads(ctx, params)
 	if err != nil {
 		msg := fmt.Sprintf("cannot get message threads with params [%+#v]", request)
 		ctxLogger.Error(stacktrace.Propagate(err, msg))
 		return h.responseInternalServerError(c)
 	}
 
-	return h.responseOK(c, fmt.Sprintf("fetched %d message %s", len(*threads), h.pluralize("thread", len(*threads))), threads)
+	var cursor *repositories.Cursor
+	*threads, cursor = nextCursor(*threads, limit, func(thread entities.MessageThread) *repositories.Cursor {
+		return repositories.NewCursor(thread.OrderTimestamp, thread.ID.String())
+	})
+	return h.responseOKWithCursor(c, fmt.Sprintf("fetched %d message %s", len(*threads), h.pluralize("thread", len(*threads))), threads, cursor)
 }
 
 // Update an entities.MessageThread
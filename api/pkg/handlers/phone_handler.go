@@ -3,6 +3,8 @@ package handlers
 import (
 	"fmt"
 
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
 	"github.com/NdoleStudio/httpsms/pkg/requests"
 	"github.com/NdoleStudio/httpsms/pkg/validators"
 	"github.com/davecgh/go-spew/spew"
@@ -52,9 +54,10 @@ func (h *PhoneHandler) RegisterRoutes(router fiber.Router) {
 // @Accept       json
 // @Produce      json
 // @Param        skip		query  int  	false	"number of heartbeats to skip"		minimum(0)
+// @Param        cursor		query  string  	false	"the next_cursor of the previous page, it is used instead of skip"
 // @Param        query		query  string  	false 	"filter phones containing query"
 // @Param        limit		query  int  	false	"number of phones to return"		minimum(1)	maximum(20)
-// @Success      200 		{object}	responses.PhonesResponse
+// @Success      200 		{object}	responses.PhonesCursorResponse
 // @Failure      400		{object}	responses.BadRequest
 // @Failure 	 401    	{object}	responses.Unauthorized
 // @Failure      422		{object}	responses.UnprocessableEntity
@@ -79,14 +82,22 @@ func (h *PhoneHandler) Index(c *fiber.Ctx) error {
 		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching phones")
 	}
 
-	phones, err := h.service.Index(ctx, h.userFromContext(c), request.ToIndexParams())
+	params := request.ToIndexParams()
+	limit := params.Limit
+	// fetch one more item than the limit to know if there is a next page
+	params.Limit++
+	phones, err := h.service.Index(ctx, h.userFromContext(c), params)
 	if err != nil {
 		msg := fmt.Sprintf("cannot index phones with params [%+#v]", request)
 		ctxLogger.Error(stacktrace.Propagate(err, msg))
 		return h.responseInternalServerError(c)
 	}
 
-	return h.responseOK(c, fmt.Sprintf("fetched %d %s", len(*phones), h.pluralize("phone", len(*phones))), phones)
+	var cursor *repositories.Cursor
+	*phones, cursor = nextCursor(*phones, limit, func(phone entities.Phone) *repositories.Cursor {
+		return repositories.NewCursor(phone.CreatedAt, phone.ID.String())
+	})
+	return h.responseOKWithCursor(c, fmt.Sprintf("fetched %d %s", len(*phones), h.pluralize("phone", len(*phones))), phones, cursor)
 }
 
 // Upsert a phone
@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// ScheduledMessageHandler handles scheduled message http requests
+type ScheduledMessageHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	validator *validators.ScheduledMessageHandlerValidator
+	service   *services.ScheduledMessageService
+}
+
+// NewScheduledMessageHandler creates a new ScheduledMessageHandler
+func NewScheduledMessageHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	validator *validators.ScheduledMessageHandlerValidator,
+	service *services.ScheduledMessageService,
+) (h *ScheduledMessageHandler) {
+	return &ScheduledMessageHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		validator: validator,
+		service:   service,
+	}
+}
+
+// RegisterRoutes registers the routes for the ScheduledMessageHandler
+func (h *ScheduledMessageHandler) RegisterRoutes(router fiber.Router) {
+	router.Get("/scheduled-messages", h.Index)
+	router.Post("/scheduled-messages", h.Store)
+	router.Get("/scheduled-messages/:scheduledMessageID", h.Show)
+	router.Post("/scheduled-messages/:scheduledMessageID/pause", h.Pause)
+	router.Post("/scheduled-messages/:scheduledMessageID/resume", h.Resume)
+	router.Delete("/scheduled-messages/:scheduledMessageID", h.Delete)
+}
+
+// Index returns the scheduled messages of a user
+// @Summary      Get scheduled messages of a user
+// @Description  Get the scheduled message rules which are evaluated when the phones of a user receive a message
+// @Security	 ApiKeyAuth
+// @Tags         ScheduledMessages
+// @Accept       json
+// @Produce      json
+// @Param        owner		query  string  	false 	"filter scheduled messages of a phone number"	default(+18005550199)
+// @Param        skip		query  int  	false	"number of scheduled messages to skip"		minimum(0)
+// @Param        query		query  string  	false 	"filter scheduled messages containing query"
+// @Param        limit		query  int  	false	"number of scheduled messages to return"	minimum(1)	maximum(100)
+// @Success      200 		{object}	responses.ScheduledMessagesResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /scheduled-messages 	[get]
+func (h *ScheduledMessageHandler) Index(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.ScheduledMessageIndex
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall URL [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateIndex(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching scheduled messages [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching scheduled messages")
+	}
+
+	scheduledMessages, err := h.service.Index(ctx, h.userIDFomContext(c), request.Owner, request.ToIndexParams())
+	if err != nil {
+		msg := fmt.Sprintf("cannot get scheduled messages with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d scheduled %s", len(scheduledMessages), h.pluralize("message", len(scheduledMessages))), scheduledMessages)
+}
+
+// Show returns a scheduled message
+// @Summary      Get a scheduled message
+// @Description  Get a scheduled message of the authenticated user by ID
+// @Security	 ApiKeyAuth
+// @Tags         ScheduledMessages
+// @Accept       json
+// @Produce      json
+// @Param 		 scheduledMessageID 	path		string 				true 	"ID of the scheduled message"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{object}	responses.ScheduledMessageResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /scheduled-messages/{scheduledMessageID} [get]
+func (h *ScheduledMessageHandler) Show(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	scheduledMessageID := c.Params("scheduledMessageID")
+	if errors := h.validator.ValidateUUID(ctx, scheduledMessageID, "scheduledMessageID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching scheduled message with ID [%s]", spew.Sdump(errors), scheduledMessageID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching scheduled message")
+	}
+
+	scheduledMessage, err := h.service.Load(ctx, h.userIDFomContext(c), uuid.MustParse(scheduledMessageID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find scheduled message with ID [%s]", scheduledMessageID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load scheduled message with ID [%s]", scheduledMessageID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "scheduled message fetched successfully", scheduledMessage)
+}
+
+// Store an entities.ScheduledMessage
+// @Summary      Store a scheduled message
+// @Description  Store a message which is sent on a recurring schedule defined by a cron expression or an RRULE in the timezone of the user
+// @Security	 ApiKeyAuth
+// @Tags         ScheduledMessages
+// @Accept       json
+// @Produce      json
+// @Param        payload   	body 		requests.ScheduledMessageStore  		true "Payload of the scheduled message"
+// @Success      201 		{object}	responses.ScheduledMessageResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /scheduled-messages [post]
+func (h *ScheduledMessageHandler) Store(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.ScheduledMessageStore
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall body [%s] into [%T]", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateStore(ctx, h.userIDFomContext(c), request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while storing scheduled message [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while storing scheduled message")
+	}
+
+	scheduledMessage, err := h.service.Store(ctx, request.ToStoreParams(h.userFromContext(c), c.OriginalURL()))
+	if err != nil {
+		msg := fmt.Sprintf("cannot store scheduled message with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseCreated(c, "scheduled message created successfully", scheduledMessage)
+}
+
+// Pause an entities.ScheduledMessage
+// @Summary      Pause a scheduled message
+// @Description  Pause a scheduled message of the authenticated user. No message is sent until the scheduled message is resumed
+// @Security	 ApiKeyAuth
+// @Tags         ScheduledMessages
+// @Accept       json
+// @Produce      json
+// @Param 		 scheduledMessageID 	path		string 				true 	"ID of the scheduled message"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{object}	responses.ScheduledMessageResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /scheduled-messages/{scheduledMessageID}/pause [post]
+func (h *ScheduledMessageHandler) Pause(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	scheduledMessageID := c.Params("scheduledMessageID")
+	if errors := h.validator.ValidateUUID(ctx, scheduledMessageID, "scheduledMessageID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while pausing scheduled message with ID [%s]", spew.Sdump(errors), scheduledMessageID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while pausing scheduled message")
+	}
+
+	scheduledMessage, err := h.service.Pause(ctx, h.userIDFomContext(c), uuid.MustParse(scheduledMessageID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find scheduled message with ID [%s]", scheduledMessageID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot pause scheduled message with ID [%s]", scheduledMessageID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "scheduled message paused successfully", scheduledMessage)
+}
+
+// Resume an entities.ScheduledMessage
+// @Summary      Resume a scheduled message
+// @Description  Resume a paused scheduled message of the authenticated user from its next occurrence
+// @Security	 ApiKeyAuth
+// @Tags         ScheduledMessages
+// @Accept       json
+// @Produce      json
+// @Param 		 scheduledMessageID 	path		string 				true 	"ID of the scheduled message"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{object}	responses.ScheduledMessageResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /scheduled-messages/{scheduledMessageID}/resume [post]
+func (h *ScheduledMessageHandler) Resume(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	scheduledMessageID := c.Params("scheduledMessageID")
+	if errors := h.validator.ValidateUUID(ctx, scheduledMessageID, "scheduledMessageID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while resuming scheduled message with ID [%s]", spew.Sdump(errors), scheduledMessageID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while resuming scheduled message")
+	}
+
+	scheduledMessage, err := h.service.Resume(ctx, c.OriginalURL(), h.userIDFomContext(c), uuid.MustParse(scheduledMessageID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find scheduled message with ID [%s]", scheduledMessageID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot resume scheduled message with ID [%s]", scheduledMessageID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "scheduled message resumed successfully", scheduledMessage)
+}
+
+// Delete an entities.ScheduledMessage
+// @Summary      Delete a scheduled message
+// @Description  Delete a scheduled message of the authenticated user. The messages which were already sent are not deleted
+// @Security	 ApiKeyAuth
+// @Tags         ScheduledMessages
+// @Accept       json
+// @Produce      json
+// @Param 		 scheduledMessageID 	path		string 				true 	"ID of the scheduled message"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      204		{object}    responses.NoContent
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /scheduled-messages/{scheduledMessageID} [delete]
+func (h *ScheduledMessageHandler) Delete(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	scheduledMessageID := c.Params("scheduledMessageID")
+	if errors := h.validator.ValidateUUID(ctx, scheduledMessageID, "scheduledMessageID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while deleting scheduled message with ID [%s]", spew.Sdump(errors), scheduledMessageID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while deleting scheduled message")
+	}
+
+	err := h.service.Delete(ctx, h.userIDFomContext(c), uuid.MustParse(scheduledMessageID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find scheduled message with ID [%s]", scheduledMessageID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete scheduled message with ID [%s]", scheduledMessageID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseNoContent(c, "scheduled message deleted successfully")
+}
@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// SenderPoolHandler handles sender pool http requests
+type SenderPoolHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	validator *validators.SenderPoolHandlerValidator
+	service   *services.SenderPoolService
+}
+
+// NewSenderPoolHandler creates a new SenderPoolHandler
+func NewSenderPoolHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	validator *validators.SenderPoolHandlerValidator,
+	service *services.SenderPoolService,
+) (h *SenderPoolHandler) {
+	return &SenderPoolHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		validator: validator,
+		service:   service,
+	}
+}
+
+// RegisterRoutes registers the routes for the SenderPoolHandler
+func (h *SenderPoolHandler) RegisterRoutes(router fiber.Router) {
+	router.Get("/sender-pools", h.Index)
+	router.Post("/sender-pools", h.Store)
+	router.Get("/sender-pools/:senderPoolID", h.Show)
+	router.Put("/sender-pools/:senderPoolID", h.Update)
+	router.Delete("/sender-pools/:senderPoolID", h.Delete)
+}
+
+// Index returns the sender pools of a user
+// @Summary      Get sender pools of a user
+// @Description  Get the sender pools of a user
+// @Security	 ApiKeyAuth
+// @Tags         SenderPools
+// @Accept       json
+// @Produce      json
+// @Param        skip		query  int  	false	"number of sender pools to skip"		minimum(0)
+// @Param        query		query  string  	false 	"filter sender pools with a name containing query"
+// @Param        limit		query  int  	false	"number of sender pools to return"	minimum(1)	maximum(100)
+// @Success      200 		{object}	responses.SenderPoolsResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /sender-pools 	[get]
+func (h *SenderPoolHandler) Index(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.SenderPoolIndex
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall URL [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateIndex(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching sender pools [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching sender pools")
+	}
+
+	pools, err := h.service.Index(ctx, h.userIDFomContext(c), request.ToIndexParams())
+	if err != nil {
+		msg := fmt.Sprintf("cannot get sender pools with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d %s", len(pools), h.pluralize("sender pool", len(pools))), pools)
+}
+
+// Show returns a sender pool
+// @Summary      Get a sender pool
+// @Description  Get a sender pool of the authenticated user by ID
+// @Security	 ApiKeyAuth
+// @Tags         SenderPools
+// @Accept       json
+// @Produce      json
+// @Param 		 senderPoolID 	path		string 				true 	"ID of the sender pool"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{object}	responses.SenderPoolResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /sender-pools/{senderPoolID} [get]
+func (h *SenderPoolHandler) Show(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	senderPoolID := c.Params("senderPoolID")
+	if errors := h.validator.ValidateUUID(ctx, senderPoolID, "senderPoolID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching sender pool with ID [%s]", spew.Sdump(errors), senderPoolID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching sender pool")
+	}
+
+	pool, err := h.service.Load(ctx, h.userIDFomContext(c), uuid.MustParse(senderPoolID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find sender pool with ID [%s]", senderPoolID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load sender pool with ID [%s]", senderPoolID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "sender pool fetched successfully", pool)
+}
+
+// Store an entities.SenderPool
+// @Summary      Store a sender pool
+// @Description  Store a pool of phones which share the messages sent with the ID of the pool as the "from" number. A message is sent by the phone which last talked to the recipient, otherwise by the online phone with the smallest backlog of pending messages.
+// @Security	 ApiKeyAuth
+// @Tags         SenderPools
+// @Accept       json
+// @Produce      json
+// @Param        payload   	body 		requests.SenderPoolStore  		true "Payload of the sender pool"
+// @Success      201 		{object}	responses.SenderPoolResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /sender-pools [post]
+func (h *SenderPoolHandler) Store(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.SenderPoolStore
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall body [%s] into [%T]", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateStore(ctx, h.userIDFomContext(c), request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while storing sender pool [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while storing sender pool")
+	}
+
+	pool, err := h.service.Store(ctx, request.ToStoreParams(h.userFromContext(c)))
+	if err != nil {
+		msg := fmt.Sprintf("cannot store sender pool with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseCreated(c, "sender pool created successfully", pool)
+}
+
+// Update an entities.SenderPool
+// @Summary      Update a sender pool
+// @Description  Update a sender pool of the authenticated user. The phone numbers replace the existing phones of the pool
+// @Security	 ApiKeyAuth
+// @Tags         SenderPools
+// @Accept       json
+// @Produce      json
+// @Param 		 senderPoolID	path		string 					true 	"ID of the sender pool" 	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        payload   	body 		requests.SenderPoolUpdate  true 	"Payload of sender pool to update"
+// @Success      200 		{object}	responses.SenderPoolResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /sender-pools/{senderPoolID} 	[put]
+func (h *SenderPoolHandler) Update(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.SenderPoolUpdate
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params [%s] into [%T]", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.SenderPoolID = c.Params("senderPoolID")
+	if errors := h.validator.ValidateUpdate(ctx, h.userIDFomContext(c), request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while updating sender pool [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while updating sender pool")
+	}
+
+	pool, err := h.service.Update(ctx, request.ToUpdateParams(h.userFromContext(c)))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find sender pool with ID [%s]", request.SenderPoolID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot update sender pool with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "sender pool updated successfully", pool)
+}
+
+// Delete an entities.SenderPool
+// @Summary      Delete a sender pool
+// @Description  Delete a sender pool of the authenticated user
+// @Security	 ApiKeyAuth
+// @Tags         SenderPools
+// @Accept       json
+// @Produce      json
+// @Param 		 senderPoolID 	path		string 				true 	"ID of the sender pool"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      204		{object}    responses.NoContent
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /sender-pools/{senderPoolID} [delete]
+func (h *SenderPoolHandler) Delete(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	senderPoolID := c.Params("senderPoolID")
+	if errors := h.validator.ValidateUUID(ctx, senderPoolID, "senderPoolID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while deleting sender pool with ID [%s]", spew.Sdump(errors), senderPoolID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while deleting sender pool")
+	}
+
+	err := h.service.Delete(ctx, h.userIDFomContext(c), uuid.MustParse(senderPoolID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find sender pool with ID [%s]", senderPoolID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete sender pool with ID [%s]", senderPoolID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseNoContent(c, "sender pool deleted successfully")
+}
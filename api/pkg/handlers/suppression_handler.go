@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// SuppressionHandler handles suppression list http requests
+type SuppressionHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	validator *validators.SuppressionHandlerValidator
+	service   *services.SuppressionService
+}
+
+// NewSuppressionHandler creates a new SuppressionHandler
+func NewSuppressionHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	validator *validators.SuppressionHandlerValidator,
+	service *services.SuppressionService,
+) (h *SuppressionHandler) {
+	return &SuppressionHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		validator: validator,
+		service:   service,
+	}
+}
+
+// RegisterRoutes registers the routes for the SuppressionHandler
+func (h *SuppressionHandler) RegisterRoutes(router fiber.Router) {
+	router.Get("/suppressions", h.Index)
+	router.Post("/suppressions", h.Store)
+	router.Delete("/suppressions/:phoneNumber", h.Delete)
+}
+
+// Index returns the suppression list of a user
+// @Summary      Get the suppression list of a user
+// @Description  Get the phone numbers which opted out of receiving messages from the authenticated user
+// @Security	 ApiKeyAuth
+// @Tags         Suppressions
+// @Accept       json
+// @Produce      json
+// @Param        skip		query  int  	false	"number of suppressions to skip"		minimum(0)
+// @Param        query		query  string  	false 	"filter suppressions with a phone number or reason containing query"
+// @Param        limit		query  int  	false	"number of suppressions to return"	minimum(1)	maximum(100)
+// @Success      200 		{object}	responses.SuppressionsResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /suppressions 	[get]
+func (h *SuppressionHandler) Index(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.SuppressionIndex
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall URL [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateIndex(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching suppressions [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching suppressions")
+	}
+
+	suppressions, err := h.service.Index(ctx, h.userIDFomContext(c), request.ToIndexParams())
+	if err != nil {
+		msg := fmt.Sprintf("cannot get suppressions with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d %s", len(suppressions), h.pluralize("suppression", len(suppressions))), suppressions)
+}
+
+// Store adds a phone number to the suppression list
+// @Summary      Add a phone number to the suppression list
+// @Description  Add a phone number to the suppression list of the authenticated user. Messages cannot be sent to phone numbers on the suppression list.
+// @Security	 ApiKeyAuth
+// @Tags         Suppressions
+// @Accept       json
+// @Produce      json
+// @Param        payload   	body 		requests.SuppressionStore  		true "Payload of the suppression"
+// @Success      201 		{object}	responses.SuppressionResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /suppressions [post]
+func (h *SuppressionHandler) Store(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.SuppressionStore
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall body [%s] into [%T]", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateStore(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while storing suppression [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while storing suppression")
+	}
+
+	suppression, err := h.service.Store(ctx, request.ToStoreParams(h.userFromContext(c), c.OriginalURL()))
+	if err != nil {
+		msg := fmt.Sprintf("cannot store suppression with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseCreated(c, "phone number added to the suppression list", suppression)
+}
+
+// Delete removes a phone number from the suppression list
+// @Summary      Remove a phone number from the suppression list
+// @Description  Remove a phone number from the suppression list of the authenticated user so it can receive messages again
+// @Security	 ApiKeyAuth
+// @Tags         Suppressions
+// @Accept       json
+// @Produce      json
+// @Param 		 phoneNumber 	path		string 				true 	"The suppressed phone number"	default(+18005550100)
+// @Success      204		{object}    responses.NoContent
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /suppressions/{phoneNumber} [delete]
+func (h *SuppressionHandler) Delete(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	request := requests.SuppressionDelete{PhoneNumber: c.Params("phoneNumber")}
+	if errors := h.validator.ValidateDelete(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while deleting suppression [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while deleting suppression")
+	}
+
+	err := h.service.Delete(ctx, request.ToDeleteParams(h.userFromContext(c), c.OriginalURL()))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("the phone number [%s] is not on the suppression list", request.PhoneNumber))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete suppression with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseNoContent(c, "phone number removed from the suppression list")
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"fmt"
 
+	"github.com/NdoleStudio/httpsms/pkg/entities"
 	"github.com/NdoleStudio/httpsms/pkg/repositories"
 
 	"github.com/NdoleStudio/httpsms/pkg/requests"
@@ -47,6 +48,10 @@ func (h *WebhookHandler) RegisterRoutes(app *fiber.App, middlewares ...fiber.Han
 	router.Post("/", h.computeRoute(middlewares, h.Store)...)
 	router.Put("/:webhookID", h.computeRoute(middlewares, h.Update)...)
 	router.Delete("/:webhookID", h.computeRoute(middlewares, h.Delete)...)
+	router.Post("/:webhookID/test", h.computeRoute(middlewares, h.Test)...)
+	router.Get("/:webhookID/deliveries", h.computeRoute(middlewares, h.IndexDeliveries)...)
+	router.Get("/:webhookID/dead-letters", h.computeRoute(middlewares, h.IndexDeadLetters)...)
+	router.Post("/:webhookID/dead-letters/:deadLetterID/redeliver", h.computeRoute(middlewares, h.Redeliver)...)
 }
 
 // Index returns the webhooks of a user
@@ -57,9 +62,10 @@ func (h *WebhookHandler) RegisterRoutes(app *fiber.App, middlewares ...fiber.Han
 // @Accept       json
 // @Produce      json
 // @Param        skip		query  int  	false	"number of webhooks to skip"		minimum(0)
+// @Param        cursor		query  string  	false	"the next_cursor of the previous page, it is used instead of skip"
 // @Param        query		query  string  	false 	"filter webhooks containing query"
 // @Param        limit		query  int  	false	"number of webhooks to return"	minimum(1)	maximum(20)
-// @Success      200 		{object}	responses.WebhooksResponse
+// @Success      200 		{object}	responses.WebhooksCursorResponse
 // @Failure      400		{object}	responses.BadRequest
 // @Failure 	 401	    {object}	responses.Unauthorized
 // @Failure      422		{object}	responses.UnprocessableEntity
@@ -82,14 +88,21 @@ func (h *WebhookHandler) Index(c *fiber.Ctx) error {
 		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching webhooks")
 	}
 
-	webhooks, err := h.service.Index(ctx, h.userIDFomContext(c), request.ToIndexParams())
+	params := request.ToIndexParams()
+	limit := params.Limit
+	// fetch one more item than the limit to know if there is a next page
+	params.Limit++
+	webhooks, err := h.service.Index(ctx, h.userIDFomContext(c), params)
 	if err != nil {
 		msg := fmt.Sprintf("cannot get webhooks with params [%+#v]", request)
 		ctxLogger.Error(stacktrace.Propagate(err, msg))
 		return h.responseInternalServerError(c)
 	}
 
-	return h.responseOK(c, fmt.Sprintf("fetched %d %s", len(webhooks), h.pluralize("webhook", len(webhooks))), webhooks)
+	webhooks, cursor := nextCursor(webhooks, limit, func(webhook *entities.Webhook) *repositories.Cursor {
+		return repositories.NewCursor(webhook.CreatedAt, webhook.ID.String())
+	})
+	return h.responseOKWithCursor(c, fmt.Sprintf("fetched %d %s", len(webhooks), h.pluralize("webhook", len(webhooks))), webhooks, cursor)
 }
 
 // Delete a webhook
@@ -223,3 +236,196 @@ func (h *WebhookHandler) Update(c *fiber.Ctx) error {
 
 	return h.responseOK(c, "webhook updated successfully", user)
 }
+
+// Test sends a sample event to a webhook
+// @Summary      Test a webhook
+// @Description  Send a sample event to a webhook and return the status code, headers and body of the response
+// @Security	 ApiKeyAuth
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Param 		 webhookID	path		string 					true 	"ID of the webhook" 	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        payload   	body 		requests.WebhookTest  	true 	"Type of the sample event"
+// @Success      200 		{object}	responses.WebhookTestResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /webhooks/{webhookID}/test [post]
+func (h *WebhookHandler) Test(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.WebhookTest
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params [%s] into [%T]", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.WebhookID = c.Params("webhookID")
+	if errors := h.validator.ValidateTest(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while testing webhook [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while testing webhook")
+	}
+
+	result, err := h.service.Test(ctx, request.ToTestParams(c.OriginalURL(), h.userFromContext(c)))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find webhook with ID [%s]", request.WebhookID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot test webhook with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("sample [%s] event sent to webhook", result.EventType), result)
+}
+
+// IndexDeliveries returns the requests made to a webhook
+// @Summary      Get webhook delivery attempts
+// @Description  Get every request made to a webhook with the response status code, latency and a truncated response body
+// @Security	 ApiKeyAuth
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Param 		 webhookID	path		string 	true 	"ID of the webhook" 					default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        skip		query  		int  	false	"number of attempts to skip"			minimum(0)
+// @Param        query		query  		string  false 	"filter attempts by event ID or response body"
+// @Param        limit		query  		int  	false	"number of attempts to return"			minimum(1)	maximum(100)
+// @Param        status		query  		string  false 	"filter attempts by status"				Enums(succeeded, failed)
+// @Param        event_type	query  		string  false 	"filter attempts by event type"			example(message.phone.received)
+// @Success      200 		{object}	responses.WebhookDeliveryAttemptsResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /webhooks/{webhookID}/deliveries [get]
+func (h *WebhookHandler) IndexDeliveries(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.WebhookDeliveryIndex
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall URL [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.WebhookID = c.Params("webhookID")
+	if errors := h.validator.ValidateDeliveryIndex(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching webhook deliveries [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching webhook deliveries")
+	}
+
+	attempts, err := h.service.IndexDeliveries(ctx, request.ToIndexParams(h.userIDFomContext(c)))
+	if err != nil {
+		msg := fmt.Sprintf("cannot get webhook deliveries with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d %s", len(attempts), h.pluralize("delivery attempt", len(attempts))), attempts)
+}
+
+// IndexDeadLetters returns the events which could not be delivered to a webhook
+// @Summary      Get webhook dead letters
+// @Description  Get the events which could not be delivered to a webhook after all retry attempts
+// @Security	 ApiKeyAuth
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Param 		 webhookID	path		string 	true 	"ID of the webhook" 					default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        skip		query  		int  	false	"number of dead letters to skip"		minimum(0)
+// @Param        query		query  		string  false 	"filter dead letters by event type or event ID"
+// @Param        limit		query  		int  	false	"number of dead letters to return"		minimum(1)	maximum(100)
+// @Success      200 		{object}	responses.WebhookDeadLettersResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /webhooks/{webhookID}/dead-letters [get]
+func (h *WebhookHandler) IndexDeadLetters(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.WebhookDeadLetterIndex
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall URL [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.WebhookID = c.Params("webhookID")
+	if errors := h.validator.ValidateDeadLetterIndex(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching webhook dead letters [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching webhook dead letters")
+	}
+
+	deadLetters, err := h.service.IndexDeadLetters(ctx, h.userIDFomContext(c), request.WebhookUUID(), request.ToIndexParams())
+	if err != nil {
+		msg := fmt.Sprintf("cannot get webhook dead letters with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d %s", len(deadLetters), h.pluralize("dead letter", len(deadLetters))), deadLetters)
+}
+
+// Redeliver sends a dead letter to the webhook again
+// @Summary      Redeliver a webhook dead letter
+// @Description  Move an event which could not be delivered back into the delivery queue of the webhook
+// @Security	 ApiKeyAuth
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Param 		 webhookID		path		string 	true 	"ID of the webhook" 		default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param 		 deadLetterID	path		string 	true 	"ID of the dead letter" 	default(32343a19-da5e-4b1b-a767-3298a73703cb)
+// @Success      200 			{object}	responses.WebhookDeliveryResponse
+// @Failure      400			{object}	responses.BadRequest
+// @Failure 	 401	    	{object}	responses.Unauthorized
+// @Failure      404			{object}	responses.NotFound
+// @Failure      422			{object}	responses.UnprocessableEntity
+// @Failure      500			{object}	responses.InternalServerError
+// @Router       /webhooks/{webhookID}/dead-letters/{deadLetterID}/redeliver [post]
+func (h *WebhookHandler) Redeliver(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	webhookID := c.Params("webhookID")
+	if errors := h.validator.ValidateUUID(ctx, webhookID, "webhookID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while redelivering dead letter for webhook [%s]", spew.Sdump(errors), webhookID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while redelivering dead letter")
+	}
+
+	deadLetterID := c.Params("deadLetterID")
+	if errors := h.validator.ValidateUUID(ctx, deadLetterID, "deadLetterID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while redelivering dead letter with ID [%s]", spew.Sdump(errors), deadLetterID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while redelivering dead letter")
+	}
+
+	delivery, err := h.service.Redeliver(ctx, &services.WebhookRedeliverParams{
+		Source:       c.OriginalURL(),
+		UserID:       h.userIDFomContext(c),
+		WebhookID:    uuid.MustParse(webhookID),
+		DeadLetterID: uuid.MustParse(deadLetterID),
+	})
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find dead letter with ID [%s]", deadLetterID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot redeliver dead letter with ID [%s] for webhook [%s]", deadLetterID, webhookID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "dead letter scheduled for redelivery", delivery)
+}
@@ -0,0 +1,75 @@
+package listeners
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// BulkMessageJobListener validates and sends the rows of an entities.BulkMessageJob in the background
+type BulkMessageJobListener struct {
+	logger  telemetry.Logger
+	tracer  telemetry.Tracer
+	service *services.BulkMessageJobService
+}
+
+// NewBulkMessageJobListener creates a new instance of BulkMessageJobListener
+func NewBulkMessageJobListener(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.BulkMessageJobService,
+) (l *BulkMessageJobListener, routes map[string]events.EventListener) {
+	l = &BulkMessageJobListener{
+		logger:  logger.WithService(fmt.Sprintf("%T", l)),
+		tracer:  tracer,
+		service: service,
+	}
+
+	return l, map[string]events.EventListener{
+		events.EventTypeBulkMessageJobCreated:  l.onBulkMessageJobCreated,
+		events.EventTypeBulkMessageJobBatchDue: l.onBulkMessageJobBatchDue,
+	}
+}
+
+// onBulkMessageJobCreated handles the events.EventTypeBulkMessageJobCreated event
+func (listener *BulkMessageJobListener) onBulkMessageJobCreated(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	payload := new(events.BulkMessageJobCreatedPayload)
+	if err := event.DataAs(payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := listener.service.Validate(ctx, event.Source(), payload); err != nil {
+		msg := fmt.Sprintf("cannot handle [%s] event with ID [%s] and userID [%s]", event.Type(), event.ID(), payload.UserID)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// onBulkMessageJobBatchDue handles the events.EventTypeBulkMessageJobBatchDue event
+func (listener *BulkMessageJobListener) onBulkMessageJobBatchDue(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	payload := new(events.BulkMessageJobBatchDuePayload)
+	if err := event.DataAs(payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := listener.service.SendBatch(ctx, event.Source(), payload); err != nil {
+		msg := fmt.Sprintf("cannot handle [%s] event with ID [%s] and userID [%s]", event.Type(), event.ID(), payload.UserID)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
@@ -43,6 +43,7 @@ func NewMessageListener(
 		events.EventTypeMessageNotificationScheduled: l.onMessageNotificationScheduled,
 		events.MessageThreadAPIDeleted:               l.onMessageThreadAPIDeleted,
 		events.MessageCallMissed:                     l.onMessageCallMissed,
+		events.EventTypeMessagePhoneReceived:         l.onMessagePhoneReceived,
 	}
 }
 
@@ -284,6 +285,15 @@ func (listener *MessageListener) onMessageNotificationScheduled(ctx context.Cont
 		Source:    event.Source(),
 		Timestamp: payload.ScheduledAt,
 	}
+
+	if payload.Deferred {
+		if err := listener.service.HandleMessageNotificationDeferred(ctx, expiredParams); err != nil {
+			msg := fmt.Sprintf("cannot handle deferred event [%s] for ID [%s] and userID [%s]", event.Type(), expiredParams.ID, expiredParams.UserID)
+			return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+		return nil
+	}
+
 	if err := listener.service.HandleMessageNotificationScheduled(ctx, expiredParams); err != nil {
 		msg := fmt.Sprintf("cannot handle event [%s] for ID [%s] and userID [%s]", event.Type(), expiredParams.ID, expiredParams.UserID)
 		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
@@ -329,3 +339,22 @@ func (listener *MessageListener) onMessageCallMissed(ctx context.Context, event
 
 	return nil
 }
+
+// onMessagePhoneReceived handles the events.EventTypeMessagePhoneReceived event
+func (listener *MessageListener) onMessagePhoneReceived(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	payload := new(events.MessagePhoneReceivedPayload)
+	if err := event.DataAs(payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := listener.service.RespondToReceivedMessage(ctx, event.Source(), payload); err != nil {
+		msg := fmt.Sprintf("cannot handle [%s] event with ID [%s] and userID [%s]", event.Type(), event.ID(), payload.UserID)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
@@ -42,6 +42,7 @@ func NewMessageThreadListener(
 		events.EventTypeMessagePhoneReceived:         l.OnMessagePhoneReceived,
 		events.EventTypeMessageNotificationScheduled: l.onMessageNotificationScheduled,
 		events.EventTypeMessageSendExpired:           l.onMessageExpired,
+		events.EventTypeAutoReplyTriggered:           l.onAutoReplyTriggered,
 	}
 }
 
@@ -296,6 +297,36 @@ func (listener *MessageThreadListener) onMessageExpired(ctx context.Context, eve
 	return nil
 }
 
+// onAutoReplyTriggered handles the events.EventTypeAutoReplyTriggered event
+func (listener *MessageThreadListener) onAutoReplyTriggered(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.AutoReplyTriggeredPayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if payload.Action != entities.AutoReplyActionTag || payload.Tag == nil {
+		return nil
+	}
+
+	tagParams := services.MessageThreadTagParams{
+		UserID:  payload.UserID,
+		Owner:   payload.Owner,
+		Contact: payload.Contact,
+		Tag:     *payload.Tag,
+	}
+
+	if err := listener.service.AddTag(ctx, tagParams); err != nil {
+		msg := fmt.Sprintf("cannot tag thread for message with ID [%s] for event with ID [%s]", payload.MessageID, event.ID())
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
 func (listener *MessageThreadListener) updateThread(ctx context.Context, params services.MessageThreadUpdateParams) error {
 	return listener.service.UpdateThread(ctx, params)
 }
@@ -34,6 +34,8 @@ func NewNotificationListener(
 
 	return l, map[string]events.EventListener{
 		events.EventTypeMessageAPISent:          l.onMessageAPISent,
+		events.EventTypeMessageAPICancelled:     l.onMessageAPICancelled,
+		events.EventTypeMessageAPIRescheduled:   l.onMessageAPIRescheduled,
 		events.EventTypeMessageSendRetry:        l.onMessageSendRetry,
 		events.EventTypeMessageNotificationSend: l.onMessageNotificationSend,
 		events.PhoneHeartbeatMissed:             l.onPhoneHeartbeatMissed,
@@ -60,6 +62,8 @@ func (listener *PhoneNotificationListener) onMessageAPISent(ctx context.Context,
 		Encrypted: payload.Encrypted,
 		Source:    event.Source(),
 		MessageID: payload.MessageID,
+
+		ScheduledSendTime: payload.ScheduledSendTime,
 	}
 
 	if err := listener.service.Schedule(ctx, sendParams); err != nil {
@@ -70,6 +74,57 @@ func (listener *PhoneNotificationListener) onMessageAPISent(ctx context.Context,
 	return nil
 }
 
+// onMessageAPICancelled handles the events.EventTypeMessageAPICancelled event
+func (listener *PhoneNotificationListener) onMessageAPICancelled(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.MessageAPICancelledPayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := listener.service.Cancel(ctx, payload.MessageID); err != nil {
+		msg := fmt.Sprintf("cannot cancel notifications for message [%s] for event with ID [%s]", payload.MessageID, event.ID())
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// onMessageAPIRescheduled handles the events.EventTypeMessageAPIRescheduled event
+func (listener *PhoneNotificationListener) onMessageAPIRescheduled(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.MessageAPIRescheduledPayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	sendParams := &services.PhoneNotificationScheduleParams{
+		UserID:    payload.UserID,
+		Owner:     payload.Owner,
+		Contact:   payload.Contact,
+		Content:   payload.Content,
+		SIM:       payload.SIM,
+		Encrypted: payload.Encrypted,
+		Source:    event.Source(),
+		MessageID: payload.MessageID,
+
+		ScheduledSendTime: &payload.ScheduledSendTime,
+	}
+
+	if err := listener.service.Reschedule(ctx, sendParams); err != nil {
+		msg := fmt.Sprintf("cannot reschedule notification with params [%s] for event with ID [%s]", spew.Sdump(sendParams), event.ID())
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
 // onMessageSendRetry handles the events.EventTypeMessageSendRetry event
 func (listener *PhoneNotificationListener) onMessageSendRetry(ctx context.Context, event cloudevents.Event) error {
 	ctx, span := listener.tracer.Start(ctx)
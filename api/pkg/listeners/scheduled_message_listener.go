@@ -0,0 +1,55 @@
+package listeners
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// ScheduledMessageListener sends the occurrences of an entities.ScheduledMessage when they are due
+type ScheduledMessageListener struct {
+	logger  telemetry.Logger
+	tracer  telemetry.Tracer
+	service *services.ScheduledMessageService
+}
+
+// NewScheduledMessageListener creates a new instance of ScheduledMessageListener
+func NewScheduledMessageListener(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.ScheduledMessageService,
+) (l *ScheduledMessageListener, routes map[string]events.EventListener) {
+	l = &ScheduledMessageListener{
+		logger:  logger.WithService(fmt.Sprintf("%T", l)),
+		tracer:  tracer,
+		service: service,
+	}
+
+	return l, map[string]events.EventListener{
+		events.EventTypeScheduledMessageDue: l.onScheduledMessageDue,
+	}
+}
+
+// onScheduledMessageDue handles the events.EventTypeScheduledMessageDue event
+func (listener *ScheduledMessageListener) onScheduledMessageDue(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	payload := new(events.ScheduledMessageDuePayload)
+	if err := event.DataAs(payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := listener.service.HandleDue(ctx, event.Source(), payload); err != nil {
+		msg := fmt.Sprintf("cannot handle [%s] event with ID [%s] and userID [%s]", event.Type(), event.ID(), payload.UserID)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/NdoleStudio/httpsms/pkg/entities"
 	"github.com/NdoleStudio/httpsms/pkg/events"
 	"github.com/NdoleStudio/httpsms/pkg/services"
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
@@ -30,7 +31,7 @@ func NewWebhookListener(
 		service: service,
 	}
 
-	return l, map[string]events.EventListener{
+	routes = map[string]events.EventListener{
 		events.EventTypeMessagePhoneReceived:  l.OnMessagePhoneReceived,
 		events.EventTypeMessageSendExpired:    l.OnMessageSendExpired,
 		events.EventTypeMessagePhoneDelivered: l.OnMessagePhoneDelivered,
@@ -39,7 +40,22 @@ func NewWebhookListener(
 		events.EventTypePhoneHeartbeatOnline:  l.onPhoneHeartbeatOnline,
 		events.EventTypePhoneHeartbeatOffline: l.onPhoneHeartbeatOffline,
 		events.MessageCallMissed:              l.onMessageCallMissed,
+		events.EventTypeWebhookDeliveryRetry:  l.onWebhookDeliveryRetry,
 	}
+
+	for _, eventType := range events.WebhookEventTypes() {
+		if _, ok := routes[eventType]; !ok {
+			routes[eventType] = l.onWebhookEvent
+		}
+	}
+
+	return l, routes
+}
+
+// webhookEventPayload contains the fields which are common to the payloads of all webhook events
+type webhookEventPayload struct {
+	UserID entities.UserID `json:"user_id"`
+	Owner  string          `json:"owner"`
 }
 
 // OnMessagePhoneReceived handles the events.EventTypeMessagePhoneReceived event
@@ -193,3 +209,41 @@ func (listener *WebhookListener) onMessageCallMissed(ctx context.Context, event
 
 	return nil
 }
+
+// onWebhookEvent handles the events in events.WebhookEventTypes which don't have a dedicated listener
+func (listener *WebhookListener) onWebhookEvent(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload webhookEventPayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := listener.service.Send(ctx, payload.UserID, event, payload.Owner); err != nil {
+		msg := fmt.Sprintf("cannot process [%s] event with ID [%s]", event.Type(), event.ID())
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// onWebhookDeliveryRetry handles the events.EventTypeWebhookDeliveryRetry event
+func (listener *WebhookListener) onWebhookDeliveryRetry(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.WebhookDeliveryRetryPayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := listener.service.Retry(ctx, &payload); err != nil {
+		msg := fmt.Sprintf("cannot process [%s] event with ID [%s]", event.Type(), event.ID())
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package middlewares
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/dustin/go-humanize"
+	"github.com/gofiber/fiber/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// BodyLimit rejects requests with a body larger than the limit. The skipPaths are not limited because they have a
+// higher limit on their route which is only applied after the request is authenticated e.g. for file uploads.
+// The fiber.App must stream request bodies so that large bodies are not read into memory before they are rejected.
+func BodyLimit(tracer telemetry.Tracer, logger telemetry.Logger, maxSize int, skipPaths ...string) fiber.Handler {
+	skip := map[string]bool{}
+	for _, path := range skipPaths {
+		skip[path] = true
+	}
+
+	return func(c *fiber.Ctx) error {
+		if skip[c.Path()] {
+			return c.Next()
+		}
+
+		_, span, ctxLogger := tracer.StartFromFiberCtxWithLogger(c, logger, "middlewares.BodyLimit")
+		defer span.End()
+
+		if c.Request().Header.ContentLength() > maxSize {
+			return bodyTooLarge(c, maxSize)
+		}
+
+		// chunked requests have no content length so the stream is read up to the limit
+		if c.Request().IsBodyStream() && c.Request().Header.ContentLength() < 0 {
+			body, err := io.ReadAll(io.LimitReader(c.Context().RequestBodyStream(), int64(maxSize)+1))
+			if err != nil {
+				ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot read the body of request [%s %s]", c.Method(), c.OriginalURL())))
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"status":  "error",
+					"message": "The request body cannot be read.",
+				})
+			}
+			if len(body) > maxSize {
+				return bodyTooLarge(c, maxSize)
+			}
+			c.Request().SetBody(body)
+		}
+
+		return c.Next()
+	}
+}
+
+func bodyTooLarge(c *fiber.Ctx, limit int) error {
+	return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+		"status":  "error",
+		"message": fmt.Sprintf("The request body must be less than %s.", humanize.Bytes(uint64(limit))),
+	})
+}
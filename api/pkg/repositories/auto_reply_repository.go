@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// AutoReplyRepository loads and persists an entities.AutoReply
+type AutoReplyRepository interface {
+	// Save Upsert a new entities.AutoReply
+	Save(ctx context.Context, reply *entities.AutoReply) error
+
+	// Index entities.AutoReply by entities.UserID
+	Index(ctx context.Context, userID entities.UserID, owner string, params IndexParams) ([]*entities.AutoReply, error)
+
+	// Load an entities.AutoReply by ID.
+	Load(ctx context.Context, userID entities.UserID, autoReplyID uuid.UUID) (*entities.AutoReply, error)
+
+	// Delete an entities.AutoReply
+	Delete(ctx context.Context, userID entities.UserID, autoReplyID uuid.UUID) error
+
+	// FetchActive returns the active entities.AutoReply of a phone ordered by priority
+	FetchActive(ctx context.Context, userID entities.UserID, owner string) ([]*entities.AutoReply, error)
+}
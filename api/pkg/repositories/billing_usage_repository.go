@@ -9,8 +9,8 @@ import (
 
 // BillingUsageRepository loads and persists an entities.BillingUsage
 type BillingUsageRepository interface {
-	// RegisterSentMessage registers a message as sent
-	RegisterSentMessage(ctx context.Context, timestamp time.Time, user entities.UserID) error
+	// RegisterSentMessage registers a message with the number of SMS segments as sent
+	RegisterSentMessage(ctx context.Context, timestamp time.Time, segments uint, user entities.UserID) error
 
 	// RegisterReceivedMessage registers a message as received
 	RegisterReceivedMessage(ctx context.Context, timestamp time.Time, user entities.UserID) error
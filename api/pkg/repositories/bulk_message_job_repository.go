@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// BulkMessageJobRepository loads and persists an entities.BulkMessageJob
+type BulkMessageJobRepository interface {
+	// Store a new entities.BulkMessageJob
+	Store(ctx context.Context, job *entities.BulkMessageJob) error
+
+	// Update an existing entities.BulkMessageJob
+	Update(ctx context.Context, job *entities.BulkMessageJob) error
+
+	// Load an entities.BulkMessageJob by ID
+	Load(ctx context.Context, userID entities.UserID, jobID uuid.UUID) (*entities.BulkMessageJob, error)
+
+	// StoreRows stores the outcomes of the rows in the file of an entities.BulkMessageJob
+	StoreRows(ctx context.Context, rows []*entities.BulkMessageJobRow) error
+
+	// PendingRows fetches the next entities.BulkMessageJobRow which are waiting to be queued ordered by row number
+	PendingRows(ctx context.Context, jobID uuid.UUID, limit int) ([]*entities.BulkMessageJobRow, error)
+
+	// UpdateRow updates the outcome of an entities.BulkMessageJobRow
+	UpdateRow(ctx context.Context, row *entities.BulkMessageJobRow) error
+
+	// DeleteRows deletes all the rows of an entities.BulkMessageJob
+	DeleteRows(ctx context.Context, jobID uuid.UUID) error
+
+	// Rows fetches the entities.BulkMessageJobRow ordered by row number with the latest status of the queued messages
+	Rows(ctx context.Context, jobID uuid.UUID, params IndexParams) ([]*entities.BulkMessageJobRow, error)
+
+	// Counts the rows of an entities.BulkMessageJob by status
+	Counts(ctx context.Context, jobID uuid.UUID) (map[string]int, error)
+}
@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// ContactGroupRepository loads and persists an entities.ContactGroup
+type ContactGroupRepository interface {
+	// Save Upsert a new entities.ContactGroup
+	Save(ctx context.Context, group *entities.ContactGroup) error
+
+	// Index entities.ContactGroup by entities.UserID
+	Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.ContactGroup, error)
+
+	// Load an entities.ContactGroup by ID.
+	Load(ctx context.Context, userID entities.UserID, groupID uuid.UUID) (*entities.ContactGroup, error)
+
+	// Delete an entities.ContactGroup
+	Delete(ctx context.Context, userID entities.UserID, groupID uuid.UUID) error
+}
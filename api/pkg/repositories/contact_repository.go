@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// ContactRepository loads and persists an entities.Contact
+type ContactRepository interface {
+	// Save Upsert a new entities.Contact
+	Save(ctx context.Context, contact *entities.Contact) error
+
+	// SaveAll upserts the entities.Contact in a single transaction so that either all or none of them are saved
+	SaveAll(ctx context.Context, contacts []*entities.Contact) error
+
+	// Index entities.Contact by entities.UserID with an optional tag
+	Index(ctx context.Context, userID entities.UserID, tag string, params IndexParams) ([]*entities.Contact, error)
+
+	// Load an entities.Contact by ID.
+	Load(ctx context.Context, userID entities.UserID, contactID uuid.UUID) (*entities.Contact, error)
+
+	// Delete an entities.Contact
+	Delete(ctx context.Context, userID entities.UserID, contactID uuid.UUID) error
+
+	// FetchAll loads all the entities.Contact of a user
+	FetchAll(ctx context.Context, userID entities.UserID) ([]*entities.Contact, error)
+
+	// FetchByIDs loads the entities.Contact with the given IDs
+	FetchByIDs(ctx context.Context, userID entities.UserID, contactIDs []uuid.UUID) ([]*entities.Contact, error)
+
+	// FetchByTags loads the entities.Contact having at least one of the tags
+	FetchByTags(ctx context.Context, userID entities.UserID, tags []string) ([]*entities.Contact, error)
+
+	// FetchByPhoneNumbers loads the entities.Contact having at least one of the phone numbers
+	FetchByPhoneNumbers(ctx context.Context, userID entities.UserID, phoneNumbers []string) ([]*entities.Contact, error)
+}
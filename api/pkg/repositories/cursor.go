@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/palantir/stacktrace"
+)
+
+// Cursor is the position of the last item of a page when paginating by a timestamp in descending order.
+// The ID breaks ties between items with the same timestamp.
+type Cursor struct {
+	Timestamp time.Time `json:"t"`
+	ID        string    `json:"i"`
+}
+
+// NewCursor creates a Cursor pointing to an item
+func NewCursor(timestamp time.Time, id string) *Cursor {
+	return &Cursor{Timestamp: timestamp, ID: id}
+}
+
+// String encodes the Cursor as an opaque token which is safe to use in a URL
+func (cursor *Cursor) String() string {
+	payload, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+// ParseCursor decodes a token created by Cursor.String
+func ParseCursor(token string) (*Cursor, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "cannot decode cursor [%s]", token)
+	}
+
+	cursor := new(Cursor)
+	if err = json.Unmarshal(payload, cursor); err != nil {
+		return nil, stacktrace.Propagate(err, "cannot unmarshal cursor [%s]", token)
+	}
+
+	if cursor.Timestamp.IsZero() || cursor.ID == "" {
+		return nil, stacktrace.NewError("cursor [%s] does not have a timestamp and an ID", token)
+	}
+
+	return cursor, nil
+}
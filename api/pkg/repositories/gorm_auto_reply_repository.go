@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
+)
+
+// gormAutoReplyRepository is responsible for persisting entities.AutoReply
+type gormAutoReplyRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormAutoReplyRepository creates the GORM version of the AutoReplyRepository
+func NewGormAutoReplyRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) AutoReplyRepository {
+	return &gormAutoReplyRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormAutoReplyRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+func (repository *gormAutoReplyRepository) Save(ctx context.Context, reply *entities.AutoReply) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Save(reply).Error; err != nil {
+		msg := fmt.Sprintf("cannot save auto reply with ID [%s]", reply.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormAutoReplyRepository) Index(ctx context.Context, userID entities.UserID, owner string, params IndexParams) ([]*entities.AutoReply, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).Where("user_id = ?", userID)
+	if owner != "" {
+		query.Where("owner = ?", owner)
+	}
+
+	if len(params.Query) > 0 {
+		queryPattern := "%" + params.Query + "%"
+		query.Where(repository.db.Where("name ILIKE ?", queryPattern).Or("pattern ILIKE ?", queryPattern))
+	}
+
+	replies := make([]*entities.AutoReply, 0)
+	if err := query.Order("priority ASC").Order("created_at DESC").Limit(params.Limit).Offset(params.Skip).Find(&replies).Error; err != nil {
+		msg := fmt.Sprintf("cannot fetch auto replies for user [%s] and params [%+#v]", userID, params)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return replies, nil
+}
+
+func (repository *gormAutoReplyRepository) Load(ctx context.Context, userID entities.UserID, autoReplyID uuid.UUID) (*entities.AutoReply, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	reply := new(entities.AutoReply)
+	err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Where("id = ?", autoReplyID).First(reply).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		msg := fmt.Sprintf("auto reply with ID [%s] for user [%s] does not exist", autoReplyID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load auto reply with ID [%s] for user [%s]", autoReplyID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return reply, nil
+}
+
+func (repository *gormAutoReplyRepository) Delete(ctx context.Context, userID entities.UserID, autoReplyID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("id = ?", autoReplyID).
+		Delete(&entities.AutoReply{}).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete auto reply with ID [%s] and userID [%s]", autoReplyID, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormAutoReplyRepository) FetchActive(ctx context.Context, userID entities.UserID, owner string) ([]*entities.AutoReply, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	replies := make([]*entities.AutoReply, 0)
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("owner = ?", owner).
+		Where("is_active = ?", true).
+		Order("priority ASC").
+		Order("created_at ASC").
+		Find(&replies).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch active auto replies for user [%s] and owner [%s]", userID, owner)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return replies, nil
+}
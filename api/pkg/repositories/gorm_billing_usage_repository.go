@@ -35,8 +35,8 @@ func NewGormBillingUsageRepository(
 	}
 }
 
-// RegisterSentMessage registers a message as sent
-func (repository *gormBillingUsageRepository) RegisterSentMessage(ctx context.Context, timestamp time.Time, userID entities.UserID) error {
+// RegisterSentMessage registers a message with the number of SMS segments as sent
+func (repository *gormBillingUsageRepository) RegisterSentMessage(ctx context.Context, timestamp time.Time, segments uint, userID entities.UserID) error {
 	ctx, span := repository.tracer.Start(ctx)
 	defer span.End()
 
@@ -46,10 +46,15 @@ func (repository *gormBillingUsageRepository) RegisterSentMessage(ctx context.Co
 				Model(&entities.BillingUsage{}).
 				Where("start_timestamp = ?", now.New(timestamp).BeginningOfMonth()).
 				Where("user_id = ?", userID).
-				UpdateColumn("sent_messages", gorm.Expr("sent_messages + ?", 1))
+				UpdateColumns(map[string]any{
+					"sent_messages": gorm.Expr("sent_messages + ?", 1),
+					"sent_segments": gorm.Expr("sent_segments + ?", segments),
+				})
 
 			if result.Error == nil && result.RowsAffected == 0 {
-				return tx.Create(repository.createBillingUsage(userID, timestamp, 1, 0)).Error
+				usage := repository.createBillingUsage(userID, timestamp, 1, 0)
+				usage.SentSegments = segments
+				return tx.Create(usage).Error
 			}
 			return result.Error
 		},
@@ -115,12 +120,11 @@ func (repository *gormBillingUsageRepository) GetHistory(ctx context.Context, us
 
 	usages := new([]entities.BillingUsage)
 
-	err := repository.db.WithContext(ctx).
+	query := repository.db.WithContext(ctx).
 		Where("user_id = ?", userID).
-		Where("start_timestamp != ?", now.BeginningOfMonth()).
-		Order("start_timestamp DESC").
-		Limit(params.Limit).
-		Offset(params.Skip).
+		Where("start_timestamp != ?", now.BeginningOfMonth())
+
+	err := paginate(query, "start_timestamp", params).
 		Find(&usages).
 		Error
 	if err != nil {
@@ -0,0 +1,195 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
+)
+
+// bulkMessageJobRowsBatchSize is the number of rows inserted in a single statement
+const bulkMessageJobRowsBatchSize = 1000
+
+// gormBulkMessageJobRepository is responsible for persisting entities.BulkMessageJob
+type gormBulkMessageJobRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormBulkMessageJobRepository creates the GORM version of the BulkMessageJobRepository
+func NewGormBulkMessageJobRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) BulkMessageJobRepository {
+	return &gormBulkMessageJobRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormBulkMessageJobRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+func (repository *gormBulkMessageJobRepository) Store(ctx context.Context, job *entities.BulkMessageJob) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Create(job).Error; err != nil {
+		msg := fmt.Sprintf("cannot store bulk message job with ID [%s]", job.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormBulkMessageJobRepository) Update(ctx context.Context, job *entities.BulkMessageJob) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Save(job).Error; err != nil {
+		msg := fmt.Sprintf("cannot update bulk message job with ID [%s]", job.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormBulkMessageJobRepository) Load(ctx context.Context, userID entities.UserID, jobID uuid.UUID) (*entities.BulkMessageJob, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	job := new(entities.BulkMessageJob)
+	err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Where("id = ?", jobID).First(job).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		msg := fmt.Sprintf("bulk message job with ID [%s] for user [%s] does not exist", jobID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load bulk message job with ID [%s] for user [%s]", jobID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return job, nil
+}
+
+func (repository *gormBulkMessageJobRepository) StoreRows(ctx context.Context, rows []*entities.BulkMessageJobRow) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if len(rows) == 0 {
+		return nil
+	}
+
+	if err := repository.db.WithContext(ctx).CreateInBatches(rows, bulkMessageJobRowsBatchSize).Error; err != nil {
+		msg := fmt.Sprintf("cannot store [%d] rows for bulk message job with ID [%s]", len(rows), rows[0].JobID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormBulkMessageJobRepository) PendingRows(ctx context.Context, jobID uuid.UUID, limit int) ([]*entities.BulkMessageJobRow, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	rows := make([]*entities.BulkMessageJobRow, 0, limit)
+	err := repository.db.WithContext(ctx).
+		Where("job_id = ?", jobID).
+		Where("status = ?", entities.BulkMessageJobRowStatusPending).
+		Order("row_number ASC").
+		Limit(limit).
+		Find(&rows).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch [%d] pending rows of bulk message job with ID [%s]", limit, jobID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return rows, nil
+}
+
+func (repository *gormBulkMessageJobRepository) UpdateRow(ctx context.Context, row *entities.BulkMessageJobRow) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Save(row).Error; err != nil {
+		msg := fmt.Sprintf("cannot update row [%d] of bulk message job with ID [%s]", row.Row, row.JobID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormBulkMessageJobRepository) DeleteRows(ctx context.Context, jobID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("job_id = ?", jobID).Delete(&entities.BulkMessageJobRow{}).Error; err != nil {
+		msg := fmt.Sprintf("cannot delete the rows of bulk message job with ID [%s]", jobID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormBulkMessageJobRepository) Rows(ctx context.Context, jobID uuid.UUID, params IndexParams) ([]*entities.BulkMessageJobRow, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	rows := make([]*entities.BulkMessageJobRow, 0)
+	err := repository.withMessages(ctx, jobID).
+		Select(
+			"bulk_message_job_rows.id, bulk_message_job_rows.job_id, bulk_message_job_rows.row_number, bulk_message_job_rows.owner, " +
+				"bulk_message_job_rows.contact, bulk_message_job_rows.message_id, bulk_message_job_rows.created_at, " +
+				"COALESCE(messages.status, bulk_message_job_rows.status) AS status, " +
+				"COALESCE(messages.failure_reason, bulk_message_job_rows.reason) AS reason",
+		).
+		Order("bulk_message_job_rows.row_number ASC").
+		Limit(params.Limit).
+		Offset(params.Skip).
+		Scan(&rows).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch rows of bulk message job with ID [%s] and params [%+#v]", jobID, params)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return rows, nil
+}
+
+func (repository *gormBulkMessageJobRepository) Counts(ctx context.Context, jobID uuid.UUID) (map[string]int, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	var results []struct {
+		Status string
+		Total  int
+	}
+
+	err := repository.withMessages(ctx, jobID).
+		Select("COALESCE(messages.status, bulk_message_job_rows.status) AS status, COUNT(*) AS total").
+		Group("COALESCE(messages.status, bulk_message_job_rows.status)").
+		Scan(&results).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot count rows of bulk message job with ID [%s]", jobID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	counts := map[string]int{}
+	for _, result := range results {
+		counts[result.Status] = result.Total
+	}
+	return counts, nil
+}
+
+// withMessages joins the rows of a job with the messages which were created for them
+func (repository *gormBulkMessageJobRepository) withMessages(ctx context.Context, jobID uuid.UUID) *gorm.DB {
+	return repository.db.WithContext(ctx).
+		Table("bulk_message_job_rows").
+		Joins("LEFT JOIN messages ON messages.id = bulk_message_job_rows.message_id").
+		Where("bulk_message_job_rows.job_id = ?", jobID)
+}
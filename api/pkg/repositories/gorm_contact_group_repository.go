@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
+)
+
+// gormContactGroupRepository is responsible for persisting entities.ContactGroup
+type gormContactGroupRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormContactGroupRepository creates the GORM version of the ContactGroupRepository
+func NewGormContactGroupRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) ContactGroupRepository {
+	return &gormContactGroupRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormContactGroupRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+func (repository *gormContactGroupRepository) Save(ctx context.Context, group *entities.ContactGroup) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Save(group).Error; err != nil {
+		msg := fmt.Sprintf("cannot save contact group with ID [%s]", group.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormContactGroupRepository) Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.ContactGroup, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).Where("user_id = ?", userID)
+	if len(params.Query) > 0 {
+		queryPattern := "%" + params.Query + "%"
+		query.Where(repository.db.Where("name ILIKE ?", queryPattern).Or("description ILIKE ?", queryPattern))
+	}
+
+	groups := make([]*entities.ContactGroup, 0)
+	if err := query.Order("created_at DESC").Limit(params.Limit).Offset(params.Skip).Find(&groups).Error; err != nil {
+		msg := fmt.Sprintf("cannot fetch contact groups for user [%s] and params [%+#v]", userID, params)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return groups, nil
+}
+
+func (repository *gormContactGroupRepository) Load(ctx context.Context, userID entities.UserID, groupID uuid.UUID) (*entities.ContactGroup, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	group := new(entities.ContactGroup)
+	err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Where("id = ?", groupID).First(group).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		msg := fmt.Sprintf("contact group with ID [%s] for user [%s] does not exist", groupID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load contact group with ID [%s] for user [%s]", groupID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return group, nil
+}
+
+func (repository *gormContactGroupRepository) Delete(ctx context.Context, userID entities.UserID, groupID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("id = ?", groupID).
+		Delete(&entities.ContactGroup{}).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete contact group with ID [%s] and userID [%s]", groupID, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
@@ -0,0 +1,206 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/cockroachdb/cockroach-go/v2/crdb/crdbgorm"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
+)
+
+// gormContactRepository is responsible for persisting entities.Contact
+type gormContactRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormContactRepository creates the GORM version of the ContactRepository
+func NewGormContactRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) ContactRepository {
+	return &gormContactRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormContactRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+func (repository *gormContactRepository) Save(ctx context.Context, contact *entities.Contact) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Save(contact).Error; err != nil {
+		msg := fmt.Sprintf("cannot save contact with ID [%s]", contact.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// contactSaveBatchSize is the number of contacts which are upserted in a single statement
+const contactSaveBatchSize = 500
+
+func (repository *gormContactRepository) SaveAll(ctx context.Context, contacts []*entities.Contact) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if len(contacts) == 0 {
+		return nil
+	}
+
+	err := crdbgorm.ExecuteTx(ctx, repository.db, nil, func(tx *gorm.DB) error {
+		for start := 0; start < len(contacts); start += contactSaveBatchSize {
+			batch := contacts[start:min(start+contactSaveBatchSize, len(contacts))]
+			if err := tx.WithContext(ctx).Save(&batch).Error; err != nil {
+				return stacktrace.Propagate(err, fmt.Sprintf("cannot save batch of [%d] contacts starting at [%d]", len(batch), start))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot save [%d] contacts", len(contacts))
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormContactRepository) Index(ctx context.Context, userID entities.UserID, tag string, params IndexParams) ([]*entities.Contact, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).Where("user_id = ?", userID)
+	if tag != "" {
+		query.Where("CAST(? as TEXT) = ANY(tags)", tag)
+	}
+
+	if len(params.Query) > 0 {
+		queryPattern := "%" + params.Query + "%"
+		query.Where(
+			repository.db.Where("name ILIKE ?", queryPattern).
+				Or("notes ILIKE ?", queryPattern).
+				Or("array_to_string(phone_numbers, ',') ILIKE ?", queryPattern),
+		)
+	}
+
+	contacts := make([]*entities.Contact, 0)
+	if err := query.Order("name ASC").Order("created_at DESC").Limit(params.Limit).Offset(params.Skip).Find(&contacts).Error; err != nil {
+		msg := fmt.Sprintf("cannot fetch contacts for user [%s] and params [%+#v]", userID, params)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return contacts, nil
+}
+
+func (repository *gormContactRepository) Load(ctx context.Context, userID entities.UserID, contactID uuid.UUID) (*entities.Contact, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	contact := new(entities.Contact)
+	err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Where("id = ?", contactID).First(contact).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		msg := fmt.Sprintf("contact with ID [%s] for user [%s] does not exist", contactID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load contact with ID [%s] for user [%s]", contactID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return contact, nil
+}
+
+func (repository *gormContactRepository) Delete(ctx context.Context, userID entities.UserID, contactID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("id = ?", contactID).
+		Delete(&entities.Contact{}).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete contact with ID [%s] and userID [%s]", contactID, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormContactRepository) FetchAll(ctx context.Context, userID entities.UserID) ([]*entities.Contact, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	contacts := make([]*entities.Contact, 0)
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Order("name ASC").Find(&contacts).Error; err != nil {
+		msg := fmt.Sprintf("cannot fetch all contacts for user [%s]", userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return contacts, nil
+}
+
+func (repository *gormContactRepository) FetchByIDs(ctx context.Context, userID entities.UserID, contactIDs []uuid.UUID) ([]*entities.Contact, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	contacts := make([]*entities.Contact, 0)
+	if len(contactIDs) == 0 {
+		return contacts, nil
+	}
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Where("id IN ?", contactIDs).Find(&contacts).Error; err != nil {
+		msg := fmt.Sprintf("cannot fetch [%d] contacts by ID for user [%s]", len(contactIDs), userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return contacts, nil
+}
+
+func (repository *gormContactRepository) FetchByTags(ctx context.Context, userID entities.UserID, tags []string) ([]*entities.Contact, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	contacts := make([]*entities.Contact, 0)
+	if len(tags) == 0 {
+		return contacts, nil
+	}
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Where("tags && ?", pq.StringArray(tags)).Find(&contacts).Error; err != nil {
+		msg := fmt.Sprintf("cannot fetch contacts with tags [%v] for user [%s]", tags, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return contacts, nil
+}
+
+func (repository *gormContactRepository) FetchByPhoneNumbers(ctx context.Context, userID entities.UserID, phoneNumbers []string) ([]*entities.Contact, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	contacts := make([]*entities.Contact, 0)
+	if len(phoneNumbers) == 0 {
+		return contacts, nil
+	}
+
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("phone_numbers && ?", pq.StringArray(phoneNumbers)).
+		Order("created_at ASC").
+		Find(&contacts).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch contacts with [%d] phone numbers for user [%s]", len(phoneNumbers), userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return contacts, nil
+}
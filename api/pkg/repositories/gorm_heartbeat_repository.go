@@ -73,7 +73,7 @@ func (repository *gormHeartbeatRepository) Index(ctx context.Context, userID ent
 	}
 
 	heartbeats := new([]entities.Heartbeat)
-	if err := query.Order("timestamp DESC").Limit(params.Limit).Offset(params.Skip).Find(&heartbeats).Error; err != nil {
+	if err := paginate(query, "timestamp", params).Find(&heartbeats).Error; err != nil {
 		msg := fmt.Sprintf("cannot fetch heartbeats with owner [%s] and params [%+#v]", owner, params)
 		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
+)
+
+// gormMessageExportRepository is responsible for persisting entities.MessageExport
+type gormMessageExportRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormMessageExportRepository creates the GORM version of the MessageExportRepository
+func NewGormMessageExportRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) MessageExportRepository {
+	return &gormMessageExportRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormMessageExportRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+func (repository *gormMessageExportRepository) Store(ctx context.Context, export *entities.MessageExport) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Create(export).Error; err != nil {
+		msg := fmt.Sprintf("cannot store message export with ID [%s]", export.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormMessageExportRepository) Update(ctx context.Context, export *entities.MessageExport) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Save(export).Error; err != nil {
+		msg := fmt.Sprintf("cannot update message export with ID [%s]", export.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormMessageExportRepository) Load(ctx context.Context, exportID uuid.UUID) (*entities.MessageExport, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	export := new(entities.MessageExport)
+	err := repository.db.WithContext(ctx).Where("id = ?", exportID).First(export).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		msg := fmt.Sprintf("message export with ID [%s] does not exist", exportID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load message export with ID [%s]", exportID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return export, nil
+}
@@ -84,7 +84,7 @@ func (repository *gormMessageRepository) Index(ctx context.Context, userID entit
 	}
 
 	messages := new([]entities.Message)
-	if err := query.Order("order_timestamp DESC").Limit(params.Limit).Offset(params.Skip).Find(&messages).Error; err != nil {
+	if err := paginate(query, "order_timestamp", params).Find(&messages).Error; err != nil {
 		msg := fmt.Sprintf("cannot fetch messges with owner [%s] and contact [%s] and params [%+#v]", owner, contact, params)
 		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
@@ -92,6 +92,119 @@ func (repository *gormMessageRepository) Index(ctx context.Context, userID entit
 	return messages, nil
 }
 
+// Search entities.Message of a user with full-text search, newest first
+func (repository *gormMessageRepository) Search(ctx context.Context, userID entities.UserID, params MessageSearchParams) (*[]entities.Message, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).Where("user_id = ?", userID)
+	if params.Query != "" {
+		// this expression must match the idx_messages_content_search index, encrypted is not a parameter so that
+		// the planner can use the partial index with a generic plan of a prepared statement
+		query.Where("encrypted = false").
+			Where("to_tsvector('simple', content) @@ websearch_to_tsquery('simple', ?)", params.Query)
+	}
+	if params.Owner != "" {
+		query.Where("owner = ?", params.Owner)
+	}
+	if params.Contact != "" {
+		query.Where("contact = ?", params.Contact)
+	}
+	if params.Status != "" {
+		query.Where("status = ?", params.Status)
+	}
+	if params.Type != "" {
+		query.Where("type = ?", params.Type)
+	}
+	if params.Encrypted != nil {
+		query.Where("encrypted = ?", *params.Encrypted)
+	}
+	if params.StartDate != nil {
+		query.Where("order_timestamp >= ?", *params.StartDate)
+	}
+	if params.EndDate != nil {
+		query.Where("order_timestamp <= ?", *params.EndDate)
+	}
+	if params.Cursor != nil {
+		query.Where("(order_timestamp, id) < (?, ?)", params.Cursor.Timestamp, params.Cursor.ID)
+	}
+
+	messages := new([]entities.Message)
+	if err := query.Order("order_timestamp DESC").Order("id DESC").Limit(params.Limit).Find(messages).Error; err != nil {
+		msg := fmt.Sprintf("cannot search messages for user [%s] with params [%+#v]", userID, params)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return messages, nil
+}
+
+// Count the entities.Message which match the MessageExportParams
+func (repository *gormMessageRepository) Count(ctx context.Context, userID entities.UserID, params MessageExportParams) (int64, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	var count int64
+	if err := repository.exportQuery(ctx, userID, params).Model(&entities.Message{}).Count(&count).Error; err != nil {
+		msg := fmt.Sprintf("cannot count messages for user [%s] with params [%+#v]", userID, params)
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return count, nil
+}
+
+// Stream entities.Message which match the MessageExportParams in batches, oldest first
+func (repository *gormMessageRepository) Stream(ctx context.Context, userID entities.UserID, params MessageExportParams, batchSize int, callback func(messages []entities.Message) error) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	var cursor *Cursor
+	for {
+		query := repository.exportQuery(ctx, userID, params)
+		if cursor != nil {
+			query.Where("(order_timestamp, id) > (?, ?)", cursor.Timestamp, cursor.ID)
+		}
+
+		messages := make([]entities.Message, 0, batchSize)
+		if err := query.Order("order_timestamp ASC").Order("id ASC").Limit(batchSize).Find(&messages).Error; err != nil {
+			msg := fmt.Sprintf("cannot fetch messages for user [%s] after cursor [%+#v]", userID, cursor)
+			return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+
+		if len(messages) == 0 {
+			return nil
+		}
+
+		if err := callback(messages); err != nil {
+			msg := fmt.Sprintf("cannot handle batch of [%d] messages for user [%s]", len(messages), userID)
+			return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+
+		if len(messages) < batchSize {
+			return nil
+		}
+
+		last := messages[len(messages)-1]
+		cursor = NewCursor(last.OrderTimestamp, last.ID.String())
+	}
+}
+
+func (repository *gormMessageRepository) exportQuery(ctx context.Context, userID entities.UserID, params MessageExportParams) *gorm.DB {
+	query := repository.db.WithContext(ctx).Where("user_id = ?", userID)
+	if params.Owner != "" {
+		query.Where("owner = ?", params.Owner)
+	}
+	if len(params.Contacts) > 0 {
+		query.Where("contact IN ?", params.Contacts)
+	}
+	if params.StartDate != nil {
+		query.Where("order_timestamp >= ?", *params.StartDate)
+	}
+	if params.EndDate != nil {
+		query.Where("order_timestamp <= ?", *params.EndDate)
+	}
+	return query
+}
+
 // Store a new entities.Message
 func (repository *gormMessageRepository) Store(ctx context.Context, message *entities.Message) error {
 	ctx, span := repository.tracer.Start(ctx)
@@ -138,6 +251,28 @@ func (repository *gormMessageRepository) Update(ctx context.Context, message *en
 	return nil
 }
 
+// UpdateIfNotPickedUp saves the message in a single conditional update so a phone which fetches it at the same time is never overwritten
+func (repository *gormMessageRepository) UpdateIfNotPickedUp(ctx context.Context, message *entities.Message) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	result := repository.db.WithContext(ctx).Model(message).
+		Where("status IN ?", []entities.MessageStatus{entities.MessageStatusPending, entities.MessageStatusScheduled}).
+		Select("*").
+		Updates(message)
+	if result.Error != nil {
+		msg := fmt.Sprintf("cannot update message with ID [%s]", message.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(result.Error, msg))
+	}
+
+	if result.RowsAffected == 0 {
+		msg := fmt.Sprintf("message with ID [%s] has already been picked up by the phone", message.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	return nil
+}
+
 // GetOutstanding fetches messages that still to be sent to the phone
 func (repository *gormMessageRepository) GetOutstanding(ctx context.Context, userID entities.UserID, messageID uuid.UUID) (*entities.Message, error) {
 	ctx, span := repository.tracer.Start(ctx)
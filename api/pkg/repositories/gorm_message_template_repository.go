@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
+)
+
+// gormMessageTemplateRepository is responsible for persisting entities.MessageTemplate
+type gormMessageTemplateRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormMessageTemplateRepository creates the GORM version of the MessageTemplateRepository
+func NewGormMessageTemplateRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) MessageTemplateRepository {
+	return &gormMessageTemplateRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormMessageTemplateRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+func (repository *gormMessageTemplateRepository) Save(ctx context.Context, template *entities.MessageTemplate) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Save(template).Error; err != nil {
+		msg := fmt.Sprintf("cannot save message template with ID [%s]", template.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormMessageTemplateRepository) Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.MessageTemplate, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).Where("user_id = ?", userID)
+	if len(params.Query) > 0 {
+		queryPattern := "%" + params.Query + "%"
+		query.Where(repository.db.Where("name ILIKE ?", queryPattern).Or("content ILIKE ?", queryPattern))
+	}
+
+	templates := make([]*entities.MessageTemplate, 0)
+	if err := query.Order("created_at DESC").Limit(params.Limit).Offset(params.Skip).Find(&templates).Error; err != nil {
+		msg := fmt.Sprintf("cannot fetch message templates for user [%s] and params [%+#v]", userID, params)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return templates, nil
+}
+
+func (repository *gormMessageTemplateRepository) Load(ctx context.Context, userID entities.UserID, templateID uuid.UUID) (*entities.MessageTemplate, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	template := new(entities.MessageTemplate)
+	err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Where("id = ?", templateID).First(template).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		msg := fmt.Sprintf("message template with ID [%s] for user [%s] does not exist", templateID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load message template with ID [%s] for user [%s]", templateID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return template, nil
+}
+
+func (repository *gormMessageTemplateRepository) Delete(ctx context.Context, userID entities.UserID, templateID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("id = ?", templateID).
+		Delete(&entities.MessageTemplate{}).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete message template with ID [%s] and userID [%s]", templateID, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
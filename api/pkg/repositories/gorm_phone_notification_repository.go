@@ -53,12 +53,85 @@ func (repository *gormPhoneNotificationRepository) UpdateStatus(ctx context.Cont
 	return nil
 }
 
+// Load an entities.PhoneNotification by ID
+func (repository *gormPhoneNotificationRepository) Load(ctx context.Context, notificationID uuid.UUID) (*entities.PhoneNotification, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	notification := new(entities.PhoneNotification)
+	err := repository.db.WithContext(ctx).Where("id = ?", notificationID).First(notification).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		msg := fmt.Sprintf("notification with ID [%s] does not exist", notificationID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load notification with ID [%s]", notificationID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return notification, nil
+}
+
+// CancelPending entities.PhoneNotification of a message
+func (repository *gormPhoneNotificationRepository) CancelPending(ctx context.Context, messageID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.
+		WithContext(ctx).
+		Model(&entities.PhoneNotification{}).
+		Where("message_id = ?", messageID).
+		Where("status = ?", entities.PhoneNotificationStatusPending).
+		Updates(map[string]any{"status": entities.PhoneNotificationStatusCancelled, "updated_at": time.Now().UTC()}).
+		Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot cancel pending notifications of message [%s]", messageID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// CountPending entities.PhoneNotification of each phone
+func (repository *gormPhoneNotificationRepository) CountPending(ctx context.Context, phoneIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	var results []struct {
+		PhoneID uuid.UUID
+		Total   int
+	}
+
+	err := repository.db.
+		WithContext(ctx).
+		Model(&entities.PhoneNotification{}).
+		Select("phone_id, COUNT(*) AS total").
+		Where("phone_id IN ?", phoneIDs).
+		Where("status = ?", entities.PhoneNotificationStatusPending).
+		Where("scheduled_at <= ?", time.Now().UTC()).
+		Group("phone_id").
+		Scan(&results).
+		Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot count pending notifications of [%d] phones", len(phoneIDs))
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	counts := make(map[uuid.UUID]int, len(phoneIDs))
+	for _, result := range results {
+		counts[result.PhoneID] = result.Total
+	}
+	return counts, nil
+}
+
 // Schedule a notification to be sent in the future
-func (repository *gormPhoneNotificationRepository) Schedule(ctx context.Context, messagesPerMinute uint, notification *entities.PhoneNotification) error {
+func (repository *gormPhoneNotificationRepository) Schedule(ctx context.Context, messagesPerMinute uint, deferral func(scheduledAt time.Time) *time.Time, notification *entities.PhoneNotification) error {
 	ctx, span := repository.tracer.Start(ctx)
 	defer span.End()
 
 	if messagesPerMinute == 0 {
+		notification.ScheduledAt = repository.deferTime(deferral, notification.ScheduledAt)
 		return repository.insert(ctx, notification)
 	}
 
@@ -66,6 +139,7 @@ func (repository *gormPhoneNotificationRepository) Schedule(ctx context.Context,
 		lastNotification := new(entities.PhoneNotification)
 		err := tx.WithContext(ctx).
 			Where("phone_id = ?", notification.PhoneID).
+			Where("status <> ?", entities.PhoneNotificationStatusCancelled).
 			Order("scheduled_at desc").
 			First(lastNotification).
 			Error
@@ -74,14 +148,17 @@ func (repository *gormPhoneNotificationRepository) Schedule(ctx context.Context,
 			return stacktrace.Propagate(err, msg)
 		}
 
-		notification.ScheduledAt = time.Now().UTC()
+		scheduledAt := repository.maxTime(time.Now().UTC(), notification.ScheduledAt)
 		if err == nil {
-			notification.ScheduledAt = repository.maxTime(
-				time.Now().UTC(),
+			scheduledAt = repository.maxTime(
+				scheduledAt,
 				lastNotification.ScheduledAt.Add(time.Duration(60/messagesPerMinute)*time.Second),
 			)
 		}
 
+		// the deferral only moves the notification later so it is still spaced out from the last notification
+		notification.ScheduledAt = repository.deferTime(deferral, scheduledAt)
+
 		if err = tx.WithContext(ctx).Create(notification).Error; err != nil {
 			msg := fmt.Sprintf("cannot create new notification with id [%s] and schedule [%s]", notification.ID, notification.ScheduledAt.String())
 			return stacktrace.Propagate(err, msg)
@@ -96,6 +173,16 @@ func (repository *gormPhoneNotificationRepository) Schedule(ctx context.Context,
 	return nil
 }
 
+func (repository *gormPhoneNotificationRepository) deferTime(deferral func(scheduledAt time.Time) *time.Time, scheduledAt time.Time) time.Time {
+	if deferral == nil {
+		return scheduledAt
+	}
+	if deferredAt := deferral(scheduledAt); deferredAt != nil && deferredAt.After(scheduledAt) {
+		return deferredAt.UTC()
+	}
+	return scheduledAt
+}
+
 func (repository *gormPhoneNotificationRepository) maxTime(a, b time.Time) time.Time {
 	if a.Unix() > b.Unix() {
 		return a
@@ -128,7 +128,7 @@ func (repository *gormPhoneRepository) Index(ctx context.Context, userID entitie
 	}
 
 	phones := new([]entities.Phone)
-	if err := query.Order("created_at DESC").Limit(params.Limit).Offset(params.Skip).Find(&phones).Error; err != nil {
+	if err := paginate(query, "created_at", params).Find(&phones).Error; err != nil {
 		msg := fmt.Sprintf("cannot fetch phones with userID [%s] and params [%+#v]", userID, params)
 		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
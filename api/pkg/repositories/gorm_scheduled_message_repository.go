@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
+)
+
+// gormScheduledMessageRepository is responsible for persisting entities.ScheduledMessage
+type gormScheduledMessageRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormScheduledMessageRepository creates the GORM version of the ScheduledMessageRepository
+func NewGormScheduledMessageRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) ScheduledMessageRepository {
+	return &gormScheduledMessageRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormScheduledMessageRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+func (repository *gormScheduledMessageRepository) Save(ctx context.Context, schedule *entities.ScheduledMessage) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Save(schedule).Error; err != nil {
+		msg := fmt.Sprintf("cannot save scheduled message with ID [%s]", schedule.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormScheduledMessageRepository) Index(ctx context.Context, userID entities.UserID, owner string, params IndexParams) ([]*entities.ScheduledMessage, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).Where("user_id = ?", userID)
+	if owner != "" {
+		query.Where("owner = ?", owner)
+	}
+
+	if len(params.Query) > 0 {
+		queryPattern := "%" + params.Query + "%"
+		query.Where(repository.db.Where("contact ILIKE ?", queryPattern).Or("content ILIKE ?", queryPattern))
+	}
+
+	schedules := make([]*entities.ScheduledMessage, 0)
+	if err := query.Order("created_at DESC").Limit(params.Limit).Offset(params.Skip).Find(&schedules).Error; err != nil {
+		msg := fmt.Sprintf("cannot fetch scheduled messages for user [%s] and params [%+#v]", userID, params)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return schedules, nil
+}
+
+func (repository *gormScheduledMessageRepository) Load(ctx context.Context, userID entities.UserID, scheduledMessageID uuid.UUID) (*entities.ScheduledMessage, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	schedule := new(entities.ScheduledMessage)
+	err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Where("id = ?", scheduledMessageID).First(schedule).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		msg := fmt.Sprintf("scheduled message with ID [%s] for user [%s] does not exist", scheduledMessageID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load scheduled message with ID [%s] for user [%s]", scheduledMessageID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return schedule, nil
+}
+
+func (repository *gormScheduledMessageRepository) Delete(ctx context.Context, userID entities.UserID, scheduledMessageID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("id = ?", scheduledMessageID).
+		Delete(&entities.ScheduledMessage{}).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete scheduled message with ID [%s] and userID [%s]", scheduledMessageID, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
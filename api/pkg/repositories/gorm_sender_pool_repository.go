@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
+)
+
+// gormSenderPoolRepository is responsible for persisting entities.SenderPool
+type gormSenderPoolRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormSenderPoolRepository creates the GORM version of the SenderPoolRepository
+func NewGormSenderPoolRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) SenderPoolRepository {
+	return &gormSenderPoolRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormSenderPoolRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+func (repository *gormSenderPoolRepository) Save(ctx context.Context, pool *entities.SenderPool) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Save(pool).Error; err != nil {
+		msg := fmt.Sprintf("cannot save sender pool with ID [%s]", pool.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormSenderPoolRepository) Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.SenderPool, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).Where("user_id = ?", userID)
+	if len(params.Query) > 0 {
+		queryPattern := "%" + params.Query + "%"
+		query.Where("name ILIKE ?", queryPattern)
+	}
+
+	pools := make([]*entities.SenderPool, 0)
+	if err := query.Order("created_at DESC").Limit(params.Limit).Offset(params.Skip).Find(&pools).Error; err != nil {
+		msg := fmt.Sprintf("cannot fetch sender pools for user [%s] and params [%+#v]", userID, params)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return pools, nil
+}
+
+func (repository *gormSenderPoolRepository) Load(ctx context.Context, userID entities.UserID, poolID uuid.UUID) (*entities.SenderPool, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	pool := new(entities.SenderPool)
+	err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Where("id = ?", poolID).First(pool).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		msg := fmt.Sprintf("sender pool with ID [%s] for user [%s] does not exist", poolID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load sender pool with ID [%s] for user [%s]", poolID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return pool, nil
+}
+
+func (repository *gormSenderPoolRepository) Delete(ctx context.Context, userID entities.UserID, poolID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("id = ?", poolID).
+		Delete(&entities.SenderPool{}).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete sender pool with ID [%s] and userID [%s]", poolID, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
+)
+
+// gormSuppressionRepository is responsible for persisting entities.Suppression
+type gormSuppressionRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormSuppressionRepository creates the GORM version of the SuppressionRepository
+func NewGormSuppressionRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) SuppressionRepository {
+	return &gormSuppressionRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormSuppressionRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+func (repository *gormSuppressionRepository) Save(ctx context.Context, suppression *entities.Suppression) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Save(suppression).Error; err != nil {
+		msg := fmt.Sprintf("cannot save suppression with ID [%s]", suppression.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormSuppressionRepository) Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.Suppression, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).Where("user_id = ?", userID)
+	if len(params.Query) > 0 {
+		queryPattern := "%" + params.Query + "%"
+		query.Where(repository.db.Where("phone_number ILIKE ?", queryPattern).Or("reason ILIKE ?", queryPattern))
+	}
+
+	suppressions := make([]*entities.Suppression, 0)
+	if err := query.Order("created_at DESC").Limit(params.Limit).Offset(params.Skip).Find(&suppressions).Error; err != nil {
+		msg := fmt.Sprintf("cannot fetch suppressions for user [%s] and params [%+#v]", userID, params)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return suppressions, nil
+}
+
+func (repository *gormSuppressionRepository) Load(ctx context.Context, userID entities.UserID, phoneNumber string) (*entities.Suppression, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	suppression := new(entities.Suppression)
+	err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Where("phone_number = ?", phoneNumber).First(suppression).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		msg := fmt.Sprintf("suppression with phone number [%s] for user [%s] does not exist", phoneNumber, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load suppression with phone number [%s] for user [%s]", phoneNumber, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return suppression, nil
+}
+
+func (repository *gormSuppressionRepository) Delete(ctx context.Context, userID entities.UserID, phoneNumber string) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("phone_number = ?", phoneNumber).
+		Delete(&entities.Suppression{}).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete suppression with phone number [%s] and userID [%s]", phoneNumber, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormSuppressionRepository) FetchByPhoneNumbers(ctx context.Context, userID entities.UserID, phoneNumbers []string) ([]*entities.Suppression, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	suppressions := make([]*entities.Suppression, 0)
+	if len(phoneNumbers) == 0 {
+		return suppressions, nil
+	}
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Where("phone_number IN ?", phoneNumbers).Find(&suppressions).Error; err != nil {
+		msg := fmt.Sprintf("cannot fetch suppressions for [%d] phone numbers of user [%s]", len(phoneNumbers), userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return suppressions, nil
+}
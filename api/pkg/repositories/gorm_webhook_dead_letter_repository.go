@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
+)
+
+// gormWebhookDeadLetterRepository is responsible for persisting entities.WebhookDeadLetter
+type gormWebhookDeadLetterRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormWebhookDeadLetterRepository creates the GORM version of the WebhookDeadLetterRepository
+func NewGormWebhookDeadLetterRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) WebhookDeadLetterRepository {
+	return &gormWebhookDeadLetterRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormWebhookDeadLetterRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+func (repository *gormWebhookDeadLetterRepository) Store(ctx context.Context, deadLetter *entities.WebhookDeadLetter) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Create(deadLetter).Error; err != nil {
+		msg := fmt.Sprintf("cannot store webhook dead letter with ID [%s]", deadLetter.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormWebhookDeadLetterRepository) Index(ctx context.Context, userID entities.UserID, webhookID uuid.UUID, params IndexParams) ([]*entities.WebhookDeadLetter, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).Where("user_id = ?", userID).Where("webhook_id = ?", webhookID)
+	if len(params.Query) > 0 {
+		queryPattern := "%" + params.Query + "%"
+		query.Where(repository.db.Where("event_type ILIKE ?", queryPattern).Or("event_id ILIKE ?", queryPattern))
+	}
+
+	deadLetters := make([]*entities.WebhookDeadLetter, 0)
+	if err := query.Order("created_at DESC").Limit(params.Limit).Offset(params.Skip).Find(&deadLetters).Error; err != nil {
+		msg := fmt.Sprintf("cannot fetch dead letters for webhook [%s] and params [%+#v]", webhookID, params)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return deadLetters, nil
+}
+
+func (repository *gormWebhookDeadLetterRepository) Load(ctx context.Context, userID entities.UserID, deadLetterID uuid.UUID) (*entities.WebhookDeadLetter, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	deadLetter := new(entities.WebhookDeadLetter)
+	err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Where("id = ?", deadLetterID).First(deadLetter).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		msg := fmt.Sprintf("webhook dead letter with ID [%s] for user [%s] does not exist", deadLetterID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load webhook dead letter with ID [%s] for user [%s]", deadLetterID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return deadLetter, nil
+}
+
+func (repository *gormWebhookDeadLetterRepository) Delete(ctx context.Context, userID entities.UserID, deadLetterID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("id = ?", deadLetterID).
+		Delete(&entities.WebhookDeadLetter{}).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete webhook dead letter with ID [%s] and userID [%s]", deadLetterID, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
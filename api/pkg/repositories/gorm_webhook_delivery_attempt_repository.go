@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
+)
+
+// gormWebhookDeliveryAttemptRepository is responsible for persisting entities.WebhookDeliveryAttempt
+type gormWebhookDeliveryAttemptRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormWebhookDeliveryAttemptRepository creates the GORM version of the WebhookDeliveryAttemptRepository
+func NewGormWebhookDeliveryAttemptRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) WebhookDeliveryAttemptRepository {
+	return &gormWebhookDeliveryAttemptRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormWebhookDeliveryAttemptRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+func (repository *gormWebhookDeliveryAttemptRepository) Store(ctx context.Context, attempt *entities.WebhookDeliveryAttempt) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Create(attempt).Error; err != nil {
+		msg := fmt.Sprintf("cannot store webhook delivery attempt with ID [%s]", attempt.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormWebhookDeliveryAttemptRepository) Index(ctx context.Context, userID entities.UserID, webhookID uuid.UUID, status string, eventType string, params IndexParams) ([]*entities.WebhookDeliveryAttempt, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).Where("user_id = ?", userID).Where("webhook_id = ?", webhookID)
+	if status != "" {
+		query.Where("status = ?", status)
+	}
+
+	if eventType != "" {
+		query.Where("event_type = ?", eventType)
+	}
+
+	if len(params.Query) > 0 {
+		queryPattern := "%" + params.Query + "%"
+		query.Where(repository.db.Where("event_id ILIKE ?", queryPattern).Or("response_body ILIKE ?", queryPattern))
+	}
+
+	attempts := make([]*entities.WebhookDeliveryAttempt, 0)
+	if err := query.Order("created_at DESC").Limit(params.Limit).Offset(params.Skip).Find(&attempts).Error; err != nil {
+		msg := fmt.Sprintf("cannot fetch delivery attempts for webhook [%s] and params [%+#v]", webhookID, params)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return attempts, nil
+}
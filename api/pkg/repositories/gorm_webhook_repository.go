@@ -56,7 +56,7 @@ func (repository *gormWebhookRepository) Index(ctx context.Context, userID entit
 	}
 
 	webhooks := make([]*entities.Webhook, 0)
-	if err := query.Order("created_at DESC").Limit(params.Limit).Offset(params.Skip).Find(&webhooks).Error; err != nil {
+	if err := paginate(query, "created_at", params).Find(&webhooks).Error; err != nil {
 		msg := fmt.Sprintf("cannot fetch webhooks for user [%s] and params [%+#v]", userID, params)
 		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
@@ -70,7 +70,7 @@ func (repository *gormWebhookRepository) LoadByEvent(ctx context.Context, userID
 
 	webhooks := make([]*entities.Webhook, 0)
 	err := repository.db.
-		Raw("SELECT * FROM webhooks WHERE user_id = ? AND CAST(? as TEXT) = ANY(events) AND CAST(? as TEXT) = ANY(phone_numbers)", userID, event, phoneNumber).
+		Raw("SELECT * FROM webhooks WHERE user_id = ? AND CAST(? as TEXT) = ANY(events) AND (COALESCE(cardinality(phone_numbers), 0) = 0 OR CAST(? as TEXT) = ANY(phone_numbers))", userID, event, phoneNumber).
 		Scan(&webhooks).
 		Error
 	if err != nil {
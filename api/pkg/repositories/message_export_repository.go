@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// MessageExportRepository loads and persists an entities.MessageExport
+type MessageExportRepository interface {
+	// Store a new entities.MessageExport
+	Store(ctx context.Context, export *entities.MessageExport) error
+
+	// Update an entities.MessageExport
+	Update(ctx context.Context, export *entities.MessageExport) error
+
+	// Load an entities.MessageExport by ID
+	Load(ctx context.Context, exportID uuid.UUID) (*entities.MessageExport, error)
+}
@@ -2,11 +2,35 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
 	"github.com/google/uuid"
 )
 
+// MessageSearchParams are the filters used to search entities.Message of a user
+type MessageSearchParams struct {
+	// Query is matched against the content of messages which are not encrypted
+	Query     string
+	Owner     string
+	Contact   string
+	Status    string
+	Type      string
+	Encrypted *bool
+	StartDate *time.Time
+	EndDate   *time.Time
+	Cursor    *Cursor
+	Limit     int
+}
+
+// MessageExportParams are the filters used to select the entities.Message of a user which are exported
+type MessageExportParams struct {
+	Owner     string
+	Contacts  []string
+	StartDate *time.Time
+	EndDate   *time.Time
+}
+
 // MessageRepository loads and persists an entities.Message
 type MessageRepository interface {
 	// Store a new entities.Message
@@ -15,12 +39,25 @@ type MessageRepository interface {
 	// Update a new entities.Message
 	Update(ctx context.Context, message *entities.Message) error
 
+	// UpdateIfNotPickedUp updates an entities.Message only while it is pending or scheduled, it returns ErrCodeNotFound when a phone has picked it up
+	UpdateIfNotPickedUp(ctx context.Context, message *entities.Message) error
+
 	// Load an entities.Message by ID
 	Load(ctx context.Context, userID entities.UserID, messageID uuid.UUID) (*entities.Message, error)
 
 	// Index entities.Message between 2 phone numbers
 	Index(ctx context.Context, userID entities.UserID, owner string, contact string, params IndexParams) (*[]entities.Message, error)
 
+	// Search entities.Message of a user with full-text search, newest first
+	Search(ctx context.Context, userID entities.UserID, params MessageSearchParams) (*[]entities.Message, error)
+
+	// Count the entities.Message which match the MessageExportParams
+	Count(ctx context.Context, userID entities.UserID, params MessageExportParams) (int64, error)
+
+	// Stream entities.Message which match the MessageExportParams in batches, oldest first.
+	// The callback is called for each batch and the stream stops when it returns an error.
+	Stream(ctx context.Context, userID entities.UserID, params MessageExportParams, batchSize int, callback func(messages []entities.Message) error) error
+
 	// GetOutstanding fetches an entities.Message which is outstanding
 	GetOutstanding(ctx context.Context, userID entities.UserID, messageID uuid.UUID) (*entities.Message, error)
 
@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// MessageTemplateRepository loads and persists an entities.MessageTemplate
+type MessageTemplateRepository interface {
+	// Save Upsert a new entities.MessageTemplate
+	Save(ctx context.Context, template *entities.MessageTemplate) error
+
+	// Index entities.MessageTemplate by entities.UserID
+	Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.MessageTemplate, error)
+
+	// Load an entities.MessageTemplate by ID.
+	Load(ctx context.Context, userID entities.UserID, templateID uuid.UUID) (*entities.MessageTemplate, error)
+
+	// Delete an entities.MessageTemplate
+	Delete(ctx context.Context, userID entities.UserID, templateID uuid.UUID) error
+}
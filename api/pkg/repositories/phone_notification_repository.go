@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -10,9 +11,19 @@ import (
 
 // PhoneNotificationRepository loads and persists an entities.PhoneNotification
 type PhoneNotificationRepository interface {
-	// Schedule a new entities.PhoneNotification
-	Schedule(ctx context.Context, messagesPerMinute uint, notification *entities.PhoneNotification) error
+	// Schedule a new entities.PhoneNotification no earlier than its ScheduledAt. The deferral is optional and it returns a
+	// later time when the notification can't be sent at the scheduled time e.g. during the quiet hours of the phone.
+	Schedule(ctx context.Context, messagesPerMinute uint, deferral func(scheduledAt time.Time) *time.Time, notification *entities.PhoneNotification) error
+
+	// Load an entities.PhoneNotification by ID
+	Load(ctx context.Context, notificationID uuid.UUID) (*entities.PhoneNotification, error)
+
+	// CancelPending cancels the pending notifications of a message
+	CancelPending(ctx context.Context, messageID uuid.UUID) error
 
 	// UpdateStatus of a notification
 	UpdateStatus(ctx context.Context, notificationID uuid.UUID, status entities.PhoneNotificationStatus) error
+
+	// CountPending counts the pending notifications of each phone
+	CountPending(ctx context.Context, phoneIDs []uuid.UUID) (map[uuid.UUID]int, error)
 }
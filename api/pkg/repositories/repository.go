@@ -1,9 +1,11 @@
 package repositories
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
 )
 
 // IndexParams parameters for indexing a database table
@@ -11,6 +13,9 @@ type IndexParams struct {
 	Skip  int    `json:"skip"`
 	Query string `json:"query"`
 	Limit int    `json:"take"`
+
+	// Cursor is used instead of Skip to fetch the items after the last item of the previous page
+	Cursor *Cursor `json:"cursor"`
 }
 
 const (
@@ -19,3 +24,13 @@ const (
 
 	dbOperationDuration = 5 * time.Second
 )
+
+// paginate sorts the query by the timestamp column and the ID in descending order and fetches the page with the
+// IndexParams.Cursor when it is set, otherwise it falls back to the IndexParams.Skip offset.
+func paginate(query *gorm.DB, column string, params IndexParams) *gorm.DB {
+	query = query.Order(column + " DESC").Order("id DESC").Limit(params.Limit)
+	if params.Cursor != nil {
+		return query.Where(fmt.Sprintf("(%s, id) < (?, ?)", column), params.Cursor.Timestamp, params.Cursor.ID)
+	}
+	return query.Offset(params.Skip)
+}
@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// ScheduledMessageRepository loads and persists an entities.ScheduledMessage
+type ScheduledMessageRepository interface {
+	// Save Upsert a new entities.ScheduledMessage
+	Save(ctx context.Context, schedule *entities.ScheduledMessage) error
+
+	// Index entities.ScheduledMessage by entities.UserID
+	Index(ctx context.Context, userID entities.UserID, owner string, params IndexParams) ([]*entities.ScheduledMessage, error)
+
+	// Load an entities.ScheduledMessage by ID.
+	Load(ctx context.Context, userID entities.UserID, scheduledMessageID uuid.UUID) (*entities.ScheduledMessage, error)
+
+	// Delete an entities.ScheduledMessage
+	Delete(ctx context.Context, userID entities.UserID, scheduledMessageID uuid.UUID) error
+}
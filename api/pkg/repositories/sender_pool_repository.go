@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// SenderPoolRepository loads and persists an entities.SenderPool
+type SenderPoolRepository interface {
+	// Save Upsert a new entities.SenderPool
+	Save(ctx context.Context, pool *entities.SenderPool) error
+
+	// Index entities.SenderPool by entities.UserID
+	Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.SenderPool, error)
+
+	// Load an entities.SenderPool by ID.
+	Load(ctx context.Context, userID entities.UserID, poolID uuid.UUID) (*entities.SenderPool, error)
+
+	// Delete an entities.SenderPool
+	Delete(ctx context.Context, userID entities.UserID, poolID uuid.UUID) error
+}
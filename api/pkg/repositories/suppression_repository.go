@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+)
+
+// SuppressionRepository loads and persists an entities.Suppression
+type SuppressionRepository interface {
+	// Save Upsert a new entities.Suppression
+	Save(ctx context.Context, suppression *entities.Suppression) error
+
+	// Index entities.Suppression by entities.UserID
+	Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.Suppression, error)
+
+	// Load an entities.Suppression by the phone number
+	Load(ctx context.Context, userID entities.UserID, phoneNumber string) (*entities.Suppression, error)
+
+	// Delete an entities.Suppression by the phone number
+	Delete(ctx context.Context, userID entities.UserID, phoneNumber string) error
+
+	// FetchByPhoneNumbers loads the entities.Suppression of the phone numbers which are on the suppression list
+	FetchByPhoneNumbers(ctx context.Context, userID entities.UserID, phoneNumbers []string) ([]*entities.Suppression, error)
+}
@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+)
+
+// WebhookDeadLetterRepository loads and persists an entities.WebhookDeadLetter
+type WebhookDeadLetterRepository interface {
+	// Store a new entities.WebhookDeadLetter
+	Store(ctx context.Context, deadLetter *entities.WebhookDeadLetter) error
+
+	// Index entities.WebhookDeadLetter of an entities.Webhook
+	Index(ctx context.Context, userID entities.UserID, webhookID uuid.UUID, params IndexParams) ([]*entities.WebhookDeadLetter, error)
+
+	// Load an entities.WebhookDeadLetter by ID
+	Load(ctx context.Context, userID entities.UserID, deadLetterID uuid.UUID) (*entities.WebhookDeadLetter, error)
+
+	// Delete an entities.WebhookDeadLetter
+	Delete(ctx context.Context, userID entities.UserID, deadLetterID uuid.UUID) error
+}
@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+)
+
+// WebhookDeliveryAttemptRepository loads and persists an entities.WebhookDeliveryAttempt
+type WebhookDeliveryAttemptRepository interface {
+	// Store a new entities.WebhookDeliveryAttempt
+	Store(ctx context.Context, attempt *entities.WebhookDeliveryAttempt) error
+
+	// Index entities.WebhookDeliveryAttempt of an entities.Webhook filtered by status and event type
+	Index(ctx context.Context, userID entities.UserID, webhookID uuid.UUID, status string, eventType string, params IndexParams) ([]*entities.WebhookDeliveryAttempt, error)
+}
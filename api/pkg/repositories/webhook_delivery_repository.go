@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+)
+
+// WebhookDeliveryRepository loads and persists an entities.WebhookDelivery
+type WebhookDeliveryRepository interface {
+	// Store a new entities.WebhookDelivery
+	Store(ctx context.Context, delivery *entities.WebhookDelivery) error
+
+	// Update an existing entities.WebhookDelivery
+	Update(ctx context.Context, delivery *entities.WebhookDelivery) error
+
+	// Load an entities.WebhookDelivery by ID
+	Load(ctx context.Context, userID entities.UserID, deliveryID uuid.UUID) (*entities.WebhookDelivery, error)
+}
@@ -16,7 +16,7 @@ type WebhookRepository interface {
 	// Index entities.Webhook by entities.UserID
 	Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.Webhook, error)
 
-	// LoadByEvent loads webhooks for a user and event.
+	// LoadByEvent loads webhooks for a user and event. Webhooks without phone numbers match every phoneNumber and an empty phoneNumber only matches them.
 	LoadByEvent(ctx context.Context, userID entities.UserID, event string, phoneNumber string) ([]*entities.Webhook, error)
 
 	// Load loads a webhook by ID.
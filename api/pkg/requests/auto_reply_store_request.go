@@ -0,0 +1,94 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/google/uuid"
+)
+
+// AutoReplyStore is the payload for creating a new entities.AutoReply
+type AutoReplyStore struct {
+	request
+	Owner string `json:"owner" example:"+18005550199"`
+	Name  string `json:"name" example:"Opening hours"`
+
+	// Match is the strategy used to match a received message. One of keyword, regex or contact
+	Match string `json:"match" example:"keyword"`
+	// Pattern is the keyword, the regular expression or the phone number of the contact
+	Pattern string `json:"pattern" example:"HOURS"`
+
+	// StartTime and EndTime are an optional daily window in your timezone in the HH:MM format
+	StartTime string `json:"start_time" example:"09:00" validate:"optional"`
+	EndTime   string `json:"end_time" example:"17:00" validate:"optional"`
+
+	// Action is what happens when a message matches. One of reply, template, forward, tag or webhook
+	Action     string `json:"action" example:"reply"`
+	Content    string `json:"content" example:"We are open from 9am to 5pm, Monday to Friday" validate:"optional"`
+	TemplateID string `json:"template_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb" validate:"optional"`
+	ForwardTo  string `json:"forward_to" example:"+18005550100" validate:"optional"`
+	Tag        string `json:"tag" example:"support" validate:"optional"`
+
+	// CooldownSeconds is the minimum duration between 2 executions of the rule for the same contact
+	CooldownSeconds uint  `json:"cooldown_seconds" example:"300" validate:"optional"`
+	IsActive        *bool `json:"is_active" example:"true" validate:"optional"`
+	Priority        uint  `json:"priority" example:"1" validate:"optional"`
+}
+
+// Sanitize sets defaults to AutoReplyStore
+func (input *AutoReplyStore) Sanitize() AutoReplyStore {
+	input.Owner = input.sanitizeAddress(input.Owner)
+	input.Name = strings.TrimSpace(input.Name)
+	input.Match = strings.ToLower(strings.TrimSpace(input.Match))
+	input.Pattern = strings.TrimSpace(input.Pattern)
+	if input.Match == entities.AutoReplyMatchContact.String() {
+		input.Pattern = input.sanitizeAddress(input.Pattern)
+	}
+
+	input.StartTime = strings.TrimSpace(input.StartTime)
+	input.EndTime = strings.TrimSpace(input.EndTime)
+	input.Action = strings.ToLower(strings.TrimSpace(input.Action))
+	input.Content = strings.TrimSpace(input.Content)
+	input.TemplateID = strings.TrimSpace(input.TemplateID)
+	if strings.TrimSpace(input.ForwardTo) != "" {
+		input.ForwardTo = input.sanitizeAddress(input.ForwardTo)
+	}
+	input.Tag = strings.ToLower(strings.TrimSpace(input.Tag))
+
+	if input.CooldownSeconds == 0 {
+		input.CooldownSeconds = 300
+	}
+
+	if input.IsActive == nil {
+		isActive := true
+		input.IsActive = &isActive
+	}
+	return *input
+}
+
+// ToStoreParams converts AutoReplyStore to services.AutoReplyStoreParams
+func (input *AutoReplyStore) ToStoreParams(user entities.AuthUser) *services.AutoReplyStoreParams {
+	var templateID *uuid.UUID
+	if id, err := uuid.Parse(input.TemplateID); err == nil {
+		templateID = &id
+	}
+
+	return &services.AutoReplyStoreParams{
+		UserID:          user.ID,
+		Owner:           input.Owner,
+		Name:            input.Name,
+		Match:           entities.AutoReplyMatch(input.Match),
+		Pattern:         input.Pattern,
+		StartTime:       input.sanitizeStringPointer(input.StartTime),
+		EndTime:         input.sanitizeStringPointer(input.EndTime),
+		Action:          entities.AutoReplyAction(input.Action),
+		Content:         input.sanitizeStringPointer(input.Content),
+		TemplateID:      templateID,
+		ForwardTo:       input.sanitizeStringPointer(input.ForwardTo),
+		Tag:             input.sanitizeStringPointer(input.Tag),
+		CooldownSeconds: input.CooldownSeconds,
+		IsActive:        input.IsActive != nil && *input.IsActive,
+		Priority:        input.Priority,
+	}
+}
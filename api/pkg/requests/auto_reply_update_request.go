@@ -0,0 +1,27 @@
+package requests
+
+import (
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/google/uuid"
+)
+
+// AutoReplyUpdate is the payload for updating an entities.AutoReply
+type AutoReplyUpdate struct {
+	AutoReplyStore
+	AutoReplyID string `json:"autoReplyID" swaggerignore:"true"` // used internally for validation
+}
+
+// Sanitize sets defaults to AutoReplyUpdate
+func (input *AutoReplyUpdate) Sanitize() AutoReplyUpdate {
+	input.AutoReplyStore.Sanitize()
+	return *input
+}
+
+// ToUpdateParams converts AutoReplyUpdate to services.AutoReplyUpdateParams
+func (input *AutoReplyUpdate) ToUpdateParams(user entities.AuthUser) *services.AutoReplyUpdateParams {
+	return &services.AutoReplyUpdateParams{
+		AutoReplyStoreParams: *input.ToStoreParams(user),
+		AutoReplyID:          uuid.MustParse(input.AutoReplyID),
+	}
+}
@@ -9,8 +9,9 @@ import (
 // BillingUsageHistory is the payload for fetching the entities.BillingUsage history
 type BillingUsageHistory struct {
 	request
-	Skip  string `json:"skip" query:"skip"`
-	Limit string `json:"limit" query:"limit"`
+	Skip   string `json:"skip" query:"skip"`
+	Limit  string `json:"limit" query:"limit"`
+	Cursor string `json:"cursor" query:"cursor"`
 }
 
 // Sanitize sets defaults to MessageOutstanding
@@ -22,13 +23,15 @@ func (input *BillingUsageHistory) Sanitize() BillingUsageHistory {
 	if input.Skip == "" {
 		input.Skip = "0"
 	}
+	input.Cursor = strings.TrimSpace(input.Cursor)
 	return *input
 }
 
 // ToIndexParams converts BillingUsageHistory to repositories.IndexParams
 func (input *BillingUsageHistory) ToIndexParams() repositories.IndexParams {
 	return repositories.IndexParams{
-		Skip:  input.getInt(input.Skip),
-		Limit: input.getInt(input.Limit),
+		Skip:   input.getInt(input.Skip),
+		Limit:  input.getInt(input.Limit),
+		Cursor: input.getCursor(input.Cursor),
 	}
 }
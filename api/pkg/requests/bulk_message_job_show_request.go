@@ -0,0 +1,36 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+)
+
+// BulkMessageJobShow is the payload for fetching an entities.BulkMessageJob with a page of row outcomes
+type BulkMessageJobShow struct {
+	request
+	JobID string `json:"jobID" swaggerignore:"true"` // used internally for validation
+	Skip  string `json:"skip" query:"skip"`
+	Limit string `json:"limit" query:"limit"`
+}
+
+// Sanitize sets defaults to BulkMessageJobShow
+func (input *BulkMessageJobShow) Sanitize() BulkMessageJobShow {
+	if strings.TrimSpace(input.Limit) == "" {
+		input.Limit = "100"
+	}
+	input.JobID = strings.TrimSpace(input.JobID)
+	input.Skip = strings.TrimSpace(input.Skip)
+	if input.Skip == "" {
+		input.Skip = "0"
+	}
+	return *input
+}
+
+// ToIndexParams converts BulkMessageJobShow to repositories.IndexParams for the rows of the job
+func (input *BulkMessageJobShow) ToIndexParams() repositories.IndexParams {
+	return repositories.IndexParams{
+		Skip:  input.getInt(input.Skip),
+		Limit: input.getInt(input.Limit),
+	}
+}
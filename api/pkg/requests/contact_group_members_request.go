@@ -0,0 +1,29 @@
+package requests
+
+import (
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/google/uuid"
+)
+
+// ContactGroupMembers is the payload for adding or removing phone numbers from an entities.ContactGroup
+type ContactGroupMembers struct {
+	request
+	PhoneNumbers   []string `json:"phone_numbers" example:"+18005550100,+18005550199"`
+	ContactGroupID string   `json:"contactGroupID" swaggerignore:"true"` // used internally for validation
+}
+
+// Sanitize sets defaults to ContactGroupMembers
+func (input *ContactGroupMembers) Sanitize() ContactGroupMembers {
+	input.PhoneNumbers = input.sanitizePhoneNumbers(input.PhoneNumbers)
+	return *input
+}
+
+// ToMembersParams converts ContactGroupMembers to services.ContactGroupMembersParams
+func (input *ContactGroupMembers) ToMembersParams(userID entities.UserID) *services.ContactGroupMembersParams {
+	return &services.ContactGroupMembersParams{
+		UserID:       userID,
+		GroupID:      uuid.MustParse(input.ContactGroupID),
+		PhoneNumbers: input.PhoneNumbers,
+	}
+}
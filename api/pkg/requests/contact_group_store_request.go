@@ -0,0 +1,34 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// ContactGroupStore is the payload for creating a new entities.ContactGroup
+type ContactGroupStore struct {
+	request
+	Name         string   `json:"name" example:"Newsletter Subscribers"`
+	Description  string   `json:"description" example:"Customers who opted in to the weekly newsletter"`
+	PhoneNumbers []string `json:"phone_numbers" example:"+18005550100,+18005550199"`
+}
+
+// Sanitize sets defaults to ContactGroupStore
+func (input *ContactGroupStore) Sanitize() ContactGroupStore {
+	input.Name = strings.TrimSpace(input.Name)
+	input.Description = strings.TrimSpace(input.Description)
+	input.PhoneNumbers = input.sanitizePhoneNumbers(input.PhoneNumbers)
+	return *input
+}
+
+// ToStoreParams converts ContactGroupStore to services.ContactGroupStoreParams
+func (input *ContactGroupStore) ToStoreParams(user entities.AuthUser) *services.ContactGroupStoreParams {
+	return &services.ContactGroupStoreParams{
+		UserID:       user.ID,
+		Name:         input.Name,
+		Description:  input.Description,
+		PhoneNumbers: input.PhoneNumbers,
+	}
+}
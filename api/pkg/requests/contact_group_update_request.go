@@ -0,0 +1,27 @@
+package requests
+
+import (
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/google/uuid"
+)
+
+// ContactGroupUpdate is the payload for updating an entities.ContactGroup
+type ContactGroupUpdate struct {
+	ContactGroupStore
+	ContactGroupID string `json:"contactGroupID" swaggerignore:"true"` // used internally for validation
+}
+
+// Sanitize sets defaults to ContactGroupUpdate
+func (input *ContactGroupUpdate) Sanitize() ContactGroupUpdate {
+	input.ContactGroupStore.Sanitize()
+	return *input
+}
+
+// ToUpdateParams converts ContactGroupUpdate to services.ContactGroupUpdateParams
+func (input *ContactGroupUpdate) ToUpdateParams(user entities.AuthUser) *services.ContactGroupUpdateParams {
+	return &services.ContactGroupUpdateParams{
+		ContactGroupStoreParams: *input.ToStoreParams(user),
+		GroupID:                 uuid.MustParse(input.ContactGroupID),
+	}
+}
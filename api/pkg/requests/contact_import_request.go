@@ -0,0 +1,54 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// ContactImportSeparator separates multiple values in a single column of the contacts CSV file
+const ContactImportSeparator = ";"
+
+// ContactImport represents a single row in the CSV file of imported contacts
+type ContactImport struct {
+	request
+	Name         string `csv:"Name"`
+	PhoneNumbers string `csv:"PhoneNumbers"`
+	Tags         string `csv:"Tags(optional)"`
+	Notes        string `csv:"Notes(optional)"`
+
+	// CustomFields are the values of the extra columns in the row keyed by the column header
+	CustomFields map[string]string `csv:"-"`
+}
+
+// Sanitize sets defaults to ContactImport
+func (input *ContactImport) Sanitize() *ContactImport {
+	input.Name = strings.TrimSpace(input.Name)
+	input.Notes = strings.TrimSpace(input.Notes)
+	input.PhoneNumbers = strings.Join(input.sanitizePhoneNumbers(strings.Split(input.PhoneNumbers, ContactImportSeparator)), ContactImportSeparator)
+	input.Tags = strings.Join(input.sanitizeTags(strings.Split(input.Tags, ContactImportSeparator)), ContactImportSeparator)
+	return input
+}
+
+// PhoneNumberList returns the phone numbers in the row
+func (input *ContactImport) PhoneNumberList() []string {
+	return input.uniqueNonEmpty(strings.Split(input.PhoneNumbers, ContactImportSeparator), strings.TrimSpace)
+}
+
+// ToStoreParams converts ContactImport to services.ContactStoreParams
+func (input *ContactImport) ToStoreParams(userID entities.UserID) *services.ContactStoreParams {
+	customFields := input.CustomFields
+	if customFields == nil {
+		customFields = map[string]string{}
+	}
+
+	return &services.ContactStoreParams{
+		UserID:       userID,
+		Name:         input.Name,
+		PhoneNumbers: input.PhoneNumberList(),
+		Tags:         input.uniqueNonEmpty(strings.Split(input.Tags, ContactImportSeparator), strings.TrimSpace),
+		CustomFields: customFields,
+		Notes:        input.Notes,
+	}
+}
@@ -0,0 +1,74 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// ContactStore is the payload for creating a new entities.Contact
+type ContactStore struct {
+	request
+	Name         string            `json:"name" example:"John Doe"`
+	PhoneNumbers []string          `json:"phone_numbers" example:"+18005550100"`
+	Tags         []string          `json:"tags" example:"customers,vip"`
+	CustomFields map[string]string `json:"custom_fields" example:"company:Acme"`
+	Notes        string            `json:"notes" example:"Prefers to be contacted in the evening"`
+}
+
+// Sanitize sets defaults to ContactStore
+func (input *ContactStore) Sanitize() ContactStore {
+	input.Name = strings.TrimSpace(input.Name)
+	input.Notes = strings.TrimSpace(input.Notes)
+	input.PhoneNumbers = input.sanitizePhoneNumbers(input.PhoneNumbers)
+	input.Tags = input.sanitizeTags(input.Tags)
+
+	customFields := map[string]string{}
+	for key, value := range input.CustomFields {
+		if key = strings.TrimSpace(key); key != "" {
+			customFields[key] = strings.TrimSpace(value)
+		}
+	}
+	input.CustomFields = customFields
+
+	return *input
+}
+
+// ToStoreParams converts ContactStore to services.ContactStoreParams
+func (input *ContactStore) ToStoreParams(user entities.AuthUser) *services.ContactStoreParams {
+	return &services.ContactStoreParams{
+		UserID:       user.ID,
+		Name:         input.Name,
+		PhoneNumbers: input.PhoneNumbers,
+		Tags:         input.Tags,
+		CustomFields: input.CustomFields,
+		Notes:        input.Notes,
+	}
+}
+
+// sanitizePhoneNumbers formats the phone numbers in E.164 and removes duplicates while keeping the order
+func (input *request) sanitizePhoneNumbers(values []string) []string {
+	return input.uniqueNonEmpty(values, input.sanitizeAddress)
+}
+
+// sanitizeTags lowercases the tags and removes duplicates while keeping the order
+func (input *request) sanitizeTags(values []string) []string {
+	return input.uniqueNonEmpty(values, func(value string) string {
+		return strings.ToLower(strings.TrimSpace(value))
+	})
+}
+
+func (input *request) uniqueNonEmpty(values []string, sanitize func(string) string) []string {
+	cache := map[string]struct{}{}
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		value = sanitize(value)
+		if _, ok := cache[value]; ok || value == "" {
+			continue
+		}
+		cache[value] = struct{}{}
+		result = append(result, value)
+	}
+	return result
+}
@@ -0,0 +1,27 @@
+package requests
+
+import (
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/google/uuid"
+)
+
+// ContactUpdate is the payload for updating an entities.Contact
+type ContactUpdate struct {
+	ContactStore
+	ContactID string `json:"contactID" swaggerignore:"true"` // used internally for validation
+}
+
+// Sanitize sets defaults to ContactUpdate
+func (input *ContactUpdate) Sanitize() ContactUpdate {
+	input.ContactStore.Sanitize()
+	return *input
+}
+
+// ToUpdateParams converts ContactUpdate to services.ContactUpdateParams
+func (input *ContactUpdate) ToUpdateParams(user entities.AuthUser) *services.ContactUpdateParams {
+	return &services.ContactUpdateParams{
+		ContactStoreParams: *input.ToStoreParams(user),
+		ContactID:          uuid.MustParse(input.ContactID),
+	}
+}
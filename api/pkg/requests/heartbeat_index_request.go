@@ -9,10 +9,11 @@ import (
 // HeartbeatIndex is the payload for fetching entities.Heartbeat of a phone number
 type HeartbeatIndex struct {
 	request
-	Skip  string `json:"skip" query:"skip"`
-	Owner string `json:"owner" query:"owner"`
-	Query string `json:"query" query:"query"`
-	Limit string `json:"limit" query:"limit"`
+	Skip   string `json:"skip" query:"skip"`
+	Owner  string `json:"owner" query:"owner"`
+	Query  string `json:"query" query:"query"`
+	Limit  string `json:"limit" query:"limit"`
+	Cursor string `json:"cursor" query:"cursor"`
 }
 
 // Sanitize sets defaults to MessageOutstanding
@@ -26,14 +27,16 @@ func (input *HeartbeatIndex) Sanitize() HeartbeatIndex {
 	if input.Skip == "" {
 		input.Skip = "0"
 	}
+	input.Cursor = strings.TrimSpace(input.Cursor)
 	return *input
 }
 
 // ToIndexParams converts HeartbeatIndex to repositories.IndexParams
 func (input *HeartbeatIndex) ToIndexParams() repositories.IndexParams {
 	return repositories.IndexParams{
-		Skip:  input.getInt(input.Skip),
-		Query: input.Query,
-		Limit: input.getInt(input.Limit),
+		Skip:   input.getInt(input.Skip),
+		Query:  input.Query,
+		Limit:  input.getInt(input.Limit),
+		Cursor: input.getCursor(input.Cursor),
 	}
 }
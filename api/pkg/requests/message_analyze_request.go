@@ -0,0 +1,16 @@
+package requests
+
+// MessageAnalyze is the payload for analyzing the content of an SMS message without sending it
+type MessageAnalyze struct {
+	request
+	Content string `json:"content" example:"This is a sample text message"`
+
+	// From is an optional phone number which is used to check the maximum number of SMS segments allowed by the phone
+	From string `json:"from" example:"+18005550199" validate:"optional"`
+}
+
+// Sanitize sets defaults to MessageAnalyze
+func (input *MessageAnalyze) Sanitize() MessageAnalyze {
+	input.From = input.sanitizeAddress(input.From)
+	return *input
+}
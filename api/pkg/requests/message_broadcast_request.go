@@ -0,0 +1,72 @@
+package requests
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/google/uuid"
+	"github.com/nyaruka/phonenumbers"
+)
+
+// MessageBroadcast is the payload for sending the same SMS message to every phone number in an entities.ContactGroup
+type MessageBroadcast struct {
+	request
+	ContactGroupID string `json:"contact_group_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+
+	// From is the phone number which sends the message or the ID of an entities.SenderPool which chooses the phone
+	From    string `json:"from" example:"+18005550199"`
+	Content string `json:"content" example:"This is a sample text message"`
+
+	// Encrypted is used to determine if the content is end-to-end encrypted. Make sure to set the encryption key on the httpSMS mobile app
+	Encrypted bool `json:"encrypted" example:"false"`
+
+	// TemplateID is an optional ID of an entities.MessageTemplate which is rendered as the content of the message
+	TemplateID string `json:"template_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb" validate:"optional"`
+	// Variables are the values of the {{variable}} placeholders in the message template
+	Variables map[string]string `json:"variables" example:"name:John" validate:"optional"`
+
+	// RequestID is an optional parameter used to track a request from the client's perspective. It defaults to "broadcast-<uuid>"
+	RequestID string `json:"request_id" example:"153554b5-ae44-44a0-8f4f-7bbac5657ad4" validate:"optional"`
+	// SendAt is an optional parameter used to schedule the messages to be sent at a later time
+	SendAt *time.Time `json:"send_at" example:"2022-06-05T14:26:09.527976+03:00" validate:"optional"`
+}
+
+// Sanitize sets defaults to MessageBroadcast
+func (input *MessageBroadcast) Sanitize() MessageBroadcast {
+	input.ContactGroupID = strings.TrimSpace(input.ContactGroupID)
+	input.From = input.sanitizeAddress(input.From)
+	input.TemplateID = strings.TrimSpace(input.TemplateID)
+	input.RequestID = strings.TrimSpace(input.RequestID)
+	if input.RequestID == "" {
+		input.RequestID = fmt.Sprintf("broadcast-%s", uuid.New())
+	}
+	return *input
+}
+
+// IsSenderPool checks if the messages are sent through an entities.SenderPool instead of a phone number
+func (input *MessageBroadcast) IsSenderPool() bool {
+	return services.SenderPoolID(input.From) != nil
+}
+
+// ToBulkMessageJobBroadcastParams converts MessageBroadcast to services.BulkMessageJobBroadcastParams
+func (input *MessageBroadcast) ToBulkMessageJobBroadcastParams(userID entities.UserID, source string, group *entities.ContactGroup) *services.BulkMessageJobBroadcastParams {
+	owner := input.From
+	if !input.IsSenderPool() {
+		from, _ := phonenumbers.Parse(input.From, phonenumbers.UNKNOWN_REGION)
+		owner = phonenumbers.Format(from, phonenumbers.E164)
+	}
+
+	return &services.BulkMessageJobBroadcastParams{
+		UserID:    userID,
+		Source:    source,
+		Owner:     owner,
+		Group:     group,
+		Content:   input.Content,
+		Encrypted: input.Encrypted,
+		RequestID: input.sanitizeStringPointer(input.RequestID),
+		SendAt:    input.SendAt,
+	}
+}
@@ -1,9 +1,11 @@
 package requests
 
 import (
+	"strings"
 	"time"
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
 
 	"github.com/nyaruka/phonenumbers"
 
@@ -13,15 +15,29 @@ import (
 // MessageBulkSend is the payload for sending bulk SMS messages
 type MessageBulkSend struct {
 	request
+	// From is the phone number which sends the message or the ID of an entities.SenderPool which chooses the phone
 	From    string   `json:"from" example:"+18005550199"`
 	To      []string `json:"to" example:"+18005550100,+18005550100"`
 	Content string   `json:"content" example:"This is a sample text message"`
 
+	// ContactIDs are optional IDs of entities.Contact which are added to the recipients
+	ContactIDs []string `json:"contact_ids" example:"32343a19-da5e-4b1b-a767-3298a73703cb" validate:"optional"`
+	// Tags are optional tags of entities.Contact which are added to the recipients
+	Tags []string `json:"tags" example:"customers" validate:"optional"`
+
 	// Encrypted is used to determine if the content is end-to-end encrypted. Make sure to set the encryption key on the httpSMS mobile app
 	Encrypted bool `json:"encrypted" example:"false"`
 
+	// TemplateID is an optional ID of an entities.MessageTemplate which is rendered as the content of the message
+	TemplateID string `json:"template_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb" validate:"optional"`
+	// Variables are the values of the {{variable}} placeholders in the message template
+	Variables map[string]string `json:"variables" example:"name:John" validate:"optional"`
+
 	// RequestID is an optional parameter used to track a request from the client's perspective
 	RequestID string `json:"request_id" example:"153554b5-ae44-44a0-8f4f-7bbac5657ad4" validate:"optional"`
+
+	// IdempotencyKey is set from the Idempotency-Key header and defaults to the RequestID
+	IdempotencyKey string `json:"-"`
 }
 
 // Sanitize sets defaults to MessageReceive
@@ -32,9 +48,61 @@ func (input *MessageBulkSend) Sanitize() MessageBulkSend {
 	}
 	input.To = to
 	input.From = input.sanitizeAddress(input.From)
+	input.RequestID = strings.TrimSpace(input.RequestID)
+	input.IdempotencyKey = input.sanitizeIdempotencyKey(input.IdempotencyKey, input.RequestID)
+	input.TemplateID = strings.TrimSpace(input.TemplateID)
+	input.ContactIDs = input.uniqueNonEmpty(input.ContactIDs, strings.TrimSpace)
+	input.Tags = input.sanitizeTags(input.Tags)
 	return *input
 }
 
+// IsSenderPool checks if the messages are sent through an entities.SenderPool instead of a phone number
+func (input *MessageBulkSend) IsSenderPool() bool {
+	return services.SenderPoolID(input.From) != nil
+}
+
+// HasContactTargets checks if the recipients should also be resolved from the address book
+func (input *MessageBulkSend) HasContactTargets() bool {
+	return len(input.ContactIDs) > 0 || len(input.Tags) > 0
+}
+
+// ToContactTargetParams converts MessageBulkSend to services.ContactTargetParams
+func (input *MessageBulkSend) ToContactTargetParams(userID entities.UserID) *services.ContactTargetParams {
+	contactIDs := make([]uuid.UUID, 0, len(input.ContactIDs))
+	for _, contactID := range input.ContactIDs {
+		contactIDs = append(contactIDs, uuid.MustParse(contactID))
+	}
+
+	return &services.ContactTargetParams{
+		UserID:     userID,
+		ContactIDs: contactIDs,
+		Tags:       input.Tags,
+	}
+}
+
+// AddContacts adds the phone number of each entities.Contact to the recipients if it is not already present
+func (input *MessageBulkSend) AddContacts(contacts []*entities.Contact) {
+	to := make([]string, 0, len(input.To)+len(contacts))
+	for _, contact := range contacts {
+		to = append(to, contact.PhoneNumber())
+	}
+	input.To = input.uniqueNonEmpty(append(input.To, to...), strings.TrimSpace)
+}
+
+// RemoveRecipients removes the suppressed phone numbers from the recipients and returns the removed phone numbers
+func (input *MessageBulkSend) RemoveRecipients(suppressed map[string]bool) []string {
+	var to, removed []string
+	for _, address := range input.To {
+		if suppressed[address] {
+			removed = append(removed, address)
+			continue
+		}
+		to = append(to, address)
+	}
+	input.To = to
+	return removed
+}
+
 // ToMessageSendParams converts MessageSend to services.MessageSendParams
 func (input *MessageBulkSend) ToMessageSendParams(userID entities.UserID, source string) []services.MessageSendParams {
 	from, _ := phonenumbers.Parse(input.From, phonenumbers.UNKNOWN_REGION)
@@ -46,10 +114,12 @@ func (input *MessageBulkSend) ToMessageSendParams(userID entities.UserID, source
 			Owner:             from,
 			Encrypted:         input.Encrypted,
 			RequestID:         input.sanitizeStringPointer(input.RequestID),
+			IdempotencyKey:    input.sanitizeStringPointer(input.IdempotencyKey),
 			UserID:            userID,
 			RequestReceivedAt: time.Now().UTC(),
 			Contact:           to,
 			Content:           input.Content,
+			SenderPoolID:      services.SenderPoolID(input.From),
 		})
 	}
 
@@ -0,0 +1,73 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/exports"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// MessageExport is the payload for exporting the entities.Message of a user
+type MessageExport struct {
+	request
+	Format    string `json:"format" query:"format"`
+	Owner     string `json:"owner" query:"owner"`
+	Contacts  string `json:"contacts" query:"contacts"`
+	StartDate string `json:"start_date" query:"start_date"`
+	EndDate   string `json:"end_date" query:"end_date"`
+	Async     string `json:"async" query:"async"`
+}
+
+// Sanitize sets defaults to MessageExport
+func (input *MessageExport) Sanitize() MessageExport {
+	if input.Format = strings.ToLower(strings.TrimSpace(input.Format)); input.Format == "" {
+		input.Format = exports.FormatCSV.String()
+	}
+
+	if input.Owner = strings.TrimSpace(input.Owner); input.Owner != "" {
+		input.Owner = input.sanitizeAddress(input.Owner)
+	}
+
+	var contacts []string
+	for _, contact := range strings.Split(input.Contacts, ",") {
+		if contact = strings.TrimSpace(contact); contact != "" {
+			contacts = append(contacts, input.sanitizeContact(input.Owner, contact))
+		}
+	}
+	input.Contacts = strings.Join(input.removeStringDuplicates(contacts), ",")
+
+	input.StartDate = strings.TrimSpace(input.StartDate)
+	input.EndDate = strings.TrimSpace(input.EndDate)
+	input.Async = input.sanitizeBool(input.Async)
+	return *input
+}
+
+// ContactList returns the contacts which are used to filter the export
+func (input *MessageExport) ContactList() []string {
+	if input.Contacts == "" {
+		return []string{}
+	}
+	return strings.Split(input.Contacts, ",")
+}
+
+// IsAsync checks if the export must be created in the background even when it is small
+func (input *MessageExport) IsAsync() bool {
+	return input.getBool(input.Async)
+}
+
+// ToExportParams converts MessageExport to services.MessageExportParams
+func (input *MessageExport) ToExportParams(userID entities.UserID, source string) *services.MessageExportParams {
+	return &services.MessageExportParams{
+		MessageExportParams: repositories.MessageExportParams{
+			Owner:     input.Owner,
+			Contacts:  input.ContactList(),
+			StartDate: input.getTime(input.StartDate),
+			EndDate:   input.getTime(input.EndDate),
+		},
+		UserID: userID,
+		Format: exports.Format(input.Format),
+		Source: source,
+	}
+}
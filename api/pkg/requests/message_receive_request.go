@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
 
 	"github.com/nyaruka/phonenumbers"
 
@@ -23,6 +24,8 @@ type MessageReceive struct {
 	SIM entities.SIM `json:"sim" example:"SIM1"`
 	// Timestamp is the time when the event was emitted, Please send the timestamp in UTC with as much precision as possible
 	Timestamp time.Time `json:"timestamp" example:"2022-06-05T14:26:09.527976+03:00"`
+	// Attachments are the IDs of the entities.Attachment uploaded by the phone for an MMS message
+	Attachments []string `json:"attachments" example:"32343a19-da5e-4b1b-a767-3298a73703cb" validate:"optional"`
 }
 
 // Sanitize sets defaults to MessageReceive
@@ -32,9 +35,15 @@ func (input *MessageReceive) Sanitize() MessageReceive {
 	if strings.TrimSpace(string(input.SIM)) == "" || input.SIM == ("DEFAULT") {
 		input.SIM = entities.SIM1
 	}
+	input.Attachments = input.uniqueNonEmpty(input.Attachments, strings.TrimSpace)
 	return *input
 }
 
+// AttachmentIDs returns the IDs of the entities.Attachment of the message
+func (input *MessageReceive) AttachmentIDs() []uuid.UUID {
+	return input.parseUUIDs(input.Attachments)
+}
+
 // ToMessageReceiveParams converts MessageReceive to services.MessageReceiveParams
 func (input *MessageReceive) ToMessageReceiveParams(userID entities.UserID, source string) *services.MessageReceiveParams {
 	phone, _ := phonenumbers.Parse(input.To, phonenumbers.UNKNOWN_REGION)
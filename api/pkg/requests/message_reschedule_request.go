@@ -0,0 +1,22 @@
+package requests
+
+import (
+	"time"
+)
+
+// MessageReschedule is the payload for changing the send time of a message
+type MessageReschedule struct {
+	request
+
+	// SendAt is the new time when the message will be sent
+	SendAt time.Time `json:"send_at" example:"2022-06-05T14:26:09.527976+03:00"`
+
+	MessageID string `json:"messageID" swaggerignore:"true"` // used internally for validation
+}
+
+// Sanitize the message reschedule request
+func (input *MessageReschedule) Sanitize() MessageReschedule {
+	input.MessageID = input.sanitizeMessageID(input.MessageID)
+	input.SendAt = input.SendAt.UTC()
+	return *input
+}
@@ -0,0 +1,67 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+)
+
+// MessageSearch is the payload for searching all the entities.Message of a user
+type MessageSearch struct {
+	request
+	Query     string `json:"query" query:"query"`
+	Owner     string `json:"owner" query:"owner"`
+	Contact   string `json:"contact" query:"contact"`
+	Status    string `json:"status" query:"status"`
+	Type      string `json:"type" query:"type"`
+	Encrypted string `json:"encrypted" query:"encrypted"`
+	StartDate string `json:"start_date" query:"start_date"`
+	EndDate   string `json:"end_date" query:"end_date"`
+	Cursor    string `json:"cursor" query:"cursor"`
+	Limit     string `json:"limit" query:"limit"`
+}
+
+// Sanitize sets defaults to MessageSearch
+func (input *MessageSearch) Sanitize() MessageSearch {
+	if strings.TrimSpace(input.Limit) == "" {
+		input.Limit = "20"
+	}
+
+	input.Query = strings.TrimSpace(input.Query)
+	if input.Owner = strings.TrimSpace(input.Owner); input.Owner != "" {
+		input.Owner = input.sanitizeAddress(input.Owner)
+	}
+	if input.Contact = strings.TrimSpace(input.Contact); input.Contact != "" {
+		input.Contact = input.sanitizeContact(input.Owner, input.Contact)
+	}
+
+	input.Status = strings.ToLower(strings.TrimSpace(input.Status))
+	input.Type = strings.ToLower(strings.TrimSpace(input.Type))
+	input.Encrypted = input.sanitizeBool(input.Encrypted)
+	input.StartDate = strings.TrimSpace(input.StartDate)
+	input.EndDate = strings.TrimSpace(input.EndDate)
+	input.Cursor = strings.TrimSpace(input.Cursor)
+	return *input
+}
+
+// ToSearchParams converts MessageSearch to repositories.MessageSearchParams
+func (input *MessageSearch) ToSearchParams() repositories.MessageSearchParams {
+	params := repositories.MessageSearchParams{
+		Query:     input.Query,
+		Owner:     input.Owner,
+		Contact:   input.Contact,
+		Status:    input.Status,
+		Type:      input.Type,
+		StartDate: input.getTime(input.StartDate),
+		EndDate:   input.getTime(input.EndDate),
+		Cursor:    input.getCursor(input.Cursor),
+		Limit:     input.getInt(input.Limit),
+	}
+
+	if input.Encrypted != "" {
+		encrypted := input.getBool(input.Encrypted)
+		params.Encrypted = &encrypted
+	}
+
+	return params
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
 
 	"github.com/nyaruka/phonenumbers"
 
@@ -14,26 +15,56 @@ import (
 // MessageSend is the payload for sending and SMS message
 type MessageSend struct {
 	request
+	// From is the phone number which sends the message or the ID of an entities.SenderPool which chooses the phone
 	From    string `json:"from" example:"+18005550199"`
 	To      string `json:"to" example:"+18005550100"`
 	Content string `json:"content" example:"This is a sample text message"`
 
+	// ContactID is an optional ID of an entities.Contact which is used as the recipient when "to" is empty
+	ContactID string `json:"contact_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb" validate:"optional"`
+
 	// Encrypted is used to determine if the content is end-to-end encrypted. Make sure to set the encryption key on the httpSMS mobile app
 	Encrypted bool `json:"encrypted" example:"false"`
+	// TemplateID is an optional ID of an entities.MessageTemplate which is rendered as the content of the message
+	TemplateID string `json:"template_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb" validate:"optional"`
+	// Variables are the values of the {{variable}} placeholders in the message template
+	Variables map[string]string `json:"variables" example:"name:John" validate:"optional"`
+
+	// Attachments are optional IDs of entities.Attachment which are sent as an MMS message
+	Attachments []string `json:"attachments" example:"32343a19-da5e-4b1b-a767-3298a73703cb" validate:"optional"`
+
 	// RequestID is an optional parameter used to track a request from the client's perspective
 	RequestID string `json:"request_id" example:"153554b5-ae44-44a0-8f4f-7bbac5657ad4" validate:"optional"`
 	// SendAt is an optional parameter used to schedule a message to be sent at a later time
 	SendAt *time.Time `json:"send_at" example:"2022-06-05T14:26:09.527976+03:00" validate:"optional"`
+
+	// IdempotencyKey is set from the Idempotency-Key header and defaults to the RequestID
+	IdempotencyKey string `json:"-"`
 }
 
 // Sanitize sets defaults to MessageReceive
 func (input *MessageSend) Sanitize() MessageSend {
 	input.To = input.sanitizeAddress(input.To)
 	input.RequestID = strings.TrimSpace(input.RequestID)
+	input.IdempotencyKey = input.sanitizeIdempotencyKey(input.IdempotencyKey, input.RequestID)
 	input.From = input.sanitizeAddress(input.From)
+	input.TemplateID = strings.TrimSpace(input.TemplateID)
+	input.ContactID = strings.TrimSpace(input.ContactID)
+	input.Attachments = input.uniqueNonEmpty(input.Attachments, strings.TrimSpace)
 	return *input
 }
 
+// IsSenderPool checks if the message is sent through an entities.SenderPool instead of a phone number
+func (input *MessageSend) IsSenderPool() bool {
+	_, err := uuid.Parse(input.From)
+	return err == nil
+}
+
+// AttachmentIDs returns the IDs of the entities.Attachment of the message
+func (input *MessageSend) AttachmentIDs() []uuid.UUID {
+	return input.parseUUIDs(input.Attachments)
+}
+
 // ToMessageSendParams converts MessageSend to services.MessageSendParams
 func (input *MessageSend) ToMessageSendParams(userID entities.UserID, source string) services.MessageSendParams {
 	from, _ := phonenumbers.Parse(input.From, phonenumbers.UNKNOWN_REGION)
@@ -42,10 +73,12 @@ func (input *MessageSend) ToMessageSendParams(userID entities.UserID, source str
 		Owner:             from,
 		Encrypted:         input.Encrypted,
 		RequestID:         input.sanitizeStringPointer(input.RequestID),
+		IdempotencyKey:    input.sanitizeStringPointer(input.IdempotencyKey),
 		UserID:            userID,
 		SendAt:            input.SendAt,
 		RequestReceivedAt: time.Now().UTC(),
 		Contact:           input.sanitizeAddress(input.To),
 		Content:           input.Content,
+		SenderPoolID:      services.SenderPoolID(input.From),
 	}
 }
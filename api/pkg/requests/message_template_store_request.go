@@ -0,0 +1,31 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// MessageTemplateStore is the payload for creating a new entities.MessageTemplate
+type MessageTemplateStore struct {
+	request
+	Name    string `json:"name" example:"Appointment Reminder"`
+	Content string `json:"content" example:"Hello {{name}}, your appointment is on {{date}}"`
+}
+
+// Sanitize sets defaults to MessageTemplateStore
+func (input *MessageTemplateStore) Sanitize() MessageTemplateStore {
+	input.Name = strings.TrimSpace(input.Name)
+	input.Content = strings.TrimSpace(input.Content)
+	return *input
+}
+
+// ToStoreParams converts MessageTemplateStore to services.MessageTemplateStoreParams
+func (input *MessageTemplateStore) ToStoreParams(user entities.AuthUser) *services.MessageTemplateStoreParams {
+	return &services.MessageTemplateStoreParams{
+		UserID:  user.ID,
+		Name:    input.Name,
+		Content: input.Content,
+	}
+}
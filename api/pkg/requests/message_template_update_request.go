@@ -0,0 +1,29 @@
+package requests
+
+import (
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/google/uuid"
+)
+
+// MessageTemplateUpdate is the payload for updating an entities.MessageTemplate
+type MessageTemplateUpdate struct {
+	MessageTemplateStore
+	MessageTemplateID string `json:"messageTemplateID" swaggerignore:"true"` // used internally for validation
+}
+
+// Sanitize sets defaults to MessageTemplateUpdate
+func (input *MessageTemplateUpdate) Sanitize() MessageTemplateUpdate {
+	input.MessageTemplateStore.Sanitize()
+	return *input
+}
+
+// ToUpdateParams converts MessageTemplateUpdate to services.MessageTemplateUpdateParams
+func (input *MessageTemplateUpdate) ToUpdateParams(user entities.AuthUser) *services.MessageTemplateUpdateParams {
+	return &services.MessageTemplateUpdateParams{
+		UserID:     user.ID,
+		Name:       input.Name,
+		Content:    input.Content,
+		TemplateID: uuid.MustParse(input.MessageTemplateID),
+	}
+}
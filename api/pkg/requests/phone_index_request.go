@@ -9,9 +9,10 @@ import (
 // PhoneIndex is the payload fetching registered phones
 type PhoneIndex struct {
 	request
-	Skip  string `json:"skip" query:"skip"`
-	Query string `json:"query" query:"query"`
-	Limit string `json:"limit" query:"limit"`
+	Skip   string `json:"skip" query:"skip"`
+	Query  string `json:"query" query:"query"`
+	Limit  string `json:"limit" query:"limit"`
+	Cursor string `json:"cursor" query:"cursor"`
 }
 
 // Sanitize sets defaults to MessageOutstanding
@@ -24,14 +25,16 @@ func (input *PhoneIndex) Sanitize() PhoneIndex {
 	if input.Skip == "" {
 		input.Skip = "0"
 	}
+	input.Cursor = strings.TrimSpace(input.Cursor)
 	return *input
 }
 
 // ToIndexParams converts HeartbeatIndex to repositories.IndexParams
 func (input *PhoneIndex) ToIndexParams() repositories.IndexParams {
 	return repositories.IndexParams{
-		Skip:  input.getInt(input.Skip),
-		Query: input.Query,
-		Limit: input.getInt(input.Limit),
+		Skip:   input.getInt(input.Skip),
+		Query:  input.Query,
+		Limit:  input.getInt(input.Limit),
+		Cursor: input.getCursor(input.Cursor),
 	}
 }
@@ -22,10 +22,34 @@ type PhoneUpsert struct {
 	// MaxSendAttempts is the number of attempts when sending an SMS message to handle the case where the phone is offline.
 	MaxSendAttempts uint `json:"max_send_attempts" example:"2"`
 
+	// MaxSegments is the maximum number of SMS segments of an outgoing message. Set it to 0 to remove the limit.
+	MaxSegments *uint `json:"max_segments" example:"3" validate:"optional"`
+
 	FcmToken string `json:"fcm_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJzd....."`
 
 	MissedCallAutoReply *string `json:"missed_call_auto_reply" example:"e.g. This phone cannot receive calls. Please send an SMS instead."`
 
+	// OptOutKeywords are the replies which add the contact to the suppression list e.g STOP
+	OptOutKeywords *[]string `json:"opt_out_keywords" example:"STOP,UNSUBSCRIBE" validate:"optional"`
+
+	// OptInKeywords are the replies which remove the contact from the suppression list e.g START
+	OptInKeywords *[]string `json:"opt_in_keywords" example:"START" validate:"optional"`
+
+	// BusinessHours are the weekly opening hours of the phone in your timezone
+	BusinessHours *[]entities.BusinessHours `json:"business_hours" validate:"optional"`
+
+	// Holidays are the dates in the YYYY-MM-DD format when the phone is out of office for the whole day
+	Holidays *[]string `json:"holidays" example:"2024-12-25" validate:"optional"`
+
+	// OutOfOfficeReply is sent once per contact when an SMS or a missed call arrives outside the business hours
+	OutOfOfficeReply *string `json:"out_of_office_reply" example:"We are closed. Our business hours are 9am to 5pm, Monday to Friday." validate:"optional"`
+
+	// QuietHoursStart is the time in the HH:MM format in your timezone from when outgoing messages are deferred. Set it to the same value as QuietHoursEnd to disable quiet hours.
+	QuietHoursStart *string `json:"quiet_hours_start" example:"21:00" validate:"optional"`
+
+	// QuietHoursEnd is the time in the HH:MM format in your timezone when deferred outgoing messages are sent
+	QuietHoursEnd *string `json:"quiet_hours_end" example:"08:00" validate:"optional"`
+
 	// SIM is the SIM slot of the phone in case the phone has more than 1 SIM slot
 	SIM string `json:"sim" example:"SIM1"`
 }
@@ -38,6 +62,31 @@ func (input *PhoneUpsert) Sanitize() PhoneUpsert {
 	if input.MissedCallAutoReply != nil {
 		input.MissedCallAutoReply = input.sanitizeStringPointer(*input.MissedCallAutoReply)
 	}
+	if input.OptOutKeywords != nil {
+		input.OptOutKeywords = input.sanitizeKeywords(*input.OptOutKeywords)
+	}
+	if input.OptInKeywords != nil {
+		input.OptInKeywords = input.sanitizeKeywords(*input.OptInKeywords)
+	}
+	if input.BusinessHours != nil {
+		for index, hours := range *input.BusinessHours {
+			(*input.BusinessHours)[index].Start = strings.TrimSpace(hours.Start)
+			(*input.BusinessHours)[index].End = strings.TrimSpace(hours.End)
+		}
+	}
+	if input.Holidays != nil {
+		holidays := input.uniqueNonEmpty(*input.Holidays, strings.TrimSpace)
+		input.Holidays = &holidays
+	}
+	if input.OutOfOfficeReply != nil {
+		input.OutOfOfficeReply = input.sanitizeStringPointer(*input.OutOfOfficeReply)
+	}
+	if input.QuietHoursStart != nil {
+		input.QuietHoursStart = input.sanitizeStringPointer(*input.QuietHoursStart)
+	}
+	if input.QuietHoursEnd != nil {
+		input.QuietHoursEnd = input.sanitizeStringPointer(*input.QuietHoursEnd)
+	}
 	return *input
 }
 
@@ -74,10 +123,25 @@ func (input *PhoneUpsert) ToUpsertParams(user entities.AuthUser, source string)
 		PhoneNumber:               phone,
 		MessagesPerMinute:         messagesPerMinute,
 		MissedCallAutoReply:       input.MissedCallAutoReply,
+		OptOutKeywords:            input.OptOutKeywords,
+		OptInKeywords:             input.OptInKeywords,
+		BusinessHours:             input.BusinessHours,
+		Holidays:                  input.Holidays,
+		OutOfOfficeReply:          input.OutOfOfficeReply,
+		QuietHoursStart:           input.QuietHoursStart,
+		QuietHoursEnd:             input.QuietHoursEnd,
 		MessageExpirationDuration: timeout,
 		MaxSendAttempts:           maxSendAttempts,
+		MaxSegments:               input.MaxSegments,
 		FcmToken:                  fcmToken,
 		UserID:                    user.ID,
 		SIM:                       entities.SIM(input.SIM),
 	}
 }
+
+func (input *PhoneUpsert) sanitizeKeywords(keywords []string) *[]string {
+	result := input.uniqueNonEmpty(keywords, func(keyword string) string {
+		return strings.ToUpper(strings.TrimSpace(keyword))
+	})
+	return &result
+}
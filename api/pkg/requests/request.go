@@ -4,9 +4,12 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/google/uuid"
 
 	"github.com/nyaruka/phonenumbers"
 )
@@ -86,6 +89,23 @@ func (input *request) sanitizeStringPointer(value string) *string {
 	return &value
 }
 
+// parseUUIDs converts IDs which have already been validated
+func (input *request) parseUUIDs(values []string) []uuid.UUID {
+	result := make([]uuid.UUID, 0, len(values))
+	for _, value := range values {
+		result = append(result, uuid.MustParse(value))
+	}
+	return result
+}
+
+// sanitizeIdempotencyKey falls back to the request ID when the Idempotency-Key header is not set
+func (input *request) sanitizeIdempotencyKey(key string, requestID string) string {
+	if key = strings.TrimSpace(key); key != "" {
+		return key
+	}
+	return strings.TrimSpace(requestID)
+}
+
 func (input *request) removeStringDuplicates(values []string) []string {
 	cache := map[string]struct{}{}
 	for _, value := range values {
@@ -125,6 +145,31 @@ func (input *request) getInt(value string) int {
 	return val
 }
 
+// getCursor decodes a cursor which has already been validated
+func (input *request) getCursor(value string) *repositories.Cursor {
+	if value == "" {
+		return nil
+	}
+	cursor, err := repositories.ParseCursor(value)
+	if err != nil {
+		return nil
+	}
+	return cursor
+}
+
+// getTime parses an RFC3339 timestamp which has already been validated
+func (input *request) getTime(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	timestamp, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	timestamp = timestamp.UTC()
+	return &timestamp
+}
+
 func (input *request) isDigits(value string) bool {
 	for _, c := range value {
 		if !unicode.IsDigit(c) {
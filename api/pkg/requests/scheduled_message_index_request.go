@@ -0,0 +1,41 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+)
+
+// ScheduledMessageIndex is the payload for fetching entities.ScheduledMessage of a user
+type ScheduledMessageIndex struct {
+	request
+	Owner string `json:"owner" query:"owner"`
+	Skip  string `json:"skip" query:"skip"`
+	Query string `json:"query" query:"query"`
+	Limit string `json:"limit" query:"limit"`
+}
+
+// Sanitize sets defaults to ScheduledMessageIndex
+func (input *ScheduledMessageIndex) Sanitize() ScheduledMessageIndex {
+	if strings.TrimSpace(input.Limit) == "" {
+		input.Limit = "20"
+	}
+	if strings.TrimSpace(input.Owner) != "" {
+		input.Owner = input.sanitizeAddress(input.Owner)
+	}
+	input.Query = strings.TrimSpace(input.Query)
+	input.Skip = strings.TrimSpace(input.Skip)
+	if input.Skip == "" {
+		input.Skip = "0"
+	}
+	return *input
+}
+
+// ToIndexParams converts ScheduledMessageIndex to repositories.IndexParams
+func (input *ScheduledMessageIndex) ToIndexParams() repositories.IndexParams {
+	return repositories.IndexParams{
+		Skip:  input.getInt(input.Skip),
+		Query: input.Query,
+		Limit: input.getInt(input.Limit),
+	}
+}
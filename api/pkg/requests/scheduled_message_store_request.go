@@ -0,0 +1,49 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// ScheduledMessageStore is the payload for creating a new entities.ScheduledMessage
+type ScheduledMessageStore struct {
+	request
+	// From is the phone number which sends the message or the ID of an entities.SenderPool which chooses the phone
+	From    string `json:"from" example:"+18005550199"`
+	To      string `json:"to" example:"+18005550100"`
+	Content string `json:"content" example:"Your weekly reminder to submit your timesheet"`
+
+	// Format is the syntax of the expression. One of cron or rrule
+	Format string `json:"format" example:"cron" validate:"optional"`
+
+	// Expression is a 5 field cron expression or an RRULE evaluated in your timezone e.g "0 9 * * MON" or "FREQ=WEEKLY;BYDAY=MO;BYHOUR=9;BYMINUTE=0"
+	Expression string `json:"expression" example:"0 9 * * MON"`
+}
+
+// Sanitize sets defaults to ScheduledMessageStore
+func (input *ScheduledMessageStore) Sanitize() ScheduledMessageStore {
+	input.From = input.sanitizeAddress(input.From)
+	input.To = input.sanitizeAddress(input.To)
+	input.Expression = strings.TrimPrefix(strings.TrimSpace(input.Expression), "RRULE:")
+
+	input.Format = strings.ToLower(strings.TrimSpace(input.Format))
+	if input.Format == "" {
+		input.Format = entities.ScheduledMessageFormatCron.String()
+	}
+	return *input
+}
+
+// ToStoreParams converts ScheduledMessageStore to services.ScheduledMessageStoreParams
+func (input *ScheduledMessageStore) ToStoreParams(user entities.AuthUser, source string) *services.ScheduledMessageStoreParams {
+	return &services.ScheduledMessageStoreParams{
+		Source:     source,
+		UserID:     user.ID,
+		Owner:      input.From,
+		Contact:    input.To,
+		Content:    input.Content,
+		Format:     entities.ScheduledMessageFormat(input.Format),
+		Expression: input.Expression,
+	}
+}
@@ -0,0 +1,31 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// SenderPoolStore is the payload for creating a new entities.SenderPool
+type SenderPoolStore struct {
+	request
+	Name         string   `json:"name" example:"Customer Support"`
+	PhoneNumbers []string `json:"phone_numbers" example:"+18005550199,+18005550100"`
+}
+
+// Sanitize sets defaults to SenderPoolStore
+func (input *SenderPoolStore) Sanitize() SenderPoolStore {
+	input.Name = strings.TrimSpace(input.Name)
+	input.PhoneNumbers = input.sanitizePhoneNumbers(input.PhoneNumbers)
+	return *input
+}
+
+// ToStoreParams converts SenderPoolStore to services.SenderPoolStoreParams
+func (input *SenderPoolStore) ToStoreParams(user entities.AuthUser) *services.SenderPoolStoreParams {
+	return &services.SenderPoolStoreParams{
+		UserID:       user.ID,
+		Name:         input.Name,
+		PhoneNumbers: input.PhoneNumbers,
+	}
+}
@@ -0,0 +1,27 @@
+package requests
+
+import (
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/google/uuid"
+)
+
+// SenderPoolUpdate is the payload for updating an entities.SenderPool
+type SenderPoolUpdate struct {
+	SenderPoolStore
+	SenderPoolID string `json:"senderPoolID" swaggerignore:"true"` // used internally for validation
+}
+
+// Sanitize sets defaults to SenderPoolUpdate
+func (input *SenderPoolUpdate) Sanitize() SenderPoolUpdate {
+	input.SenderPoolStore.Sanitize()
+	return *input
+}
+
+// ToUpdateParams converts SenderPoolUpdate to services.SenderPoolUpdateParams
+func (input *SenderPoolUpdate) ToUpdateParams(user entities.AuthUser) *services.SenderPoolUpdateParams {
+	return &services.SenderPoolUpdateParams{
+		SenderPoolStoreParams: *input.ToStoreParams(user),
+		PoolID:                uuid.MustParse(input.SenderPoolID),
+	}
+}
@@ -0,0 +1,28 @@
+package requests
+
+import (
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// SuppressionDelete is the payload for removing a phone number from the suppression list
+type SuppressionDelete struct {
+	request
+	PhoneNumber string `json:"phoneNumber" swaggerignore:"true"` // used internally for validation
+}
+
+// Sanitize sets defaults to SuppressionDelete
+func (input *SuppressionDelete) Sanitize() SuppressionDelete {
+	input.PhoneNumber = input.sanitizeAddress(input.PhoneNumber)
+	return *input
+}
+
+// ToDeleteParams converts SuppressionDelete to services.SuppressionDeleteParams
+func (input *SuppressionDelete) ToDeleteParams(user entities.AuthUser, source string) *services.SuppressionDeleteParams {
+	return &services.SuppressionDeleteParams{
+		Source:      source,
+		UserID:      user.ID,
+		PhoneNumber: input.PhoneNumber,
+		Origin:      entities.SuppressionSourceAPI,
+	}
+}
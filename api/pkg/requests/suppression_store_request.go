@@ -0,0 +1,30 @@
+package requests
+
+import (
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// SuppressionStore is the payload for adding a phone number to the suppression list
+type SuppressionStore struct {
+	request
+	PhoneNumber string `json:"phone_number" example:"+18005550100"`
+	Reason      string `json:"reason" example:"The customer asked not to be contacted" validate:"optional"`
+}
+
+// Sanitize sets defaults to SuppressionStore
+func (input *SuppressionStore) Sanitize() SuppressionStore {
+	input.PhoneNumber = input.sanitizeAddress(input.PhoneNumber)
+	return *input
+}
+
+// ToStoreParams converts SuppressionStore to services.SuppressionStoreParams
+func (input *SuppressionStore) ToStoreParams(user entities.AuthUser, source string) *services.SuppressionStoreParams {
+	return &services.SuppressionStoreParams{
+		Source:      source,
+		UserID:      user.ID,
+		PhoneNumber: input.PhoneNumber,
+		Origin:      entities.SuppressionSourceAPI,
+		Reason:      input.sanitizeStringPointer(input.Reason),
+	}
+}
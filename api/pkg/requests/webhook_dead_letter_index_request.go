@@ -0,0 +1,46 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+)
+
+// WebhookDeadLetterIndex is the payload for fetching entities.WebhookDeadLetter of a webhook
+type WebhookDeadLetterIndex struct {
+	request
+	WebhookID string `json:"webhookID" swaggerignore:"true"` // used internally for validation
+	Skip      string `json:"skip" query:"skip"`
+	Query     string `json:"query" query:"query"`
+	Limit     string `json:"limit" query:"limit"`
+}
+
+// Sanitize sets defaults to WebhookDeadLetterIndex
+func (input *WebhookDeadLetterIndex) Sanitize() WebhookDeadLetterIndex {
+	if strings.TrimSpace(input.Limit) == "" {
+		input.Limit = "20"
+	}
+	input.Query = strings.TrimSpace(input.Query)
+	input.WebhookID = strings.TrimSpace(input.WebhookID)
+	input.Skip = strings.TrimSpace(input.Skip)
+	if input.Skip == "" {
+		input.Skip = "0"
+	}
+	return *input
+}
+
+// ToIndexParams converts WebhookDeadLetterIndex to repositories.IndexParams
+func (input *WebhookDeadLetterIndex) ToIndexParams() repositories.IndexParams {
+	return repositories.IndexParams{
+		Skip:  input.getInt(input.Skip),
+		Query: input.Query,
+		Limit: input.getInt(input.Limit),
+	}
+}
+
+// WebhookUUID returns the webhook ID as uuid.UUID
+func (input *WebhookDeadLetterIndex) WebhookUUID() uuid.UUID {
+	return uuid.MustParse(input.WebhookID)
+}
@@ -0,0 +1,52 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/google/uuid"
+)
+
+// WebhookDeliveryIndex is the payload for fetching entities.WebhookDeliveryAttempt of a webhook
+type WebhookDeliveryIndex struct {
+	request
+	WebhookID string `json:"webhookID" swaggerignore:"true"` // used internally for validation
+	Skip      string `json:"skip" query:"skip"`
+	Query     string `json:"query" query:"query"`
+	Limit     string `json:"limit" query:"limit"`
+	Status    string `json:"status" query:"status"`
+	EventType string `json:"event_type" query:"event_type"`
+}
+
+// Sanitize sets defaults to WebhookDeliveryIndex
+func (input *WebhookDeliveryIndex) Sanitize() WebhookDeliveryIndex {
+	if strings.TrimSpace(input.Limit) == "" {
+		input.Limit = "20"
+	}
+	input.Query = strings.TrimSpace(input.Query)
+	input.WebhookID = strings.TrimSpace(input.WebhookID)
+	input.Status = strings.ToLower(strings.TrimSpace(input.Status))
+	input.EventType = strings.TrimSpace(input.EventType)
+	input.Skip = strings.TrimSpace(input.Skip)
+	if input.Skip == "" {
+		input.Skip = "0"
+	}
+	return *input
+}
+
+// ToIndexParams converts WebhookDeliveryIndex to services.WebhookDeliveryIndexParams
+func (input *WebhookDeliveryIndex) ToIndexParams(userID entities.UserID) *services.WebhookDeliveryIndexParams {
+	return &services.WebhookDeliveryIndexParams{
+		UserID:    userID,
+		WebhookID: uuid.MustParse(input.WebhookID),
+		Status:    input.Status,
+		EventType: input.EventType,
+		IndexParams: repositories.IndexParams{
+			Skip:  input.getInt(input.Skip),
+			Query: input.Query,
+			Limit: input.getInt(input.Limit),
+		},
+	}
+}
@@ -10,33 +10,46 @@ import (
 // WebhookStore is the payload for creating a new entities.Webhook
 type WebhookStore struct {
 	request
-	SigningKey   string   `json:"signing_key"`
-	URL          string   `json:"url"`
-	PhoneNumbers []string `json:"phone_numbers" example:"+18005550100,+18005550100"`
-	Events       []string `json:"events"`
+	SigningKey    string   `json:"signing_key"`
+	SigningScheme string   `json:"signing_scheme" example:"hmac-sha256"`
+	Format        string   `json:"format" example:"cloudevents-structured"`
+	URL           string   `json:"url"`
+	PhoneNumbers  []string `json:"phone_numbers" example:"+18005550100,+18005550100"`
+	Events        []string `json:"events"`
 }
 
-// Sanitize sets defaults to WebhookStore
+// Sanitize sets defaults to WebhookStore, the format is chosen from the URL when it is empty
 func (input *WebhookStore) Sanitize() WebhookStore {
+	input.sanitize()
+	if input.SigningScheme == "" {
+		input.SigningScheme = string(entities.WebhookSigningSchemeJWT)
+	}
+	return *input
+}
+
+// sanitize cleans up the fields without setting defaults so that an update keeps the stored values of empty fields
+func (input *WebhookStore) sanitize() {
 	input.URL = input.sanitizeURL(input.URL)
 	input.SigningKey = strings.TrimSpace(input.SigningKey)
+	input.SigningScheme = strings.ToLower(strings.TrimSpace(input.SigningScheme))
+	input.Format = strings.ToLower(strings.TrimSpace(input.Format))
 	input.Events = input.removeStringDuplicates(input.Events)
 
 	var phoneNumbers []string
 	for _, address := range input.PhoneNumbers {
 		phoneNumbers = append(phoneNumbers, input.sanitizeAddress(address))
 	}
-
-	return *input
 }
 
 // ToStoreParams converts WebhookStore to services.WebhookStoreParams
 func (input *WebhookStore) ToStoreParams(user entities.AuthUser) *services.WebhookStoreParams {
 	return &services.WebhookStoreParams{
-		UserID:       user.ID,
-		SigningKey:   input.SigningKey,
-		URL:          input.URL,
-		PhoneNumbers: input.PhoneNumbers,
-		Events:       input.Events,
+		UserID:        user.ID,
+		SigningKey:    input.SigningKey,
+		SigningScheme: entities.WebhookSigningScheme(input.SigningScheme),
+		Format:        entities.WebhookFormat(input.Format),
+		URL:           input.URL,
+		PhoneNumbers:  input.PhoneNumbers,
+		Events:        input.Events,
 	}
 }
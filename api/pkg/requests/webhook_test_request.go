@@ -0,0 +1,37 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/google/uuid"
+)
+
+// WebhookTest is the payload for sending a sample event to an entities.Webhook
+type WebhookTest struct {
+	request
+	WebhookID string `json:"webhookID" swaggerignore:"true"` // used internally for validation
+	EventType string `json:"event_type" example:"message.phone.received"`
+}
+
+// Sanitize sets defaults to WebhookTest
+func (input *WebhookTest) Sanitize() WebhookTest {
+	input.WebhookID = strings.TrimSpace(input.WebhookID)
+	input.EventType = strings.TrimSpace(input.EventType)
+	if input.EventType == "" {
+		input.EventType = events.EventTypeMessagePhoneReceived
+	}
+	return *input
+}
+
+// ToTestParams converts WebhookTest to services.WebhookTestParams
+func (input *WebhookTest) ToTestParams(source string, user entities.AuthUser) *services.WebhookTestParams {
+	return &services.WebhookTestParams{
+		Source:    source,
+		UserID:    user.ID,
+		WebhookID: uuid.MustParse(input.WebhookID),
+		EventType: input.EventType,
+	}
+}
@@ -12,20 +12,22 @@ type WebhookUpdate struct {
 	WebhookID string `json:"webhookID" swaggerignore:"true"` // used internally for validation
 }
 
-// Sanitize sets defaults to WebhookUpdate
+// Sanitize sets defaults to WebhookUpdate, the stored signing_scheme and format are kept when they are empty
 func (input *WebhookUpdate) Sanitize() WebhookUpdate {
-	input.WebhookStore.Sanitize()
+	input.WebhookStore.sanitize()
 	return *input
 }
 
 // ToUpdateParams converts WebhookUpdate to services.WebhookUpdateParams
 func (input *WebhookUpdate) ToUpdateParams(user entities.AuthUser) *services.WebhookUpdateParams {
 	return &services.WebhookUpdateParams{
-		UserID:       user.ID,
-		WebhookID:    uuid.MustParse(input.WebhookID),
-		SigningKey:   input.SigningKey,
-		URL:          input.URL,
-		PhoneNumbers: input.PhoneNumbers,
-		Events:       input.Events,
+		UserID:        user.ID,
+		WebhookID:     uuid.MustParse(input.WebhookID),
+		SigningKey:    input.SigningKey,
+		SigningScheme: entities.WebhookSigningScheme(input.SigningScheme),
+		Format:        entities.WebhookFormat(input.Format),
+		URL:           input.URL,
+		PhoneNumbers:  input.PhoneNumbers,
+		Events:        input.Events,
 	}
 }
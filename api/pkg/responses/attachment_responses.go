@@ -0,0 +1,9 @@
+package responses
+
+import "github.com/NdoleStudio/httpsms/pkg/entities"
+
+// AttachmentResponse is the payload containing entities.Attachment
+type AttachmentResponse struct {
+	response
+	Data entities.Attachment `json:"data"`
+}
@@ -0,0 +1,15 @@
+package responses
+
+import "github.com/NdoleStudio/httpsms/pkg/entities"
+
+// AutoReplyResponse is the payload containing entities.AutoReply
+type AutoReplyResponse struct {
+	response
+	Data entities.AutoReply `json:"data"`
+}
+
+// AutoRepliesResponse is the payload containing []entities.AutoReply
+type AutoRepliesResponse struct {
+	response
+	Data []entities.AutoReply `json:"data"`
+}
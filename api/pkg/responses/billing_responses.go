@@ -8,6 +8,9 @@ type BillingUsagesResponse struct {
 	Data []entities.BillingUsage `json:"data"`
 }
 
+// BillingUsagesCursorResponse is the payload containing a page of []entities.BillingUsage
+type BillingUsagesCursorResponse OkCursor[[]entities.BillingUsage]
+
 // BillingUsageResponse is the payload containing entities.BillingUsage
 type BillingUsageResponse struct {
 	response
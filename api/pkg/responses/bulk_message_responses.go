@@ -0,0 +1,9 @@
+package responses
+
+import "github.com/NdoleStudio/httpsms/pkg/entities"
+
+// BulkMessageJobResponse is the payload containing entities.BulkMessageJob
+type BulkMessageJobResponse struct {
+	response
+	Data entities.BulkMessageJob `json:"data"`
+}
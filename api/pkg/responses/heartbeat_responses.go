@@ -8,6 +8,9 @@ type HeartbeatsResponse struct {
 	Data []entities.Heartbeat `json:"data"`
 }
 
+// HeartbeatsCursorResponse is the payload containing a page of []entities.Heartbeat
+type HeartbeatsCursorResponse OkCursor[[]entities.Heartbeat]
+
 // HeartbeatResponse is the payload containing entities.Heartbeat
 type HeartbeatResponse struct {
 	response
@@ -13,3 +13,18 @@ type MessagesResponse struct {
 	response
 	Data []entities.Message `json:"data"`
 }
+
+// MessagesCursorResponse is the payload containing a page of []entities.Message
+type MessagesCursorResponse OkCursor[[]entities.Message]
+
+// MessageAnalysisResponse is the payload containing the entities.MessageAnalysis of the content of a message
+type MessageAnalysisResponse struct {
+	response
+	Data entities.MessageAnalysis `json:"data"`
+}
+
+// MessageExportResponse is the payload containing entities.MessageExport
+type MessageExportResponse struct {
+	response
+	Data entities.MessageExport `json:"data"`
+}
@@ -0,0 +1,15 @@
+package responses
+
+import "github.com/NdoleStudio/httpsms/pkg/entities"
+
+// MessageTemplateResponse is the payload containing entities.MessageTemplate
+type MessageTemplateResponse struct {
+	response
+	Data entities.MessageTemplate `json:"data"`
+}
+
+// MessageTemplatesResponse is the payload containing []entities.MessageTemplate
+type MessageTemplatesResponse struct {
+	response
+	Data []entities.MessageTemplate `json:"data"`
+}
@@ -7,3 +7,6 @@ type MessageThreadsResponse struct {
 	response
 	Data []entities.MessageThread `json:"data"`
 }
+
+// MessageThreadsCursorResponse is the payload containing a page of []entities.MessageThread
+type MessageThreadsCursorResponse OkCursor[[]entities.MessageThread]
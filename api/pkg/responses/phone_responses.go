@@ -8,6 +8,9 @@ type PhonesResponse struct {
 	Data []entities.Phone `json:"data"`
 }
 
+// PhonesCursorResponse is the payload containing a page of []entities.Phone
+type PhonesCursorResponse OkCursor[[]entities.Phone]
+
 // PhoneResponse is the payload containing entities.Phone
 type PhoneResponse struct {
 	response
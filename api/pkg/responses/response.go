@@ -17,6 +17,12 @@ type NotFound struct {
 	Message string `json:"message" example:"cannot find message with ID [32343a19-da5e-4b1b-a767-3298a73703ca]"`
 }
 
+// Conflict is the response with status code is 409
+type Conflict struct {
+	Status  string `json:"status" example:"error"`
+	Message string `json:"message" example:"A message with the same Idempotency-Key is still being sent, retry the request later."`
+}
+
 // BadRequest is the response with status code is 400
 type BadRequest struct {
 	Status  string `json:"status" example:"error"`
@@ -51,5 +57,13 @@ type Ok[T any] struct {
 	Data    T      `json:"data"`
 }
 
+// OkCursor is the response with status code is 200 for a page of items. The next page is fetched with the next_cursor
+type OkCursor[T any] struct {
+	Status     string  `json:"status" example:"success"`
+	Message    string  `json:"message" example:"Request handled successfully"`
+	Data       T       `json:"data"`
+	NextCursor *string `json:"next_cursor" example:"eyJ0IjoiMjAyMi0wNi0wNVQxMToyNjowOS41Mjc5NzZaIiwiaSI6IjMyMzQzYTE5LWRhNWUtNGIxYi1hNzY3LTMyOThhNzM3MDNjYiJ9"`
+}
+
 // OkString returns a string response
 type OkString Ok[string]
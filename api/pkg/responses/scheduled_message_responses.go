@@ -0,0 +1,15 @@
+package responses
+
+import "github.com/NdoleStudio/httpsms/pkg/entities"
+
+// ScheduledMessageResponse is the payload containing entities.ScheduledMessage
+type ScheduledMessageResponse struct {
+	response
+	Data entities.ScheduledMessage `json:"data"`
+}
+
+// ScheduledMessagesResponse is the payload containing []entities.ScheduledMessage
+type ScheduledMessagesResponse struct {
+	response
+	Data []entities.ScheduledMessage `json:"data"`
+}
@@ -0,0 +1,15 @@
+package responses
+
+import "github.com/NdoleStudio/httpsms/pkg/entities"
+
+// SenderPoolResponse is the payload containing entities.SenderPool
+type SenderPoolResponse struct {
+	response
+	Data entities.SenderPool `json:"data"`
+}
+
+// SenderPoolsResponse is the payload containing []entities.SenderPool
+type SenderPoolsResponse struct {
+	response
+	Data []entities.SenderPool `json:"data"`
+}
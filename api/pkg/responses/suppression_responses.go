@@ -0,0 +1,15 @@
+package responses
+
+import "github.com/NdoleStudio/httpsms/pkg/entities"
+
+// SuppressionResponse is the payload containing entities.Suppression
+type SuppressionResponse struct {
+	response
+	Data entities.Suppression `json:"data"`
+}
+
+// SuppressionsResponse is the payload containing []entities.Suppression
+type SuppressionsResponse struct {
+	response
+	Data []entities.Suppression `json:"data"`
+}
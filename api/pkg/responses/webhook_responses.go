@@ -13,3 +13,30 @@ type WebhooksResponse struct {
 	response
 	Data []entities.Webhook `json:"data"`
 }
+
+// WebhooksCursorResponse is the payload containing a page of []entities.Webhook
+type WebhooksCursorResponse OkCursor[[]entities.Webhook]
+
+// WebhookDeliveryResponse is the payload containing entities.WebhookDelivery
+type WebhookDeliveryResponse struct {
+	response
+	Data entities.WebhookDelivery `json:"data"`
+}
+
+// WebhookDeadLettersResponse is the payload containing []entities.WebhookDeadLetter
+type WebhookDeadLettersResponse struct {
+	response
+	Data []entities.WebhookDeadLetter `json:"data"`
+}
+
+// WebhookDeliveryAttemptsResponse is the payload containing []entities.WebhookDeliveryAttempt
+type WebhookDeliveryAttemptsResponse struct {
+	response
+	Data []entities.WebhookDeliveryAttempt `json:"data"`
+}
+
+// WebhookTestResponse is the payload containing entities.WebhookTestResult
+type WebhookTestResponse struct {
+	response
+	Data entities.WebhookTestResult `json:"data"`
+}
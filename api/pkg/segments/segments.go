@@ -0,0 +1,124 @@
+// Package segments detects the encoding of an SMS message and counts the number of SMS segments needed to send it.
+package segments
+
+import (
+	"strings"
+)
+
+// Encoding is the character set used to send an SMS message
+type Encoding string
+
+const (
+	// EncodingGSM7 is the GSM 03.38 7-bit default alphabet
+	EncodingGSM7 = Encoding("GSM-7")
+
+	// EncodingUCS2 is the 16-bit encoding used when a character is not in the GSM 03.38 alphabet
+	EncodingUCS2 = Encoding("UCS-2")
+)
+
+// String converts the Encoding to a string
+func (encoding Encoding) String() string {
+	return string(encoding)
+}
+
+const (
+	gsm7SingleSegmentLength    = 160
+	gsm7MultipleSegmentLength  = 153
+	ucs2SingleSegmentLength    = 70
+	ucs2MultipleSegmentLength  = 67
+	gsm7BasicCharacters        = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+	gsm7ExtensionCharacters    = "\f^{}\\[~]|€"
+	gsm7ExtensionCharacterSize = 2
+)
+
+// Analysis is the result of analyzing the content of an SMS message
+type Analysis struct {
+	Encoding Encoding
+	// Characters is the number of characters in the content
+	Characters int
+	// Length is the number of GSM-7 septets or UCS-2 code units in the content
+	Length int
+	// Segments is the number of SMS messages which are needed to send the content
+	Segments int
+	// Remaining is the number of GSM-7 septets or UCS-2 code units which can still be added to the last segment
+	Remaining int
+	// UnicodeCharacters are the unique characters which are not in the GSM 03.38 alphabet
+	UnicodeCharacters []string
+}
+
+// Analyze detects the encoding of the content and counts the SMS segments
+func Analyze(content string) Analysis {
+	unicodeCharacters := nonGSM7Characters(content)
+	if len(unicodeCharacters) == 0 {
+		return split(EncodingGSM7, content, gsm7Size, gsm7SingleSegmentLength, gsm7MultipleSegmentLength, unicodeCharacters)
+	}
+	return split(EncodingUCS2, content, ucs2Size, ucs2SingleSegmentLength, ucs2MultipleSegmentLength, unicodeCharacters)
+}
+
+// IsGSM7 checks if the content can be sent with the GSM 03.38 alphabet
+func IsGSM7(content string) bool {
+	return len(nonGSM7Characters(content)) == 0
+}
+
+func split(encoding Encoding, content string, size func(rune) int, singleLength int, multipleLength int, unicodeCharacters []string) Analysis {
+	analysis := Analysis{
+		Encoding:          encoding,
+		Characters:        len([]rune(content)),
+		Segments:          1,
+		UnicodeCharacters: unicodeCharacters,
+	}
+
+	for _, char := range content {
+		analysis.Length += size(char)
+	}
+
+	if analysis.Length <= singleLength {
+		analysis.Remaining = singleLength - analysis.Length
+		return analysis
+	}
+
+	// a character which uses more than 1 unit cannot be split across 2 segments
+	current := 0
+	for _, char := range content {
+		if current+size(char) > multipleLength {
+			analysis.Segments++
+			current = 0
+		}
+		current += size(char)
+	}
+
+	analysis.Remaining = multipleLength - current
+	return analysis
+}
+
+func nonGSM7Characters(content string) []string {
+	seen := map[rune]bool{}
+	result := make([]string, 0)
+	for _, char := range content {
+		if isGSM7(char) || seen[char] {
+			continue
+		}
+		seen[char] = true
+		result = append(result, string(char))
+	}
+	return result
+}
+
+func isGSM7(char rune) bool {
+	return strings.ContainsRune(gsm7BasicCharacters, char) || strings.ContainsRune(gsm7ExtensionCharacters, char)
+}
+
+func gsm7Size(char rune) int {
+	if strings.ContainsRune(gsm7ExtensionCharacters, char) {
+		return gsm7ExtensionCharacterSize
+	}
+	return 1
+}
+
+func ucs2Size(char rune) int {
+	// characters outside the basic multilingual plane are encoded as a surrogate pair
+	if char > 0xFFFF {
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,85 @@
+package segments
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyze(t *testing.T) {
+	t.Run("it uses GSM-7 for a single segment message", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Act
+		analysis := Analyze("This is a sample text message")
+
+		// Assert
+		assert.Equal(t, EncodingGSM7, analysis.Encoding)
+		assert.Equal(t, 1, analysis.Segments)
+		assert.Equal(t, 29, analysis.Length)
+		assert.Equal(t, 131, analysis.Remaining)
+		assert.Empty(t, analysis.UnicodeCharacters)
+	})
+
+	t.Run("it splits GSM-7 messages into segments of 153 characters", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Act
+		single := Analyze(strings.Repeat("a", 160))
+		multiple := Analyze(strings.Repeat("a", 161))
+
+		// Assert
+		assert.Equal(t, 1, single.Segments)
+		assert.Equal(t, 2, multiple.Segments)
+		assert.Equal(t, 145, multiple.Remaining)
+	})
+
+	t.Run("it counts extension characters as 2 septets without splitting them", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		content := strings.Repeat("a", 152) + "€" + strings.Repeat("a", 10)
+
+		// Act
+		analysis := Analyze(content)
+
+		// Assert
+		assert.Equal(t, EncodingGSM7, analysis.Encoding)
+		assert.Equal(t, 164, analysis.Length)
+		assert.Equal(t, 2, analysis.Segments)
+		assert.Equal(t, 141, analysis.Remaining)
+	})
+
+	t.Run("it uses UCS-2 when a character is not in the GSM-7 alphabet", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Act
+		analysis := Analyze("Don’t forget 😀")
+
+		// Assert
+		assert.Equal(t, EncodingUCS2, analysis.Encoding)
+		assert.Equal(t, 14, analysis.Characters)
+		assert.Equal(t, 15, analysis.Length)
+		assert.Equal(t, 1, analysis.Segments)
+		assert.Equal(t, []string{"’", "😀"}, analysis.UnicodeCharacters)
+	})
+
+	t.Run("it splits UCS-2 messages into segments of 67 characters", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Act
+		single := Analyze(strings.Repeat("ж", 70))
+		multiple := Analyze(strings.Repeat("ж", 135))
+
+		// Assert
+		assert.Equal(t, 1, single.Segments)
+		assert.Equal(t, 3, multiple.Segments)
+		assert.Equal(t, 66, multiple.Remaining)
+	})
+}
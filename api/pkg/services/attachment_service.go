@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/storage"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// AttachmentService is responsible for handling entities.Attachment
+type AttachmentService struct {
+	service
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	repository repositories.AttachmentRepository
+	storage    storage.Storage
+	baseURL    string
+}
+
+// NewAttachmentService creates a new AttachmentService
+func NewAttachmentService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.AttachmentRepository,
+	storage storage.Storage,
+	baseURL string,
+) (s *AttachmentService) {
+	return &AttachmentService{
+		logger:     logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:     tracer,
+		repository: repository,
+		storage:    storage,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// AttachmentStoreParams are parameters for uploading an entities.Attachment
+type AttachmentStoreParams struct {
+	UserID      entities.UserID
+	Name        string
+	ContentType string
+	Size        int64
+	Content     io.Reader
+}
+
+// Store uploads the content of an entities.Attachment to the storage.Storage
+func (service *AttachmentService) Store(ctx context.Context, params *AttachmentStoreParams) (*entities.Attachment, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	attachment := &entities.Attachment{
+		ID:          uuid.New(),
+		UserID:      params.UserID,
+		Name:        params.Name,
+		ContentType: params.ContentType,
+		Size:        params.Size,
+		CreatedAt:   time.Now().UTC(),
+	}
+	attachment.URL = fmt.Sprintf("%s/v1/attachments/%s/download", service.baseURL, attachment.ID)
+	attachment.StorageKey = fmt.Sprintf("attachments/%s/%s", attachment.UserID, attachment.ID)
+
+	if err := service.storage.Store(ctx, attachment.StorageKey, params.Content); err != nil {
+		msg := fmt.Sprintf("cannot store content of attachment [%s] for user [%s]", attachment.ID, attachment.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := service.repository.Store(ctx, attachment); err != nil {
+		msg := fmt.Sprintf("cannot save attachment [%s] for user [%s]", attachment.ID, attachment.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("stored attachment [%s] with size [%d] for user [%s]", attachment.ID, attachment.Size, attachment.UserID))
+	return attachment, nil
+}
+
+// Load an entities.Attachment by ID
+func (service *AttachmentService) Load(ctx context.Context, userID entities.UserID, attachmentID uuid.UUID) (*entities.Attachment, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	attachment, err := service.repository.Load(ctx, userID, attachmentID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load attachment with ID [%s] for user [%s]", attachmentID, userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	return attachment, nil
+}
+
+// URLs returns the download links of the attachments in the same order as the IDs
+func (service *AttachmentService) URLs(ctx context.Context, userID entities.UserID, attachmentIDs []uuid.UUID) ([]string, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	urls := make([]string, 0, len(attachmentIDs))
+	for _, attachmentID := range attachmentIDs {
+		attachment, err := service.Load(ctx, userID, attachmentID)
+		if err != nil {
+			msg := fmt.Sprintf("cannot load URL of attachment [%s] for user [%s]", attachmentID, userID)
+			return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+		}
+		urls = append(urls, attachment.URL)
+	}
+
+	return urls, nil
+}
+
+// Download returns the content of the entities.Attachment. The caller must close the reader.
+func (service *AttachmentService) Download(ctx context.Context, attachment *entities.Attachment) (io.ReadCloser, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	content, err := service.storage.Load(ctx, attachment.StorageKey)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load content of attachment [%s] with key [%s]", attachment.ID, attachment.StorageKey)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return content, nil
+}
+
+// Delete an entities.Attachment and its content
+func (service *AttachmentService) Delete(ctx context.Context, userID entities.UserID, attachmentID uuid.UUID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	attachment, err := service.repository.Load(ctx, userID, attachmentID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load attachment with userID [%s] and attachmentID [%s]", userID, attachmentID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if err = service.repository.Delete(ctx, userID, attachmentID); err != nil {
+		msg := fmt.Sprintf("cannot delete attachment with id [%s] and user id [%s]", attachmentID, userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = service.storage.Delete(ctx, attachment.StorageKey); err != nil {
+		msg := fmt.Sprintf("cannot delete content of attachment [%s] with key [%s]", attachment.ID, attachment.StorageKey)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted attachment with id [%s] and user id [%s]", attachmentID, userID))
+	return nil
+}
@@ -0,0 +1,388 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/cache"
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/nyaruka/phonenumbers"
+	"github.com/palantir/stacktrace"
+)
+
+// AutoReplyService is responsible for handling entities.AutoReply
+type AutoReplyService struct {
+	service
+	logger             telemetry.Logger
+	tracer             telemetry.Tracer
+	cache              cache.Cache
+	repository         repositories.AutoReplyRepository
+	userRepository     repositories.UserRepository
+	phoneService       *PhoneService
+	messageService     *MessageService
+	templateService    *MessageTemplateService
+	suppressionService *SuppressionService
+	eventDispatcher    *EventDispatcher
+}
+
+// NewAutoReplyService creates a new AutoReplyService
+func NewAutoReplyService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	cache cache.Cache,
+	repository repositories.AutoReplyRepository,
+	userRepository repositories.UserRepository,
+	phoneService *PhoneService,
+	messageService *MessageService,
+	templateService *MessageTemplateService,
+	suppressionService *SuppressionService,
+	eventDispatcher *EventDispatcher,
+) (s *AutoReplyService) {
+	return &AutoReplyService{
+		logger:             logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:             tracer,
+		cache:              cache,
+		repository:         repository,
+		userRepository:     userRepository,
+		phoneService:       phoneService,
+		messageService:     messageService,
+		templateService:    templateService,
+		suppressionService: suppressionService,
+		eventDispatcher:    eventDispatcher,
+	}
+}
+
+// Index fetches the entities.AutoReply for an entities.UserID
+func (service *AutoReplyService) Index(ctx context.Context, userID entities.UserID, owner string, params repositories.IndexParams) ([]*entities.AutoReply, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	replies, err := service.repository.Index(ctx, userID, owner, params)
+	if err != nil {
+		msg := fmt.Sprintf("could not fetch auto replies with params [%+#v]", params)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("fetched [%d] auto replies with prams [%+#v]", len(replies), params))
+	return replies, nil
+}
+
+// Load an entities.AutoReply by ID
+func (service *AutoReplyService) Load(ctx context.Context, userID entities.UserID, autoReplyID uuid.UUID) (*entities.AutoReply, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	reply, err := service.repository.Load(ctx, userID, autoReplyID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load auto reply with ID [%s] for user [%s]", autoReplyID, userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	return reply, nil
+}
+
+// Delete an entities.AutoReply
+func (service *AutoReplyService) Delete(ctx context.Context, userID entities.UserID, autoReplyID uuid.UUID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if _, err := service.repository.Load(ctx, userID, autoReplyID); err != nil {
+		msg := fmt.Sprintf("cannot load auto reply with userID [%s] and autoReplyID [%s]", userID, autoReplyID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if err := service.repository.Delete(ctx, userID, autoReplyID); err != nil {
+		msg := fmt.Sprintf("cannot delete auto reply with id [%s] and user id [%s]", autoReplyID, userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted auto reply with id [%s] and user id [%s]", autoReplyID, userID))
+	return nil
+}
+
+// AutoReplyStoreParams are parameters for creating a new entities.AutoReply
+type AutoReplyStoreParams struct {
+	UserID          entities.UserID
+	Owner           string
+	Name            string
+	Match           entities.AutoReplyMatch
+	Pattern         string
+	StartTime       *string
+	EndTime         *string
+	Action          entities.AutoReplyAction
+	Content         *string
+	TemplateID      *uuid.UUID
+	ForwardTo       *string
+	Tag             *string
+	CooldownSeconds uint
+	IsActive        bool
+	Priority        uint
+}
+
+// Store a new entities.AutoReply
+func (service *AutoReplyService) Store(ctx context.Context, params *AutoReplyStoreParams) (*entities.AutoReply, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	reply := service.apply(&entities.AutoReply{
+		ID:        uuid.New(),
+		UserID:    params.UserID,
+		CreatedAt: time.Now().UTC(),
+	}, params)
+
+	if err := service.repository.Save(ctx, reply); err != nil {
+		msg := fmt.Sprintf("cannot save auto reply with id [%s]", reply.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("auto reply saved with id [%s] in the [%T]", reply.ID, service.repository))
+	return reply, nil
+}
+
+// AutoReplyUpdateParams are parameters for updating an entities.AutoReply
+type AutoReplyUpdateParams struct {
+	AutoReplyStoreParams
+	AutoReplyID uuid.UUID
+}
+
+// Update an entities.AutoReply
+func (service *AutoReplyService) Update(ctx context.Context, params *AutoReplyUpdateParams) (*entities.AutoReply, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	reply, err := service.repository.Load(ctx, params.UserID, params.AutoReplyID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load auto reply with userID [%s] and autoReplyID [%s]", params.UserID, params.AutoReplyID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if err = service.repository.Save(ctx, service.apply(reply, &params.AutoReplyStoreParams)); err != nil {
+		msg := fmt.Sprintf("cannot save auto reply with id [%s] after update", reply.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("auto reply updated with id [%s] in the [%T]", reply.ID, service.repository))
+	return reply, nil
+}
+
+func (service *AutoReplyService) apply(reply *entities.AutoReply, params *AutoReplyStoreParams) *entities.AutoReply {
+	reply.Owner = params.Owner
+	reply.Name = params.Name
+	reply.Match = params.Match
+	reply.Pattern = params.Pattern
+	reply.StartTime = params.StartTime
+	reply.EndTime = params.EndTime
+	reply.Action = params.Action
+	reply.Content = params.Content
+	reply.TemplateID = params.TemplateID
+	reply.ForwardTo = params.ForwardTo
+	reply.Tag = params.Tag
+	reply.CooldownSeconds = params.CooldownSeconds
+	reply.IsActive = params.IsActive
+	reply.Priority = params.Priority
+	reply.UpdatedAt = time.Now().UTC()
+	return reply
+}
+
+// HandleReceived evaluates the active entities.AutoReply of the phone which received a message
+func (service *AutoReplyService) HandleReceived(ctx context.Context, source string, payload *events.MessagePhoneReceivedPayload) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if payload.Encrypted {
+		ctxLogger.Info(fmt.Sprintf("skipping auto replies for encrypted message [%s] of user [%s]", payload.MessageID, payload.UserID))
+		return nil
+	}
+
+	replies, err := service.repository.FetchActive(ctx, payload.UserID, payload.Owner)
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch auto replies for owner [%s] and user [%s]", payload.Owner, payload.UserID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if len(replies) == 0 {
+		return nil
+	}
+
+	skip, err := service.shouldSkip(ctx, payload)
+	if err != nil {
+		msg := fmt.Sprintf("cannot check if auto replies should be skipped for message [%s]", payload.MessageID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+	if skip {
+		return nil
+	}
+
+	user, err := service.userRepository.Load(ctx, payload.UserID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load user with ID [%s]", payload.UserID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	timestamp := payload.Timestamp.In(user.Location())
+	for _, reply := range replies {
+		if !reply.IsActiveAt(timestamp) || !reply.Matches(payload.Contact, payload.Content) {
+			continue
+		}
+
+		if service.isRateLimited(ctx, reply, payload.Contact) {
+			ctxLogger.Info(fmt.Sprintf("auto reply [%s] is rate limited for contact [%s] of user [%s]", reply.ID, payload.Contact, payload.UserID))
+			continue
+		}
+
+		if err = service.execute(ctx, source, reply, payload); err != nil {
+			msg := fmt.Sprintf("cannot execute auto reply [%s] for message [%s]", reply.ID, payload.MessageID)
+			ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		}
+	}
+
+	return nil
+}
+
+// shouldSkip protects against loops between phones of the user and never replies to contacts who opted out
+func (service *AutoReplyService) shouldSkip(ctx context.Context, payload *events.MessagePhoneReceivedPayload) (bool, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	phone, err := service.phoneService.Load(ctx, payload.UserID, payload.Owner)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load phone [%s] for user [%s]", payload.Owner, payload.UserID)
+		return false, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if phone.OptOutKeyword(payload.Content) != nil || phone.OptInKeyword(payload.Content) != nil {
+		ctxLogger.Info(fmt.Sprintf("skipping auto replies for opt-out or opt-in message [%s]", payload.MessageID))
+		return true, nil
+	}
+
+	_, err = service.phoneService.Load(ctx, payload.UserID, payload.Contact)
+	if err == nil {
+		ctxLogger.Info(fmt.Sprintf("skipping auto replies for message [%s] because the contact [%s] is a phone of user [%s]", payload.MessageID, payload.Contact, payload.UserID))
+		return true, nil
+	}
+
+	if stacktrace.GetCode(err) != repositories.ErrCodeNotFound {
+		msg := fmt.Sprintf("cannot load phone [%s] for user [%s]", payload.Contact, payload.UserID)
+		return false, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	suppressed, err := service.suppressionService.IsSuppressed(ctx, payload.UserID, payload.Contact)
+	if err != nil {
+		msg := fmt.Sprintf("cannot check if contact [%s] is suppressed for user [%s]", payload.Contact, payload.UserID)
+		return false, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if suppressed {
+		ctxLogger.Info(fmt.Sprintf("skipping auto replies for message [%s] because the contact [%s] opted out", payload.MessageID, payload.Contact))
+	}
+	return suppressed, nil
+}
+
+// isRateLimited checks if the entities.AutoReply was executed for the contact within the cooldown
+func (service *AutoReplyService) isRateLimited(ctx context.Context, reply *entities.AutoReply, contact string) bool {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if !reply.IsRateLimited() {
+		return false
+	}
+
+	// the cooldown is started atomically so that only one of the messages received at the same time is replied to
+	key := fmt.Sprintf("auto-reply:%s:%s", reply.ID, contact)
+	added, err := service.cache.Add(ctx, key, time.Now().UTC().Format(time.RFC3339), reply.Cooldown())
+	if err != nil {
+		msg := fmt.Sprintf("cannot set rate limit for auto reply [%s] and contact [%s]", reply.ID, contact)
+		ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		return false
+	}
+
+	return !added
+}
+
+func (service *AutoReplyService) execute(ctx context.Context, source string, reply *entities.AutoReply, payload *events.MessagePhoneReceivedPayload) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	var replyMessageID *uuid.UUID
+	switch reply.Action {
+	case entities.AutoReplyActionReply, entities.AutoReplyActionTemplate, entities.AutoReplyActionForward:
+		message, err := service.send(ctx, source, reply, payload)
+		if err != nil {
+			msg := fmt.Sprintf("cannot send message for auto reply [%s] with action [%s]", reply.ID, reply.Action)
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+		replyMessageID = &message.ID
+	}
+
+	event, err := service.createEvent(events.EventTypeAutoReplyTriggered, source, &events.AutoReplyTriggeredPayload{
+		AutoReplyID:    reply.ID,
+		UserID:         reply.UserID,
+		Owner:          payload.Owner,
+		Contact:        payload.Contact,
+		MessageID:      payload.MessageID,
+		Content:        payload.Content,
+		Action:         reply.Action,
+		Tag:            reply.Tag,
+		ReplyMessageID: replyMessageID,
+		Timestamp:      time.Now().UTC(),
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot create [%s] event for auto reply [%s]", events.EventTypeAutoReplyTriggered, reply.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = service.eventDispatcher.Dispatch(ctx, event); err != nil {
+		msg := fmt.Sprintf("cannot dispatch event [%s] for auto reply [%s]", event.Type(), reply.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("executed auto reply [%s] with action [%s] for message [%s]", reply.ID, reply.Action, payload.MessageID))
+	return nil
+}
+
+func (service *AutoReplyService) send(ctx context.Context, source string, reply *entities.AutoReply, payload *events.MessagePhoneReceivedPayload) (*entities.Message, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	contact, content := payload.Contact, ""
+	switch reply.Action {
+	case entities.AutoReplyActionReply:
+		content = *reply.Content
+	case entities.AutoReplyActionTemplate:
+		rendered, err := service.templateService.Render(ctx, reply.UserID, *reply.TemplateID, map[string]string{
+			"contact": payload.Contact,
+			"owner":   payload.Owner,
+			"content": payload.Content,
+		})
+		if err != nil {
+			msg := fmt.Sprintf("cannot render message template [%s] for auto reply [%s]", reply.TemplateID, reply.ID)
+			return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+		content = rendered
+	case entities.AutoReplyActionForward:
+		contact, content = *reply.ForwardTo, fmt.Sprintf("From %s: %s", payload.Contact, payload.Content)
+	}
+
+	requestID := fmt.Sprintf("auto-reply-%s", payload.MessageID)
+	owner, _ := phonenumbers.Parse(payload.Owner, phonenumbers.UNKNOWN_REGION)
+	message, err := service.messageService.SendMessage(ctx, MessageSendParams{
+		Owner:             owner,
+		Contact:           contact,
+		Content:           content,
+		Source:            source,
+		RequestID:         &requestID,
+		UserID:            reply.UserID,
+		RequestReceivedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot send auto reply message from [%s] to [%s] for user [%s]", payload.Owner, contact, reply.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return message, nil
+}
@@ -137,13 +137,18 @@ func (service *BillingService) GetUsageHistory(ctx context.Context, userID entit
 }
 
 // RegisterSentMessage records the billing usage for a sent message
-func (service *BillingService) RegisterSentMessage(ctx context.Context, messageID uuid.UUID, timestamp time.Time, userID entities.UserID) error {
+func (service *BillingService) RegisterSentMessage(ctx context.Context, messageID uuid.UUID, timestamp time.Time, segments uint, userID entities.UserID) error {
 	ctx, span := service.tracer.Start(ctx)
 	defer span.End()
 
 	ctxLogger := service.tracer.CtxLogger(service.logger, span)
 
-	if err := service.billingUsageRepository.RegisterSentMessage(ctx, timestamp, userID); err != nil {
+	// encrypted messages are not analyzed so they are counted as a single segment
+	if segments == 0 {
+		segments = 1
+	}
+
+	if err := service.billingUsageRepository.RegisterSentMessage(ctx, timestamp, segments, userID); err != nil {
 		msg := fmt.Sprintf("could not register [sent] message with ID [%s] for user with ID [%s]", messageID, userID)
 		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
@@ -0,0 +1,866 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/NdoleStudio/httpsms/pkg/bulk"
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/storage"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/templates"
+	"github.com/google/uuid"
+	"github.com/nyaruka/phonenumbers"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	// bulkMessageJobResultsBatchSize is the number of rows loaded at once when writing the results file
+	bulkMessageJobResultsBatchSize = 1000
+
+	// bulkMessageJobValidationBatchSize is the number of validated rows which are checked for opt-outs and stored at once
+	bulkMessageJobValidationBatchSize = 1000
+
+	// bulkMessageJobSendBatchSize is the number of rows which are queued by a single entities.BulkMessageJob batch
+	bulkMessageJobSendBatchSize = 200
+
+	// bulkMessageJobSendInterval is the delay between batches so that large files don't flood the queue
+	bulkMessageJobSendInterval = 10 * time.Second
+
+	// bulkMessageJobMaxBatchAttempts is the number of times a batch is retried after an error before the job fails
+	bulkMessageJobMaxBatchAttempts = 5
+
+	// bulkMessageJobMaxErrors is the maximum number of validation errors stored on an invalid entities.BulkMessageJob
+	bulkMessageJobMaxErrors = 100
+
+	// bulkMessageMaxContentLength is the maximum length of the content of a row after filling in the placeholders
+	bulkMessageMaxContentLength = 1024
+)
+
+// BulkMessageJobService is responsible for validating and sending the rows of an entities.BulkMessageJob
+type BulkMessageJobService struct {
+	service
+	logger             telemetry.Logger
+	tracer             telemetry.Tracer
+	repository         repositories.BulkMessageJobRepository
+	userRepository     repositories.UserRepository
+	messageService     *MessageService
+	phoneService       *PhoneService
+	templateService    *MessageTemplateService
+	suppressionService *SuppressionService
+	billingService     *BillingService
+	senderPoolService  *SenderPoolService
+	storage            storage.Storage
+	eventDispatcher    *EventDispatcher
+}
+
+// NewBulkMessageJobService creates a new BulkMessageJobService
+func NewBulkMessageJobService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.BulkMessageJobRepository,
+	userRepository repositories.UserRepository,
+	messageService *MessageService,
+	phoneService *PhoneService,
+	templateService *MessageTemplateService,
+	suppressionService *SuppressionService,
+	billingService *BillingService,
+	senderPoolService *SenderPoolService,
+	storage storage.Storage,
+	eventDispatcher *EventDispatcher,
+) (s *BulkMessageJobService) {
+	return &BulkMessageJobService{
+		logger:             logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:             tracer,
+		repository:         repository,
+		userRepository:     userRepository,
+		messageService:     messageService,
+		phoneService:       phoneService,
+		templateService:    templateService,
+		suppressionService: suppressionService,
+		billingService:     billingService,
+		senderPoolService:  senderPoolService,
+		storage:            storage,
+		eventDispatcher:    eventDispatcher,
+	}
+}
+
+// BulkMessageJobStoreParams are the parameters for uploading a bulk SMS file
+type BulkMessageJobStoreParams struct {
+	UserID     entities.UserID
+	Filename   string
+	Format     bulk.Format
+	TemplateID *uuid.UUID
+	Source     string
+	Content    io.Reader
+}
+
+// Store the uploaded file of a new entities.BulkMessageJob and validate it in the background
+func (service *BulkMessageJobService) Store(ctx context.Context, params *BulkMessageJobStoreParams) (*entities.BulkMessageJob, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	job := &entities.BulkMessageJob{
+		ID:         uuid.New(),
+		UserID:     params.UserID,
+		Filename:   params.Filename,
+		Status:     entities.BulkMessageJobStatusValidating,
+		TemplateID: params.TemplateID,
+		Source:     params.Source,
+		Errors:     []string{},
+		CreatedAt:  time.Now().UTC(),
+		UpdatedAt:  time.Now().UTC(),
+	}
+	job.StorageKey = fmt.Sprintf("bulk-messages/%s/%s.%s", job.UserID, job.ID, params.Format)
+
+	if err := service.storage.Store(ctx, job.StorageKey, params.Content); err != nil {
+		msg := fmt.Sprintf("cannot store file [%s] of bulk message job [%s]", job.StorageKey, job.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := service.repository.Store(ctx, job); err != nil {
+		msg := fmt.Sprintf("cannot store bulk message job [%s] for user [%s]", job.ID, job.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	event, err := service.createEvent(events.EventTypeBulkMessageJobCreated, params.Source, &events.BulkMessageJobCreatedPayload{
+		BulkMessageJobID: job.ID,
+		UserID:           job.UserID,
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot create [%s] event for bulk message job [%s]", events.EventTypeBulkMessageJobCreated, job.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = service.eventDispatcher.Dispatch(ctx, event); err != nil {
+		msg := fmt.Sprintf("cannot dispatch [%s] event for bulk message job [%s]", event.Type(), job.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("stored file [%s] of bulk message job [%s] for user [%s]", job.Filename, job.ID, job.UserID))
+	return job, nil
+}
+
+// BulkMessageJobBroadcastParams are the parameters for sending the same message to every phone number in an entities.ContactGroup
+type BulkMessageJobBroadcastParams struct {
+	UserID    entities.UserID
+	Source    string
+	Owner     string
+	Group     *entities.ContactGroup
+	Content   string
+	Encrypted bool
+	RequestID *string
+	SendAt    *time.Time
+}
+
+// Broadcast stores a row for each phone number in an entities.ContactGroup and sends the rows in batches in the background
+func (service *BulkMessageJobService) Broadcast(ctx context.Context, params *BulkMessageJobBroadcastParams) (*entities.BulkMessageJob, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	job := &entities.BulkMessageJob{
+		ID:               uuid.New(),
+		UserID:           params.UserID,
+		Status:           entities.BulkMessageJobStatusValidating,
+		Source:           params.Source,
+		ContactGroupID:   &params.Group.ID,
+		Encrypted:        params.Encrypted,
+		MessageRequestID: params.RequestID,
+		Errors:           []string{},
+		CreatedAt:        time.Now().UTC(),
+		UpdatedAt:        time.Now().UTC(),
+	}
+
+	if err := service.repository.Store(ctx, job); err != nil {
+		msg := fmt.Sprintf("cannot store broadcast job [%s] for user [%s]", job.ID, job.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	validation := &bulkMessageJobValidation{job: job}
+	for index, contact := range params.Group.PhoneNumbers {
+		job.Total++
+		validation.rows = append(validation.rows, &entities.BulkMessageJobRow{
+			ID:        uuid.New(),
+			JobID:     job.ID,
+			Row:       index + 1,
+			Owner:     params.Owner,
+			Contact:   contact,
+			Status:    entities.BulkMessageJobRowStatusPending.String(),
+			Content:   params.Content,
+			SendAt:    params.SendAt,
+			CreatedAt: time.Now().UTC(),
+		})
+
+		if len(validation.rows) < bulkMessageJobValidationBatchSize && index < len(params.Group.PhoneNumbers)-1 {
+			continue
+		}
+
+		if err := service.storeRows(ctx, validation); err != nil {
+			msg := fmt.Sprintf("cannot store the rows of broadcast job [%s] for contact group [%s]", job.ID, params.Group.ID)
+			return nil, service.tracer.WrapErrorSpan(span, service.failBroadcast(ctx, job, stacktrace.Propagate(err, msg)))
+		}
+	}
+
+	job.Status = entities.BulkMessageJobStatusSending
+	job.UpdatedAt = time.Now().UTC()
+	if err := service.update(ctx, job); err != nil {
+		msg := fmt.Sprintf("cannot start sending broadcast job [%s]", job.ID)
+		return nil, service.tracer.WrapErrorSpan(span, service.failBroadcast(ctx, job, stacktrace.Propagate(err, msg)))
+	}
+
+	if err := service.dispatchBatch(ctx, params.Source, job, 0, 0); err != nil {
+		msg := fmt.Sprintf("cannot queue the first batch of broadcast job [%s]", job.ID)
+		return nil, service.tracer.WrapErrorSpan(span, service.failBroadcast(ctx, job, stacktrace.Propagate(err, msg)))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("created broadcast job [%s] with [%d] rows to send to contact group [%s]", job.ID, job.Valid, params.Group.ID))
+	return job, nil
+}
+
+// failBroadcast stops a broadcast which could not be started and returns the cause
+func (service *BulkMessageJobService) failBroadcast(ctx context.Context, job *entities.BulkMessageJob, cause error) error {
+	if err := service.fail(ctx, job, "The broadcast could not be started because of an internal error, please try again later."); err != nil {
+		service.logger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot fail broadcast job [%s]", job.ID)))
+	}
+	return cause
+}
+
+// bulkMessageJobValidation is the state of an entities.BulkMessageJob while its file is being validated
+type bulkMessageJobValidation struct {
+	job      *entities.BulkMessageJob
+	template *entities.MessageTemplate
+	owners   map[string]bool
+	rows     []*entities.BulkMessageJobRow
+	// errors are the first validation errors and errorCount is the number of all the validation errors
+	errors     []string
+	errorCount int
+}
+
+func (validation *bulkMessageJobValidation) addError(message string) {
+	validation.errorCount++
+	if len(validation.errors) < bulkMessageJobMaxErrors {
+		validation.errors = append(validation.errors, message)
+	}
+}
+
+// messages returns the validation errors which are stored on the entities.BulkMessageJob
+func (validation *bulkMessageJobValidation) messages() []string {
+	if validation.errorCount > len(validation.errors) {
+		return append(validation.errors, fmt.Sprintf("The file contains %d more errors which are not shown.", validation.errorCount-len(validation.errors)))
+	}
+	return validation.errors
+}
+
+// Validate reads the uploaded file of an entities.BulkMessageJob one row at a time and queues the first batch when every row is valid
+func (service *BulkMessageJobService) Validate(ctx context.Context, source string, payload *events.BulkMessageJobCreatedPayload) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	job, err := service.repository.Load(ctx, payload.UserID, payload.BulkMessageJobID)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		ctxLogger.Info(fmt.Sprintf("bulk message job [%s] for user [%s] does not exist", payload.BulkMessageJobID, payload.UserID))
+		return nil
+	}
+	if err != nil {
+		msg := fmt.Sprintf("cannot load bulk message job [%s] for user [%s]", payload.BulkMessageJobID, payload.UserID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if job.Status != entities.BulkMessageJobStatusValidating {
+		ctxLogger.Info(fmt.Sprintf("bulk message job [%s] has already been validated with status [%s]", job.ID, job.Status))
+		return nil
+	}
+
+	if err = service.validate(ctx, source, job); err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot validate bulk message job [%s]", job.ID)))
+		return service.fail(ctx, job, "The uploaded file could not be validated because of an internal error, please upload it again.")
+	}
+
+	return nil
+}
+
+// validate stores the rows of the uploaded file, the rows of a previous attempt are deleted first so a redelivered event doesn't count them twice
+func (service *BulkMessageJobService) validate(ctx context.Context, source string, job *entities.BulkMessageJob) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.DeleteRows(ctx, job.ID); err != nil {
+		msg := fmt.Sprintf("cannot delete the rows of a previous attempt of bulk message job [%s]", job.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+	job.Total, job.Valid = 0, 0
+
+	var err error
+	validation := &bulkMessageJobValidation{job: job, owners: map[string]bool{}}
+	if job.TemplateID != nil {
+		validation.template, err = service.templateService.Load(ctx, job.UserID, *job.TemplateID)
+		if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+			return service.invalidate(ctx, job, fmt.Sprintf("The message template with ID [%s] does not exist.", job.TemplateID))
+		}
+		if err != nil {
+			msg := fmt.Sprintf("cannot load message template [%s] of bulk message job [%s]", job.TemplateID, job.ID)
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+	}
+
+	if err = service.readRows(ctx, validation); err != nil {
+		msg := fmt.Sprintf("cannot read the rows of bulk message job [%s]", job.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if validation.errorCount == 0 && job.Total == 0 {
+		validation.addError("The uploaded file doesn't contain any valid records. Make sure you are using the official httpSMS template.")
+	}
+
+	if validation.errorCount != 0 {
+		return service.invalidate(ctx, job, validation.messages()...)
+	}
+
+	if msg := service.billingService.IsEntitledWithCount(ctx, job.UserID, uint(job.Valid)); msg != nil {
+		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("user [%s] is not entitled to send [%d] messages of bulk message job [%s]", job.UserID, job.Valid, job.ID)))
+		if err = service.repository.DeleteRows(ctx, job.ID); err != nil {
+			msg := fmt.Sprintf("cannot delete the rows of bulk message job [%s]", job.ID)
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+		return service.update(ctx, job.Fail(entities.BulkMessageJobStatusFailed, *msg))
+	}
+
+	job.Status = entities.BulkMessageJobStatusSending
+	job.UpdatedAt = time.Now().UTC()
+	if err = service.update(ctx, job); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot start sending bulk message job [%s]", job.ID)))
+	}
+
+	if err = service.dispatchBatch(ctx, source, job, 0, 0); err != nil {
+		msg := fmt.Sprintf("cannot queue the first batch of bulk message job [%s]", job.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	service.deleteFile(ctx, job)
+	ctxLogger.Info(fmt.Sprintf("validated [%d] rows of bulk message job [%s] with [%d] rows to send", job.Total, job.ID, job.Valid))
+	return nil
+}
+
+// SendBatch queues the next batch of pending rows of an entities.BulkMessageJob and schedules the batch after it
+func (service *BulkMessageJobService) SendBatch(ctx context.Context, source string, payload *events.BulkMessageJobBatchDuePayload) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	job, err := service.repository.Load(ctx, payload.UserID, payload.BulkMessageJobID)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		ctxLogger.Info(fmt.Sprintf("bulk message job [%s] for user [%s] does not exist", payload.BulkMessageJobID, payload.UserID))
+		return nil
+	}
+	if err != nil {
+		msg := fmt.Sprintf("cannot load bulk message job [%s] for user [%s]", payload.BulkMessageJobID, payload.UserID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if job.Status != entities.BulkMessageJobStatusSending {
+		ctxLogger.Info(fmt.Sprintf("skipping batch of bulk message job [%s] with status [%s]", job.ID, job.Status))
+		return nil
+	}
+
+	rows, err := service.repository.PendingRows(ctx, job.ID, bulkMessageJobSendBatchSize)
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch pending rows of bulk message job [%s]", job.ID)
+		return service.retryBatch(ctx, source, job, payload.Attempt, stacktrace.Propagate(err, msg))
+	}
+
+	if len(rows) != 0 {
+		if msg := service.billingService.IsEntitledWithCount(ctx, job.UserID, uint(len(rows))); msg != nil {
+			ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("user [%s] is not entitled to send the next [%d] messages of bulk message job [%s]", job.UserID, len(rows), job.ID)))
+			return service.update(ctx, job.Fail(entities.BulkMessageJobStatusFailed, *msg))
+		}
+	}
+
+	for _, row := range rows {
+		if err = service.sendRow(ctx, job, row); err != nil {
+			msg := fmt.Sprintf("cannot send row [%d] of bulk message job [%s]", row.Row, job.ID)
+			return service.retryBatch(ctx, source, job, payload.Attempt, stacktrace.Propagate(err, msg))
+		}
+		job.Processed++
+	}
+
+	job.UpdatedAt = time.Now().UTC()
+	if len(rows) < bulkMessageJobSendBatchSize {
+		job.Status = entities.BulkMessageJobStatusCompleted
+	}
+
+	if err = service.update(ctx, job); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot save progress of bulk message job [%s]", job.ID)))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("queued [%d] of [%d] rows of bulk message job [%s]", job.Processed, job.Valid, job.ID))
+	if job.Status == entities.BulkMessageJobStatusCompleted {
+		return nil
+	}
+	return service.dispatchBatch(ctx, source, job, bulkMessageJobSendInterval, 0)
+}
+
+// retryBatch saves the progress of a batch which failed and queues it again with an exponential backoff, the job fails after bulkMessageJobMaxBatchAttempts
+func (service *BulkMessageJobService) retryBatch(ctx context.Context, source string, job *entities.BulkMessageJob, attempt uint, cause error) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	ctxLogger.Error(stacktrace.Propagate(cause, fmt.Sprintf("attempt [%d] of batch of bulk message job [%s] failed", attempt+1, job.ID)))
+	if attempt+1 >= bulkMessageJobMaxBatchAttempts {
+		job.Fail(entities.BulkMessageJobStatusFailed, fmt.Sprintf("The remaining %d messages could not be queued because of an internal error, please try again later.", job.Valid-job.Processed))
+		if err := service.update(ctx, job); err != nil {
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot fail bulk message job [%s]", job.ID)))
+		}
+		return nil
+	}
+
+	job.UpdatedAt = time.Now().UTC()
+	if err := service.update(ctx, job); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot save progress of bulk message job [%s]", job.ID)))
+	}
+
+	return service.dispatchBatch(ctx, source, job, bulkMessageJobSendInterval<<attempt, attempt+1)
+}
+
+// Load an entities.BulkMessageJob with the aggregate counts and a page of row outcomes
+func (service *BulkMessageJobService) Load(ctx context.Context, userID entities.UserID, jobID uuid.UUID, params repositories.IndexParams) (*entities.BulkMessageJob, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	job, err := service.repository.Load(ctx, userID, jobID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load bulk message job with ID [%s] for user [%s]", jobID, userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if job.Counts, err = service.repository.Counts(ctx, job.ID); err != nil {
+		msg := fmt.Sprintf("cannot count rows of bulk message job [%s]", job.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if job.Rows, err = service.repository.Rows(ctx, job.ID, params); err != nil {
+		msg := fmt.Sprintf("cannot fetch rows of bulk message job [%s] with params [%+#v]", job.ID, params)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return job, nil
+}
+
+// Results writes the outcome of every row of an entities.BulkMessageJob as a CSV file
+func (service *BulkMessageJobService) Results(ctx context.Context, job *entities.BulkMessageJob, writer io.Writer) error {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	output := csv.NewWriter(writer)
+	if err := output.Write([]string{"Row", "FromPhoneNumber", "ToPhoneNumber", "MessageID", "Status", "Reason"}); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, "cannot write the header of the results file"))
+	}
+
+	params := repositories.IndexParams{Limit: bulkMessageJobResultsBatchSize}
+	for {
+		rows, err := service.repository.Rows(ctx, job.ID, params)
+		if err != nil {
+			msg := fmt.Sprintf("cannot fetch rows of bulk message job [%s] with params [%+#v]", job.ID, params)
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+
+		for _, row := range rows {
+			if err = output.Write(service.toRecord(row)); err != nil {
+				msg := fmt.Sprintf("cannot write row [%d] of bulk message job [%s]", row.Row, job.ID)
+				return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+			}
+		}
+
+		if len(rows) < params.Limit {
+			break
+		}
+		params.Skip += params.Limit
+	}
+
+	output.Flush()
+	if err := output.Error(); err != nil {
+		msg := fmt.Sprintf("cannot flush the results file of bulk message job [%s]", job.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// readRows streams the uploaded file and stores the valid rows in batches until the first validation error
+func (service *BulkMessageJobService) readRows(ctx context.Context, validation *bulkMessageJobValidation) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	job := validation.job
+	user, err := service.userRepository.Load(ctx, job.UserID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load user [%s] of bulk message job [%s]", job.UserID, job.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	content, err := service.storage.Load(ctx, job.StorageKey)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load file [%s] of bulk message job [%s]", job.StorageKey, job.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+	defer func() {
+		if err = content.Close(); err != nil {
+			ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot close file [%s] of bulk message job [%s]", job.StorageKey, job.ID)))
+		}
+	}()
+
+	reader, err := bulk.NewReader(bulk.Format(strings.TrimPrefix(path.Ext(job.StorageKey), ".")), content, user.Location())
+	if err != nil {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot read file [%s] of bulk message job [%s]", job.StorageKey, job.ID)))
+		validation.addError(fmt.Sprintf("Cannot read the contents of the uploaded file [%s].", job.Filename))
+		return nil
+	}
+	defer func() {
+		if err = reader.Close(); err != nil {
+			ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot close reader of bulk message job [%s]", job.ID)))
+		}
+	}()
+
+	for {
+		row, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var rowErr *bulk.RowError
+		if errors.As(err, &rowErr) {
+			job.Total++
+			validation.addError(rowErr.Error())
+			continue
+		}
+
+		if err != nil {
+			ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot read row after [%d] rows of bulk message job [%s]", job.Total, job.ID)))
+			validation.addError(fmt.Sprintf("Cannot read the contents of the uploaded file [%s] after row [%d].", job.Filename, job.Total+1))
+			return nil
+		}
+
+		job.Total++
+		result, err := service.validateRow(ctx, validation, row)
+		if err != nil {
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot validate row [%d] of bulk message job [%s]", row.Number, job.ID)))
+		}
+
+		if result != nil && validation.errorCount == 0 {
+			validation.rows = append(validation.rows, result)
+		}
+
+		if len(validation.rows) >= bulkMessageJobValidationBatchSize {
+			if err = service.storeRows(ctx, validation); err != nil {
+				return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot store rows of bulk message job [%s]", job.ID)))
+			}
+		}
+	}
+
+	if validation.errorCount != 0 {
+		return nil
+	}
+	return service.storeRows(ctx, validation)
+}
+
+// validateRow returns the pending entities.BulkMessageJobRow of a valid row or nil when the row has validation errors
+func (service *BulkMessageJobService) validateRow(ctx context.Context, validation *bulkMessageJobValidation, row *bulk.Row) (*entities.BulkMessageJobRow, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	errorCount := validation.errorCount
+	owner, err := service.validateOwner(ctx, validation, row)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot validate the FromPhoneNumber of row [%d]", row.Number)))
+	}
+
+	contact, err := phonenumbers.Parse(service.sanitizePhoneNumber(row.ToPhoneNumber), phonenumbers.UNKNOWN_REGION)
+	if err != nil {
+		validation.addError(fmt.Sprintf("Row [%d]: The ToPhoneNumber [%s] is not a valid E.164 phone number", row.Number, row.ToPhoneNumber))
+	}
+
+	content := strings.TrimSpace(row.Content)
+	if utf8.RuneCountInString(content) > bulkMessageMaxContentLength {
+		validation.addError(fmt.Sprintf("Row [%d]: The message content must be less than %d characters.", row.Number, bulkMessageMaxContentLength))
+	}
+
+	if validation.template != nil {
+		content = validation.template.Content
+		if missing := templates.Missing(content, row.Variables); len(missing) != 0 {
+			validation.addError(fmt.Sprintf("Row [%d]: The columns [%s] are required by the message template [%s].", row.Number, strings.Join(missing, ", "), validation.template.Name))
+		}
+	} else if unknown := templates.Missing(content, row.Variables); len(unknown) != 0 {
+		validation.addError(fmt.Sprintf("Row [%d]: The placeholders [%s] in the Content don't match any column in the uploaded file.", row.Number, strings.Join(unknown, ", ")))
+	}
+
+	content = templates.Render(content, row.Variables)
+	if utf8.RuneCountInString(content) > bulkMessageMaxContentLength {
+		validation.addError(fmt.Sprintf("Row [%d]: The message content must be less than %d characters after filling in the placeholders.", row.Number, bulkMessageMaxContentLength))
+	}
+
+	if row.SendTime != nil && row.SendTime.After(time.Now().Add(24*time.Hour)) {
+		validation.addError(fmt.Sprintf("Row [%d]: The SendTime [%s] cannot be more than 24 hours in the future.", row.Number, row.SendTime.Format(time.RFC3339)))
+	}
+
+	if validation.errorCount != errorCount {
+		return nil, nil
+	}
+
+	return &entities.BulkMessageJobRow{
+		ID:        uuid.New(),
+		JobID:     validation.job.ID,
+		Row:       row.Number,
+		Owner:     owner,
+		Contact:   phonenumbers.Format(contact, phonenumbers.E164),
+		Status:    entities.BulkMessageJobRowStatusPending.String(),
+		Content:   content,
+		SendAt:    row.SendTime,
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+// storeRows skips the rows sent to phone numbers which opted out and stores the validated rows
+func (service *BulkMessageJobService) storeRows(ctx context.Context, validation *bulkMessageJobValidation) error {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	if len(validation.rows) == 0 {
+		return nil
+	}
+
+	numbers := make([]string, 0, len(validation.rows))
+	for _, row := range validation.rows {
+		numbers = append(numbers, row.Contact)
+	}
+
+	suppressed, err := service.suppressionService.Suppressed(ctx, validation.job.UserID, numbers)
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch suppressed phone numbers for user [%s]", validation.job.UserID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	for _, row := range validation.rows {
+		if !suppressed[row.Contact] {
+			validation.job.Valid++
+			continue
+		}
+		reason := "the recipient opted out of receiving messages"
+		row.Status = entities.BulkMessageJobRowStatusSkipped.String()
+		row.Reason = &reason
+	}
+
+	if err = service.repository.StoreRows(ctx, validation.rows); err != nil {
+		msg := fmt.Sprintf("cannot store [%d] rows of bulk message job [%s]", len(validation.rows), validation.job.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	validation.rows = nil
+	validation.job.UpdatedAt = time.Now().UTC()
+	return service.update(ctx, validation.job)
+}
+
+// validateOwner returns the E.164 phone number or the ID of the entities.SenderPool which sends the message of the row
+func (service *BulkMessageJobService) validateOwner(ctx context.Context, validation *bulkMessageJobValidation, row *bulk.Row) (string, error) {
+	owner := strings.TrimSpace(row.FromPhoneNumber)
+	if SenderPoolID(owner) == nil {
+		number, err := phonenumbers.Parse(service.sanitizePhoneNumber(row.FromPhoneNumber), phonenumbers.UNKNOWN_REGION)
+		if err != nil {
+			validation.addError(fmt.Sprintf("Row [%d]: The FromPhoneNumber [%s] is not a valid E.164 phone number", row.Number, row.FromPhoneNumber))
+			return "", nil
+		}
+		owner = phonenumbers.Format(number, phonenumbers.E164)
+	}
+
+	registered, err := service.isRegistered(ctx, validation, owner)
+	if err != nil {
+		return "", stacktrace.Propagate(err, fmt.Sprintf("cannot check if [%s] is registered for user [%s]", owner, validation.job.UserID))
+	}
+
+	if !registered {
+		validation.addError(fmt.Sprintf("Row [%d]: The FromPhoneNumber [%s] is not registered on your account", row.Number, row.FromPhoneNumber))
+	}
+	return owner, nil
+}
+
+// isRegistered checks if the owner is a phone or a sender pool with a phone of the user, the result is cached for the rest of the file
+func (service *BulkMessageJobService) isRegistered(ctx context.Context, validation *bulkMessageJobValidation, owner string) (bool, error) {
+	if registered, ok := validation.owners[owner]; ok {
+		return registered, nil
+	}
+
+	if poolID := SenderPoolID(owner); poolID != nil {
+		phones, err := service.senderPoolService.Phones(ctx, validation.job.UserID, *poolID)
+		if err != nil && stacktrace.GetCode(err) != repositories.ErrCodeNotFound {
+			return false, stacktrace.Propagate(err, fmt.Sprintf("cannot load sender pool [%s] for user [%s]", owner, validation.job.UserID))
+		}
+		validation.owners[owner] = len(phones) > 0
+		return validation.owners[owner], nil
+	}
+
+	_, err := service.phoneService.Load(ctx, validation.job.UserID, owner)
+	if err != nil && stacktrace.GetCode(err) != repositories.ErrCodeNotFound {
+		return false, stacktrace.Propagate(err, fmt.Sprintf("cannot load phone [%s] for user [%s]", owner, validation.job.UserID))
+	}
+
+	validation.owners[owner] = err == nil
+	return validation.owners[owner], nil
+}
+
+// sendRow queues the message of a pending row, the row ID is the idempotency key so a retried batch doesn't send it twice
+func (service *BulkMessageJobService) sendRow(ctx context.Context, job *entities.BulkMessageJob, row *entities.BulkMessageJobRow) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	owner, _ := phonenumbers.Parse(row.Owner, phonenumbers.UNKNOWN_REGION)
+	requestID := job.RequestID()
+	idempotencyKey := row.ID.String()
+
+	message, err := service.messageService.SendMessage(ctx, MessageSendParams{
+		Owner:             owner,
+		Contact:           row.Contact,
+		Content:           row.Content,
+		Source:            job.Source,
+		SendAt:            row.SendAt,
+		RequestID:         &requestID,
+		UserID:            job.UserID,
+		Encrypted:         job.Encrypted,
+		RequestReceivedAt: time.Now().UTC(),
+		IdempotencyKey:    &idempotencyKey,
+		SenderPoolID:      SenderPoolID(row.Owner),
+	})
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot send row [%d] of bulk message job [%s] to [%s]", row.Row, job.ID, row.Contact)))
+		reason := "the message could not be queued, please try again later"
+		row.Status = entities.BulkMessageJobRowStatusFailed.String()
+		row.Reason = &reason
+	} else {
+		row.Status = entities.BulkMessageJobRowStatusQueued.String()
+		row.MessageID = &message.ID
+	}
+
+	if err = service.repository.UpdateRow(ctx, row); err != nil {
+		msg := fmt.Sprintf("cannot update row [%d] of bulk message job [%s] with status [%s]", row.Row, job.ID, row.Status)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// invalidate stops an entities.BulkMessageJob with validation errors, no message of an invalid file is sent
+func (service *BulkMessageJobService) invalidate(ctx context.Context, job *entities.BulkMessageJob, reasons ...string) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.DeleteRows(ctx, job.ID); err != nil {
+		msg := fmt.Sprintf("cannot delete the rows of invalid bulk message job [%s]", job.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	job.Valid = 0
+	if err := service.update(ctx, job.Fail(entities.BulkMessageJobStatusInvalid, reasons...)); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot invalidate bulk message job [%s]", job.ID)))
+	}
+
+	service.deleteFile(ctx, job)
+	ctxLogger.Info(fmt.Sprintf("bulk message job [%s] is invalid with [%d] errors", job.ID, len(reasons)))
+	return nil
+}
+
+// fail stops an entities.BulkMessageJob which cannot be validated, no message of the file is sent
+func (service *BulkMessageJobService) fail(ctx context.Context, job *entities.BulkMessageJob, reason string) error {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	if err := service.repository.DeleteRows(ctx, job.ID); err != nil {
+		msg := fmt.Sprintf("cannot delete the rows of failed bulk message job [%s]", job.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	job.Valid = 0
+	if err := service.update(ctx, job.Fail(entities.BulkMessageJobStatusFailed, reason)); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot fail bulk message job [%s]", job.ID)))
+	}
+
+	if job.StorageKey != "" {
+		service.deleteFile(ctx, job)
+	}
+	return nil
+}
+
+func (service *BulkMessageJobService) update(ctx context.Context, job *entities.BulkMessageJob) error {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	if err := service.repository.Update(ctx, job); err != nil {
+		msg := fmt.Sprintf("cannot update bulk message job [%s] with status [%s]", job.ID, job.Status)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+	return nil
+}
+
+// deleteFile removes the uploaded file once its rows are stored, a failure doesn't stop the job
+func (service *BulkMessageJobService) deleteFile(ctx context.Context, job *entities.BulkMessageJob) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.storage.Delete(ctx, job.StorageKey); err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot delete file [%s] of bulk message job [%s]", job.StorageKey, job.ID)))
+	}
+}
+
+func (service *BulkMessageJobService) dispatchBatch(ctx context.Context, source string, job *entities.BulkMessageJob, delay time.Duration, attempt uint) error {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	event, err := service.createEvent(events.EventTypeBulkMessageJobBatchDue, source, &events.BulkMessageJobBatchDuePayload{
+		BulkMessageJobID: job.ID,
+		UserID:           job.UserID,
+		Attempt:          attempt,
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot create [%s] event for bulk message job [%s]", events.EventTypeBulkMessageJobBatchDue, job.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if _, err = service.eventDispatcher.DispatchWithTimeout(ctx, event, delay); err != nil {
+		msg := fmt.Sprintf("cannot dispatch [%s] event for bulk message job [%s]", event.Type(), job.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// sanitizePhoneNumber adds the missing + prefix of an international phone number
+func (service *BulkMessageJobService) sanitizePhoneNumber(value string) string {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "+") || len(value) <= 9 {
+		return value
+	}
+
+	for _, char := range value {
+		if !unicode.IsDigit(char) {
+			return value
+		}
+	}
+	return "+" + value
+}
+
+func (service *BulkMessageJobService) toRecord(row *entities.BulkMessageJobRow) []string {
+	record := []string{strconv.Itoa(row.Row), row.Owner, row.Contact, "", row.Status, ""}
+	if row.MessageID != nil {
+		record[3] = row.MessageID.String()
+	}
+	if row.Reason != nil {
+		record[5] = *row.Reason
+	}
+	return record
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/palantir/stacktrace"
+)
+
+// ContactGroupService is responsible for handling entities.ContactGroup
+type ContactGroupService struct {
+	service
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	repository repositories.ContactGroupRepository
+}
+
+// NewContactGroupService creates a new ContactGroupService
+func NewContactGroupService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.ContactGroupRepository,
+) (s *ContactGroupService) {
+	return &ContactGroupService{
+		logger:     logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:     tracer,
+		repository: repository,
+	}
+}
+
+// Index fetches the entities.ContactGroup for an entities.UserID
+func (service *ContactGroupService) Index(ctx context.Context, userID entities.UserID, params repositories.IndexParams) ([]*entities.ContactGroup, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	groups, err := service.repository.Index(ctx, userID, params)
+	if err != nil {
+		msg := fmt.Sprintf("could not fetch contact groups with params [%+#v]", params)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("fetched [%d] contact groups with prams [%+#v]", len(groups), params))
+	return groups, nil
+}
+
+// Load an entities.ContactGroup by ID
+func (service *ContactGroupService) Load(ctx context.Context, userID entities.UserID, groupID uuid.UUID) (*entities.ContactGroup, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	group, err := service.repository.Load(ctx, userID, groupID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load contact group with ID [%s] for user [%s]", groupID, userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	return group, nil
+}
+
+// Delete an entities.ContactGroup
+func (service *ContactGroupService) Delete(ctx context.Context, userID entities.UserID, groupID uuid.UUID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if _, err := service.repository.Load(ctx, userID, groupID); err != nil {
+		msg := fmt.Sprintf("cannot load contact group with userID [%s] and groupID [%s]", userID, groupID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if err := service.repository.Delete(ctx, userID, groupID); err != nil {
+		msg := fmt.Sprintf("cannot delete contact group with id [%s] and user id [%s]", groupID, userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted contact group with id [%s] and user id [%s]", groupID, userID))
+	return nil
+}
+
+// ContactGroupStoreParams are parameters for creating a new entities.ContactGroup
+type ContactGroupStoreParams struct {
+	UserID       entities.UserID
+	Name         string
+	Description  string
+	PhoneNumbers []string
+}
+
+// Store a new entities.ContactGroup
+func (service *ContactGroupService) Store(ctx context.Context, params *ContactGroupStoreParams) (*entities.ContactGroup, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	group := &entities.ContactGroup{
+		ID:           uuid.New(),
+		UserID:       params.UserID,
+		Name:         params.Name,
+		Description:  params.Description,
+		PhoneNumbers: pq.StringArray(params.PhoneNumbers),
+		CreatedAt:    time.Now().UTC(),
+		UpdatedAt:    time.Now().UTC(),
+	}
+
+	if err := service.repository.Save(ctx, group); err != nil {
+		msg := fmt.Sprintf("cannot save contact group with id [%s]", group.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("contact group saved with id [%s] and [%d] phone numbers in the [%T]", group.ID, group.Size(), service.repository))
+	return group, nil
+}
+
+// ContactGroupUpdateParams are parameters for updating an entities.ContactGroup
+type ContactGroupUpdateParams struct {
+	ContactGroupStoreParams
+	GroupID uuid.UUID
+}
+
+// Update an entities.ContactGroup
+func (service *ContactGroupService) Update(ctx context.Context, params *ContactGroupUpdateParams) (*entities.ContactGroup, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	group, err := service.repository.Load(ctx, params.UserID, params.GroupID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load contact group with userID [%s] and groupID [%s]", params.UserID, params.GroupID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	group.Name = params.Name
+	group.Description = params.Description
+	group.PhoneNumbers = params.PhoneNumbers
+	group.UpdatedAt = time.Now().UTC()
+
+	if err = service.repository.Save(ctx, group); err != nil {
+		msg := fmt.Sprintf("cannot save contact group with id [%s] after update", group.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("contact group updated with id [%s] in the [%T]", group.ID, service.repository))
+	return group, nil
+}
+
+// ContactGroupMembersParams are parameters for adding or removing phone numbers from an entities.ContactGroup
+type ContactGroupMembersParams struct {
+	UserID       entities.UserID
+	GroupID      uuid.UUID
+	PhoneNumbers []string
+}
+
+// AddMembers adds phone numbers which are not already in the entities.ContactGroup
+func (service *ContactGroupService) AddMembers(ctx context.Context, params *ContactGroupMembersParams) (*entities.ContactGroup, error) {
+	return service.updateMembers(ctx, params, func(members map[string]bool, phoneNumber string) {
+		members[phoneNumber] = true
+	})
+}
+
+// RemoveMembers removes phone numbers from the entities.ContactGroup
+func (service *ContactGroupService) RemoveMembers(ctx context.Context, params *ContactGroupMembersParams) (*entities.ContactGroup, error) {
+	return service.updateMembers(ctx, params, func(members map[string]bool, phoneNumber string) {
+		members[phoneNumber] = false
+	})
+}
+
+func (service *ContactGroupService) updateMembers(ctx context.Context, params *ContactGroupMembersParams, apply func(members map[string]bool, phoneNumber string)) (*entities.ContactGroup, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	group, err := service.repository.Load(ctx, params.UserID, params.GroupID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load contact group with userID [%s] and groupID [%s]", params.UserID, params.GroupID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	members := map[string]bool{}
+	for _, phoneNumber := range group.PhoneNumbers {
+		members[phoneNumber] = true
+	}
+
+	for _, phoneNumber := range params.PhoneNumbers {
+		apply(members, phoneNumber)
+	}
+
+	phoneNumbers := make([]string, 0, len(members))
+	for _, phoneNumber := range append(group.PhoneNumbers, params.PhoneNumbers...) {
+		if members[phoneNumber] {
+			phoneNumbers = append(phoneNumbers, phoneNumber)
+			delete(members, phoneNumber)
+		}
+	}
+
+	size := group.Size()
+	group.PhoneNumbers = phoneNumbers
+	group.UpdatedAt = time.Now().UTC()
+
+	if err = service.repository.Save(ctx, group); err != nil {
+		msg := fmt.Sprintf("cannot save contact group with id [%s] after updating members", group.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("contact group with id [%s] changed from [%d] to [%d] phone numbers", group.ID, size, group.Size()))
+	return group, nil
+}
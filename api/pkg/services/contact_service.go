@@ -0,0 +1,329 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/palantir/stacktrace"
+)
+
+// contactSeparator separates multiple phone numbers or tags in a single CSV column
+const contactSeparator = ";"
+
+// ContactService is responsible for handling entities.Contact
+type ContactService struct {
+	service
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	repository repositories.ContactRepository
+}
+
+// NewContactService creates a new ContactService
+func NewContactService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.ContactRepository,
+) (s *ContactService) {
+	return &ContactService{
+		logger:     logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:     tracer,
+		repository: repository,
+	}
+}
+
+// Index fetches the entities.Contact for an entities.UserID
+func (service *ContactService) Index(ctx context.Context, userID entities.UserID, tag string, params repositories.IndexParams) ([]*entities.Contact, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	contacts, err := service.repository.Index(ctx, userID, tag, params)
+	if err != nil {
+		msg := fmt.Sprintf("could not fetch contacts with tag [%s] and params [%+#v]", tag, params)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("fetched [%d] contacts with tag [%s] and prams [%+#v]", len(contacts), tag, params))
+	return contacts, nil
+}
+
+// Load an entities.Contact by ID
+func (service *ContactService) Load(ctx context.Context, userID entities.UserID, contactID uuid.UUID) (*entities.Contact, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	contact, err := service.repository.Load(ctx, userID, contactID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load contact with ID [%s] for user [%s]", contactID, userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	return contact, nil
+}
+
+// Export all the entities.Contact of a user as a CSV file in the same format used by Import
+func (service *ContactService) Export(ctx context.Context, userID entities.UserID) ([]byte, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	contacts, err := service.repository.FetchAll(ctx, userID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch contacts to export for user [%s]", userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	fieldSet := map[string]struct{}{}
+	for _, contact := range contacts {
+		for key := range contact.CustomFields {
+			fieldSet[key] = struct{}{}
+		}
+	}
+
+	fields := make([]string, 0, len(fieldSet))
+	for key := range fieldSet {
+		fields = append(fields, key)
+	}
+	sort.Strings(fields)
+
+	buffer := new(bytes.Buffer)
+	writer := csv.NewWriter(buffer)
+	if err = writer.Write(append([]string{"Name", "PhoneNumbers", "Tags(optional)", "Notes(optional)"}, fields...)); err != nil {
+		msg := fmt.Sprintf("cannot write CSV header for contacts of user [%s]", userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	for _, contact := range contacts {
+		record := []string{
+			contact.Name,
+			strings.Join(contact.PhoneNumbers, contactSeparator),
+			strings.Join(contact.Tags, contactSeparator),
+			contact.Notes,
+		}
+		for _, field := range fields {
+			record = append(record, contact.CustomFields[field])
+		}
+
+		if err = writer.Write(record); err != nil {
+			msg := fmt.Sprintf("cannot write CSV record for contact [%s] of user [%s]", contact.ID, userID)
+			return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+	}
+
+	if writer.Flush(); writer.Error() != nil {
+		msg := fmt.Sprintf("cannot flush CSV writer for contacts of user [%s]", userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(writer.Error(), msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("exported [%d] contacts with [%d] custom fields for user [%s]", len(contacts), len(fields), userID))
+	return buffer.Bytes(), nil
+}
+
+// Delete an entities.Contact
+func (service *ContactService) Delete(ctx context.Context, userID entities.UserID, contactID uuid.UUID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if _, err := service.repository.Load(ctx, userID, contactID); err != nil {
+		msg := fmt.Sprintf("cannot load contact with userID [%s] and contactID [%s]", userID, contactID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if err := service.repository.Delete(ctx, userID, contactID); err != nil {
+		msg := fmt.Sprintf("cannot delete contact with id [%s] and user id [%s]", contactID, userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted contact with id [%s] and user id [%s]", contactID, userID))
+	return nil
+}
+
+// ContactStoreParams are parameters for creating a new entities.Contact
+type ContactStoreParams struct {
+	UserID       entities.UserID
+	Name         string
+	PhoneNumbers []string
+	Tags         []string
+	CustomFields map[string]string
+	Notes        string
+}
+
+// Store a new entities.Contact
+func (service *ContactService) Store(ctx context.Context, params *ContactStoreParams) (*entities.Contact, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	contact := &entities.Contact{
+		ID:           uuid.New(),
+		UserID:       params.UserID,
+		Name:         params.Name,
+		PhoneNumbers: pq.StringArray(params.PhoneNumbers),
+		Tags:         pq.StringArray(params.Tags),
+		CustomFields: params.CustomFields,
+		Notes:        params.Notes,
+		CreatedAt:    time.Now().UTC(),
+		UpdatedAt:    time.Now().UTC(),
+	}
+
+	if err := service.repository.Save(ctx, contact); err != nil {
+		msg := fmt.Sprintf("cannot save contact with id [%s]", contact.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("contact saved with id [%s] in the [%T]", contact.ID, service.repository))
+	return contact, nil
+}
+
+// ContactUpdateParams are parameters for updating an entities.Contact
+type ContactUpdateParams struct {
+	ContactStoreParams
+	ContactID uuid.UUID
+}
+
+// Update an entities.Contact
+func (service *ContactService) Update(ctx context.Context, params *ContactUpdateParams) (*entities.Contact, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	contact, err := service.repository.Load(ctx, params.UserID, params.ContactID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load contact with userID [%s] and contactID [%s]", params.UserID, params.ContactID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	contact.Name = params.Name
+	contact.PhoneNumbers = params.PhoneNumbers
+	contact.Tags = params.Tags
+	contact.CustomFields = params.CustomFields
+	contact.Notes = params.Notes
+	contact.UpdatedAt = time.Now().UTC()
+
+	if err = service.repository.Save(ctx, contact); err != nil {
+		msg := fmt.Sprintf("cannot save contact with id [%s] after update", contact.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("contact updated with id [%s] in the [%T]", contact.ID, service.repository))
+	return contact, nil
+}
+
+// Import creates or updates entities.Contact in bulk. An existing contact with any of the phone numbers of a row is updated.
+// All the contacts are saved in a single transaction so a failure never leaves a partial import.
+func (service *ContactService) Import(ctx context.Context, userID entities.UserID, params []*ContactStoreParams) ([]*entities.Contact, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	phoneNumbers := make([]string, 0, len(params))
+	for _, param := range params {
+		phoneNumbers = append(phoneNumbers, param.PhoneNumbers...)
+	}
+
+	existing, err := service.repository.FetchByPhoneNumbers(ctx, userID, phoneNumbers)
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch existing contacts for [%d] imported rows of user [%s]", len(params), userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	contactsByPhone := map[string]*entities.Contact{}
+	for _, contact := range existing {
+		for _, phoneNumber := range contact.PhoneNumbers {
+			if _, ok := contactsByPhone[phoneNumber]; !ok {
+				contactsByPhone[phoneNumber] = contact
+			}
+		}
+	}
+
+	contacts := make([]*entities.Contact, 0, len(params))
+	imported := map[uuid.UUID]bool{}
+	for _, param := range params {
+		contact := service.findImportedContact(contactsByPhone, param.PhoneNumbers)
+		if contact == nil {
+			contact = &entities.Contact{
+				ID:        uuid.New(),
+				UserID:    userID,
+				CreatedAt: time.Now().UTC(),
+			}
+		}
+
+		contact.Name = param.Name
+		contact.PhoneNumbers = param.PhoneNumbers
+		contact.Tags = param.Tags
+		contact.CustomFields = param.CustomFields
+		contact.Notes = param.Notes
+		contact.UpdatedAt = time.Now().UTC()
+
+		for _, phoneNumber := range contact.PhoneNumbers {
+			contactsByPhone[phoneNumber] = contact
+		}
+
+		// a contact which matches multiple rows is saved once with the values of the last row
+		if !imported[contact.ID] {
+			imported[contact.ID] = true
+			contacts = append(contacts, contact)
+		}
+	}
+
+	if err = service.repository.SaveAll(ctx, contacts); err != nil {
+		msg := fmt.Sprintf("cannot save [%d] imported contacts for user [%s]", len(contacts), userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("imported [%d] contacts for user [%s]", len(contacts), userID))
+	return contacts, nil
+}
+
+// findImportedContact returns the contact with the first phone number of the row which is already known
+func (service *ContactService) findImportedContact(contactsByPhone map[string]*entities.Contact, phoneNumbers []string) *entities.Contact {
+	for _, phoneNumber := range phoneNumbers {
+		if contact, ok := contactsByPhone[phoneNumber]; ok {
+			return contact
+		}
+	}
+	return nil
+}
+
+// ContactTargetParams are parameters for resolving the entities.Contact a message should be sent to
+type ContactTargetParams struct {
+	UserID     entities.UserID
+	ContactIDs []uuid.UUID
+	Tags       []string
+}
+
+// Targets fetches the unique entities.Contact matching either the IDs or the tags
+func (service *ContactService) Targets(ctx context.Context, params *ContactTargetParams) ([]*entities.Contact, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	byID, err := service.repository.FetchByIDs(ctx, params.UserID, params.ContactIDs)
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch [%d] contacts by ID for user [%s]", len(params.ContactIDs), params.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	byTag, err := service.repository.FetchByTags(ctx, params.UserID, params.Tags)
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch contacts with tags [%v] for user [%s]", params.Tags, params.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	seen := map[uuid.UUID]bool{}
+	contacts := make([]*entities.Contact, 0, len(byID)+len(byTag))
+	for _, contact := range append(byID, byTag...) {
+		if seen[contact.ID] {
+			continue
+		}
+		seen[contact.ID] = true
+		contacts = append(contacts, contact)
+	}
+
+	ctxLogger.Info(fmt.Sprintf("resolved [%d] contacts for user [%s] with [%d] IDs and tags [%v]", len(contacts), params.UserID, len(params.ContactIDs), params.Tags))
+	return contacts, nil
+}
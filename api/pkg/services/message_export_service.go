@@ -0,0 +1,388 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/emails"
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/exports"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/storage"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	// messageExportBatchSize is the number of messages which are loaded from the database at once
+	messageExportBatchSize = 1000
+
+	// messageExportSyncLimit is the maximum number of messages which are streamed in the HTTP response.
+	// Larger exports are created in the background and the download link is sent by email.
+	messageExportSyncLimit = 50_000
+
+	// messageExportTTL is how long the download link of an entities.MessageExport is valid
+	messageExportTTL = 7 * 24 * time.Hour
+)
+
+// MessageExportService is responsible for exporting entities.Message
+type MessageExportService struct {
+	service
+	logger            telemetry.Logger
+	tracer            telemetry.Tracer
+	messageRepository repositories.MessageRepository
+	repository        repositories.MessageExportRepository
+	userRepository    repositories.UserRepository
+	storage           storage.Storage
+	mailer            emails.Mailer
+	emailFactory      emails.UserEmailFactory
+	eventDispatcher   *EventDispatcher
+	baseURL           string
+}
+
+// NewMessageExportService creates a new MessageExportService
+func NewMessageExportService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	messageRepository repositories.MessageRepository,
+	repository repositories.MessageExportRepository,
+	userRepository repositories.UserRepository,
+	storage storage.Storage,
+	mailer emails.Mailer,
+	emailFactory emails.UserEmailFactory,
+	eventDispatcher *EventDispatcher,
+	baseURL string,
+) (s *MessageExportService) {
+	return &MessageExportService{
+		logger:            logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:            tracer,
+		messageRepository: messageRepository,
+		repository:        repository,
+		userRepository:    userRepository,
+		storage:           storage,
+		mailer:            mailer,
+		emailFactory:      emailFactory,
+		eventDispatcher:   eventDispatcher,
+		baseURL:           baseURL,
+	}
+}
+
+// MessageExportParams are the parameters for exporting messages
+type MessageExportParams struct {
+	repositories.MessageExportParams
+	UserID entities.UserID
+	Format exports.Format
+	Source string
+}
+
+// Count the messages which will be exported
+func (service *MessageExportService) Count(ctx context.Context, params *MessageExportParams) (int64, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	count, err := service.messageRepository.Count(ctx, params.UserID, params.MessageExportParams)
+	if err != nil {
+		msg := fmt.Sprintf("cannot count messages to export for user [%s]", params.UserID)
+		return 0, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return count, nil
+}
+
+// RequiresAsync checks if an export with this number of messages is too large to stream in the HTTP response
+func (service *MessageExportService) RequiresAsync(count int64) bool {
+	return count > messageExportSyncLimit
+}
+
+// Export streams the messages to the writer in the export format and returns the number of exported messages
+func (service *MessageExportService) Export(ctx context.Context, writer io.Writer, params *MessageExportParams) (int64, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	buffer := bufio.NewWriter(writer)
+	export, err := exports.NewWriter(params.Format, buffer)
+	if err != nil {
+		msg := fmt.Sprintf("cannot create [%s] export writer for user [%s]", params.Format, params.UserID)
+		return 0, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	var count int64
+	err = service.messageRepository.Stream(ctx, params.UserID, params.MessageExportParams, messageExportBatchSize, func(messages []entities.Message) error {
+		for index := range messages {
+			if err = export.Write(&messages[index]); err != nil {
+				return stacktrace.Propagate(err, fmt.Sprintf("cannot export message [%s]", messages[index].ID))
+			}
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot stream messages to export for user [%s]", params.UserID)
+		return count, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = export.Close(); err != nil {
+		msg := fmt.Sprintf("cannot close [%s] export writer for user [%s]", params.Format, params.UserID)
+		return count, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = buffer.Flush(); err != nil {
+		msg := fmt.Sprintf("cannot flush [%s] export for user [%s]", params.Format, params.UserID)
+		return count, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("exported [%d] messages as [%s] for user [%s]", count, params.Format, params.UserID))
+	return count, nil
+}
+
+// Schedule creates an entities.MessageExport which is processed in the background
+func (service *MessageExportService) Schedule(ctx context.Context, params *MessageExportParams) (*entities.MessageExport, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	token, err := service.generateToken()
+	if err != nil {
+		msg := fmt.Sprintf("cannot generate download token for the message export of user [%s]", params.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	export := &entities.MessageExport{
+		ID:        uuid.New(),
+		UserID:    params.UserID,
+		Format:    params.Format.String(),
+		Owner:     params.Owner,
+		Contacts:  params.Contacts,
+		StartDate: params.StartDate,
+		EndDate:   params.EndDate,
+		Status:    entities.MessageExportStatusPending,
+		Token:     token,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	if err = service.repository.Store(ctx, export); err != nil {
+		msg := fmt.Sprintf("cannot store message export [%s] for user [%s]", export.ID, export.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	event, err := service.createEvent(events.EventTypeMessageExportRequested, params.Source, &events.MessageExportRequestedPayload{
+		MessageExportID: export.ID,
+		UserID:          export.UserID,
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot create [%s] event for message export [%s]", events.EventTypeMessageExportRequested, export.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = service.eventDispatcher.Dispatch(ctx, event); err != nil {
+		msg := fmt.Sprintf("cannot dispatch [%s] event for message export [%s]", event.Type(), export.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("scheduled [%s] message export [%s] for user [%s]", export.Format, export.ID, export.UserID))
+	return export, nil
+}
+
+// Process creates the file of a pending entities.MessageExport and emails the download link to the user
+func (service *MessageExportService) Process(ctx context.Context, payload *events.MessageExportRequestedPayload) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	export, err := service.repository.Load(ctx, payload.MessageExportID)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		ctxLogger.Info(fmt.Sprintf("message export [%s] for user [%s] does not exist", payload.MessageExportID, payload.UserID))
+		return nil
+	}
+	if err != nil {
+		msg := fmt.Sprintf("cannot load message export [%s]", payload.MessageExportID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if export.Status != entities.MessageExportStatusPending {
+		ctxLogger.Info(fmt.Sprintf("message export [%s] has already been processed with status [%s]", export.ID, export.Status))
+		return nil
+	}
+
+	user, err := service.userRepository.Load(ctx, export.UserID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load user [%s] for message export [%s]", export.UserID, export.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	// the file is only created once, a retry after the email could not be sent only sends the email again
+	if export.StorageKey == "" {
+		if err = service.store(ctx, export); err != nil {
+			service.fail(ctx, export)
+			msg := fmt.Sprintf("cannot create the file of message export [%s]", export.ID)
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+	}
+
+	email, err := service.emailFactory.MessageExportCompleted(user, export, service.downloadURL(export))
+	if err != nil {
+		service.fail(ctx, export)
+		msg := fmt.Sprintf("cannot create email for message export [%s]", export.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	// the export stays pending when the email cannot be sent so that it is processed again when the event is retried
+	if err = service.mailer.Send(ctx, email); err != nil {
+		msg := fmt.Sprintf("cannot send email for message export [%s] to user [%s]", export.ID, export.UserID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	export.Status = entities.MessageExportStatusCompleted
+	export.UpdatedAt = time.Now().UTC()
+	if err = service.repository.Update(ctx, export); err != nil {
+		msg := fmt.Sprintf("cannot update message export [%s] as completed", export.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("emailed download link of message export [%s] with [%d] messages to user [%s]", export.ID, export.Count, export.UserID))
+	return nil
+}
+
+// Load an entities.MessageExport of a user
+func (service *MessageExportService) Load(ctx context.Context, userID entities.UserID, exportID uuid.UUID) (*entities.MessageExport, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	export, err := service.repository.Load(ctx, exportID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load message export [%s]", exportID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if export.UserID != userID {
+		msg := fmt.Sprintf("message export [%s] does not belong to user [%s]", exportID, userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(repositories.ErrCodeNotFound, msg))
+	}
+
+	return export, nil
+}
+
+// Download the file of a completed entities.MessageExport with the token of the download link
+func (service *MessageExportService) Download(ctx context.Context, exportID uuid.UUID, token string) (*entities.MessageExport, io.ReadCloser, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	export, err := service.repository.Load(ctx, exportID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load message export [%s]", exportID)
+		return nil, nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if subtle.ConstantTimeCompare([]byte(export.Token), []byte(token)) != 1 || !export.IsCompleted() || export.IsExpired(time.Now().UTC()) {
+		msg := fmt.Sprintf("the download link of message export [%s] is invalid or has expired", exportID)
+		return nil, nil, service.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(repositories.ErrCodeNotFound, msg))
+	}
+
+	content, err := service.storage.Load(ctx, export.StorageKey)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load file [%s] of message export [%s]", export.StorageKey, exportID)
+		return nil, nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return export, content, nil
+}
+
+// store streams the export file into the storage.Storage without buffering it in memory
+func (service *MessageExportService) store(ctx context.Context, export *entities.MessageExport) error {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	params := &MessageExportParams{
+		MessageExportParams: repositories.MessageExportParams{
+			Owner:     export.Owner,
+			Contacts:  export.Contacts,
+			StartDate: export.StartDate,
+			EndDate:   export.EndDate,
+		},
+		UserID: export.UserID,
+		Format: exports.Format(export.Format),
+	}
+
+	type result struct {
+		count int64
+		err   error
+	}
+
+	reader, writer := io.Pipe()
+	done := make(chan result, 1)
+	go func() {
+		count, err := service.Export(ctx, writer, params)
+		_ = writer.CloseWithError(err)
+		done <- result{count: count, err: err}
+	}()
+
+	key := fmt.Sprintf("exports/%s/%s.%s", export.UserID, export.ID, export.Format)
+	err := service.storage.Store(ctx, key, reader)
+	_ = reader.CloseWithError(err)
+
+	exported := <-done
+	if exported.err != nil {
+		msg := fmt.Sprintf("cannot export messages for message export [%s]", export.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(exported.err, msg))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot store file [%s] of message export [%s]", key, export.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	expiresAt := time.Now().UTC().Add(messageExportTTL)
+	export.Count = exported.count
+	export.StorageKey = key
+	export.ExpiresAt = &expiresAt
+	export.UpdatedAt = time.Now().UTC()
+
+	if err = service.repository.Update(ctx, export); err != nil {
+		msg := fmt.Sprintf("cannot update message export [%s] with the file [%s]", export.ID, key)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// fail marks the export as failed so the user can request it again, the file of an export which was stored
+// before the email could be created is deleted because nobody can download it.
+func (service *MessageExportService) fail(ctx context.Context, export *entities.MessageExport) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	export.Status = entities.MessageExportStatusFailed
+	export.UpdatedAt = time.Now().UTC()
+	if err := service.repository.Update(ctx, export); err != nil {
+		msg := fmt.Sprintf("cannot update message export [%s] as failed", export.ID)
+		ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+	}
+
+	if export.StorageKey == "" {
+		return
+	}
+
+	if err := service.storage.Delete(ctx, export.StorageKey); err != nil {
+		msg := fmt.Sprintf("cannot delete file [%s] of message export [%s]", export.StorageKey, export.ID)
+		ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+	}
+}
+
+func (service *MessageExportService) downloadURL(export *entities.MessageExport) string {
+	return fmt.Sprintf("%s/message-exports/%s/download?token=%s", service.baseURL, export.ID, export.Token)
+}
+
+func (service *MessageExportService) generateToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", stacktrace.Propagate(err, "cannot generate random bytes")
+	}
+	return hex.EncodeToString(bytes), nil
+}
@@ -10,8 +10,10 @@ import (
 
 	"github.com/nyaruka/phonenumbers"
 
+	"github.com/NdoleStudio/httpsms/pkg/cache"
 	"github.com/NdoleStudio/httpsms/pkg/events"
 	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/segments"
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/google/uuid"
 	"github.com/palantir/stacktrace"
@@ -23,11 +25,15 @@ import (
 // MessageService is handles message requests
 type MessageService struct {
 	service
-	logger          telemetry.Logger
-	tracer          telemetry.Tracer
-	eventDispatcher *EventDispatcher
-	phoneService    *PhoneService
-	repository      repositories.MessageRepository
+	logger             telemetry.Logger
+	tracer             telemetry.Tracer
+	eventDispatcher    *EventDispatcher
+	phoneService       *PhoneService
+	suppressionService *SuppressionService
+	senderPoolService  *SenderPoolService
+	repository         repositories.MessageRepository
+	userRepository     repositories.UserRepository
+	cache              cache.Cache
 }
 
 // NewMessageService creates a new MessageService
@@ -37,13 +43,21 @@ func NewMessageService(
 	repository repositories.MessageRepository,
 	eventDispatcher *EventDispatcher,
 	phoneService *PhoneService,
+	suppressionService *SuppressionService,
+	senderPoolService *SenderPoolService,
+	userRepository repositories.UserRepository,
+	cache cache.Cache,
 ) (s *MessageService) {
 	return &MessageService{
-		logger:          logger.WithService(fmt.Sprintf("%T", s)),
-		tracer:          tracer,
-		repository:      repository,
-		phoneService:    phoneService,
-		eventDispatcher: eventDispatcher,
+		logger:             logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:             tracer,
+		repository:         repository,
+		phoneService:       phoneService,
+		suppressionService: suppressionService,
+		eventDispatcher:    eventDispatcher,
+		senderPoolService:  senderPoolService,
+		userRepository:     userRepository,
+		cache:              cache,
 	}
 }
 
@@ -69,14 +83,15 @@ func (service *MessageService) GetOutstanding(ctx context.Context, params Messag
 	}
 
 	event, err := service.createMessagePhoneSendingEvent(params.Source, events.MessagePhoneSendingPayload{
-		ID:        message.ID,
-		Owner:     message.Owner,
-		Contact:   message.Contact,
-		Timestamp: params.Timestamp,
-		Encrypted: message.Encrypted,
-		UserID:    message.UserID,
-		Content:   message.Content,
-		SIM:       message.SIM,
+		ID:          message.ID,
+		Owner:       message.Owner,
+		Contact:     message.Contact,
+		Timestamp:   params.Timestamp,
+		Encrypted:   message.Encrypted,
+		UserID:      message.UserID,
+		Content:     message.Content,
+		Attachments: message.Attachments,
+		SIM:         message.SIM,
 	})
 	if err != nil {
 		msg := fmt.Sprintf("cannot create [%T] for message with ID [%s]", event, message.ID)
@@ -132,6 +147,89 @@ func (service *MessageService) DeleteMessage(ctx context.Context, source string,
 	return nil
 }
 
+// CancelMessage cancels a message which has not been picked up by a phone
+func (service *MessageService) CancelMessage(ctx context.Context, source string, message *entities.Message) (*entities.Message, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	err := service.repository.UpdateIfNotPickedUp(ctx, message.Cancelled(time.Now().UTC()))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		msg := fmt.Sprintf("message with id [%s] has already been picked up by the phone", message.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeMessagePickedUp, msg))
+	}
+	if err != nil {
+		msg := fmt.Sprintf("cannot update message with id [%s] as cancelled", message.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	event, err := service.createEvent(events.EventTypeMessageAPICancelled, source, &events.MessageAPICancelledPayload{
+		MessageID: message.ID,
+		UserID:    message.UserID,
+		Owner:     message.Owner,
+		Encrypted: message.Encrypted,
+		RequestID: message.RequestID,
+		Contact:   message.Contact,
+		Timestamp: time.Now().UTC(),
+		Content:   message.Content,
+		SIM:       message.SIM,
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot create [%s] event for message with ID [%s]", events.EventTypeMessageAPICancelled, message.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = service.eventDispatcher.Dispatch(ctx, event); err != nil {
+		msg := fmt.Sprintf("cannot dispatch event [%s] with id [%s] for message [%s]", event.Type(), event.ID(), message.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("cancelled message with ID [%s] for user [%s]", message.ID, message.UserID))
+	return message, nil
+}
+
+// RescheduleMessage changes the send time of a message which has not been picked up by a phone
+func (service *MessageService) RescheduleMessage(ctx context.Context, source string, message *entities.Message, sendAt time.Time) (*entities.Message, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	err := service.repository.UpdateIfNotPickedUp(ctx, message.Rescheduled(sendAt.UTC()))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		msg := fmt.Sprintf("message with id [%s] has already been picked up by the phone", message.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeMessagePickedUp, msg))
+	}
+	if err != nil {
+		msg := fmt.Sprintf("cannot update message with id [%s] with send time [%s]", message.ID, sendAt)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	event, err := service.createEvent(events.EventTypeMessageAPIRescheduled, source, &events.MessageAPIRescheduledPayload{
+		MessageID:         message.ID,
+		UserID:            message.UserID,
+		Owner:             message.Owner,
+		RequestID:         message.RequestID,
+		Contact:           message.Contact,
+		Content:           message.Content,
+		Encrypted:         message.Encrypted,
+		SIM:               message.SIM,
+		ScheduledSendTime: *message.ScheduledSendTime,
+		Timestamp:         time.Now().UTC(),
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot create [%s] event for message with ID [%s]", events.EventTypeMessageAPIRescheduled, message.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	// the notification is created when the message is due so that it does not hold back the rate limit of the phone
+	timeout := message.ScheduledSendTime.Sub(time.Now().UTC())
+	if _, err = service.eventDispatcher.DispatchWithTimeout(ctx, event, timeout); err != nil {
+		msg := fmt.Sprintf("cannot dispatch event [%s] with id [%s] for message [%s]", event.Type(), event.ID(), message.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("rescheduled message with ID [%s] for user [%s] to [%s]", message.ID, message.UserID, message.ScheduledSendTime))
+	return message, nil
+}
+
 // DeleteByOwnerAndContact deletes all the messages between an owner and a contact
 func (service *MessageService) DeleteByOwnerAndContact(ctx context.Context, userID entities.UserID, owner, contact string) error {
 	ctx, span := service.tracer.Start(ctx)
@@ -148,7 +246,8 @@ func (service *MessageService) DeleteByOwnerAndContact(ctx context.Context, user
 	return nil
 }
 
-// RespondToMissedCall creates an SMS response to a missed phone call on the android phone
+// RespondToMissedCall creates an SMS response to a missed phone call on the android phone.
+// The out-of-office reply is sent instead when the call is outside the business hours of the phone.
 func (service *MessageService) RespondToMissedCall(ctx context.Context, source string, payload *events.MessageCallMissedPayload) error {
 	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
 	defer span.End()
@@ -159,6 +258,17 @@ func (service *MessageService) RespondToMissedCall(ctx context.Context, source s
 		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
 
+	handled, err := service.respondOutOfOffice(ctx, source, phone, payload.Contact, payload.MessageID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot send out-of-office reply for missed phone call message [%s] of user [%s]", payload.MessageID, payload.UserID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if handled {
+		ctxLogger.Info(fmt.Sprintf("missed phone call message [%s] was handled by the out-of-office reply of phone [%s]", payload.MessageID, payload.Owner))
+		return nil
+	}
+
 	if phone.MissedCallAutoReply == nil || strings.TrimSpace(*phone.MissedCallAutoReply) == "" {
 		ctxLogger.Info(fmt.Sprintf("no auto reply set for phone [%s] for message [%s] with user [%s]", payload.Owner, payload.MessageID, payload.UserID))
 		return nil
@@ -186,6 +296,112 @@ func (service *MessageService) RespondToMissedCall(ctx context.Context, source s
 	return nil
 }
 
+// RespondToReceivedMessage sends the out-of-office reply when a message is received outside the business hours of the phone
+func (service *MessageService) RespondToReceivedMessage(ctx context.Context, source string, payload *events.MessagePhoneReceivedPayload) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if payload.Encrypted {
+		return nil
+	}
+
+	phone, err := service.phoneService.Load(ctx, payload.UserID, payload.Owner)
+	if err != nil {
+		msg := fmt.Sprintf("cannot find phone with owner [%s] for user with ID [%s] when handling received message [%s]", payload.Owner, payload.UserID, payload.MessageID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if phone.OptOutKeyword(payload.Content) != nil || phone.OptInKeyword(payload.Content) != nil {
+		ctxLogger.Info(fmt.Sprintf("no out-of-office reply for opt-out or opt-in message [%s] of user [%s]", payload.MessageID, payload.UserID))
+		return nil
+	}
+
+	// the contact is another phone of the user so replying could create a loop between both phones
+	if _, err = service.phoneService.Load(ctx, payload.UserID, payload.Contact); err == nil {
+		ctxLogger.Info(fmt.Sprintf("no out-of-office reply for message [%s] because the contact [%s] is a phone of user [%s]", payload.MessageID, payload.Contact, payload.UserID))
+		return nil
+	}
+
+	if _, err = service.respondOutOfOffice(ctx, source, phone, payload.Contact, payload.MessageID); err != nil {
+		msg := fmt.Sprintf("cannot send out-of-office reply for received message [%s] of user [%s]", payload.MessageID, payload.UserID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// respondOutOfOffice sends the out-of-office reply once per contact until the phone is back in office.
+// It returns true when the phone is out of office so other automatic replies are not sent.
+func (service *MessageService) respondOutOfOffice(ctx context.Context, source string, phone *entities.Phone, contact string, messageID uuid.UUID) (bool, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if !phone.HasOutOfOfficeReply() {
+		return false, nil
+	}
+
+	user, err := service.userRepository.Load(ctx, phone.UserID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load user with ID [%s]", phone.UserID)
+		return false, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	timestamp := time.Now().In(user.Location())
+	if !phone.IsOutOfOffice(timestamp) {
+		return false, nil
+	}
+
+	suppressed, err := service.suppressionService.IsSuppressed(ctx, phone.UserID, contact)
+	if err != nil {
+		msg := fmt.Sprintf("cannot check if contact [%s] is suppressed for user [%s]", contact, phone.UserID)
+		return true, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+	if suppressed {
+		ctxLogger.Info(fmt.Sprintf("no out-of-office reply for contact [%s] of user [%s] because the contact opted out", contact, phone.UserID))
+		return true, nil
+	}
+
+	owner, err := phonenumbers.Parse(phone.PhoneNumber, phonenumbers.UNKNOWN_REGION)
+	if err != nil {
+		msg := fmt.Sprintf("cannot parse phone number [%s] of phone [%s] for the out-of-office reply", phone.PhoneNumber, phone.ID)
+		return true, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	// the key is added atomically so that only one of the messages received at the same time gets an out-of-office reply
+	opening := phone.NextOpening(timestamp)
+	key := fmt.Sprintf("out-of-office:%s:%s:%d", phone.ID, contact, opening.Unix())
+	added, err := service.cache.Add(ctx, key, messageID.String(), time.Until(opening))
+	if err != nil {
+		msg := fmt.Sprintf("cannot store out-of-office key [%s] in the cache", key)
+		return true, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+	if !added {
+		ctxLogger.Info(fmt.Sprintf("out-of-office reply already sent to contact [%s] from phone [%s] before [%s]", contact, phone.PhoneNumber, opening))
+		return true, nil
+	}
+
+	requestID := fmt.Sprintf("out-of-office-%s", messageID)
+	message, err := service.SendMessage(ctx, MessageSendParams{
+		Owner:             owner,
+		Contact:           contact,
+		Content:           *phone.OutOfOfficeReply,
+		Source:            source,
+		RequestID:         &requestID,
+		UserID:            phone.UserID,
+		RequestReceivedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		if deleteErr := service.cache.Delete(ctx, key); deleteErr != nil {
+			ctxLogger.Error(stacktrace.Propagate(deleteErr, fmt.Sprintf("cannot delete out-of-office key [%s] from the cache", key)))
+		}
+		msg := fmt.Sprintf("cannot send out-of-office reply from [%s] to [%s] for user [%s]", phone.PhoneNumber, contact, phone.UserID)
+		return true, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("sent out-of-office reply [%s] to contact [%s] for message [%s] of user [%s]", message.ID, contact, messageID, phone.UserID))
+	return true, nil
+}
+
 // MessageGetParams parameters for sending a new message
 type MessageGetParams struct {
 	repositories.IndexParams
@@ -211,6 +427,31 @@ func (service *MessageService) GetMessages(ctx context.Context, params MessageGe
 	return messages, nil
 }
 
+// SearchMessages searches all the messages of a user and returns the cursor of the next page if there are more results
+func (service *MessageService) SearchMessages(ctx context.Context, userID entities.UserID, params repositories.MessageSearchParams) (*[]entities.Message, *repositories.Cursor, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	limit := params.Limit
+	params.Limit++
+
+	messages, err := service.repository.Search(ctx, userID, params)
+	if err != nil {
+		msg := fmt.Sprintf("could not search messages for user [%s] with params [%+#v]", userID, params)
+		return nil, nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	var cursor *repositories.Cursor
+	if len(*messages) > limit {
+		*messages = (*messages)[:limit]
+		last := (*messages)[limit-1]
+		cursor = repositories.NewCursor(last.OrderTimestamp, last.ID.String())
+	}
+
+	ctxLogger.Info(fmt.Sprintf("found [%d] messages for user [%s] with params [%+#v]", len(*messages), userID, params))
+	return messages, cursor, nil
+}
+
 // GetMessage fetches a message by the ID
 func (service *MessageService) GetMessage(ctx context.Context, userID entities.UserID, messageID uuid.UUID) (*entities.Message, error) {
 	ctx, span := service.tracer.Start(ctx)
@@ -270,6 +511,9 @@ type MessageReceiveParams struct {
 	Timestamp time.Time
 	Encrypted bool
 	Source    string
+
+	// Attachments are the download URLs of the media files of an MMS message
+	Attachments []string
 }
 
 // ReceiveMessage handles message received by a mobile phone
@@ -280,14 +524,15 @@ func (service *MessageService) ReceiveMessage(ctx context.Context, params *Messa
 	ctxLogger := service.tracer.CtxLogger(service.logger, span)
 
 	eventPayload := events.MessagePhoneReceivedPayload{
-		MessageID: uuid.New(),
-		UserID:    params.UserID,
-		Encrypted: params.Encrypted,
-		Owner:     phonenumbers.Format(&params.Owner, phonenumbers.E164),
-		Contact:   params.Contact,
-		Timestamp: params.Timestamp,
-		Content:   params.Content,
-		SIM:       params.SIM,
+		MessageID:   uuid.New(),
+		UserID:      params.UserID,
+		Encrypted:   params.Encrypted,
+		Owner:       phonenumbers.Format(&params.Owner, phonenumbers.E164),
+		Contact:     params.Contact,
+		Timestamp:   params.Timestamp,
+		Content:     params.Content,
+		Attachments: params.Attachments,
+		SIM:         params.SIM,
 	}
 
 	ctxLogger.Info(fmt.Sprintf("creating cloud event for received with ID [%s]", eventPayload.MessageID))
@@ -306,7 +551,36 @@ func (service *MessageService) ReceiveMessage(ctx context.Context, params *Messa
 	}
 	ctxLogger.Info(fmt.Sprintf("event [%s] dispatched succesfully", event.ID()))
 
-	return service.storeReceivedMessage(ctx, eventPayload)
+	message, err := service.storeReceivedMessage(ctx, eventPayload)
+	if err != nil {
+		msg := fmt.Sprintf("cannot store received message with id [%s]", eventPayload.MessageID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	service.handleOptOutKeyword(ctx, params.Source, message)
+	return message, nil
+}
+
+// handleOptOutKeyword updates the suppression list when the contact replies with an opt-out or opt-in keyword
+func (service *MessageService) handleOptOutKeyword(ctx context.Context, source string, message *entities.Message) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if message.Encrypted {
+		return
+	}
+
+	phone, err := service.phoneService.Load(ctx, message.UserID, message.Owner)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load phone [%s] for user [%s] to check opt-out keywords of message [%s]", message.Owner, message.UserID, message.ID)
+		ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		return
+	}
+
+	if err = service.suppressionService.HandleKeyword(ctx, source, phone, message.Contact, message.Content); err != nil {
+		msg := fmt.Sprintf("cannot handle opt-out keywords for message [%s] and user [%s]", message.ID, message.UserID)
+		ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+	}
 }
 
 func (service *MessageService) handleMessageSentEvent(ctx context.Context, params MessageStoreEventParams, message *entities.Message) error {
@@ -407,8 +681,34 @@ type MessageSendParams struct {
 	RequestID         *string
 	UserID            entities.UserID
 	RequestReceivedAt time.Time
+
+	// IdempotencyKey is used to return the original message when the same request is sent again to the same contact
+	IdempotencyKey *string
+
+	// Attachments are the download URLs of the media files of an MMS message
+	Attachments []string
+
+	// SenderPoolID is the entities.SenderPool which chooses the Owner of the message when it is set
+	SenderPoolID *uuid.UUID
 }
 
+const (
+	// messageIdempotencyTTL is how long an idempotency key is remembered after a message is sent
+	messageIdempotencyTTL = 24 * time.Hour
+
+	// messageIdempotencyWaitAttempts is the number of times a repeated request waits for the message of the first request to be stored
+	messageIdempotencyWaitAttempts = 10
+
+	// messageIdempotencyWaitInterval is the time between the attempts to load the message of the first request
+	messageIdempotencyWaitInterval = 200 * time.Millisecond
+)
+
+// ErrCodeIdempotencyKeyInUse is returned when a message with the same idempotency key is still being sent
+const ErrCodeIdempotencyKeyInUse = stacktrace.ErrorCode(2000)
+
+// ErrCodeMessagePickedUp is returned when a phone picks up a message while it is being cancelled or rescheduled
+const ErrCodeMessagePickedUp = stacktrace.ErrorCode(2001)
+
 // SendMessage a new message
 func (service *MessageService) SendMessage(ctx context.Context, params MessageSendParams) (*entities.Message, error) {
 	ctx, span := service.tracer.Start(ctx)
@@ -416,10 +716,34 @@ func (service *MessageService) SendMessage(ctx context.Context, params MessageSe
 
 	ctxLogger := service.tracer.CtxLogger(service.logger, span)
 
+	messageID := uuid.New()
+	if params.IdempotencyKey != nil {
+		message, err := service.reserveIdempotencyKey(ctx, params, messageID)
+		if err != nil {
+			msg := fmt.Sprintf("cannot reserve idempotency key [%s] for user [%s]", *params.IdempotencyKey, params.UserID)
+			return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+		}
+		if message != nil {
+			ctxLogger.Info(fmt.Sprintf("returning message [%s] for repeated idempotency key [%s] of user [%s]", message.ID, *params.IdempotencyKey, params.UserID))
+			return message, nil
+		}
+	}
+
+	if params.SenderPoolID != nil {
+		owner, err := service.routeSenderPool(ctx, params)
+		if err != nil {
+			service.releaseIdempotencyKey(ctx, params)
+			msg := fmt.Sprintf("cannot route message to [%s] through sender pool [%s]", params.Contact, params.SenderPoolID)
+			return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+		}
+		params.Owner = owner
+	}
+
 	sendAttempts, sim := service.phoneSettings(ctx, params.UserID, phonenumbers.Format(params.Owner, phonenumbers.E164))
+	encoding, segmentCount := service.analyzeContent(params.Content, params.Encrypted)
 
 	eventPayload := events.MessageAPISentPayload{
-		MessageID:         uuid.New(),
+		MessageID:         messageID,
 		UserID:            params.UserID,
 		Encrypted:         params.Encrypted,
 		MaxSendAttempts:   sendAttempts,
@@ -429,11 +753,15 @@ func (service *MessageService) SendMessage(ctx context.Context, params MessageSe
 		RequestReceivedAt: params.RequestReceivedAt,
 		Content:           params.Content,
 		ScheduledSendTime: params.SendAt,
+		Encoding:          encoding,
+		Segments:          segmentCount,
+		Attachments:       params.Attachments,
 		SIM:               sim,
 	}
 
 	event, err := service.createMessageAPISentEvent(params.Source, eventPayload)
 	if err != nil {
+		service.releaseIdempotencyKey(ctx, params)
 		msg := fmt.Sprintf("cannot create %T from payload with message id [%s]", event, eventPayload.MessageID)
 		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
@@ -441,12 +769,14 @@ func (service *MessageService) SendMessage(ctx context.Context, params MessageSe
 
 	message, err := service.storeSentMessage(ctx, eventPayload)
 	if err != nil {
+		service.releaseIdempotencyKey(ctx, params)
 		msg := fmt.Sprintf("cannot store message with id [%s]", eventPayload.MessageID)
 		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
 
 	timeout := service.getSendDelay(ctxLogger, eventPayload, params.SendAt)
 	if _, err = service.eventDispatcher.DispatchWithTimeout(ctx, event, timeout); err != nil {
+		service.releaseIdempotencyKey(ctx, params)
 		msg := fmt.Sprintf("cannot dispatch event type [%s] and id [%s]", event.Type(), event.ID())
 		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
@@ -455,6 +785,136 @@ func (service *MessageService) SendMessage(ctx context.Context, params MessageSe
 	return message, err
 }
 
+// routeSenderPool chooses the phone of the entities.SenderPool which sends the message
+func (service *MessageService) routeSenderPool(ctx context.Context, params MessageSendParams) (*phonenumbers.PhoneNumber, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	phone, err := service.senderPoolService.Route(ctx, &SenderPoolRouteParams{
+		UserID:  params.UserID,
+		PoolID:  *params.SenderPoolID,
+		Contact: params.Contact,
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot route message of sender pool [%s] for user [%s]", params.SenderPoolID, params.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	owner, err := phonenumbers.Parse(phone.PhoneNumber, phonenumbers.UNKNOWN_REGION)
+	if err != nil {
+		msg := fmt.Sprintf("cannot parse phone number [%s] of sender pool [%s]", phone.PhoneNumber, params.SenderPoolID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return owner, nil
+}
+
+// reserveIdempotencyKey atomically stores the ID of the new message under the idempotency key so that concurrent requests
+// with the same key send only one message. When the key is already reserved, it returns the message of the first request.
+func (service *MessageService) reserveIdempotencyKey(ctx context.Context, params MessageSendParams, messageID uuid.UUID) (*entities.Message, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	key := service.idempotencyCacheKey(params)
+	reserved, err := service.cache.Add(ctx, key, messageID.String(), messageIdempotencyTTL)
+	if err != nil {
+		msg := fmt.Sprintf("cannot add idempotency key [%s] for message [%s]", *params.IdempotencyKey, messageID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	value, err := service.cache.Get(ctx, key)
+	if err != nil {
+		msg := fmt.Sprintf("the message for idempotency key [%s] is still being sent", *params.IdempotencyKey)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeIdempotencyKeyInUse, msg))
+	}
+
+	existingID, err := uuid.Parse(value)
+	if err != nil {
+		msg := fmt.Sprintf("cannot parse message ID [%s] for idempotency key [%s]", value, *params.IdempotencyKey)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	// the first request stores the message right after it reserves the key
+	for attempt := 0; attempt < messageIdempotencyWaitAttempts; attempt++ {
+		message, err := service.repository.Load(ctx, params.UserID, existingID)
+		if err == nil {
+			return message, nil
+		}
+		if stacktrace.GetCode(err) != repositories.ErrCodeNotFound {
+			msg := fmt.Sprintf("cannot load message [%s] for idempotency key [%s]", existingID, *params.IdempotencyKey)
+			return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(ctx.Err(), fmt.Sprintf("stopped waiting for message [%s]", existingID)))
+		case <-time.After(messageIdempotencyWaitInterval):
+		}
+	}
+
+	msg := fmt.Sprintf("message [%s] for idempotency key [%s] is still being sent", existingID, *params.IdempotencyKey)
+	return nil, service.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeIdempotencyKeyInUse, msg))
+}
+
+// releaseIdempotencyKey removes the idempotency key of a message which could not be sent so that the request can be retried
+func (service *MessageService) releaseIdempotencyKey(ctx context.Context, params MessageSendParams) {
+	if params.IdempotencyKey == nil {
+		return
+	}
+	if err := service.cache.Delete(ctx, service.idempotencyCacheKey(params)); err != nil {
+		service.logger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot release idempotency key [%s] of user [%s]", *params.IdempotencyKey, params.UserID)))
+	}
+}
+
+func (service *MessageService) idempotencyCacheKey(params MessageSendParams) string {
+	return fmt.Sprintf("idempotency:%s:%s:%s", params.UserID, *params.IdempotencyKey, params.Contact)
+}
+
+// Analyze detects the encoding and counts the SMS segments of the content without sending a message.
+// The owner is optional and it is used to fetch the maximum number of segments allowed by the phone.
+func (service *MessageService) Analyze(ctx context.Context, userID entities.UserID, owner string, content string) (*entities.MessageAnalysis, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	analysis := segments.Analyze(content)
+	result := &entities.MessageAnalysis{
+		Encoding:          analysis.Encoding.String(),
+		Characters:        uint(analysis.Characters),
+		Length:            uint(analysis.Length),
+		Segments:          uint(analysis.Segments),
+		Remaining:         uint(analysis.Remaining),
+		UnicodeCharacters: analysis.UnicodeCharacters,
+	}
+
+	if owner == "" {
+		return result, nil
+	}
+
+	phone, err := service.phoneService.Load(ctx, userID, owner)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load phone for userID [%s] and owner [%s]", userID, owner)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if phone.MaxSegments > 0 {
+		result.MaxSegments = &phone.MaxSegments
+	}
+
+	return result, nil
+}
+
+// analyzeContent returns the encoding and number of segments of the content. Encrypted content can't be analyzed.
+func (service *MessageService) analyzeContent(content string, encrypted bool) (string, uint) {
+	if encrypted {
+		return "", 0
+	}
+	analysis := segments.Analyze(content)
+	return analysis.Encoding.String(), uint(analysis.Segments)
+}
+
 // MissedCallParams parameters for sending a new message
 type MissedCallParams struct {
 	Owner     *phonenumbers.PhoneNumber
@@ -531,6 +991,7 @@ func (service *MessageService) storeReceivedMessage(ctx context.Context, params
 		UserID:            params.UserID,
 		Contact:           params.Contact,
 		Content:           params.Content,
+		Attachments:       params.Attachments,
 		SIM:               params.SIM,
 		Encrypted:         params.Encrypted,
 		Type:              entities.MessageTypeMobileOriginated,
@@ -707,6 +1168,26 @@ func (service *MessageService) HandleMessageNotificationScheduled(ctx context.Co
 	return nil
 }
 
+// HandleMessageNotificationDeferred handles the event when the notification of a message has been deferred because of quiet hours
+func (service *MessageService) HandleMessageNotificationDeferred(ctx context.Context, params HandleMessageParams) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	message, err := service.repository.Load(ctx, params.UserID, params.ID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot find message with id [%s]", params.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = service.repository.Update(ctx, message.NotificationDeferred(params.Timestamp)); err != nil {
+		msg := fmt.Sprintf("cannot update message with id [%s] as deferred", message.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("message with id [%s] has been deferred to send at [%s]", message.ID, message.ScheduledSendTime.String()))
+	return nil
+}
+
 // HandleMessageNotificationSent handles the event when the notification of a message has been sent
 func (service *MessageService) HandleMessageNotificationSent(ctx context.Context, params HandleMessageParams) error {
 	ctx, span := service.tracer.Start(ctx)
@@ -917,6 +1398,9 @@ func (service *MessageService) storeSentMessage(ctx context.Context, payload eve
 		RequestID:         payload.RequestID,
 		SIM:               payload.SIM,
 		Encrypted:         payload.Encrypted,
+		Encoding:          payload.Encoding,
+		Segments:          payload.Segments,
+		Attachments:       payload.Attachments,
 		ScheduledSendTime: payload.ScheduledSendTime,
 		Type:              entities.MessageTypeMobileTerminated,
 		Status:            entities.MessageStatusPending,
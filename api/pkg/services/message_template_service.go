@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/templates"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// MessageTemplateService is responsible for handling entities.MessageTemplate
+type MessageTemplateService struct {
+	service
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	repository repositories.MessageTemplateRepository
+}
+
+// NewMessageTemplateService creates a new MessageTemplateService
+func NewMessageTemplateService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.MessageTemplateRepository,
+) (s *MessageTemplateService) {
+	return &MessageTemplateService{
+		logger:     logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:     tracer,
+		repository: repository,
+	}
+}
+
+// Index fetches the entities.MessageTemplate for an entities.UserID
+func (service *MessageTemplateService) Index(ctx context.Context, userID entities.UserID, params repositories.IndexParams) ([]*entities.MessageTemplate, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	messageTemplates, err := service.repository.Index(ctx, userID, params)
+	if err != nil {
+		msg := fmt.Sprintf("could not fetch message templates with params [%+#v]", params)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("fetched [%d] message templates with prams [%+#v]", len(messageTemplates), params))
+	return messageTemplates, nil
+}
+
+// Load an entities.MessageTemplate by ID
+func (service *MessageTemplateService) Load(ctx context.Context, userID entities.UserID, templateID uuid.UUID) (*entities.MessageTemplate, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	messageTemplate, err := service.repository.Load(ctx, userID, templateID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load message template with ID [%s] for user [%s]", templateID, userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	return messageTemplate, nil
+}
+
+// Delete an entities.MessageTemplate
+func (service *MessageTemplateService) Delete(ctx context.Context, userID entities.UserID, templateID uuid.UUID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if _, err := service.repository.Load(ctx, userID, templateID); err != nil {
+		msg := fmt.Sprintf("cannot load message template with userID [%s] and templateID [%s]", userID, templateID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if err := service.repository.Delete(ctx, userID, templateID); err != nil {
+		msg := fmt.Sprintf("cannot delete message template with id [%s] and user id [%s]", templateID, userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted message template with id [%s] and user id [%s]", templateID, userID))
+	return nil
+}
+
+// MessageTemplateStoreParams are parameters for creating a new entities.MessageTemplate
+type MessageTemplateStoreParams struct {
+	UserID  entities.UserID
+	Name    string
+	Content string
+}
+
+// Store a new entities.MessageTemplate
+func (service *MessageTemplateService) Store(ctx context.Context, params *MessageTemplateStoreParams) (*entities.MessageTemplate, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	messageTemplate := &entities.MessageTemplate{
+		ID:        uuid.New(),
+		UserID:    params.UserID,
+		Name:      params.Name,
+		Content:   params.Content,
+		Variables: templates.Placeholders(params.Content),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	if err := service.repository.Save(ctx, messageTemplate); err != nil {
+		msg := fmt.Sprintf("cannot save message template with id [%s]", messageTemplate.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("message template saved with id [%s] in the [%T]", messageTemplate.ID, service.repository))
+	return messageTemplate, nil
+}
+
+// MessageTemplateUpdateParams are parameters for updating an entities.MessageTemplate
+type MessageTemplateUpdateParams struct {
+	UserID     entities.UserID
+	Name       string
+	Content    string
+	TemplateID uuid.UUID
+}
+
+// Update an entities.MessageTemplate
+func (service *MessageTemplateService) Update(ctx context.Context, params *MessageTemplateUpdateParams) (*entities.MessageTemplate, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	messageTemplate, err := service.repository.Load(ctx, params.UserID, params.TemplateID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load message template with userID [%s] and templateID [%s]", params.UserID, params.TemplateID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	messageTemplate.Name = params.Name
+	messageTemplate.Content = params.Content
+	messageTemplate.Variables = templates.Placeholders(params.Content)
+	messageTemplate.UpdatedAt = time.Now().UTC()
+
+	if err = service.repository.Save(ctx, messageTemplate); err != nil {
+		msg := fmt.Sprintf("cannot save message template with id [%s] after update", messageTemplate.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("message template updated with id [%s] in the [%T]", messageTemplate.ID, service.repository))
+	return messageTemplate, nil
+}
+
+// Render the content of an entities.MessageTemplate with the variables
+func (service *MessageTemplateService) Render(ctx context.Context, userID entities.UserID, templateID uuid.UUID, variables map[string]string) (string, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	messageTemplate, err := service.Load(ctx, userID, templateID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load message template with ID [%s] for user [%s]", templateID, userID)
+		return "", service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	return templates.Render(messageTemplate.Content, variables), nil
+}
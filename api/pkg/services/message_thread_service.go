@@ -18,10 +18,11 @@ import (
 // MessageThreadService is handles message requests
 type MessageThreadService struct {
 	service
-	logger          telemetry.Logger
-	tracer          telemetry.Tracer
-	repository      repositories.MessageThreadRepository
-	eventDispatcher *EventDispatcher
+	logger            telemetry.Logger
+	tracer            telemetry.Tracer
+	repository        repositories.MessageThreadRepository
+	contactRepository repositories.ContactRepository
+	eventDispatcher   *EventDispatcher
 }
 
 // NewMessageThreadService creates a new MessageThreadService
@@ -29,13 +30,15 @@ func NewMessageThreadService(
 	logger telemetry.Logger,
 	tracer telemetry.Tracer,
 	repository repositories.MessageThreadRepository,
+	contactRepository repositories.ContactRepository,
 	eventDispatcher *EventDispatcher,
 ) (s *MessageThreadService) {
 	return &MessageThreadService{
-		logger:          logger.WithService(fmt.Sprintf("%T", s)),
-		tracer:          tracer,
-		eventDispatcher: eventDispatcher,
-		repository:      repository,
+		logger:            logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:            tracer,
+		eventDispatcher:   eventDispatcher,
+		repository:        repository,
+		contactRepository: contactRepository,
 	}
 }
 
@@ -116,6 +119,34 @@ func (service *MessageThreadService) UpdateStatus(ctx context.Context, params Me
 	return thread, nil
 }
 
+// MessageThreadTagParams are parameters for tagging a thread
+type MessageThreadTagParams struct {
+	UserID  entities.UserID
+	Owner   string
+	Contact string
+	Tag     string
+}
+
+// AddTag adds a tag to the thread between the owner and the contact
+func (service *MessageThreadService) AddTag(ctx context.Context, params MessageThreadTagParams) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	thread, err := service.repository.LoadByOwnerContact(ctx, params.UserID, params.Owner, params.Contact)
+	if err != nil {
+		msg := fmt.Sprintf("cannot find thread between owner [%s] and contact [%s] for user [%s]", params.Owner, params.Contact, params.UserID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if err = service.repository.Update(ctx, thread.AddTag(params.Tag)); err != nil {
+		msg := fmt.Sprintf("cannot update message thread with id [%s] with tag [%s]", thread.ID, params.Tag)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("thread with id [%s] tagged with [%s]", thread.ID, params.Tag))
+	return nil
+}
+
 // UpdateAfterDeletedMessage updates a thread after the last message has been deleted
 func (service *MessageThreadService) UpdateAfterDeletedMessage(ctx context.Context, userID entities.UserID, messageID uuid.UUID) error {
 	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
@@ -213,6 +244,7 @@ func (service *MessageThreadService) GetThreads(ctx context.Context, params Mess
 	}
 
 	ctxLogger.Info(fmt.Sprintf("fetched [%d] threads with params [%+#v]", len(*threads), params))
+	service.resolveContactNames(ctx, params.UserID, *threads)
 	return threads, nil
 }
 
@@ -227,9 +259,50 @@ func (service *MessageThreadService) GetThread(ctx context.Context, userID entit
 		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
 	}
 
+	threads := []entities.MessageThread{*thread}
+	service.resolveContactNames(ctx, userID, threads)
+	thread.ContactName = threads[0].ContactName
+
 	return thread, nil
 }
 
+// resolveContactNames sets the name of the entities.Contact for each thread which is in the address book
+func (service *MessageThreadService) resolveContactNames(ctx context.Context, userID entities.UserID, threads []entities.MessageThread) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if len(threads) == 0 {
+		return
+	}
+
+	phoneNumbers := make([]string, 0, len(threads))
+	for _, thread := range threads {
+		phoneNumbers = append(phoneNumbers, thread.Contact)
+	}
+
+	contacts, err := service.contactRepository.FetchByPhoneNumbers(ctx, userID, phoneNumbers)
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch contacts for [%d] threads of user [%s]", len(threads), userID)
+		ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		return
+	}
+
+	names := map[string]string{}
+	for _, contact := range contacts {
+		for _, phoneNumber := range contact.PhoneNumbers {
+			if _, ok := names[phoneNumber]; !ok {
+				names[phoneNumber] = contact.Name
+			}
+		}
+	}
+
+	for index := range threads {
+		if name, ok := names[threads[index].Contact]; ok {
+			threads[index].ContactName = &name
+		}
+	}
+}
+
 // DeleteThread deletes an entities.MessageThread from the database
 func (service *MessageThreadService) DeleteThread(ctx context.Context, source string, thread *entities.MessageThread) error {
 	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
@@ -26,6 +26,8 @@ type PhoneNotificationService struct {
 	phoneRepository             repositories.PhoneRepository
 	messagingClient             *messaging.Client
 	eventDispatcher             *EventDispatcher
+	userRepository              repositories.UserRepository
+	messageRepository           repositories.MessageRepository
 }
 
 // NewNotificationService creates a new PhoneNotificationService
@@ -36,6 +38,8 @@ func NewNotificationService(
 	phoneRepository repositories.PhoneRepository,
 	phoneNotificationRepository repositories.PhoneNotificationRepository,
 	dispatcher *EventDispatcher,
+	userRepository repositories.UserRepository,
+	messageRepository repositories.MessageRepository,
 ) (s *PhoneNotificationService) {
 	return &PhoneNotificationService{
 		logger:                      logger.WithService(fmt.Sprintf("%T", s)),
@@ -44,6 +48,8 @@ func NewNotificationService(
 		phoneNotificationRepository: phoneNotificationRepository,
 		phoneRepository:             phoneRepository,
 		eventDispatcher:             dispatcher,
+		userRepository:              userRepository,
+		messageRepository:           messageRepository,
 	}
 }
 
@@ -97,6 +103,17 @@ func (service *PhoneNotificationService) Send(ctx context.Context, params *Phone
 	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
 	defer span.End()
 
+	stale, err := service.isStale(ctx, params)
+	if err != nil {
+		msg := fmt.Sprintf("cannot check if notification [%s] for message [%s] is stale", params.PhoneNotificationID, params.MessageID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if stale {
+		ctxLogger.Info(fmt.Sprintf("skipping stale notification [%s] for message [%s]", params.PhoneNotificationID, params.MessageID))
+		return nil
+	}
+
 	phone, err := service.phoneRepository.LoadByID(ctx, params.UserID, params.PhoneID)
 	if err != nil {
 		msg := fmt.Sprintf("cannot load phone with userID [%s] and phoneID [%s]", params.UserID, params.PhoneID)
@@ -138,6 +155,9 @@ type PhoneNotificationScheduleParams struct {
 	Content   string
 	SIM       entities.SIM
 	MessageID uuid.UUID
+
+	// ScheduledSendTime is the send time of the message when the notification was requested
+	ScheduledSendTime *time.Time
 }
 
 // Schedule a notification to be sent to a phone
@@ -147,6 +167,22 @@ func (service *PhoneNotificationService) Schedule(ctx context.Context, params *P
 
 	ctxLogger := service.tracer.CtxLogger(service.logger, span)
 
+	message, err := service.messageRepository.Load(ctx, params.UserID, params.MessageID)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		ctxLogger.Info(fmt.Sprintf("message with ID [%s] has been deleted, skipping notification", params.MessageID))
+		return nil
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load message with userID [%s] and ID [%s]", params.UserID, params.MessageID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if message.IsCancelled() || (params.ScheduledSendTime != nil && !service.isSameSendTime(message.ScheduledSendTime, *params.ScheduledSendTime)) {
+		ctxLogger.Info(fmt.Sprintf("message with ID [%s] has been cancelled or rescheduled to [%v], skipping notification", message.ID, message.ScheduledSendTime))
+		return nil
+	}
+
 	phone, err := service.phoneRepository.Load(ctx, params.UserID, params.Owner)
 	if err != nil {
 		msg := fmt.Sprintf("cannot load phone with userID [%s] and phone [%s]", params.UserID, params.Owner)
@@ -163,13 +199,29 @@ func (service *PhoneNotificationService) Schedule(ctx context.Context, params *P
 		CreatedAt:   time.Now().UTC(),
 		UpdatedAt:   time.Now().UTC(),
 	}
+	if params.ScheduledSendTime != nil {
+		notification.ScheduledAt = params.ScheduledSendTime.UTC()
+	}
 
-	if err = service.phoneNotificationRepository.Schedule(ctx, phone.MessagesPerMinute, notification); err != nil {
+	deferral, originalScheduledAt, err := service.quietHoursDeferral(ctx, params.UserID, phone)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load the quiet hours of phone [%s] for message [%s]", phone.ID, params.MessageID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = service.phoneNotificationRepository.Schedule(ctx, phone.MessagesPerMinute, deferral, notification); err != nil {
 		msg := fmt.Sprintf("cannot schedule notification for message [%s] to phone [%s]", params.MessageID, phone.ID)
 		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
 
-	if err = service.dispatchMessageNotificationScheduled(ctx, params, notification); err != nil {
+	deferred := originalScheduledAt != nil && !originalScheduledAt.Equal(notification.ScheduledAt)
+	if deferred {
+		if err = service.dispatchMessageSendDeferred(ctx, params, phone, notification, *originalScheduledAt); err != nil {
+			ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot dispatch quiet hours event for notification [%s] of message [%s]", notification.ID, params.MessageID)))
+		}
+	}
+
+	if err = service.dispatchMessageNotificationScheduled(ctx, params, notification, deferred); err != nil {
 		ctxLogger.Error(err)
 	}
 
@@ -181,6 +233,152 @@ func (service *PhoneNotificationService) Schedule(ctx context.Context, params *P
 	return nil
 }
 
+// Reschedule cancels the pending notifications of a message and schedules a new notification at the new send time
+func (service *PhoneNotificationService) Reschedule(ctx context.Context, params *PhoneNotificationScheduleParams) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	message, err := service.messageRepository.Load(ctx, params.UserID, params.MessageID)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		ctxLogger.Info(fmt.Sprintf("message with ID [%s] has been deleted, skipping reschedule", params.MessageID))
+		return nil
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load message with userID [%s] and ID [%s]", params.UserID, params.MessageID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	// a later reschedule supersedes this one so the notifications it created must be left alone
+	if params.ScheduledSendTime != nil && !service.isSameSendTime(message.ScheduledSendTime, *params.ScheduledSendTime) {
+		ctxLogger.Info(fmt.Sprintf("message with ID [%s] has been rescheduled to [%v], skipping reschedule to [%s]", message.ID, message.ScheduledSendTime, params.ScheduledSendTime))
+		return nil
+	}
+
+	if err = service.Cancel(ctx, params.MessageID); err != nil {
+		msg := fmt.Sprintf("cannot cancel notifications before rescheduling message [%s]", params.MessageID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = service.Schedule(ctx, params); err != nil {
+		msg := fmt.Sprintf("cannot reschedule notification for message [%s]", params.MessageID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// Cancel the pending notifications of a message
+func (service *PhoneNotificationService) Cancel(ctx context.Context, messageID uuid.UUID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.phoneNotificationRepository.CancelPending(ctx, messageID); err != nil {
+		msg := fmt.Sprintf("cannot cancel pending notifications of message [%s]", messageID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("cancelled pending notifications of message [%s]", messageID))
+	return nil
+}
+
+// isStale checks if a notification was already handled or if the message was deleted, cancelled or rescheduled to a later time
+func (service *PhoneNotificationService) isStale(ctx context.Context, params *PhoneNotificationSendParams) (bool, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	notification, err := service.phoneNotificationRepository.Load(ctx, params.PhoneNotificationID)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return true, nil
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load notification with ID [%s]", params.PhoneNotificationID)
+		return false, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if !notification.IsPending() {
+		ctxLogger.Info(fmt.Sprintf("notification [%s] has already been handled with status [%s]", notification.ID, notification.Status))
+		return true, nil
+	}
+
+	message, err := service.messageRepository.Load(ctx, params.UserID, params.MessageID)
+	if err != nil && stacktrace.GetCode(err) != repositories.ErrCodeNotFound {
+		msg := fmt.Sprintf("cannot load message with userID [%s] and ID [%s]", params.UserID, params.MessageID)
+		return false, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err != nil || message.IsCancelled() || (message.ScheduledSendTime != nil && message.ScheduledSendTime.After(notification.ScheduledAt.Add(time.Minute))) {
+		service.updateStatus(ctx, notification.ID, entities.PhoneNotificationStatusCancelled)
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// isSameSendTime compares send times ignoring the precision lost when the time is stored in the database
+func (service *PhoneNotificationService) isSameSendTime(current *time.Time, requested time.Time) bool {
+	if current == nil {
+		return false
+	}
+	return current.Sub(requested).Abs() < time.Second
+}
+
+// quietHoursDeferral returns the deferral which moves a notification to the end of the quiet hours of the phone.
+// The time before the deferral is stored in originalScheduledAt every time the deferral is applied.
+func (service *PhoneNotificationService) quietHoursDeferral(ctx context.Context, userID entities.UserID, phone *entities.Phone) (deferral func(time.Time) *time.Time, originalScheduledAt *time.Time, err error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	if !phone.HasQuietHours() {
+		return nil, nil, nil
+	}
+
+	user, err := service.userRepository.Load(ctx, userID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load user with ID [%s] to apply quiet hours of phone [%s]", userID, phone.ID)
+		return nil, nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	location := user.Location()
+	originalScheduledAt = new(time.Time)
+	return func(scheduledAt time.Time) *time.Time {
+		*originalScheduledAt = scheduledAt
+		return phone.QuietHoursDeferral(scheduledAt.In(location))
+	}, originalScheduledAt, nil
+}
+
+func (service *PhoneNotificationService) dispatchMessageSendDeferred(ctx context.Context, params *PhoneNotificationScheduleParams, phone *entities.Phone, notification *entities.PhoneNotification, originalScheduledAt time.Time) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	event, err := service.createEvent(events.EventTypeMessageSendDeferred, params.Source, &events.MessageSendDeferredPayload{
+		MessageID:           params.MessageID,
+		UserID:              params.UserID,
+		PhoneID:             phone.ID,
+		Owner:               params.Owner,
+		Contact:             params.Contact,
+		NotificationID:      notification.ID,
+		QuietHoursStart:     *phone.QuietHoursStart,
+		QuietHoursEnd:       *phone.QuietHoursEnd,
+		OriginalScheduledAt: originalScheduledAt,
+		ScheduledAt:         notification.ScheduledAt,
+		Timestamp:           time.Now().UTC(),
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot create [%s] event for notification [%s]", events.EventTypeMessageSendDeferred, notification.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = service.eventDispatcher.Dispatch(ctx, event); err != nil {
+		msg := fmt.Sprintf("cannot dispatch event [%s] for notification [%s]", event.Type(), notification.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deferred notification [%s] for message [%s] from [%s] to [%s] because of quiet hours", notification.ID, params.MessageID, originalScheduledAt, notification.ScheduledAt))
+	return nil
+}
+
 func (service *PhoneNotificationService) dispatchMessageNotificationSend(ctx context.Context, source string, notification *entities.PhoneNotification) error {
 	event, err := service.createMessageNotificationSendEvent(source, &events.MessageNotificationSendPayload{
 		MessageID:      notification.MessageID,
@@ -199,7 +397,7 @@ func (service *PhoneNotificationService) dispatchMessageNotificationSend(ctx con
 	return nil
 }
 
-func (service *PhoneNotificationService) dispatchMessageNotificationScheduled(ctx context.Context, params *PhoneNotificationScheduleParams, notification *entities.PhoneNotification) error {
+func (service *PhoneNotificationService) dispatchMessageNotificationScheduled(ctx context.Context, params *PhoneNotificationScheduleParams, notification *entities.PhoneNotification, deferred bool) error {
 	event, err := service.createMessageNotificationScheduledEvent(params.Source, &events.MessageNotificationScheduledPayload{
 		MessageID:      notification.MessageID,
 		Owner:          params.Owner,
@@ -210,6 +408,7 @@ func (service *PhoneNotificationService) dispatchMessageNotificationScheduled(ct
 		UserID:         notification.UserID,
 		PhoneID:        notification.PhoneID,
 		ScheduledAt:    notification.ScheduledAt,
+		Deferred:       deferred,
 		NotificationID: notification.ID,
 	})
 	if err != nil {
@@ -73,9 +73,17 @@ type PhoneUpsertParams struct {
 	FcmToken                  *string
 	MessagesPerMinute         *uint
 	MaxSendAttempts           *uint
+	MaxSegments               *uint
 	WebhookURL                *string
 	MessageExpirationDuration *time.Duration
 	MissedCallAutoReply       *string
+	OptOutKeywords            *[]string
+	OptInKeywords             *[]string
+	BusinessHours             *[]entities.BusinessHours
+	Holidays                  *[]string
+	OutOfOfficeReply          *string
+	QuietHoursStart           *string
+	QuietHoursEnd             *string
 	SIM                       entities.SIM
 	Source                    string
 	UserID                    entities.UserID
@@ -219,6 +227,10 @@ func (service *PhoneService) update(phone *entities.Phone, params *PhoneUpsertPa
 		phone.MaxSendAttempts = *params.MaxSendAttempts
 	}
 
+	if params.MaxSegments != nil {
+		phone.MaxSegments = *params.MaxSegments
+	}
+
 	if params.MessageExpirationDuration != nil {
 		phone.MessageExpirationSeconds = uint(params.MessageExpirationDuration.Seconds())
 	}
@@ -227,6 +239,31 @@ func (service *PhoneService) update(phone *entities.Phone, params *PhoneUpsertPa
 		phone.MissedCallAutoReply = params.MissedCallAutoReply
 	}
 
+	if params.OptOutKeywords != nil {
+		phone.OptOutKeywords = *params.OptOutKeywords
+	}
+
+	if params.OptInKeywords != nil {
+		phone.OptInKeywords = *params.OptInKeywords
+	}
+
+	if params.BusinessHours != nil {
+		phone.BusinessHours = *params.BusinessHours
+	}
+
+	if params.Holidays != nil {
+		phone.Holidays = *params.Holidays
+	}
+
+	if params.OutOfOfficeReply != nil {
+		phone.OutOfOfficeReply = params.OutOfOfficeReply
+	}
+
+	if params.QuietHoursStart != nil && params.QuietHoursEnd != nil {
+		phone.QuietHoursStart = params.QuietHoursStart
+		phone.QuietHoursEnd = params.QuietHoursEnd
+	}
+
 	phone.SIM = params.SIM
 
 	return phone
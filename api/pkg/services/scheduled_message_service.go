@@ -0,0 +1,366 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/nyaruka/phonenumbers"
+	"github.com/palantir/stacktrace"
+)
+
+// scheduledMessageMaxDelay is the longest delay of a queued event, occurrences further away are re-queued until they are due
+const scheduledMessageMaxDelay = 7 * 24 * time.Hour
+
+// ScheduledMessageService is responsible for handling entities.ScheduledMessage
+type ScheduledMessageService struct {
+	service
+	logger             telemetry.Logger
+	tracer             telemetry.Tracer
+	repository         repositories.ScheduledMessageRepository
+	userRepository     repositories.UserRepository
+	messageService     *MessageService
+	billingService     *BillingService
+	suppressionService *SuppressionService
+	eventDispatcher    *EventDispatcher
+}
+
+// NewScheduledMessageService creates a new ScheduledMessageService
+func NewScheduledMessageService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.ScheduledMessageRepository,
+	userRepository repositories.UserRepository,
+	messageService *MessageService,
+	billingService *BillingService,
+	suppressionService *SuppressionService,
+	eventDispatcher *EventDispatcher,
+) (s *ScheduledMessageService) {
+	return &ScheduledMessageService{
+		logger:             logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:             tracer,
+		repository:         repository,
+		userRepository:     userRepository,
+		messageService:     messageService,
+		billingService:     billingService,
+		suppressionService: suppressionService,
+		eventDispatcher:    eventDispatcher,
+	}
+}
+
+// Index fetches the entities.ScheduledMessage for an entities.UserID
+func (service *ScheduledMessageService) Index(ctx context.Context, userID entities.UserID, owner string, params repositories.IndexParams) ([]*entities.ScheduledMessage, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	schedules, err := service.repository.Index(ctx, userID, owner, params)
+	if err != nil {
+		msg := fmt.Sprintf("could not fetch scheduled messages with params [%+#v]", params)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("fetched [%d] scheduled messages with prams [%+#v]", len(schedules), params))
+	return schedules, nil
+}
+
+// Load an entities.ScheduledMessage by ID
+func (service *ScheduledMessageService) Load(ctx context.Context, userID entities.UserID, scheduledMessageID uuid.UUID) (*entities.ScheduledMessage, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	schedule, err := service.repository.Load(ctx, userID, scheduledMessageID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load scheduled message with ID [%s] for user [%s]", scheduledMessageID, userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	return schedule, nil
+}
+
+// Delete an entities.ScheduledMessage, pending occurrences are ignored when they are due
+func (service *ScheduledMessageService) Delete(ctx context.Context, userID entities.UserID, scheduledMessageID uuid.UUID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if _, err := service.repository.Load(ctx, userID, scheduledMessageID); err != nil {
+		msg := fmt.Sprintf("cannot load scheduled message with userID [%s] and scheduledMessageID [%s]", userID, scheduledMessageID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if err := service.repository.Delete(ctx, userID, scheduledMessageID); err != nil {
+		msg := fmt.Sprintf("cannot delete scheduled message with id [%s] and user id [%s]", scheduledMessageID, userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted scheduled message with id [%s] and user id [%s]", scheduledMessageID, userID))
+	return nil
+}
+
+// ScheduledMessageStoreParams are parameters for creating a new entities.ScheduledMessage
+type ScheduledMessageStoreParams struct {
+	Source     string
+	UserID     entities.UserID
+	Owner      string
+	Contact    string
+	Content    string
+	Format     entities.ScheduledMessageFormat
+	Expression string
+}
+
+// Store a new entities.ScheduledMessage and queue its first occurrence
+func (service *ScheduledMessageService) Store(ctx context.Context, params *ScheduledMessageStoreParams) (*entities.ScheduledMessage, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	schedule := &entities.ScheduledMessage{
+		ID:         uuid.New(),
+		UserID:     params.UserID,
+		Owner:      params.Owner,
+		Contact:    params.Contact,
+		Content:    params.Content,
+		Format:     params.Format,
+		Expression: params.Expression,
+		Status:     entities.ScheduledMessageStatusActive,
+		MessageIDs: []string{},
+		CreatedAt:  time.Now().UTC(),
+		UpdatedAt:  time.Now().UTC(),
+	}
+
+	next, err := service.nextOccurrence(ctx, schedule, time.Now().UTC())
+	if err != nil {
+		msg := fmt.Sprintf("cannot compute the first occurrence of scheduled message [%s]", schedule.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = service.repository.Save(ctx, schedule.Reschedule(next)); err != nil {
+		msg := fmt.Sprintf("cannot save scheduled message with id [%s]", schedule.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = service.dispatchDue(ctx, params.Source, schedule, schedule.NextRunAt); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot queue scheduled message [%s]", schedule.ID)))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("scheduled message saved with id [%s] in the [%T]", schedule.ID, service.repository))
+	return schedule, nil
+}
+
+// Pause an entities.ScheduledMessage
+func (service *ScheduledMessageService) Pause(ctx context.Context, userID entities.UserID, scheduledMessageID uuid.UUID) (*entities.ScheduledMessage, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	schedule, err := service.repository.Load(ctx, userID, scheduledMessageID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load scheduled message with userID [%s] and scheduledMessageID [%s]", userID, scheduledMessageID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if err = service.repository.Save(ctx, schedule.Pause()); err != nil {
+		msg := fmt.Sprintf("cannot save scheduled message with id [%s] after pausing", schedule.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("paused scheduled message with id [%s] for user [%s]", schedule.ID, userID))
+	return schedule, nil
+}
+
+// Resume a paused entities.ScheduledMessage from its next occurrence
+func (service *ScheduledMessageService) Resume(ctx context.Context, source string, userID entities.UserID, scheduledMessageID uuid.UUID) (*entities.ScheduledMessage, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	schedule, err := service.repository.Load(ctx, userID, scheduledMessageID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load scheduled message with userID [%s] and scheduledMessageID [%s]", userID, scheduledMessageID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if schedule.IsActive() {
+		ctxLogger.Info(fmt.Sprintf("scheduled message with id [%s] is already active", schedule.ID))
+		return schedule, nil
+	}
+
+	next, err := service.nextOccurrence(ctx, schedule, time.Now().UTC())
+	if err != nil {
+		msg := fmt.Sprintf("cannot compute the next occurrence of scheduled message [%s]", schedule.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = service.repository.Save(ctx, schedule.Resume(next)); err != nil {
+		msg := fmt.Sprintf("cannot save scheduled message with id [%s] after resuming", schedule.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = service.dispatchDue(ctx, source, schedule, schedule.NextRunAt); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot queue scheduled message [%s]", schedule.ID)))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("resumed scheduled message with id [%s] for user [%s]", schedule.ID, userID))
+	return schedule, nil
+}
+
+// HandleDue sends the occurrence of an entities.ScheduledMessage and queues the next occurrence
+func (service *ScheduledMessageService) HandleDue(ctx context.Context, source string, payload *events.ScheduledMessageDuePayload) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	schedule, err := service.repository.Load(ctx, payload.UserID, payload.ScheduledMessageID)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		ctxLogger.Info(fmt.Sprintf("scheduled message [%s] for user [%s] has been deleted", payload.ScheduledMessageID, payload.UserID))
+		return nil
+	}
+	if err != nil {
+		msg := fmt.Sprintf("cannot load scheduled message with userID [%s] and scheduledMessageID [%s]", payload.UserID, payload.ScheduledMessageID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	// the schedule was paused, resumed or has already sent this occurrence
+	if !schedule.IsDue(payload.OccurrenceAt) {
+		ctxLogger.Info(fmt.Sprintf("skipping stale occurrence [%s] of scheduled message [%s] with status [%s]", payload.OccurrenceAt, schedule.ID, schedule.Status))
+		return nil
+	}
+
+	if time.Until(payload.OccurrenceAt) > time.Minute {
+		return service.dispatchDue(ctx, source, schedule, schedule.NextRunAt)
+	}
+
+	next, err := service.nextOccurrence(ctx, schedule, time.Now().UTC())
+	if err != nil {
+		msg := fmt.Sprintf("cannot compute the next occurrence of scheduled message [%s]", schedule.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	// the next occurrence is queued first so that a failure below doesn't stop the schedule.
+	// A retried event queues it again and the duplicate is skipped as a stale occurrence.
+	if err = service.dispatchDue(ctx, source, schedule, next); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot queue the next occurrence of scheduled message [%s]", schedule.ID)))
+	}
+
+	if reason := service.skipReason(ctx, schedule); reason != "" {
+		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("skipping occurrence [%s] of scheduled message [%s] because %s", payload.OccurrenceAt, schedule.ID, reason)))
+		schedule.Reschedule(next)
+	} else {
+		message, err := service.send(ctx, source, schedule, payload.OccurrenceAt)
+		if err != nil {
+			msg := fmt.Sprintf("cannot send occurrence [%s] of scheduled message [%s]", payload.OccurrenceAt, schedule.ID)
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+		schedule.Occurred(message.ID, payload.OccurrenceAt, next)
+		ctxLogger.Info(fmt.Sprintf("sent message [%s] for occurrence [%s] of scheduled message [%s]", message.ID, payload.OccurrenceAt, schedule.ID))
+	}
+
+	if err = service.repository.Save(ctx, schedule); err != nil {
+		msg := fmt.Sprintf("cannot save scheduled message with id [%s] after occurrence [%s]", schedule.ID, payload.OccurrenceAt)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// skipReason returns why an occurrence cannot be sent or an empty string when it can be sent
+func (service *ScheduledMessageService) skipReason(ctx context.Context, schedule *entities.ScheduledMessage) string {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if msg := service.billingService.IsEntitled(ctx, schedule.UserID); msg != nil {
+		return *msg
+	}
+
+	// the occurrence is not sent when the opt-out cannot be checked
+	suppressed, err := service.suppressionService.IsSuppressed(ctx, schedule.UserID, schedule.Contact)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot check if contact [%s] of scheduled message [%s] is suppressed", schedule.Contact, schedule.ID)))
+		return fmt.Sprintf("the opt-out status of the contact [%s] could not be checked", schedule.Contact)
+	}
+
+	if suppressed {
+		return fmt.Sprintf("the contact [%s] opted out of receiving messages", schedule.Contact)
+	}
+	return ""
+}
+
+func (service *ScheduledMessageService) send(ctx context.Context, source string, schedule *entities.ScheduledMessage, occurrence time.Time) (*entities.Message, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	// the request ID is the idempotency key so that a retried occurrence doesn't send the message twice
+	requestID := fmt.Sprintf("scheduled-%s-%d", schedule.ID, occurrence.Unix())
+	owner, _ := phonenumbers.Parse(schedule.Owner, phonenumbers.UNKNOWN_REGION)
+	message, err := service.messageService.SendMessage(ctx, MessageSendParams{
+		Owner:             owner,
+		Contact:           schedule.Contact,
+		Content:           schedule.Content,
+		Source:            source,
+		RequestID:         &requestID,
+		UserID:            schedule.UserID,
+		RequestReceivedAt: time.Now().UTC(),
+		IdempotencyKey:    &requestID,
+		SenderPoolID:      SenderPoolID(schedule.Owner),
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot send scheduled message from [%s] to [%s] for user [%s]", schedule.Owner, schedule.Contact, schedule.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return message, nil
+}
+
+// nextOccurrence evaluates the recurrence expression in the timezone of the user
+func (service *ScheduledMessageService) nextOccurrence(ctx context.Context, schedule *entities.ScheduledMessage, after time.Time) (*time.Time, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	user, err := service.userRepository.Load(ctx, schedule.UserID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load user with ID [%s] for scheduled message [%s]", schedule.UserID, schedule.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	next, err := schedule.NextOccurrence(after.In(user.Location()))
+	if err != nil {
+		msg := fmt.Sprintf("cannot evaluate [%s] expression [%s] of scheduled message [%s]", schedule.Format, schedule.Expression, schedule.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return next, nil
+}
+
+// dispatchDue queues an occurrence of an active entities.ScheduledMessage
+func (service *ScheduledMessageService) dispatchDue(ctx context.Context, source string, schedule *entities.ScheduledMessage, occurrence *time.Time) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if !schedule.IsActive() || occurrence == nil {
+		ctxLogger.Info(fmt.Sprintf("scheduled message [%s] has no next occurrence with status [%s]", schedule.ID, schedule.Status))
+		return nil
+	}
+
+	event, err := service.createEvent(events.EventTypeScheduledMessageDue, source, &events.ScheduledMessageDuePayload{
+		ScheduledMessageID: schedule.ID,
+		UserID:             schedule.UserID,
+		OccurrenceAt:       *occurrence,
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot create [%s] event for scheduled message [%s]", events.EventTypeScheduledMessageDue, schedule.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	delay := time.Until(*occurrence)
+	if delay > scheduledMessageMaxDelay {
+		delay = scheduledMessageMaxDelay
+	}
+
+	if _, err = service.eventDispatcher.DispatchWithTimeout(ctx, event, delay); err != nil {
+		msg := fmt.Sprintf("cannot dispatch [%s] event for scheduled message [%s]", event.Type(), schedule.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("queued occurrence [%s] of scheduled message [%s] after [%s]", occurrence, schedule.ID, delay))
+	return nil
+}
@@ -0,0 +1,447 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/cache"
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	// senderPoolLockTTL is the longest time a route can hold the lock of an entities.SenderPool
+	senderPoolLockTTL = 10 * time.Second
+
+	// senderPoolLockWaitAttempts is the number of times a route tries to acquire the lock of an entities.SenderPool
+	senderPoolLockWaitAttempts = 100
+
+	// senderPoolLockWaitInterval is the time between the attempts to acquire the lock of an entities.SenderPool
+	senderPoolLockWaitInterval = 50 * time.Millisecond
+
+	// senderPoolReservationTTL is how long a routed message counts towards the load of a phone while its notification is being scheduled
+	senderPoolReservationTTL = 30 * time.Second
+)
+
+// SenderPoolID returns the ID of the entities.SenderPool when the sender of a message is a pool instead of a phone number
+func SenderPoolID(from string) *uuid.UUID {
+	poolID, err := uuid.Parse(from)
+	if err != nil {
+		return nil
+	}
+	return &poolID
+}
+
+// SenderPoolService is responsible for handling entities.SenderPool and choosing the phone which sends a message of a pool
+type SenderPoolService struct {
+	service
+	logger                 telemetry.Logger
+	tracer                 telemetry.Tracer
+	repository             repositories.SenderPoolRepository
+	phoneRepository        repositories.PhoneRepository
+	monitorRepository      repositories.HeartbeatMonitorRepository
+	threadRepository       repositories.MessageThreadRepository
+	notificationRepository repositories.PhoneNotificationRepository
+	cache                  cache.Cache
+}
+
+// NewSenderPoolService creates a new SenderPoolService
+func NewSenderPoolService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.SenderPoolRepository,
+	phoneRepository repositories.PhoneRepository,
+	monitorRepository repositories.HeartbeatMonitorRepository,
+	threadRepository repositories.MessageThreadRepository,
+	notificationRepository repositories.PhoneNotificationRepository,
+	cache cache.Cache,
+) (s *SenderPoolService) {
+	return &SenderPoolService{
+		logger:                 logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:                 tracer,
+		repository:             repository,
+		phoneRepository:        phoneRepository,
+		monitorRepository:      monitorRepository,
+		threadRepository:       threadRepository,
+		notificationRepository: notificationRepository,
+		cache:                  cache,
+	}
+}
+
+// Index fetches the entities.SenderPool for an entities.UserID
+func (service *SenderPoolService) Index(ctx context.Context, userID entities.UserID, params repositories.IndexParams) ([]*entities.SenderPool, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	pools, err := service.repository.Index(ctx, userID, params)
+	if err != nil {
+		msg := fmt.Sprintf("could not fetch sender pools with params [%+#v]", params)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("fetched [%d] sender pools with prams [%+#v]", len(pools), params))
+	return pools, nil
+}
+
+// Load an entities.SenderPool by ID
+func (service *SenderPoolService) Load(ctx context.Context, userID entities.UserID, poolID uuid.UUID) (*entities.SenderPool, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	pool, err := service.repository.Load(ctx, userID, poolID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load sender pool with ID [%s] for user [%s]", poolID, userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	return pool, nil
+}
+
+// Delete an entities.SenderPool
+func (service *SenderPoolService) Delete(ctx context.Context, userID entities.UserID, poolID uuid.UUID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if _, err := service.repository.Load(ctx, userID, poolID); err != nil {
+		msg := fmt.Sprintf("cannot load sender pool with userID [%s] and poolID [%s]", userID, poolID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if err := service.repository.Delete(ctx, userID, poolID); err != nil {
+		msg := fmt.Sprintf("cannot delete sender pool with id [%s] and user id [%s]", poolID, userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted sender pool with id [%s] and user id [%s]", poolID, userID))
+	return nil
+}
+
+// SenderPoolStoreParams are parameters for creating a new entities.SenderPool
+type SenderPoolStoreParams struct {
+	UserID       entities.UserID
+	Name         string
+	PhoneNumbers []string
+}
+
+// Store a new entities.SenderPool
+func (service *SenderPoolService) Store(ctx context.Context, params *SenderPoolStoreParams) (*entities.SenderPool, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	pool := &entities.SenderPool{
+		ID:           uuid.New(),
+		UserID:       params.UserID,
+		Name:         params.Name,
+		PhoneNumbers: pq.StringArray(params.PhoneNumbers),
+		CreatedAt:    time.Now().UTC(),
+		UpdatedAt:    time.Now().UTC(),
+	}
+
+	if err := service.repository.Save(ctx, pool); err != nil {
+		msg := fmt.Sprintf("cannot save sender pool with id [%s]", pool.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("sender pool saved with id [%s] and [%d] phones in the [%T]", pool.ID, pool.Size(), service.repository))
+	return pool, nil
+}
+
+// SenderPoolUpdateParams are parameters for updating an entities.SenderPool
+type SenderPoolUpdateParams struct {
+	SenderPoolStoreParams
+	PoolID uuid.UUID
+}
+
+// Update an entities.SenderPool
+func (service *SenderPoolService) Update(ctx context.Context, params *SenderPoolUpdateParams) (*entities.SenderPool, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	pool, err := service.repository.Load(ctx, params.UserID, params.PoolID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load sender pool with userID [%s] and poolID [%s]", params.UserID, params.PoolID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	pool.Name = params.Name
+	pool.PhoneNumbers = params.PhoneNumbers
+	pool.UpdatedAt = time.Now().UTC()
+
+	if err = service.repository.Save(ctx, pool); err != nil {
+		msg := fmt.Sprintf("cannot save sender pool with id [%s] after update", pool.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("sender pool updated with id [%s] in the [%T]", pool.ID, service.repository))
+	return pool, nil
+}
+
+// Phones loads the registered phones of an entities.SenderPool in the order of the pool, phones which were deleted are ignored
+func (service *SenderPoolService) Phones(ctx context.Context, userID entities.UserID, poolID uuid.UUID) ([]*entities.Phone, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	pool, err := service.repository.Load(ctx, userID, poolID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load sender pool with ID [%s] for user [%s]", poolID, userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	phones := make([]*entities.Phone, 0, pool.Size())
+	for _, phoneNumber := range pool.PhoneNumbers {
+		phone, err := service.phoneRepository.Load(ctx, userID, phoneNumber)
+		if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+			continue
+		}
+		if err != nil {
+			msg := fmt.Sprintf("cannot load phone [%s] of sender pool [%s]", phoneNumber, pool.ID)
+			return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+		phones = append(phones, phone)
+	}
+
+	return phones, nil
+}
+
+// SenderPoolRouteParams are parameters for choosing the phone which sends a message of an entities.SenderPool
+type SenderPoolRouteParams struct {
+	UserID  entities.UserID
+	PoolID  uuid.UUID
+	Contact string
+}
+
+// Route chooses the phone of an entities.SenderPool which sends a message to the contact.
+// The phone which last talked to the contact is used while it is online so that the contact keeps talking to the same number,
+// otherwise the online phone which needs the least time to send its pending notifications is used.
+// The routes of a pool are done one at a time so that concurrent messages are spread over the phones of the pool.
+func (service *SenderPoolService) Route(ctx context.Context, params *SenderPoolRouteParams) (*entities.Phone, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	unlock, err := service.lock(ctx, params.PoolID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot lock sender pool [%s] for user [%s]", params.PoolID, params.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+	defer unlock()
+
+	phone, err := service.route(ctx, params)
+	if err != nil {
+		msg := fmt.Sprintf("cannot route message of sender pool [%s] to [%s]", params.PoolID, params.Contact)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if err = service.reserve(ctx, params.PoolID, phone); err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot reserve phone [%s] of sender pool [%s]", phone.PhoneNumber, params.PoolID)))
+	}
+
+	return phone, nil
+}
+
+func (service *SenderPoolService) route(ctx context.Context, params *SenderPoolRouteParams) (*entities.Phone, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	phones, err := service.Phones(ctx, params.UserID, params.PoolID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load the phones of sender pool [%s]", params.PoolID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if len(phones) == 0 {
+		msg := fmt.Sprintf("sender pool [%s] for user [%s] has no registered phones", params.PoolID, params.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(repositories.ErrCodeNotFound, msg))
+	}
+
+	candidates, err := service.onlinePhones(ctx, params.UserID, phones)
+	if err != nil {
+		msg := fmt.Sprintf("cannot check which phones of sender pool [%s] are online", params.PoolID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if len(candidates) == 0 {
+		// the message is queued on a phone anyway so it is sent once the phone is back online
+		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("all the [%d] phones of sender pool [%s] are offline", len(phones), params.PoolID)))
+		candidates = phones
+	}
+
+	phone, err := service.stickyPhone(ctx, params, candidates)
+	if err != nil {
+		msg := fmt.Sprintf("cannot find the phone of sender pool [%s] which last talked to [%s]", params.PoolID, params.Contact)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if phone != nil {
+		ctxLogger.Info(fmt.Sprintf("routed message of sender pool [%s] to [%s] through phone [%s] which last talked to the contact", params.PoolID, params.Contact, phone.PhoneNumber))
+		return phone, nil
+	}
+
+	if phone, err = service.leastLoadedPhone(ctx, params.PoolID, candidates); err != nil {
+		msg := fmt.Sprintf("cannot find the least loaded phone of sender pool [%s]", params.PoolID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("routed message of sender pool [%s] to [%s] through the least loaded phone [%s]", params.PoolID, params.Contact, phone.PhoneNumber))
+	return phone, nil
+}
+
+// onlinePhones filters the phones which are online according to their entities.HeartbeatMonitor.
+// A phone without a monitor hasn't sent its first heartbeat yet and it is considered to be online.
+func (service *SenderPoolService) onlinePhones(ctx context.Context, userID entities.UserID, phones []*entities.Phone) ([]*entities.Phone, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	online := make([]*entities.Phone, 0, len(phones))
+	for _, phone := range phones {
+		monitor, err := service.monitorRepository.Load(ctx, userID, phone.PhoneNumber)
+		if err != nil && stacktrace.GetCode(err) != repositories.ErrCodeNotFound {
+			msg := fmt.Sprintf("cannot load heartbeat monitor of phone [%s] for user [%s]", phone.PhoneNumber, userID)
+			return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+
+		if err == nil && monitor.PhoneIsOffline() {
+			continue
+		}
+		online = append(online, phone)
+	}
+
+	return online, nil
+}
+
+// stickyPhone returns the phone with the most recent entities.MessageThread with the contact or nil when no phone has talked to the contact
+func (service *SenderPoolService) stickyPhone(ctx context.Context, params *SenderPoolRouteParams, phones []*entities.Phone) (*entities.Phone, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	var result *entities.Phone
+	var lastMessageAt time.Time
+	for _, phone := range phones {
+		thread, err := service.threadRepository.LoadByOwnerContact(ctx, params.UserID, phone.PhoneNumber, params.Contact)
+		if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+			continue
+		}
+		if err != nil {
+			msg := fmt.Sprintf("cannot load message thread between [%s] and [%s] for user [%s]", phone.PhoneNumber, params.Contact, params.UserID)
+			return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+
+		if result == nil || thread.OrderTimestamp.After(lastMessageAt) {
+			result = phone
+			lastMessageAt = thread.OrderTimestamp
+		}
+	}
+
+	return result, nil
+}
+
+// leastLoadedPhone returns the phone which needs the least time to send its pending notifications and the messages which were
+// recently routed to it, ties are broken by the number of pending messages and then by the order of the phones in the pool
+func (service *SenderPoolService) leastLoadedPhone(ctx context.Context, poolID uuid.UUID, phones []*entities.Phone) (*entities.Phone, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	phoneIDs := make([]uuid.UUID, 0, len(phones))
+	for _, phone := range phones {
+		phoneIDs = append(phoneIDs, phone.ID)
+	}
+
+	pending, err := service.notificationRepository.CountPending(ctx, phoneIDs)
+	if err != nil {
+		msg := fmt.Sprintf("cannot count the pending notifications of [%d] phones", len(phones))
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	reservations, err := service.reservations(ctx, poolID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load the reservations of sender pool [%s]", poolID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return reservations.LeastLoadedPhone(phones, pending), nil
+}
+
+// lock acquires the lock of an entities.SenderPool and returns the function which releases it
+func (service *SenderPoolService) lock(ctx context.Context, poolID uuid.UUID) (func(), error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	key := fmt.Sprintf("sender-pool:%s:lock", poolID)
+	token := uuid.New().String()
+	for attempt := 0; attempt < senderPoolLockWaitAttempts; attempt++ {
+		locked, err := service.cache.Add(ctx, key, token, senderPoolLockTTL)
+		if err != nil {
+			return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot add lock [%s]", key)))
+		}
+
+		if locked {
+			return func() {
+				// the lock is only deleted with the token so that a holder whose lock expired doesn't release the lock of the next holder
+				released, err := service.cache.DeleteIfEqual(context.WithoutCancel(ctx), key, token)
+				if err != nil {
+					service.logger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot release lock [%s]", key)))
+					return
+				}
+				if !released {
+					service.logger.Warn(stacktrace.NewError(fmt.Sprintf("lock [%s] expired before it was released", key)))
+				}
+			}, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(ctx.Err(), fmt.Sprintf("stopped waiting for lock [%s]", key)))
+		case <-time.After(senderPoolLockWaitInterval):
+		}
+	}
+
+	return nil, service.tracer.WrapErrorSpan(span, stacktrace.NewError(fmt.Sprintf("cannot acquire lock [%s] after [%d] attempts", key, senderPoolLockWaitAttempts)))
+}
+
+// reservations loads the entities.SenderPoolReservations of the pool which have not expired
+func (service *SenderPoolService) reservations(ctx context.Context, poolID uuid.UUID) (entities.SenderPoolReservations, error) {
+	value, err := service.cache.Get(ctx, service.reservationsKey(poolID))
+	if err != nil {
+		// the reservations expire with the cache item
+		return entities.SenderPoolReservations{}, nil
+	}
+
+	var reservations entities.SenderPoolReservations
+	if err = json.Unmarshal([]byte(value), &reservations); err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot unmarshal reservations [%s] of sender pool [%s]", value, poolID))
+	}
+
+	return reservations.Active(time.Now().UTC()), nil
+}
+
+// reserve counts a routed message towards the load of the phone until its notification is scheduled, the lock of the pool must be held
+func (service *SenderPoolService) reserve(ctx context.Context, poolID uuid.UUID, phone *entities.Phone) error {
+	reservations, err := service.reservations(ctx, poolID)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot load the reservations of sender pool [%s]", poolID))
+	}
+
+	reservations = append(reservations, entities.SenderPoolReservation{
+		PhoneID:   phone.ID,
+		ExpiresAt: time.Now().UTC().Add(senderPoolReservationTTL),
+	})
+
+	value, err := json.Marshal(reservations)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot marshal [%d] reservations of sender pool [%s]", len(reservations), poolID))
+	}
+
+	if err = service.cache.Set(ctx, service.reservationsKey(poolID), string(value), senderPoolReservationTTL); err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot store [%d] reservations of sender pool [%s]", len(reservations), poolID))
+	}
+	return nil
+}
+
+func (service *SenderPoolService) reservationsKey(poolID uuid.UUID) string {
+	return fmt.Sprintf("sender-pool:%s:reservations", poolID)
+}
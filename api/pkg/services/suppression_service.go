@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// SuppressionService is responsible for handling entities.Suppression
+type SuppressionService struct {
+	service
+	logger          telemetry.Logger
+	tracer          telemetry.Tracer
+	repository      repositories.SuppressionRepository
+	eventDispatcher *EventDispatcher
+}
+
+// NewSuppressionService creates a new SuppressionService
+func NewSuppressionService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.SuppressionRepository,
+	eventDispatcher *EventDispatcher,
+) (s *SuppressionService) {
+	return &SuppressionService{
+		logger:          logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:          tracer,
+		repository:      repository,
+		eventDispatcher: eventDispatcher,
+	}
+}
+
+// Index fetches the entities.Suppression for an entities.UserID
+func (service *SuppressionService) Index(ctx context.Context, userID entities.UserID, params repositories.IndexParams) ([]*entities.Suppression, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	suppressions, err := service.repository.Index(ctx, userID, params)
+	if err != nil {
+		msg := fmt.Sprintf("could not fetch suppressions with params [%+#v]", params)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("fetched [%d] suppressions with prams [%+#v]", len(suppressions), params))
+	return suppressions, nil
+}
+
+// IsSuppressed checks if a phone number is on the suppression list of a user
+func (service *SuppressionService) IsSuppressed(ctx context.Context, userID entities.UserID, phoneNumber string) (bool, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	_, err := service.repository.Load(ctx, userID, phoneNumber)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return false, nil
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load suppression for phone number [%s] and user [%s]", phoneNumber, userID)
+		return false, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return true, nil
+}
+
+// Suppressed returns the phone numbers which are on the suppression list of a user
+func (service *SuppressionService) Suppressed(ctx context.Context, userID entities.UserID, phoneNumbers []string) (map[string]bool, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	suppressions, err := service.repository.FetchByPhoneNumbers(ctx, userID, phoneNumbers)
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch suppressions for [%d] phone numbers of user [%s]", len(phoneNumbers), userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	result := make(map[string]bool, len(suppressions))
+	for _, suppression := range suppressions {
+		result[suppression.PhoneNumber] = true
+	}
+	return result, nil
+}
+
+// SuppressionStoreParams are parameters for adding a phone number to the suppression list
+type SuppressionStoreParams struct {
+	Source      string
+	UserID      entities.UserID
+	PhoneNumber string
+	Owner       *string
+	Origin      entities.SuppressionSource
+	Keyword     *string
+	Reason      *string
+}
+
+// Store adds a phone number to the suppression list. An existing entities.Suppression for the phone number is returned unchanged.
+func (service *SuppressionService) Store(ctx context.Context, params *SuppressionStoreParams) (*entities.Suppression, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	suppression, err := service.repository.Load(ctx, params.UserID, params.PhoneNumber)
+	if err == nil {
+		ctxLogger.Info(fmt.Sprintf("phone number [%s] is already suppressed with id [%s] for user [%s]", params.PhoneNumber, suppression.ID, params.UserID))
+		return suppression, nil
+	}
+
+	if stacktrace.GetCode(err) != repositories.ErrCodeNotFound {
+		msg := fmt.Sprintf("cannot load suppression for phone number [%s] and user [%s]", params.PhoneNumber, params.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	suppression = &entities.Suppression{
+		ID:          uuid.New(),
+		UserID:      params.UserID,
+		PhoneNumber: params.PhoneNumber,
+		Owner:       params.Owner,
+		Source:      params.Origin,
+		Keyword:     params.Keyword,
+		Reason:      params.Reason,
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	}
+
+	if err = service.repository.Save(ctx, suppression); err != nil {
+		msg := fmt.Sprintf("cannot save suppression with id [%s]", suppression.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("suppression saved with id [%s] for phone number [%s] in the [%T]", suppression.ID, suppression.PhoneNumber, service.repository))
+
+	event, err := service.createEvent(events.EventTypeContactOptOut, params.Source, &events.ContactOptOutPayload{
+		SuppressionID: suppression.ID,
+		UserID:        suppression.UserID,
+		Owner:         service.stringValue(suppression.Owner),
+		Contact:       suppression.PhoneNumber,
+		Source:        suppression.Source,
+		Keyword:       suppression.Keyword,
+		Timestamp:     suppression.CreatedAt,
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot create [%s] event for suppression [%s]", events.EventTypeContactOptOut, suppression.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = service.eventDispatcher.Dispatch(ctx, event); err != nil {
+		msg := fmt.Sprintf("cannot dispatch event [%s] for suppression [%s]", event.Type(), suppression.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return suppression, nil
+}
+
+// SuppressionDeleteParams are parameters for removing a phone number from the suppression list
+type SuppressionDeleteParams struct {
+	Source      string
+	UserID      entities.UserID
+	PhoneNumber string
+	Owner       *string
+	Origin      entities.SuppressionSource
+	Keyword     *string
+}
+
+// Delete removes a phone number from the suppression list
+func (service *SuppressionService) Delete(ctx context.Context, params *SuppressionDeleteParams) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if _, err := service.repository.Load(ctx, params.UserID, params.PhoneNumber); err != nil {
+		msg := fmt.Sprintf("cannot load suppression with userID [%s] and phone number [%s]", params.UserID, params.PhoneNumber)
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if err := service.repository.Delete(ctx, params.UserID, params.PhoneNumber); err != nil {
+		msg := fmt.Sprintf("cannot delete suppression with phone number [%s] and user id [%s]", params.PhoneNumber, params.UserID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted suppression with phone number [%s] and user id [%s]", params.PhoneNumber, params.UserID))
+
+	event, err := service.createEvent(events.EventTypeContactOptIn, params.Source, &events.ContactOptInPayload{
+		UserID:    params.UserID,
+		Owner:     service.stringValue(params.Owner),
+		Contact:   params.PhoneNumber,
+		Source:    params.Origin,
+		Keyword:   params.Keyword,
+		Timestamp: time.Now().UTC(),
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot create [%s] event for phone number [%s]", events.EventTypeContactOptIn, params.PhoneNumber)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = service.eventDispatcher.Dispatch(ctx, event); err != nil {
+		msg := fmt.Sprintf("cannot dispatch event [%s] for phone number [%s]", event.Type(), params.PhoneNumber)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// HandleKeyword updates the suppression list when a received message matches an opt-out or opt-in keyword of the entities.Phone
+func (service *SuppressionService) HandleKeyword(ctx context.Context, source string, phone *entities.Phone, contact string, content string) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if keyword := phone.OptOutKeyword(content); keyword != nil {
+		ctxLogger.Info(fmt.Sprintf("contact [%s] replied with opt-out keyword [%s] to phone [%s]", contact, *keyword, phone.ID))
+		_, err := service.Store(ctx, &SuppressionStoreParams{
+			Source:      source,
+			UserID:      phone.UserID,
+			PhoneNumber: contact,
+			Owner:       &phone.PhoneNumber,
+			Origin:      entities.SuppressionSourceKeyword,
+			Keyword:     keyword,
+		})
+		if err != nil {
+			msg := fmt.Sprintf("cannot suppress contact [%s] for phone [%s]", contact, phone.ID)
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+		return nil
+	}
+
+	if keyword := phone.OptInKeyword(content); keyword != nil {
+		ctxLogger.Info(fmt.Sprintf("contact [%s] replied with opt-in keyword [%s] to phone [%s]", contact, *keyword, phone.ID))
+		err := service.Delete(ctx, &SuppressionDeleteParams{
+			Source:      source,
+			UserID:      phone.UserID,
+			PhoneNumber: contact,
+			Owner:       &phone.PhoneNumber,
+			Origin:      entities.SuppressionSourceKeyword,
+			Keyword:     keyword,
+		})
+		if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+			ctxLogger.Info(fmt.Sprintf("contact [%s] is not suppressed for user [%s]", contact, phone.UserID))
+			return nil
+		}
+		if err != nil {
+			msg := fmt.Sprintf("cannot remove suppression of contact [%s] for phone [%s]", contact, phone.ID)
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+	}
+
+	return nil
+}
+
+func (service *SuppressionService) stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
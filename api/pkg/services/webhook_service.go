@@ -13,13 +13,13 @@ import (
 
 	"github.com/pkg/errors"
 
-	"github.com/gofiber/fiber/v2"
-
 	"github.com/NdoleStudio/httpsms/pkg/events"
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
 	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/signature"
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/webhooks"
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/golang-jwt/jwt"
 	"github.com/google/uuid"
@@ -27,14 +27,26 @@ import (
 	"github.com/palantir/stacktrace"
 )
 
+const (
+	webhookRetryBaseDelay = 30 * time.Second
+	webhookRetryMaxDelay  = 6 * time.Hour
+
+	webhookResponseBodyMaxLength = 2048
+)
+
 // WebhookService is responsible for handling webhooks
 type WebhookService struct {
 	service
-	logger     telemetry.Logger
-	tracer     telemetry.Tracer
-	client     *http.Client
-	repository repositories.WebhookRepository
-	dispatcher *EventDispatcher
+	logger               telemetry.Logger
+	tracer               telemetry.Tracer
+	client               *http.Client
+	repository           repositories.WebhookRepository
+	deliveryRepository   repositories.WebhookDeliveryRepository
+	attemptRepository    repositories.WebhookDeliveryAttemptRepository
+	deadLetterRepository repositories.WebhookDeadLetterRepository
+	dispatcher           *EventDispatcher
+	renderers            *webhooks.Registry
+	maxAttempts          uint
 }
 
 // NewWebhookService creates a new WebhookService
@@ -43,14 +55,24 @@ func NewWebhookService(
 	tracer telemetry.Tracer,
 	client *http.Client,
 	repository repositories.WebhookRepository,
+	deliveryRepository repositories.WebhookDeliveryRepository,
+	attemptRepository repositories.WebhookDeliveryAttemptRepository,
+	deadLetterRepository repositories.WebhookDeadLetterRepository,
 	dispatcher *EventDispatcher,
+	renderers *webhooks.Registry,
+	maxAttempts uint,
 ) (s *WebhookService) {
 	return &WebhookService{
-		logger:     logger.WithService(fmt.Sprintf("%T", s)),
-		tracer:     tracer,
-		client:     client,
-		dispatcher: dispatcher,
-		repository: repository,
+		logger:               logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:               tracer,
+		client:               client,
+		dispatcher:           dispatcher,
+		repository:           repository,
+		deliveryRepository:   deliveryRepository,
+		attemptRepository:    attemptRepository,
+		deadLetterRepository: deadLetterRepository,
+		renderers:            renderers,
+		maxAttempts:          maxAttempts,
 	}
 }
 
@@ -94,11 +116,13 @@ func (service *WebhookService) Delete(ctx context.Context, userID entities.UserI
 
 // WebhookStoreParams are parameters for creating a new entities.Webhook
 type WebhookStoreParams struct {
-	UserID       entities.UserID
-	SigningKey   string
-	URL          string
-	PhoneNumbers pq.StringArray
-	Events       pq.StringArray
+	UserID        entities.UserID
+	SigningKey    string
+	SigningScheme entities.WebhookSigningScheme
+	Format        entities.WebhookFormat
+	URL           string
+	PhoneNumbers  pq.StringArray
+	Events        pq.StringArray
 }
 
 // Store a new entities.Webhook
@@ -109,14 +133,16 @@ func (service *WebhookService) Store(ctx context.Context, params *WebhookStorePa
 	ctxLogger := service.tracer.CtxLogger(service.logger, span)
 
 	webhook := &entities.Webhook{
-		ID:           uuid.New(),
-		UserID:       params.UserID,
-		URL:          params.URL,
-		PhoneNumbers: params.PhoneNumbers,
-		SigningKey:   params.SigningKey,
-		Events:       params.Events,
-		CreatedAt:    time.Now().UTC(),
-		UpdatedAt:    time.Now().UTC(),
+		ID:            uuid.New(),
+		UserID:        params.UserID,
+		URL:           params.URL,
+		PhoneNumbers:  params.PhoneNumbers,
+		SigningKey:    params.SigningKey,
+		SigningScheme: params.SigningScheme,
+		Format:        params.Format,
+		Events:        params.Events,
+		CreatedAt:     time.Now().UTC(),
+		UpdatedAt:     time.Now().UTC(),
 	}
 
 	if err := service.repository.Save(ctx, webhook); err != nil {
@@ -130,12 +156,14 @@ func (service *WebhookService) Store(ctx context.Context, params *WebhookStorePa
 
 // WebhookUpdateParams are parameters for updating an entities.Webhook
 type WebhookUpdateParams struct {
-	UserID       entities.UserID
-	SigningKey   string
-	URL          string
-	Events       pq.StringArray
-	PhoneNumbers pq.StringArray
-	WebhookID    uuid.UUID
+	UserID        entities.UserID
+	SigningKey    string
+	SigningScheme entities.WebhookSigningScheme
+	Format        entities.WebhookFormat
+	URL           string
+	Events        pq.StringArray
+	PhoneNumbers  pq.StringArray
+	WebhookID     uuid.UUID
 }
 
 // Update an entities.Webhook
@@ -151,6 +179,12 @@ func (service *WebhookService) Update(ctx context.Context, params *WebhookUpdate
 
 	webhook.URL = params.URL
 	webhook.SigningKey = params.SigningKey
+	if params.SigningScheme != "" {
+		webhook.SigningScheme = params.SigningScheme
+	}
+	if params.Format != "" {
+		webhook.Format = params.Format
+	}
 	webhook.Events = params.Events
 	webhook.PhoneNumbers = params.PhoneNumbers
 
@@ -184,7 +218,7 @@ func (service *WebhookService) Send(ctx context.Context, userID entities.UserID,
 		wg.Add(1)
 		go func(webhook *entities.Webhook) {
 			defer wg.Done()
-			service.sendNotification(ctx, event, phoneNumber, webhook)
+			service.deliver(ctx, event, phoneNumber, webhook)
 		}(webhook)
 	}
 	wg.Wait()
@@ -192,10 +226,290 @@ func (service *WebhookService) Send(ctx context.Context, userID entities.UserID,
 	return nil
 }
 
-func (service *WebhookService) sendNotification(ctx context.Context, event cloudevents.Event, owner string, webhook *entities.Webhook) {
+// Retry sends an entities.WebhookDelivery whose previous attempt failed
+func (service *WebhookService) Retry(ctx context.Context, payload *events.WebhookDeliveryRetryPayload) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	delivery, err := service.deliveryRepository.Load(ctx, payload.UserID, payload.DeliveryID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load webhook delivery with ID [%s] for user [%s]", payload.DeliveryID, payload.UserID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if delivery.IsFinal() {
+		ctxLogger.Info(fmt.Sprintf("webhook delivery [%s] already has status [%s] after [%d] attempts", delivery.ID, delivery.Status, delivery.Attempts))
+		return nil
+	}
+
+	webhook, err := service.repository.Load(ctx, delivery.UserID, delivery.WebhookID)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		ctxLogger.Info(fmt.Sprintf("webhook [%s] for delivery [%s] has been deleted", delivery.WebhookID, delivery.ID))
+		delivery.Status = entities.WebhookDeliveryStatusFailed
+		delivery.NextAttemptAt = nil
+		delivery.UpdatedAt = time.Now().UTC()
+		return service.updateDelivery(ctx, delivery)
+	}
+	if err != nil {
+		msg := fmt.Sprintf("cannot load webhook [%s] for delivery [%s]", delivery.WebhookID, delivery.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	event := cloudevents.NewEvent()
+	if err = json.Unmarshal([]byte(delivery.Event), &event); err != nil {
+		msg := fmt.Sprintf("cannot unmarshal event with ID [%s] for webhook delivery [%s]", delivery.EventID, delivery.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	service.sendNotification(ctx, event, webhook, delivery)
+	return nil
+}
+
+// IndexDeadLetters fetches the entities.WebhookDeadLetter of an entities.Webhook
+func (service *WebhookService) IndexDeadLetters(ctx context.Context, userID entities.UserID, webhookID uuid.UUID, params repositories.IndexParams) ([]*entities.WebhookDeadLetter, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	deadLetters, err := service.deadLetterRepository.Index(ctx, userID, webhookID, params)
+	if err != nil {
+		msg := fmt.Sprintf("could not fetch dead letters for webhook [%s] with params [%+#v]", webhookID, params)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("fetched [%d] dead letters for webhook [%s] with prams [%+#v]", len(deadLetters), webhookID, params))
+	return deadLetters, nil
+}
+
+// WebhookDeliveryIndexParams are parameters for fetching the entities.WebhookDeliveryAttempt of a webhook
+type WebhookDeliveryIndexParams struct {
+	UserID      entities.UserID
+	WebhookID   uuid.UUID
+	Status      string
+	EventType   string
+	IndexParams repositories.IndexParams
+}
+
+// IndexDeliveries fetches the entities.WebhookDeliveryAttempt made to an entities.Webhook
+func (service *WebhookService) IndexDeliveries(ctx context.Context, params *WebhookDeliveryIndexParams) ([]*entities.WebhookDeliveryAttempt, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	attempts, err := service.attemptRepository.Index(ctx, params.UserID, params.WebhookID, params.Status, params.EventType, params.IndexParams)
+	if err != nil {
+		msg := fmt.Sprintf("could not fetch delivery attempts for webhook [%s] with params [%+#v]", params.WebhookID, params)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("fetched [%d] delivery attempts for webhook [%s] with prams [%+#v]", len(attempts), params.WebhookID, params))
+	return attempts, nil
+}
+
+// WebhookRedeliverParams are parameters for sending an entities.WebhookDeadLetter again
+type WebhookRedeliverParams struct {
+	Source       string
+	UserID       entities.UserID
+	WebhookID    uuid.UUID
+	DeadLetterID uuid.UUID
+}
+
+// Redeliver moves an entities.WebhookDeadLetter back into the delivery queue
+func (service *WebhookService) Redeliver(ctx context.Context, params *WebhookRedeliverParams) (*entities.WebhookDelivery, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	deadLetter, err := service.deadLetterRepository.Load(ctx, params.UserID, params.DeadLetterID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load dead letter with ID [%s] for user [%s]", params.DeadLetterID, params.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if deadLetter.WebhookID != params.WebhookID {
+		msg := fmt.Sprintf("dead letter with ID [%s] does not belong to webhook [%s]", deadLetter.ID, params.WebhookID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(repositories.ErrCodeNotFound, msg))
+	}
+
+	if _, err = service.repository.Load(ctx, params.UserID, params.WebhookID); err != nil {
+		msg := fmt.Sprintf("cannot load webhook with userID [%s] and webhookID [%s]", params.UserID, params.WebhookID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	delivery := &entities.WebhookDelivery{
+		ID:          uuid.New(),
+		WebhookID:   deadLetter.WebhookID,
+		UserID:      deadLetter.UserID,
+		Owner:       deadLetter.Owner,
+		EventID:     deadLetter.EventID,
+		EventType:   deadLetter.EventType,
+		Event:       deadLetter.Event,
+		Status:      entities.WebhookDeliveryStatusPending,
+		MaxAttempts: service.maxAttempts,
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	}
+
+	if err = service.deliveryRepository.Store(ctx, delivery); err != nil {
+		msg := fmt.Sprintf("cannot store webhook delivery for dead letter [%s]", deadLetter.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	// the dead letter is only deleted after the redelivery is dispatched so that it is not lost when the dispatch fails
+	if err = service.dispatchRetry(ctx, params.Source, delivery, 0); err != nil {
+		delivery.Status = entities.WebhookDeliveryStatusFailed
+		delivery.UpdatedAt = time.Now().UTC()
+		if updateErr := service.updateDelivery(ctx, delivery); updateErr != nil {
+			ctxLogger.Error(stacktrace.Propagate(updateErr, fmt.Sprintf("cannot update undispatched webhook delivery [%s] as failed", delivery.ID)))
+		}
+		msg := fmt.Sprintf("cannot dispatch redelivery for dead letter [%s]", deadLetter.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = service.deadLetterRepository.Delete(ctx, params.UserID, deadLetter.ID); err != nil {
+		msg := fmt.Sprintf("cannot delete dead letter [%s] after dispatching delivery [%s]", deadLetter.ID, delivery.ID)
+		ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("dead letter [%s] scheduled for redelivery with delivery ID [%s]", deadLetter.ID, delivery.ID))
+	return delivery, nil
+}
+
+// WebhookTestParams are parameters for sending a sample event to an entities.Webhook
+type WebhookTestParams struct {
+	Source    string
+	UserID    entities.UserID
+	WebhookID uuid.UUID
+	EventType string
+}
+
+// Test sends a sample event to an entities.Webhook and returns the response of the webhook
+func (service *WebhookService) Test(ctx context.Context, params *WebhookTestParams) (*entities.WebhookTestResult, error) {
 	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
 	defer span.End()
 
+	webhook, err := service.repository.Load(ctx, params.UserID, params.WebhookID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load webhook with userID [%s] and webhookID [%s]", params.UserID, params.WebhookID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	owner := ""
+	if len(webhook.PhoneNumbers) > 0 {
+		owner = webhook.PhoneNumbers[0]
+	}
+
+	payload, ok := events.SamplePayload(params.EventType, webhook.UserID, owner)
+	if !ok {
+		msg := fmt.Sprintf("there is no sample payload for event type [%s]", params.EventType)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.NewError(msg))
+	}
+
+	event, err := service.createEvent(params.EventType, params.Source, payload)
+	if err != nil {
+		msg := fmt.Sprintf("cannot create sample [%s] event for webhook [%s]", params.EventType, webhook.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	result := &entities.WebhookTestResult{
+		EventID:   event.ID(),
+		EventType: event.Type(),
+		URL:       webhook.URL,
+	}
+
+	requestCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	request, err := service.createRequest(requestCtx, event, webhook)
+	if err != nil {
+		msg := fmt.Sprintf("cannot create request for sample [%s] event to webhook [%s]", event.Type(), webhook.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	start := time.Now()
+	response, err := service.client.Do(request)
+	if err != nil {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot send sample [%s] event to webhook [%s]", event.Type(), webhook.URL)))
+		result.LatencyMilliseconds = time.Since(start).Milliseconds()
+		result.ErrorMessage = service.getStringPointer(service.getErrorMessage(err, nil, nil))
+		return result, nil
+	}
+
+	defer func() {
+		err = response.Body.Close()
+		if err != nil {
+			ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot close response body for sample [%s] event with ID [%s]", event.Type(), event.ID())))
+		}
+	}()
+
+	body, err := service.readResponseBody(response)
+	if err != nil {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot read response body for sample [%s] event with ID [%s]", event.Type(), event.ID())))
+	}
+
+	result.LatencyMilliseconds = time.Since(start).Milliseconds()
+	result.HTTPResponseStatusCode = &response.StatusCode
+	result.HTTPResponseHeaders = response.Header
+	result.HTTPResponseBody = service.truncateResponseBody(body)
+	if response.StatusCode >= 400 {
+		result.ErrorMessage = service.getStringPointer(http.StatusText(response.StatusCode))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("sent sample [%s] event with ID [%s] to webhook [%s] with response code [%d]", event.Type(), event.ID(), webhook.ID, response.StatusCode))
+	return result, nil
+}
+
+func (service *WebhookService) deliver(ctx context.Context, event cloudevents.Event, owner string, webhook *entities.Webhook) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		msg := fmt.Sprintf("cannot marshal [%s] event with ID [%s] for webhook [%s]", event.Type(), event.ID(), webhook.ID)
+		ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		return
+	}
+
+	delivery := &entities.WebhookDelivery{
+		ID:          uuid.New(),
+		WebhookID:   webhook.ID,
+		UserID:      webhook.UserID,
+		Owner:       owner,
+		EventID:     event.ID(),
+		EventType:   event.Type(),
+		Event:       string(payload),
+		Status:      entities.WebhookDeliveryStatusPending,
+		MaxAttempts: service.maxAttempts,
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	}
+
+	if err = service.deliveryRepository.Store(ctx, delivery); err != nil {
+		msg := fmt.Sprintf("cannot store delivery of [%s] event with ID [%s] to webhook [%s]", event.Type(), event.ID(), webhook.ID)
+		ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		return
+	}
+
+	service.sendNotification(ctx, event, webhook, delivery)
+}
+
+func (service *WebhookService) sendNotification(ctx context.Context, event cloudevents.Event, webhook *entities.Webhook, delivery *entities.WebhookDelivery) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	delivery.Attempts++
+
+	attempt := &entities.WebhookDeliveryAttempt{
+		ID:         uuid.New(),
+		DeliveryID: delivery.ID,
+		WebhookID:  webhook.ID,
+		UserID:     webhook.UserID,
+		EventID:    event.ID(),
+		EventType:  event.Type(),
+		URL:        webhook.URL,
+		Attempt:    delivery.Attempts,
+		Status:     entities.WebhookDeliveryStatusFailed,
+		CreatedAt:  time.Now().UTC(),
+	}
+	defer service.storeDeliveryAttempt(ctx, attempt)
+
 	requestCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
@@ -203,13 +517,18 @@ func (service *WebhookService) sendNotification(ctx context.Context, event cloud
 	if err != nil {
 		msg := fmt.Sprintf("cannot create [%s] event to webhook [%s] for user [%s]", event.Type(), webhook.URL, webhook.UserID)
 		ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		attempt.ErrorMessage = service.getStringPointer(service.getErrorMessage(err, nil, nil))
+		service.handleDeliveryFailed(ctx, event, webhook, delivery, err, nil, nil)
 		return
 	}
 
+	start := time.Now()
 	response, err := service.client.Do(request)
 	if err != nil {
 		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot send [%s] event to webhook [%s] for user [%s]", event.Type(), webhook.URL, webhook.UserID)))
-		service.handleWebhookSendFailed(ctx, event, webhook, owner, err, nil)
+		attempt.LatencyMilliseconds = time.Since(start).Milliseconds()
+		attempt.ErrorMessage = service.getStringPointer(service.getErrorMessage(err, nil, nil))
+		service.handleDeliveryFailed(ctx, event, webhook, delivery, err, nil, nil)
 		return
 	}
 
@@ -220,92 +539,253 @@ func (service *WebhookService) sendNotification(ctx context.Context, event cloud
 		}
 	}()
 
+	body, err := service.readResponseBody(response)
+	if err != nil {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot read response body for [%s] event with ID [%s]", event.Type(), event.ID())))
+	}
+
+	attempt.LatencyMilliseconds = time.Since(start).Milliseconds()
+	attempt.HTTPResponseStatusCode = &response.StatusCode
+	attempt.ResponseBody = service.truncateResponseBody(body)
+
 	if response.StatusCode >= 400 {
 		ctxLogger.Info(fmt.Sprintf("cannot send [%s] event to webhook [%s] for user [%s] with response code [%d]", event.Type(), webhook.URL, webhook.UserID, response.StatusCode))
-		service.handleWebhookSendFailed(ctx, event, webhook, owner, stacktrace.NewError(http.StatusText(response.StatusCode)), response)
+		attempt.ErrorMessage = service.getStringPointer(http.StatusText(response.StatusCode))
+		service.handleDeliveryFailed(ctx, event, webhook, delivery, stacktrace.NewError(http.StatusText(response.StatusCode)), &response.StatusCode, body)
 		return
 	}
 
+	attempt.Status = entities.WebhookDeliveryStatusSucceeded
+
+	delivery.Status = entities.WebhookDeliveryStatusSucceeded
+	delivery.NextAttemptAt = nil
+	delivery.HTTPResponseStatusCode = &response.StatusCode
+	delivery.ErrorMessage = nil
+	delivery.UpdatedAt = time.Now().UTC()
+	if err = service.updateDelivery(ctx, delivery); err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot update successful webhook delivery [%s]", delivery.ID)))
+	}
+
 	ctxLogger.Info(fmt.Sprintf("sent webhook to url [%s] for event [%s] with ID [%s] and response code [%d]", webhook.URL, event.Type(), event.ID(), response.StatusCode))
 }
 
-func (service *WebhookService) createRequest(ctx context.Context, event cloudevents.Event, webhook *entities.Webhook) (*http.Request, error) {
+func (service *WebhookService) storeDeliveryAttempt(ctx context.Context, attempt *entities.WebhookDeliveryAttempt) {
 	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
 	defer span.End()
 
-	payload, err := json.Marshal(service.getPayload(ctxLogger, event, webhook))
+	if err := service.attemptRepository.Store(ctx, attempt); err != nil {
+		msg := fmt.Sprintf("cannot store attempt [%d] for webhook delivery [%s]", attempt.Attempt, attempt.DeliveryID)
+		ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+	}
+}
+
+func (service *WebhookService) handleDeliveryFailed(ctx context.Context, event cloudevents.Event, webhook *entities.Webhook, delivery *entities.WebhookDelivery, err error, statusCode *int, body []byte) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	errorMessage := service.getErrorMessage(err, statusCode, body)
+	delivery.HTTPResponseStatusCode = statusCode
+	delivery.ErrorMessage = &errorMessage
+	delivery.UpdatedAt = time.Now().UTC()
+
+	if delivery.CanRetry() {
+		delay := service.getRetryDelay(delivery.Attempts)
+		nextAttemptAt := time.Now().UTC().Add(delay)
+		delivery.Status = entities.WebhookDeliveryStatusRetrying
+		delivery.NextAttemptAt = &nextAttemptAt
+		if err = service.updateDelivery(ctx, delivery); err != nil {
+			ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot update webhook delivery [%s] before retry", delivery.ID)))
+		}
+
+		dispatchErr := service.dispatchRetry(ctx, event.Source(), delivery, delay)
+		if dispatchErr == nil {
+			return
+		}
+
+		// the delivery would stay in the retrying status forever without the retry event so it is dead-lettered instead
+		ctxLogger.Error(stacktrace.Propagate(dispatchErr, fmt.Sprintf("cannot schedule retry [%d] for webhook delivery [%s]", delivery.Attempts+1, delivery.ID)))
+	}
+
+	delivery.Status = entities.WebhookDeliveryStatusFailed
+	delivery.NextAttemptAt = nil
+	if err = service.updateDelivery(ctx, delivery); err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot update failed webhook delivery [%s]", delivery.ID)))
+	}
+
+	deadLetter := &entities.WebhookDeadLetter{
+		ID:                     uuid.New(),
+		WebhookID:              delivery.WebhookID,
+		DeliveryID:             delivery.ID,
+		UserID:                 delivery.UserID,
+		Owner:                  delivery.Owner,
+		EventID:                delivery.EventID,
+		EventType:              delivery.EventType,
+		Event:                  delivery.Event,
+		Attempts:               delivery.Attempts,
+		HTTPResponseStatusCode: statusCode,
+		ErrorMessage:           errorMessage,
+		CreatedAt:              time.Now().UTC(),
+	}
+	if err = service.deadLetterRepository.Store(ctx, deadLetter); err != nil {
+		msg := fmt.Sprintf("cannot store dead letter for webhook delivery [%s]", delivery.ID)
+		ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("webhook delivery [%s] moved to dead letters after [%d] attempts", delivery.ID, delivery.Attempts))
+	service.handleWebhookSendFailed(ctx, event, webhook, delivery.Owner, statusCode, errorMessage)
+}
+
+func (service *WebhookService) dispatchRetry(ctx context.Context, source string, delivery *entities.WebhookDelivery, delay time.Duration) error {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	scheduledAt := time.Now().UTC().Add(delay)
+	event, err := service.createEvent(events.EventTypeWebhookDeliveryRetry, source, &events.WebhookDeliveryRetryPayload{
+		DeliveryID:  delivery.ID,
+		WebhookID:   delivery.WebhookID,
+		UserID:      delivery.UserID,
+		Attempt:     delivery.Attempts + 1,
+		ScheduledAt: scheduledAt,
+	})
 	if err != nil {
-		msg := fmt.Sprintf("cannot marshal payload for user [%s] and webhook [%s] for event [%s]", webhook.UserID, webhook.ID, event.ID())
+		msg := fmt.Sprintf("cannot create [%s] event for webhook delivery [%s]", events.EventTypeWebhookDeliveryRetry, delivery.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if delay == 0 {
+		if err = service.dispatcher.Dispatch(ctx, event); err != nil {
+			msg := fmt.Sprintf("cannot dispatch [%s] event with ID [%s]", event.Type(), event.ID())
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+		return nil
+	}
+
+	if _, err = service.dispatcher.DispatchWithTimeout(ctx, event, delay); err != nil {
+		msg := fmt.Sprintf("cannot dispatch [%s] event with ID [%s] and delay [%s]", event.Type(), event.ID(), delay)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (service *WebhookService) updateDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	if err := service.deliveryRepository.Update(ctx, delivery); err != nil {
+		msg := fmt.Sprintf("cannot update webhook delivery [%s] with status [%s]", delivery.ID, delivery.Status)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+	return nil
+}
+
+// getRetryDelay returns the exponential backoff delay after a number of failed attempts
+func (service *WebhookService) getRetryDelay(attempts uint) time.Duration {
+	delay := webhookRetryBaseDelay
+	for i := uint(1); i < attempts && delay < webhookRetryMaxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > webhookRetryMaxDelay {
+		return webhookRetryMaxDelay
+	}
+	return delay
+}
+
+func (service *WebhookService) getErrorMessage(err error, statusCode *int, body []byte) string {
+	if message := service.truncateResponseBody(body); statusCode != nil && message != nil {
+		return *message
+	}
+
+	if statusCode != nil {
+		return http.StatusText(*statusCode)
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "TIMOUT after 10 seconds"
+	}
+
+	return stacktrace.RootCause(err).Error()
+}
+
+// readResponseBody reads at most webhookResponseBodyMaxLength bytes so that a large response doesn't exhaust the memory
+func (service *WebhookService) readResponseBody(response *http.Response) ([]byte, error) {
+	return io.ReadAll(io.LimitReader(response.Body, webhookResponseBodyMaxLength))
+}
+
+func (service *WebhookService) truncateResponseBody(body []byte) *string {
+	if len(body) == 0 {
+		return nil
+	}
+
+	if len(body) > webhookResponseBodyMaxLength {
+		body = body[:webhookResponseBodyMaxLength]
+	}
+
+	return service.getStringPointer(strings.ToValidUTF8(string(body), ""))
+}
+
+func (service *WebhookService) getStringPointer(value string) *string {
+	return &value
+}
+
+func (service *WebhookService) createRequest(ctx context.Context, event cloudevents.Event, webhook *entities.Webhook) (*http.Request, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	renderer, err := service.renderers.Get(service.getFormat(webhook))
+	if err != nil {
+		msg := fmt.Sprintf("cannot get renderer for format [%s] of webhook [%s]", webhook.Format, webhook.ID)
 		return nil, stacktrace.Propagate(err, msg)
 	}
 
-	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(payload))
+	payload, err := renderer.Render(event)
+	if err != nil {
+		msg := fmt.Sprintf("cannot render payload for user [%s] and webhook [%s] for event [%s]", webhook.UserID, webhook.ID, event.ID())
+		return nil, stacktrace.Propagate(err, msg)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(payload.Body))
 	if err != nil {
 		msg := fmt.Sprintf("cannot create request for user [%s] and webhook [%s] for event [%s]", webhook.UserID, webhook.ID, event.ID())
 		return nil, stacktrace.Propagate(err, msg)
 	}
 
 	request.Header.Add("X-Event-Type", event.Type())
-	request.Header.Set("Content-Type", "application/json")
+	for key, value := range payload.Headers {
+		request.Header.Set(key, value)
+	}
 
-	if strings.TrimSpace(webhook.SigningKey) != "" {
-		token, err := service.getAuthToken(webhook)
-		if err != nil {
-			msg := fmt.Sprintf("cannot generate auth token for user [%s] and webhook [%s]", webhook.UserID, webhook.ID)
-			return nil, stacktrace.Propagate(err, msg)
-		}
-		request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	if strings.TrimSpace(webhook.SigningKey) == "" {
+		return request, nil
 	}
 
+	if webhook.SigningScheme == entities.WebhookSigningSchemeHMAC {
+		request.Header.Set(signature.HeaderName, signature.Sign(payload.Body, webhook.SigningKey, time.Now().UTC()))
+		return request, nil
+	}
+
+	token, err := service.getAuthToken(webhook)
+	if err != nil {
+		msg := fmt.Sprintf("cannot generate auth token for user [%s] and webhook [%s]", webhook.UserID, webhook.ID)
+		return nil, stacktrace.Propagate(err, msg)
+	}
+
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 	return request, nil
 }
 
-func (service *WebhookService) getPayload(ctxLogger telemetry.Logger, event cloudevents.Event, webhook *entities.Webhook) any {
-	if event.Type() != events.EventTypeMessagePhoneReceived {
-		return event
+// getFormat chooses the format from the URL when the format is empty, which is the case for webhooks stored before the format could be configured
+func (service *WebhookService) getFormat(webhook *entities.Webhook) entities.WebhookFormat {
+	if webhook.Format != "" {
+		return webhook.Format
 	}
 
-	if !strings.HasPrefix(webhook.URL, "https://discord.com/api/webhooks/") {
-		return event
+	if strings.HasPrefix(webhook.URL, "https://discord.com/api/webhooks/") {
+		return entities.WebhookFormatDiscord
 	}
 
-	payload := new(events.MessagePhoneReceivedPayload)
-
-	err := event.DataAs(payload)
-	if err != nil {
-		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot unmarshal event [%s] with ID [%s] into [%T]", event.Type(), event.ID(), payload)))
-		return event
-	}
-
-	return map[string]any{
-		"avatar_url": "https://httpsms.com/avatar.png",
-		"username":   "httpsms.com",
-		"content":    "✉ new message received",
-		"embeds": []fiber.Map{
-			{
-				"fields": []fiber.Map{
-					{
-						"name":   "From:",
-						"value":  service.getFormattedNumber(ctxLogger, payload.Contact),
-						"inline": true,
-					},
-					{
-						"name":   "To:",
-						"value":  service.getFormattedNumber(ctxLogger, payload.Owner),
-						"inline": true,
-					},
-					{
-						"name":  "Content:",
-						"value": payload.Content,
-					},
-					{
-						"name":  "MessageID:",
-						"value": payload.MessageID,
-					},
-				},
-			},
-		},
-	}
+	return entities.WebhookFormatCloudEventsStructured
 }
 
 func (service *WebhookService) getAuthToken(webhook *entities.Webhook) (string, error) {
@@ -320,7 +800,7 @@ func (service *WebhookService) getAuthToken(webhook *entities.Webhook) (string,
 	return token.SignedString([]byte(webhook.SigningKey))
 }
 
-func (service *WebhookService) handleWebhookSendFailed(ctx context.Context, event cloudevents.Event, webhook *entities.Webhook, owner string, err error, response *http.Response) {
+func (service *WebhookService) handleWebhookSendFailed(ctx context.Context, event cloudevents.Event, webhook *entities.Webhook, owner string, statusCode *int, errorMessage string) {
 	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
 	defer span.End()
 
@@ -332,25 +812,11 @@ func (service *WebhookService) handleWebhookSendFailed(ctx context.Context, even
 		Owner:                  owner,
 		EventType:              event.Type(),
 		EventPayload:           string(event.Data()),
-		HTTPResponseStatusCode: nil,
-		ErrorMessage:           err.Error(),
-	}
-
-	if errors.Is(err, context.DeadlineExceeded) {
-		payload.ErrorMessage = "TIMOUT after 10 seconds"
-	}
-
-	if response != nil {
-		payload.HTTPResponseStatusCode = &response.StatusCode
-		payload.ErrorMessage = http.StatusText(response.StatusCode)
-
-		body, err := io.ReadAll(response.Body)
-		if err == nil && len(body) > 0 {
-			payload.ErrorMessage = string(body)
-		}
+		HTTPResponseStatusCode: statusCode,
+		ErrorMessage:           errorMessage,
 	}
 
-	event, err = service.createEvent(events.EventTypeWebhookSendFailed, event.Source(), payload)
+	event, err := service.createEvent(events.EventTypeWebhookSendFailed, event.Source(), payload)
 	if err != nil {
 		msg := fmt.Sprintf("cannot create event [%s] for user with id [%s]", events.EventTypeWebhookSendFailed, payload.UserID)
 		ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
@@ -0,0 +1,110 @@
+// Package signature creates and verifies the X-Httpsms-Signature header which is sent with webhook events.
+//
+// The header has the format "t=<unix timestamp>,v1=<hex encoded signature>" where the signature is the
+// HMAC-SHA256 of "<unix timestamp>.<raw request body>" using the signing key of the webhook.
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// HeaderName is the HTTP header containing the signature of a webhook request
+	HeaderName = "X-Httpsms-Signature"
+
+	// DefaultTolerance is the recommended maximum age of a signature
+	DefaultTolerance = 5 * time.Minute
+
+	timestampKey = "t"
+	signatureKey = "v1"
+)
+
+var (
+	// ErrInvalidHeader is returned when the header is not in the expected format
+	ErrInvalidHeader = errors.New("signature: header must have the format t=<timestamp>,v1=<signature>")
+
+	// ErrTimestampOutsideTolerance is returned when the signature is older or newer than the tolerance window
+	ErrTimestampOutsideTolerance = errors.New("signature: timestamp is outside the tolerance window")
+
+	// ErrNoValidSignature is returned when none of the signatures in the header match the payload
+	ErrNoValidSignature = errors.New("signature: no signature matches the payload")
+)
+
+// Sign returns the value of the HeaderName header for a payload signed at the given time
+func Sign(payload []byte, secret string, timestamp time.Time) string {
+	return fmt.Sprintf("%s=%d,%s=%s", timestampKey, timestamp.Unix(), signatureKey, hex.EncodeToString(compute(payload, secret, timestamp.Unix())))
+}
+
+// Verify checks that the header was created from the raw request body with the secret within the tolerance window.
+// A tolerance of 0 disables the timestamp check.
+func Verify(payload []byte, header string, secret string, tolerance time.Duration) error {
+	return verify(payload, header, secret, tolerance, time.Now())
+}
+
+func verify(payload []byte, header string, secret string, tolerance time.Duration, now time.Time) error {
+	timestamp, signatures, err := parse(header)
+	if err != nil {
+		return err
+	}
+
+	if tolerance > 0 {
+		age := now.Sub(time.Unix(timestamp, 0))
+		if age > tolerance || age < -tolerance {
+			return ErrTimestampOutsideTolerance
+		}
+	}
+
+	expected := compute(payload, secret, timestamp)
+	for _, signature := range signatures {
+		if hmac.Equal(expected, signature) {
+			return nil
+		}
+	}
+
+	return ErrNoValidSignature
+}
+
+func parse(header string) (timestamp int64, signatures [][]byte, err error) {
+	hasTimestamp := false
+	for _, part := range strings.Split(header, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			return 0, nil, ErrInvalidHeader
+		}
+
+		switch key {
+		case timestampKey:
+			if timestamp, err = strconv.ParseInt(value, 10, 64); err != nil {
+				return 0, nil, ErrInvalidHeader
+			}
+			hasTimestamp = true
+		case signatureKey:
+			signature, err := hex.DecodeString(value)
+			if err != nil {
+				continue
+			}
+			signatures = append(signatures, signature)
+		}
+	}
+
+	if !hasTimestamp || len(signatures) == 0 {
+		return 0, nil, ErrInvalidHeader
+	}
+
+	return timestamp, signatures, nil
+}
+
+func compute(payload []byte, secret string, timestamp int64) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
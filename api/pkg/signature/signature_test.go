@@ -0,0 +1,131 @@
+package signature
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSign(t *testing.T) {
+	t.Run("header contains the timestamp and signature", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		timestamp := time.Unix(1700000000, 0)
+
+		// Act
+		header := Sign([]byte(`{"id":"1"}`), "secret", timestamp)
+
+		// Assert
+		assert.True(t, strings.HasPrefix(header, "t=1700000000,v1="))
+		assert.Len(t, strings.TrimPrefix(header, "t=1700000000,v1="), 64)
+	})
+}
+
+func TestVerify(t *testing.T) {
+	payload := []byte(`{"id":"32343a19-da5e-4b1b-a767-3298a73703cb","type":"message.phone.received"}`)
+	now := time.Unix(1700000000, 0)
+
+	t.Run("valid signature is accepted", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		header := Sign(payload, "secret", now.Add(-time.Minute))
+
+		// Act
+		err := verify(payload, header, "secret", DefaultTolerance, now)
+
+		// Assert
+		assert.Nil(t, err)
+	})
+
+	t.Run("modified payload is rejected", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		header := Sign(payload, "secret", now)
+
+		// Act
+		err := verify([]byte(`{"id":"other"}`), header, "secret", DefaultTolerance, now)
+
+		// Assert
+		assert.ErrorIs(t, err, ErrNoValidSignature)
+	})
+
+	t.Run("wrong secret is rejected", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		header := Sign(payload, "secret", now)
+
+		// Act
+		err := verify(payload, header, "another-secret", DefaultTolerance, now)
+
+		// Assert
+		assert.ErrorIs(t, err, ErrNoValidSignature)
+	})
+
+	t.Run("old timestamp is rejected", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		header := Sign(payload, "secret", now.Add(-10*time.Minute))
+
+		// Act
+		err := verify(payload, header, "secret", DefaultTolerance, now)
+
+		// Assert
+		assert.ErrorIs(t, err, ErrTimestampOutsideTolerance)
+	})
+
+	t.Run("old timestamp is accepted when the tolerance is 0", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		header := Sign(payload, "secret", now.Add(-10*time.Hour))
+
+		// Act
+		err := verify(payload, header, "secret", 0, now)
+
+		// Assert
+		assert.Nil(t, err)
+	})
+
+	t.Run("one matching signature out of many is accepted", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		header := Sign(payload, "secret", now) + ",v1=" + strings.Repeat("0", 64)
+
+		// Act
+		err := verify(payload, header, "secret", DefaultTolerance, now)
+
+		// Assert
+		assert.Nil(t, err)
+	})
+
+	t.Run("malformed header is rejected", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		headers := []string{"", "v1=abc", "t=abc,v1=abc", "t=1700000000", "garbage"}
+
+		for _, header := range headers {
+			// Act
+			err := verify(payload, header, "secret", DefaultTolerance, now)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidHeader, header)
+		}
+	})
+}
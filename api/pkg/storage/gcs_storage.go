@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	gcs "cloud.google.com/go/storage"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+)
+
+// gcsStorage is the Storage implementation on a Google Cloud Storage bucket which is shared by all the instances of the API
+type gcsStorage struct {
+	tracer telemetry.Tracer
+	bucket *gcs.BucketHandle
+}
+
+// NewGCSStorage creates a new instance of gcsStorage which stores objects in the bucket
+func NewGCSStorage(tracer telemetry.Tracer, bucket *gcs.BucketHandle) Storage {
+	return &gcsStorage{
+		tracer: tracer,
+		bucket: bucket,
+	}
+}
+
+// Store uploads the content as an object in the bucket
+func (storage *gcsStorage) Store(ctx context.Context, key string, content io.Reader) error {
+	ctx, span := storage.tracer.Start(ctx)
+	defer span.End()
+
+	writer := storage.bucket.Object(key).NewWriter(ctx)
+	if _, err := io.Copy(writer, content); err != nil {
+		_ = writer.Close()
+		return storage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot write content for key [%s]", key)))
+	}
+
+	// the object is only created when the writer is closed so a reader never sees a partially written object
+	if err := writer.Close(); err != nil {
+		return storage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot upload object for key [%s]", key)))
+	}
+
+	return nil
+}
+
+// Load opens a reader for the object stored under the key
+func (storage *gcsStorage) Load(ctx context.Context, key string) (io.ReadCloser, error) {
+	ctx, span := storage.tracer.Start(ctx)
+	defer span.End()
+
+	reader, err := storage.bucket.Object(key).NewReader(ctx)
+	if err != nil {
+		return nil, storage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot open object for key [%s]", key)))
+	}
+
+	return reader, nil
+}
+
+// Delete removes the object stored under the key. It is not an error if the object does not exist.
+func (storage *gcsStorage) Delete(ctx context.Context, key string) error {
+	ctx, span := storage.tracer.Start(ctx)
+	defer span.End()
+
+	if err := storage.bucket.Object(key).Delete(ctx); err != nil && !errors.Is(err, gcs.ErrObjectNotExist) {
+		return storage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot delete object for key [%s]", key)))
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+)
+
+// localStorage is the Storage implementation on the local file system
+type localStorage struct {
+	tracer telemetry.Tracer
+	root   string
+}
+
+// NewLocalStorage creates a new instance of localStorage which stores objects in the root directory.
+// The current working directory is used when the root is empty.
+func NewLocalStorage(tracer telemetry.Tracer, root string) Storage {
+	if path, err := filepath.Abs(root); err == nil {
+		root = path
+	}
+	return &localStorage{
+		tracer: tracer,
+		root:   filepath.Clean(root),
+	}
+}
+
+// Store saves the content in a file under the root directory
+func (storage *localStorage) Store(ctx context.Context, key string, content io.Reader) error {
+	_, span := storage.tracer.Start(ctx)
+	defer span.End()
+
+	path, err := storage.path(key)
+	if err != nil {
+		return storage.tracer.WrapErrorSpan(span, err)
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return storage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot create directory for key [%s]", key)))
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return storage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot create temporary file for key [%s]", key)))
+	}
+	defer os.Remove(file.Name())
+
+	if _, err = io.Copy(file, content); err != nil {
+		_ = file.Close()
+		return storage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot write content for key [%s]", key)))
+	}
+
+	if err = file.Close(); err != nil {
+		return storage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot close file for key [%s]", key)))
+	}
+
+	// the file is renamed so a reader never sees a partially written object
+	if err = os.Rename(file.Name(), path); err != nil {
+		return storage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot move file for key [%s]", key)))
+	}
+
+	return nil
+}
+
+// Load opens the file stored under the key
+func (storage *localStorage) Load(ctx context.Context, key string) (io.ReadCloser, error) {
+	_, span := storage.tracer.Start(ctx)
+	defer span.End()
+
+	path, err := storage.path(key)
+	if err != nil {
+		return nil, storage.tracer.WrapErrorSpan(span, err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, storage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot open file for key [%s]", key)))
+	}
+
+	return file, nil
+}
+
+// Delete removes the file stored under the key. It is not an error if the file does not exist.
+func (storage *localStorage) Delete(ctx context.Context, key string) error {
+	_, span := storage.tracer.Start(ctx)
+	defer span.End()
+
+	path, err := storage.path(key)
+	if err != nil {
+		return storage.tracer.WrapErrorSpan(span, err)
+	}
+
+	if err = os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return storage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot delete file for key [%s]", key)))
+	}
+
+	return nil
+}
+
+// path converts the key into a file path and makes sure the path is within the root directory
+func (storage *localStorage) path(key string) (string, error) {
+	path := filepath.Join(storage.root, filepath.FromSlash(key))
+	if key == "" || !strings.HasPrefix(path, storage.root+string(filepath.Separator)) {
+		return "", stacktrace.NewError(fmt.Sprintf("the key [%s] is not a valid storage key", key))
+	}
+	return path, nil
+}
@@ -0,0 +1,18 @@
+package storage
+
+import (
+	"context"
+	"io"
+)
+
+// Storage stores binary objects like message attachments
+type Storage interface {
+	// Store saves the content under the key and replaces an existing object
+	Store(ctx context.Context, key string, content io.Reader) error
+
+	// Load returns the content stored under the key. The caller must close the reader.
+	Load(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes the object stored under the key
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,57 @@
+// Package templates fills the {{variable}} placeholders in the content of a message.
+package templates
+
+import (
+	"regexp"
+	"strings"
+)
+
+var placeholderRegex = regexp.MustCompile(`{{\s*([A-Za-z0-9_.\-]+)\s*}}`)
+
+// Placeholders returns the unique variable names used in the content in the order they first appear
+func Placeholders(content string) []string {
+	seen := map[string]bool{}
+	result := make([]string, 0)
+	for _, match := range placeholderRegex.FindAllStringSubmatch(content, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			result = append(result, match[1])
+		}
+	}
+	return result
+}
+
+// Missing returns the placeholders in the content which don't have a value in variables
+func Missing(content string, variables map[string]string) []string {
+	result := make([]string, 0)
+	for _, name := range Placeholders(content) {
+		if _, ok := lookup(variables, name); !ok {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// Render replaces the placeholders in the content with their values. Placeholders without a value are left unchanged.
+func Render(content string, variables map[string]string) string {
+	return placeholderRegex.ReplaceAllStringFunc(content, func(placeholder string) string {
+		value, ok := lookup(variables, placeholderRegex.FindStringSubmatch(placeholder)[1])
+		if !ok {
+			return placeholder
+		}
+		return value
+	})
+}
+
+// lookup finds a variable by name, falling back to a case-insensitive match so CSV headers like "FirstName" match {{firstname}}
+func lookup(variables map[string]string, name string) (string, bool) {
+	if value, ok := variables[name]; ok {
+		return value, true
+	}
+	for key, value := range variables {
+		if strings.EqualFold(strings.TrimSpace(key), name) {
+			return value, true
+		}
+	}
+	return "", false
+}
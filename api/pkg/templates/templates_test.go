@@ -0,0 +1,77 @@
+package templates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlaceholders(t *testing.T) {
+	t.Run("it returns unique placeholders in order", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		content := "Hi {{ name }}, your code is {{code}}. Bye {{name}}"
+
+		// Act
+		placeholders := Placeholders(content)
+
+		// Assert
+		assert.Equal(t, []string{"name", "code"}, placeholders)
+	})
+
+	t.Run("it returns an empty list when there are no placeholders", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Act
+		placeholders := Placeholders("Hello {world}")
+
+		// Assert
+		assert.Empty(t, placeholders)
+	})
+}
+
+func TestMissing(t *testing.T) {
+	t.Run("it returns the variables without a value", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		content := "Hi {{name}}, your code is {{code}}"
+
+		// Act
+		missing := Missing(content, map[string]string{"Name": "John"})
+
+		// Assert
+		assert.Equal(t, []string{"code"}, missing)
+	})
+}
+
+func TestRender(t *testing.T) {
+	t.Run("it replaces the placeholders with their values", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		content := "Hi {{ name }}, your code is {{code}}"
+
+		// Act
+		result := Render(content, map[string]string{"name": "John", "code": "1234"})
+
+		// Assert
+		assert.Equal(t, "Hi John, your code is 1234", result)
+	})
+
+	t.Run("it leaves placeholders without a value unchanged", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Act
+		result := Render("Hi {{name}}, your code is {{code}}", map[string]string{"name": "John"})
+
+		// Assert
+		assert.Equal(t, "Hi John, your code is {{code}}", result)
+	})
+}
@@ -0,0 +1,101 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/dustin/go-humanize"
+	"github.com/palantir/stacktrace"
+)
+
+// maxAttachmentSize is the largest file which carriers reliably deliver as an MMS message
+const maxAttachmentSize = 3 * 1024 * 1024
+
+// AttachmentHandlerValidator validates models used in handlers.AttachmentHandler
+type AttachmentHandlerValidator struct {
+	validator
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+}
+
+// NewAttachmentHandlerValidator creates a new handlers.AttachmentHandler validator
+func NewAttachmentHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+) (v *AttachmentHandlerValidator) {
+	return &AttachmentHandlerValidator{
+		logger: logger.WithService(fmt.Sprintf("%T", v)),
+		tracer: tracer,
+	}
+}
+
+// ValidateStore validates the uploaded attachment and returns its content type
+func (validator *AttachmentHandlerValidator) ValidateStore(ctx context.Context, header *multipart.FileHeader) (string, url.Values) {
+	_, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
+	defer span.End()
+
+	result := url.Values{}
+	if header.Size == 0 || header.Size > maxAttachmentSize {
+		result.Add("file", fmt.Sprintf("the file must be less than %s, the file you uploaded is [%s]", humanize.Bytes(maxAttachmentSize), humanize.Bytes(uint64(header.Size))))
+		return "", result
+	}
+
+	if len(header.Filename) > 255 {
+		result.Add("file", "the name of the file must be less than 255 characters")
+		return "", result
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot open file [%s] for reading", header.Filename)))
+		result.Add("file", fmt.Sprintf("cannot open the uploaded file with name [%s]", header.Filename))
+		return "", result
+	}
+	defer func() {
+		if e := file.Close(); e != nil {
+			ctxLogger.Error(stacktrace.Propagate(e, fmt.Sprintf("cannot close file [%s]", header.Filename)))
+		}
+	}()
+
+	// http.DetectContentType considers at most the first 512 bytes
+	buffer := make([]byte, 512)
+	n, err := io.ReadFull(file, buffer)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot read file [%s]", header.Filename)))
+		result.Add("file", fmt.Sprintf("cannot read the contents of the uploaded file [%s]", header.Filename))
+		return "", result
+	}
+
+	contentType := validator.contentType(header.Header.Get("Content-Type"), buffer[:n])
+	if !validator.isMediaType(contentType) {
+		result.Add("file", fmt.Sprintf("the file type [%s] is not supported, you can only upload images, videos, audio files and contact cards", contentType))
+		return "", result
+	}
+
+	return contentType, result
+}
+
+// contentType uses the declared content type unless the client did not send a specific one
+func (validator *AttachmentHandlerValidator) contentType(declared string, content []byte) string {
+	mediaType, _, err := mime.ParseMediaType(declared)
+	if err != nil || mediaType == "application/octet-stream" {
+		mediaType, _, _ = mime.ParseMediaType(http.DetectContentType(content))
+	}
+	return mediaType
+}
+
+func (validator *AttachmentHandlerValidator) isMediaType(contentType string) bool {
+	for _, prefix := range []string{"image/", "video/", "audio/"} {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return contentType == "text/vcard" || contentType == "text/x-vcard"
+}
@@ -0,0 +1,240 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+	"github.com/thedevsaddam/govalidator"
+)
+
+// AutoReplyHandlerValidator validates models used in handlers.AutoReplyHandler
+type AutoReplyHandlerValidator struct {
+	validator
+	logger          telemetry.Logger
+	tracer          telemetry.Tracer
+	phoneService    *services.PhoneService
+	templateService *services.MessageTemplateService
+}
+
+// NewAutoReplyHandlerValidator creates a new handlers.AutoReplyHandler validator
+func NewAutoReplyHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	phoneService *services.PhoneService,
+	templateService *services.MessageTemplateService,
+) (v *AutoReplyHandlerValidator) {
+	return &AutoReplyHandlerValidator{
+		logger:          logger.WithService(fmt.Sprintf("%T", v)),
+		tracer:          tracer,
+		phoneService:    phoneService,
+		templateService: templateService,
+	}
+}
+
+// ValidateIndex validates the requests.AutoReplyIndex request
+func (validator *AutoReplyHandlerValidator) ValidateIndex(_ context.Context, request requests.AutoReplyIndex) url.Values {
+	rules := govalidator.MapData{
+		"limit": []string{
+			"required",
+			"numeric",
+			"min:1",
+			"max:100",
+		},
+		"skip": []string{
+			"required",
+			"numeric",
+			"min:0",
+		},
+		"query": []string{
+			"max:100",
+		},
+	}
+
+	if request.Owner != "" {
+		rules["owner"] = []string{phoneNumberRule}
+	}
+
+	v := govalidator.New(govalidator.Options{
+		Data:  &request,
+		Rules: rules,
+	})
+	return v.ValidateStruct()
+}
+
+// ValidateStore validates the requests.AutoReplyStore request
+func (validator *AutoReplyHandlerValidator) ValidateStore(ctx context.Context, userID entities.UserID, request requests.AutoReplyStore) url.Values {
+	ctx, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
+	defer span.End()
+
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"owner": []string{
+				"required",
+				phoneNumberRule,
+			},
+			"name": []string{
+				"required",
+				"min:1",
+				"max:255",
+			},
+			"match": []string{
+				"required",
+				"in:" + strings.Join([]string{
+					entities.AutoReplyMatchKeyword.String(),
+					entities.AutoReplyMatchRegex.String(),
+					entities.AutoReplyMatchContact.String(),
+				}, ","),
+			},
+			"pattern": []string{
+				"required",
+				"min:1",
+				"max:255",
+			},
+			"action": []string{
+				"required",
+				"in:" + strings.Join([]string{
+					entities.AutoReplyActionReply.String(),
+					entities.AutoReplyActionTemplate.String(),
+					entities.AutoReplyActionForward.String(),
+					entities.AutoReplyActionTag.String(),
+					entities.AutoReplyActionWebhook.String(),
+				}, ","),
+			},
+			"cooldown_seconds": []string{
+				"min:60",
+				"max:86400",
+			},
+		},
+	})
+
+	result := v.ValidateStruct()
+	if len(result) != 0 {
+		return result
+	}
+
+	validator.validatePattern(result, request)
+	validator.validateWindow(result, request)
+	validator.validateAction(ctx, result, userID, request)
+	if len(result) != 0 {
+		return result
+	}
+
+	_, err := validator.phoneService.Load(ctx, userID, request.Owner)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		result.Add("owner", fmt.Sprintf("no phone found with the 'owner' number [%s]. install the android app on your phone to start receiving messages", request.Owner))
+		return result
+	}
+
+	if err != nil {
+		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("could not load phone for user [%s] and phone [%s]", userID, request.Owner))))
+		result.Add("owner", fmt.Sprintf("could not validate the 'owner' number [%s], please try again later", request.Owner))
+	}
+
+	return result
+}
+
+// ValidateUpdate validates the requests.AutoReplyUpdate request
+func (validator *AutoReplyHandlerValidator) ValidateUpdate(ctx context.Context, userID entities.UserID, request requests.AutoReplyUpdate) url.Values {
+	if result := validator.ValidateUUID(ctx, request.AutoReplyID, "autoReplyID"); len(result) != 0 {
+		return result
+	}
+	return validator.ValidateStore(ctx, userID, request.AutoReplyStore)
+}
+
+func (validator *AutoReplyHandlerValidator) validatePattern(result url.Values, request requests.AutoReplyStore) {
+	switch entities.AutoReplyMatch(request.Match) {
+	case entities.AutoReplyMatchRegex:
+		if _, err := regexp.Compile(request.Pattern); err != nil {
+			result.Add("pattern", fmt.Sprintf("the pattern [%s] is not a valid regular expression", request.Pattern))
+		}
+	case entities.AutoReplyMatchKeyword:
+		if len(strings.Fields(request.Pattern)) != 1 {
+			result.Add("pattern", fmt.Sprintf("the keyword [%s] must be a single word", request.Pattern))
+		}
+	case entities.AutoReplyMatchContact:
+		if !validator.isPhoneNumber(request.Pattern) {
+			result.Add("pattern", fmt.Sprintf("the pattern [%s] must be a valid phone number when matching a contact", request.Pattern))
+		}
+	}
+}
+
+func (validator *AutoReplyHandlerValidator) validateWindow(result url.Values, request requests.AutoReplyStore) {
+	if request.StartTime == "" && request.EndTime == "" {
+		return
+	}
+
+	if request.StartTime == "" || request.EndTime == "" {
+		result.Add("start_time", "the start_time and end_time fields must be set together")
+		return
+	}
+
+	for attribute, value := range map[string]string{"start_time": request.StartTime, "end_time": request.EndTime} {
+		if _, err := time.Parse("15:04", value); err != nil || len(value) != 5 {
+			result.Add(attribute, fmt.Sprintf("the %s [%s] must be in the HH:MM format e.g 09:00", attribute, value))
+		}
+	}
+}
+
+func (validator *AutoReplyHandlerValidator) validateAction(ctx context.Context, result url.Values, userID entities.UserID, request requests.AutoReplyStore) {
+	switch entities.AutoReplyAction(request.Action) {
+	case entities.AutoReplyActionReply:
+		if request.Content == "" || len(request.Content) > 1024 {
+			result.Add("content", "the content field is required for the reply action and must be less than 1024 characters")
+		}
+	case entities.AutoReplyActionForward:
+		if !validator.isPhoneNumber(request.ForwardTo) {
+			result.Add("forward_to", fmt.Sprintf("the forward_to field [%s] must be a valid phone number for the forward action", request.ForwardTo))
+		}
+	case entities.AutoReplyActionTag:
+		if request.Tag == "" || len(request.Tag) > 50 {
+			result.Add("tag", "the tag field is required for the tag action and must be less than 50 characters")
+		}
+	case entities.AutoReplyActionTemplate:
+		validator.validateTemplate(ctx, result, userID, request.TemplateID)
+	}
+}
+
+func (validator *AutoReplyHandlerValidator) validateTemplate(ctx context.Context, result url.Values, userID entities.UserID, templateID string) {
+	ctx, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
+	defer span.End()
+
+	id, err := uuid.Parse(templateID)
+	if err != nil {
+		result.Add("template_id", fmt.Sprintf("the template_id [%s] must be a valid UUID for the template action", templateID))
+		return
+	}
+
+	_, err = validator.templateService.Load(ctx, userID, id)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		result.Add("template_id", fmt.Sprintf("no message template found with ID [%s]", templateID))
+		return
+	}
+
+	if err != nil {
+		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("could not load message template [%s] for user [%s]", templateID, userID))))
+		result.Add("template_id", fmt.Sprintf("could not validate the message template [%s], please try again later", templateID))
+	}
+}
+
+func (validator *AutoReplyHandlerValidator) isPhoneNumber(value string) bool {
+	request := map[string]string{"phone_number": value}
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"phone_number": []string{"required", phoneNumberRule},
+		},
+	})
+	return len(v.ValidateStruct()) == 0
+}
@@ -45,6 +45,9 @@ func (validator *BillingHandlerValidator) ValidateHistory(_ context.Context, req
 				"numeric",
 				"min:0",
 			},
+			"cursor": []string{
+				cursorRule,
+			},
 		},
 	})
 	return v.ValidateStruct()
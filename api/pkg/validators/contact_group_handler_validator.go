@@ -0,0 +1,117 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/thedevsaddam/govalidator"
+)
+
+// contactGroupMaxPhoneNumbers is the maximum number of phone numbers in an entities.ContactGroup
+const contactGroupMaxPhoneNumbers = 10000
+
+// ContactGroupHandlerValidator validates models used in handlers.ContactGroupHandler
+type ContactGroupHandlerValidator struct {
+	validator
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+}
+
+// NewContactGroupHandlerValidator creates a new handlers.ContactGroupHandler validator
+func NewContactGroupHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+) (v *ContactGroupHandlerValidator) {
+	return &ContactGroupHandlerValidator{
+		logger: logger.WithService(fmt.Sprintf("%T", v)),
+		tracer: tracer,
+	}
+}
+
+// ValidateIndex validates the requests.ContactGroupIndex request
+func (validator *ContactGroupHandlerValidator) ValidateIndex(_ context.Context, request requests.ContactGroupIndex) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"limit": []string{
+				"required",
+				"numeric",
+				"min:1",
+				"max:100",
+			},
+			"skip": []string{
+				"required",
+				"numeric",
+				"min:0",
+			},
+			"query": []string{
+				"max:100",
+			},
+		},
+	})
+	return v.ValidateStruct()
+}
+
+// ValidateStore validates the requests.ContactGroupStore request
+func (validator *ContactGroupHandlerValidator) ValidateStore(_ context.Context, request requests.ContactGroupStore) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data:  &request,
+		Rules: validator.storeRules(),
+	})
+	return v.ValidateStruct()
+}
+
+// ValidateUpdate validates the requests.ContactGroupUpdate request
+func (validator *ContactGroupHandlerValidator) ValidateUpdate(_ context.Context, request requests.ContactGroupUpdate) url.Values {
+	rules := validator.storeRules()
+	rules["contactGroupID"] = []string{
+		"required",
+		"uuid",
+	}
+
+	v := govalidator.New(govalidator.Options{
+		Data:  &request,
+		Rules: rules,
+	})
+	return v.ValidateStruct()
+}
+
+// ValidateMembers validates the requests.ContactGroupMembers request
+func (validator *ContactGroupHandlerValidator) ValidateMembers(_ context.Context, request requests.ContactGroupMembers) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"phone_numbers": []string{
+				"required",
+				"min:1",
+				fmt.Sprintf("max:%d", contactGroupMaxPhoneNumbers),
+				multipleContactPhoneNumberRule,
+			},
+			"contactGroupID": []string{
+				"required",
+				"uuid",
+			},
+		},
+	})
+	return v.ValidateStruct()
+}
+
+func (validator *ContactGroupHandlerValidator) storeRules() govalidator.MapData {
+	return govalidator.MapData{
+		"name": []string{
+			"required",
+			"min:1",
+			"max:255",
+		},
+		"description": []string{
+			"max:1024",
+		},
+		"phone_numbers": []string{
+			fmt.Sprintf("max:%d", contactGroupMaxPhoneNumbers),
+			multipleContactPhoneNumberRule,
+		},
+	}
+}
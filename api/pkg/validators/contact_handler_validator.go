@@ -0,0 +1,270 @@
+package validators
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/url"
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/dustin/go-humanize"
+	"github.com/jszwec/csvutil"
+	"github.com/palantir/stacktrace"
+	"github.com/thedevsaddam/govalidator"
+)
+
+const (
+	contactMaxTags         = 20
+	contactMaxTagLength    = 64
+	contactMaxCustomFields = 50
+	contactMaxImportRows   = 5000
+)
+
+// ContactHandlerValidator validates models used in handlers.ContactHandler
+type ContactHandlerValidator struct {
+	validator
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+}
+
+// NewContactHandlerValidator creates a new handlers.ContactHandler validator
+func NewContactHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+) (v *ContactHandlerValidator) {
+	return &ContactHandlerValidator{
+		logger: logger.WithService(fmt.Sprintf("%T", v)),
+		tracer: tracer,
+	}
+}
+
+// ValidateIndex validates the requests.ContactIndex request
+func (validator *ContactHandlerValidator) ValidateIndex(_ context.Context, request requests.ContactIndex) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"limit": []string{
+				"required",
+				"numeric",
+				"min:1",
+				"max:100",
+			},
+			"skip": []string{
+				"required",
+				"numeric",
+				"min:0",
+			},
+			"query": []string{
+				"max:100",
+			},
+			"tag": []string{
+				"max:64",
+			},
+		},
+	})
+	return v.ValidateStruct()
+}
+
+// ValidateStore validates the requests.ContactStore request
+func (validator *ContactHandlerValidator) ValidateStore(_ context.Context, request requests.ContactStore) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data:  &request,
+		Rules: validator.storeRules(),
+	})
+
+	result := v.ValidateStruct()
+	if len(result) != 0 {
+		return result
+	}
+
+	return validator.validateContact(request)
+}
+
+// ValidateUpdate validates the requests.ContactUpdate request
+func (validator *ContactHandlerValidator) ValidateUpdate(_ context.Context, request requests.ContactUpdate) url.Values {
+	rules := validator.storeRules()
+	rules["contactID"] = []string{
+		"required",
+		"uuid",
+	}
+
+	v := govalidator.New(govalidator.Options{
+		Data:  &request,
+		Rules: rules,
+	})
+
+	result := v.ValidateStruct()
+	if len(result) != 0 {
+		return result
+	}
+
+	return validator.validateContact(request.ContactStore)
+}
+
+// ValidateImport validates the CSV file of contacts which is imported
+func (validator *ContactHandlerValidator) ValidateImport(ctx context.Context, userID entities.UserID, header *multipart.FileHeader) ([]*requests.ContactImport, url.Values) {
+	_, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
+	defer span.End()
+
+	rows, result := validator.parseCSV(ctxLogger, userID, header)
+	if len(result) != 0 {
+		return rows, result
+	}
+
+	if len(rows) == 0 {
+		result.Add("document", "The uploaded file doesn't contain any contacts. Make sure the file has the [Name] and [PhoneNumbers] columns.")
+		return rows, result
+	}
+
+	if len(rows) > contactMaxImportRows {
+		result.Add("document", fmt.Sprintf("The uploaded file must contain less than %d contacts.", contactMaxImportRows))
+		return rows, result
+	}
+
+	for index, row := range rows {
+		row.Sanitize()
+		errors := validator.validateContact(requests.ContactStore{
+			Name:         row.Name,
+			PhoneNumbers: row.PhoneNumberList(),
+			Tags:         strings.Split(row.Tags, requests.ContactImportSeparator),
+			CustomFields: row.CustomFields,
+			Notes:        row.Notes,
+		})
+		if row.Name == "" {
+			errors.Add("name", "The name field is required")
+		}
+
+		for _, messages := range errors {
+			for _, message := range messages {
+				result.Add("document", fmt.Sprintf("Row [%d]: %s", index+2, message))
+			}
+		}
+	}
+
+	return rows, result
+}
+
+func (validator *ContactHandlerValidator) storeRules() govalidator.MapData {
+	return govalidator.MapData{
+		"name": []string{
+			"required",
+			"min:1",
+			"max:255",
+		},
+		"phone_numbers": []string{
+			"required",
+			"min:1",
+			"max:10",
+			multipleContactPhoneNumberRule,
+		},
+		"notes": []string{
+			"max:2048",
+		},
+	}
+}
+
+func (validator *ContactHandlerValidator) validateContact(request requests.ContactStore) url.Values {
+	result := url.Values{}
+
+	if len(request.PhoneNumbers) == 0 {
+		result.Add("phone_numbers", "The phone_numbers field must contain at least 1 phone number")
+	}
+
+	if len(request.Tags) > contactMaxTags {
+		result.Add("tags", fmt.Sprintf("The tags field must contain less than %d tags", contactMaxTags))
+	}
+
+	for _, tag := range request.Tags {
+		if len(tag) > contactMaxTagLength {
+			result.Add("tags", fmt.Sprintf("The tag [%s] must be less than %d characters", tag, contactMaxTagLength))
+		}
+	}
+
+	if len(request.CustomFields) > contactMaxCustomFields {
+		result.Add("custom_fields", fmt.Sprintf("The custom_fields field must contain less than %d fields", contactMaxCustomFields))
+	}
+
+	for key, value := range request.CustomFields {
+		if len(key) > 64 || len(value) > 1024 {
+			result.Add("custom_fields", fmt.Sprintf("The custom field [%s] must have a name less than 64 characters and a value less than 1024 characters", key))
+		}
+	}
+
+	if len(request.Notes) > 2048 {
+		result.Add("notes", "The notes field must be less than 2048 characters")
+	}
+
+	return result
+}
+
+func (validator *ContactHandlerValidator) parseCSV(ctxLogger telemetry.Logger, userID entities.UserID, header *multipart.FileHeader) ([]*requests.ContactImport, url.Values) {
+	result := url.Values{}
+	if header.Header.Get("Content-Type") != "text/csv" && !strings.HasSuffix(header.Filename, ".csv") {
+		result.Add("document", fmt.Sprintf("The file [%s] is not a valid CSV file.", header.Filename))
+		return nil, result
+	}
+
+	if header.Size >= 5000000 {
+		result.Add("document", fmt.Sprintf("The CSV file must be less than 5 MB the file you uploaded is [%s].", humanize.Bytes(uint64(header.Size))))
+		return nil, result
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot open file [%s] for reading for user [%s]", header.Filename, userID)))
+		result.Add("document", fmt.Sprintf("Cannot open the uploaded file with name [%s].", header.Filename))
+		return nil, result
+	}
+	defer func() {
+		if e := file.Close(); e != nil {
+			ctxLogger.Error(stacktrace.Propagate(e, fmt.Sprintf("cannot close file [%s] for user [%s]", header.Filename, userID)))
+		}
+	}()
+
+	content := new(bytes.Buffer)
+	if _, err = io.Copy(content, file); err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot copy file [%s] to buffer for user [%s]", header.Filename, userID)))
+		result.Add("document", fmt.Sprintf("Cannot read the conents of the uploaded file [%s].", header.Filename))
+		return nil, result
+	}
+
+	decoder, err := csvutil.NewDecoder(csv.NewReader(content))
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot create decoder for file [%s] and user [%s]", header.Filename, userID)))
+		result.Add("document", fmt.Sprintf("Cannot read the conents of the uploaded file [%s].", header.Filename))
+		return nil, result
+	}
+
+	var rows []*requests.ContactImport
+	columns := decoder.Header()
+	for {
+		row := new(requests.ContactImport)
+		if err = decoder.Decode(row); errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot unmarshall row into type [%T] for file [%s] and user [%s]", row, header.Filename, userID)))
+			result.Add("document", fmt.Sprintf("Cannot read the conents of the uploaded file [%s].", header.Filename))
+			return nil, result
+		}
+
+		row.CustomFields = map[string]string{}
+		for _, column := range decoder.Unused() {
+			if name := strings.TrimSpace(columns[column]); name != "" {
+				row.CustomFields[name] = strings.TrimSpace(decoder.Record()[column])
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, result
+}
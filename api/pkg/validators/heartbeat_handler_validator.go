@@ -45,6 +45,9 @@ func (validator *HeartbeatHandlerValidator) ValidateIndex(_ context.Context, req
 				"numeric",
 				"min:0",
 			},
+			"cursor": []string{
+				cursorRule,
+			},
 			"query": []string{
 				"max:100",
 			},
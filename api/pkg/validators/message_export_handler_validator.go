@@ -0,0 +1,65 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/exports"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/thedevsaddam/govalidator"
+)
+
+// messageExportMaxContacts is the maximum number of contacts which can be used to filter an export
+const messageExportMaxContacts = 100
+
+// MessageExportHandlerValidator validates models used in handlers.MessageExportHandler
+type MessageExportHandlerValidator struct {
+	validator
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+}
+
+// NewMessageExportHandlerValidator creates a new handlers.MessageExportHandler validator
+func NewMessageExportHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+) (v *MessageExportHandlerValidator) {
+	return &MessageExportHandlerValidator{
+		logger: logger.WithService(fmt.Sprintf("%T", v)),
+		tracer: tracer,
+	}
+}
+
+// ValidateExport validates the requests.MessageExport request
+func (validator *MessageExportHandlerValidator) ValidateExport(_ context.Context, request requests.MessageExport) url.Values {
+	rules := govalidator.MapData{
+		"format": []string{
+			"required",
+			"in:" + strings.Join(exports.Formats(), ","),
+		},
+		"async": []string{
+			"in:true,false",
+		},
+	}
+
+	if request.Owner != "" {
+		rules["owner"] = []string{phoneNumberRule}
+	}
+
+	v := govalidator.New(govalidator.Options{
+		Data:  &request,
+		Rules: rules,
+	})
+
+	result := v.ValidateStruct()
+	validator.validateDateRange(result, request.StartDate, request.EndDate)
+
+	if contacts := request.ContactList(); len(contacts) > messageExportMaxContacts {
+		result.Add("contacts", fmt.Sprintf("you can filter the export by a maximum of %d contacts but %d were provided", messageExportMaxContacts, len(contacts)))
+	}
+
+	return result
+}
@@ -5,9 +5,14 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/segments"
 	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/templates"
+	"github.com/google/uuid"
 	"github.com/palantir/stacktrace"
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
@@ -17,12 +22,21 @@ import (
 	"github.com/thedevsaddam/govalidator"
 )
 
+// messageRescheduleMaxDelay is the furthest in the future a message can be rescheduled since the event is queued until the message is due
+const messageRescheduleMaxDelay = 30 * 24 * time.Hour
+
 // MessageHandlerValidator validates models used in handlers.MessageHandler
 type MessageHandlerValidator struct {
 	validator
-	logger       telemetry.Logger
-	tracer       telemetry.Tracer
-	phoneService *services.PhoneService
+	logger             telemetry.Logger
+	tracer             telemetry.Tracer
+	phoneService       *services.PhoneService
+	templateService    *services.MessageTemplateService
+	contactService     *services.ContactService
+	groupService       *services.ContactGroupService
+	suppressionService *services.SuppressionService
+	attachmentService  *services.AttachmentService
+	senderPoolService  *services.SenderPoolService
 }
 
 // NewMessageHandlerValidator creates a new handlers.MessageHandler validator
@@ -30,42 +44,66 @@ func NewMessageHandlerValidator(
 	logger telemetry.Logger,
 	tracer telemetry.Tracer,
 	phoneService *services.PhoneService,
+	templateService *services.MessageTemplateService,
+	contactService *services.ContactService,
+	groupService *services.ContactGroupService,
+	suppressionService *services.SuppressionService,
+	attachmentService *services.AttachmentService,
+	senderPoolService *services.SenderPoolService,
 ) (v *MessageHandlerValidator) {
 	return &MessageHandlerValidator{
-		logger:       logger.WithService(fmt.Sprintf("%T", v)),
-		tracer:       tracer,
-		phoneService: phoneService,
+		logger:             logger.WithService(fmt.Sprintf("%T", v)),
+		tracer:             tracer,
+		phoneService:       phoneService,
+		templateService:    templateService,
+		contactService:     contactService,
+		groupService:       groupService,
+		suppressionService: suppressionService,
+		attachmentService:  attachmentService,
+		senderPoolService:  senderPoolService,
 	}
 }
 
 // ValidateMessageReceive validates the requests.MessageReceive request
-func (validator MessageHandlerValidator) ValidateMessageReceive(_ context.Context, request requests.MessageReceive) url.Values {
-	v := govalidator.New(govalidator.Options{
-		Data: &request,
-		Rules: govalidator.MapData{
-			"to": []string{
-				"required",
-				phoneNumberRule,
-			},
-			"from": []string{
-				"required",
-			},
-			"content": []string{
-				"required",
-				"min:1",
-				"max:2048",
-			},
-			"sim": []string{
-				"required",
-				"in:" + strings.Join([]string{
-					string(entities.SIM1),
-					string(entities.SIM2),
-				}, ","),
-			},
+func (validator MessageHandlerValidator) ValidateMessageReceive(ctx context.Context, userID entities.UserID, request requests.MessageReceive) url.Values {
+	rules := govalidator.MapData{
+		"to": []string{
+			"required",
+			phoneNumberRule,
+		},
+		"from": []string{
+			"required",
+		},
+		"content": []string{
+			"required",
+			"min:1",
+			"max:2048",
 		},
+		"sim": []string{
+			"required",
+			"in:" + strings.Join([]string{
+				string(entities.SIM1),
+				string(entities.SIM2),
+			}, ","),
+		},
+	}
+
+	// an MMS message can have only pictures without text
+	if len(request.Attachments) > 0 {
+		rules["content"] = []string{"max:2048"}
+	}
+
+	v := govalidator.New(govalidator.Options{
+		Data:  &request,
+		Rules: rules,
 	})
 
-	return v.ValidateStruct()
+	result := v.ValidateStruct()
+	if len(result) != 0 {
+		return result
+	}
+
+	return validator.validateAttachments(ctx, userID, request.Attachments)
 }
 
 // ValidateMessageSend validates the requests.MessageSend request
@@ -75,26 +113,46 @@ func (validator MessageHandlerValidator) ValidateMessageSend(ctx context.Context
 
 	ctxLogger := validator.tracer.CtxLogger(validator.logger, span)
 
-	v := govalidator.New(govalidator.Options{
-		Data: &request,
-		Rules: govalidator.MapData{
-			"to": []string{
-				"required",
-				contactPhoneNumberRule,
-			},
-			"request_id": []string{
-				"max:255",
-			},
-			"from": []string{
-				"required",
-				phoneNumberRule,
-			},
-			"content": []string{
-				"required",
-				"min:1",
-				"max:2048",
-			},
+	rules := govalidator.MapData{
+		"to": []string{
+			"required",
+			contactPhoneNumberRule,
+		},
+		"request_id": []string{
+			"max:255",
 		},
+		"from": []string{
+			"required",
+			phoneNumberRule,
+		},
+		"content": []string{
+			"required",
+			"min:1",
+			"max:2048",
+		},
+	}
+
+	if request.TemplateID != "" {
+		delete(rules, "content")
+		rules["template_id"] = []string{"uuid"}
+	}
+
+	if request.To == "" && request.ContactID != "" {
+		delete(rules, "to")
+		rules["contact_id"] = []string{"uuid"}
+	}
+
+	if len(request.Attachments) > 0 && request.TemplateID == "" {
+		rules["content"] = []string{"max:2048"}
+	}
+
+	if request.IsSenderPool() {
+		rules["from"] = []string{"required", "uuid"}
+	}
+
+	v := govalidator.New(govalidator.Options{
+		Data:  &request,
+		Rules: rules,
 	})
 
 	result := v.ValidateStruct()
@@ -102,14 +160,46 @@ func (validator MessageHandlerValidator) ValidateMessageSend(ctx context.Context
 		return result
 	}
 
-	_, err := validator.phoneService.Load(ctx, userID, request.From)
+	content := request.Content
+	if request.TemplateID != "" {
+		if content, result = validator.validateTemplate(ctx, userID, request.TemplateID, request.Variables, 2048); len(result) != 0 {
+			return result
+		}
+	}
+
+	recipient := request.To
+	if request.To == "" {
+		if recipient, result = validator.validateContact(ctx, userID, request.ContactID); len(result) != 0 {
+			return result
+		}
+	}
+
+	if result = validator.validateSuppression(ctx, userID, recipient); len(result) != 0 {
+		return result
+	}
+
+	if result = validator.validateAttachments(ctx, userID, request.Attachments); len(result) != 0 {
+		return result
+	}
+
+	if request.IsSenderPool() {
+		return validator.validateSenderPool(ctx, userID, request.From, content, request.Encrypted)
+	}
+
+	phone, err := validator.phoneService.Load(ctx, userID, request.From)
 	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
 		result.Add("from", fmt.Sprintf("no phone found with with 'from' number [%s]. install the android app on your phone to start sending messages", request.From))
+		return result
 	}
 
 	if err != nil {
 		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("could not load phone for user [%s] and phone [%s]", userID, request.From))))
 		result.Add("from", fmt.Sprintf("could not validate 'from' number [%s], please try again later", request.From))
+		return result
+	}
+
+	if !request.Encrypted {
+		validator.validateSegments(result, phone, content)
 	}
 
 	return result
@@ -122,25 +212,119 @@ func (validator MessageHandlerValidator) ValidateMessageBulkSend(ctx context.Con
 
 	ctxLogger := validator.tracer.CtxLogger(validator.logger, span)
 
+	rules := govalidator.MapData{
+		"to": []string{
+			"required",
+			"max:1000",
+			"min:1",
+			multipleContactPhoneNumberRule,
+		},
+		"from": []string{
+			"required",
+			phoneNumberRule,
+		},
+		"content": []string{
+			"required",
+			"min:1",
+			"max:1024",
+		},
+	}
+
+	if request.TemplateID != "" {
+		delete(rules, "content")
+		rules["template_id"] = []string{"uuid"}
+	}
+
+	if len(request.To) == 0 && request.HasContactTargets() {
+		delete(rules, "to")
+	}
+
+	if request.IsSenderPool() {
+		rules["from"] = []string{"required", "uuid"}
+	}
+
 	v := govalidator.New(govalidator.Options{
-		Data: &request,
-		Rules: govalidator.MapData{
-			"to": []string{
-				"required",
-				"max:1000",
-				"min:1",
-				multipleContactPhoneNumberRule,
-			},
-			"from": []string{
-				"required",
-				phoneNumberRule,
-			},
-			"content": []string{
-				"required",
-				"min:1",
-				"max:1024",
-			},
+		Data:  &request,
+		Rules: rules,
+	})
+
+	result := v.ValidateStruct()
+	if len(result) != 0 {
+		return result
+	}
+
+	content := request.Content
+	if request.TemplateID != "" {
+		if content, result = validator.validateTemplate(ctx, userID, request.TemplateID, request.Variables, 1024); len(result) != 0 {
+			return result
+		}
+	}
+
+	if request.HasContactTargets() {
+		if result = validator.validateContactTargets(ctx, userID, request); len(result) != 0 {
+			return result
+		}
+	}
+
+	if request.IsSenderPool() {
+		return validator.validateSenderPool(ctx, userID, request.From, content, request.Encrypted)
+	}
+
+	phone, err := validator.phoneService.Load(ctx, userID, request.From)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		result.Add("from", fmt.Sprintf("no phone found with with 'from' number [%s]. Install the android app on your phone to start sending messages", request.From))
+		return result
+	}
+
+	if err != nil {
+		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("could not load phone for user [%s] and phone [%s]", userID, request.From))))
+		result.Add("from", fmt.Sprintf("could not validate 'from' number [%s], please try again later", request.From))
+		return result
+	}
+
+	if !request.Encrypted {
+		validator.validateSegments(result, phone, content)
+	}
+
+	return result
+}
+
+// ValidateMessageBroadcast validates the requests.MessageBroadcast request
+func (validator MessageHandlerValidator) ValidateMessageBroadcast(ctx context.Context, userID entities.UserID, request requests.MessageBroadcast) url.Values {
+	ctx, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
+	defer span.End()
+
+	rules := govalidator.MapData{
+		"contact_group_id": []string{
+			"required",
+			"uuid",
+		},
+		"request_id": []string{
+			"max:255",
+		},
+		"from": []string{
+			"required",
+			phoneNumberRule,
 		},
+		"content": []string{
+			"required",
+			"min:1",
+			"max:1024",
+		},
+	}
+
+	if request.TemplateID != "" {
+		delete(rules, "content")
+		rules["template_id"] = []string{"uuid"}
+	}
+
+	if request.IsSenderPool() {
+		rules["from"] = []string{"required", "uuid"}
+	}
+
+	v := govalidator.New(govalidator.Options{
+		Data:  &request,
+		Rules: rules,
 	})
 
 	result := v.ValidateStruct()
@@ -148,14 +332,251 @@ func (validator MessageHandlerValidator) ValidateMessageBulkSend(ctx context.Con
 		return result
 	}
 
-	_, err := validator.phoneService.Load(ctx, userID, request.From)
+	content := request.Content
+	if request.TemplateID != "" {
+		if content, result = validator.validateTemplate(ctx, userID, request.TemplateID, request.Variables, 1024); len(result) != 0 {
+			return result
+		}
+	}
+
+	group, err := validator.groupService.Load(ctx, userID, uuid.MustParse(request.ContactGroupID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		result.Add("contact_group_id", fmt.Sprintf("no contact group found with ID [%s]", request.ContactGroupID))
+		return result
+	}
+
+	if err != nil {
+		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("could not load contact group [%s] for user [%s]", request.ContactGroupID, userID))))
+		result.Add("contact_group_id", fmt.Sprintf("could not validate the contact group [%s], please try again later", request.ContactGroupID))
+		return result
+	}
+
+	if group.Size() == 0 {
+		result.Add("contact_group_id", fmt.Sprintf("the contact group [%s] does not have any phone numbers", group.Name))
+		return result
+	}
+
+	if request.IsSenderPool() {
+		return validator.validateSenderPool(ctx, userID, request.From, content, request.Encrypted)
+	}
+
+	phone, err := validator.phoneService.Load(ctx, userID, request.From)
 	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
 		result.Add("from", fmt.Sprintf("no phone found with with 'from' number [%s]. Install the android app on your phone to start sending messages", request.From))
+		return result
 	}
 
 	if err != nil {
 		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("could not load phone for user [%s] and phone [%s]", userID, request.From))))
 		result.Add("from", fmt.Sprintf("could not validate 'from' number [%s], please try again later", request.From))
+		return result
+	}
+
+	if !request.Encrypted {
+		validator.validateSegments(result, phone, content)
+	}
+
+	return result
+}
+
+// validateTemplate checks that the entities.MessageTemplate exists and that all its variables have a value
+func (validator MessageHandlerValidator) validateTemplate(ctx context.Context, userID entities.UserID, templateID string, variables map[string]string, maxLength int) (string, url.Values) {
+	ctx, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
+	defer span.End()
+
+	result := url.Values{}
+	template, err := validator.templateService.Load(ctx, userID, uuid.MustParse(templateID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		result.Add("template_id", fmt.Sprintf("no message template found with ID [%s]", templateID))
+		return "", result
+	}
+
+	if err != nil {
+		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("could not load message template [%s] for user [%s]", templateID, userID))))
+		result.Add("template_id", fmt.Sprintf("could not validate the message template [%s], please try again later", templateID))
+		return "", result
+	}
+
+	if missing := templates.Missing(template.Content, variables); len(missing) != 0 {
+		result.Add("variables", fmt.Sprintf("the message template [%s] requires values for the variables [%s]", template.Name, strings.Join(missing, ", ")))
+		return "", result
+	}
+
+	content := templates.Render(template.Content, variables)
+	if utf8.RuneCountInString(content) > maxLength {
+		result.Add("variables", fmt.Sprintf("the content of the message template [%s] must be less than %d characters after adding the variables", template.Name, maxLength))
+	}
+
+	return content, result
+}
+
+// validateAttachments checks that there are at most 10 attachments and that each of them belongs to the user
+func (validator MessageHandlerValidator) validateAttachments(ctx context.Context, userID entities.UserID, attachmentIDs []string) url.Values {
+	ctx, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
+	defer span.End()
+
+	result := url.Values{}
+	if len(attachmentIDs) > 10 {
+		result.Add("attachments", "a message can have a maximum of 10 attachments")
+		return result
+	}
+
+	for _, attachmentID := range attachmentIDs {
+		id, err := uuid.Parse(attachmentID)
+		if err != nil {
+			result.Add("attachments", fmt.Sprintf("the attachment ID [%s] must be a valid UUID", attachmentID))
+			continue
+		}
+
+		_, err = validator.attachmentService.Load(ctx, userID, id)
+		if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+			result.Add("attachments", fmt.Sprintf("no attachment found with ID [%s]", attachmentID))
+			continue
+		}
+
+		if err != nil {
+			ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("could not load attachment [%s] for user [%s]", attachmentID, userID))))
+			result.Add("attachments", fmt.Sprintf("could not validate the attachment [%s], please try again later", attachmentID))
+		}
+	}
+
+	return result
+}
+
+func (validator MessageHandlerValidator) validateSegments(result url.Values, phone *entities.Phone, content string) {
+	if phone.MaxSegments == 0 {
+		return
+	}
+
+	if analysis := segments.Analyze(content); uint(analysis.Segments) > phone.MaxSegments {
+		result.Add("content", fmt.Sprintf("the content needs [%d] %s SMS segments but the phone [%s] allows a maximum of [%d] segments", analysis.Segments, analysis.Encoding, phone.PhoneNumber, phone.MaxSegments))
+	}
+}
+
+// validateSenderPool checks that the sender pool has a registered phone and that every phone in the pool can send the content
+func (validator MessageHandlerValidator) validateSenderPool(ctx context.Context, userID entities.UserID, poolID string, content string, encrypted bool) url.Values {
+	ctx, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
+	defer span.End()
+
+	result := url.Values{}
+	phones, err := validator.senderPoolService.Phones(ctx, userID, uuid.MustParse(poolID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		result.Add("from", fmt.Sprintf("no sender pool found with ID [%s]", poolID))
+		return result
+	}
+
+	if err != nil {
+		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("could not load sender pool [%s] for user [%s]", poolID, userID))))
+		result.Add("from", fmt.Sprintf("could not validate the sender pool [%s], please try again later", poolID))
+		return result
+	}
+
+	if len(phones) == 0 {
+		result.Add("from", fmt.Sprintf("the sender pool [%s] doesn't contain any phone which is registered on your account", poolID))
+		return result
+	}
+
+	if encrypted {
+		return result
+	}
+
+	for _, phone := range phones {
+		if validator.validateSegments(result, phone, content); len(result) != 0 {
+			break
+		}
+	}
+
+	return result
+}
+
+// validateContact checks that the entities.Contact used as the recipient exists and returns its phone number
+func (validator MessageHandlerValidator) validateContact(ctx context.Context, userID entities.UserID, contactID string) (string, url.Values) {
+	ctx, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
+	defer span.End()
+
+	result := url.Values{}
+	contact, err := validator.contactService.Load(ctx, userID, uuid.MustParse(contactID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		result.Add("contact_id", fmt.Sprintf("no contact found with ID [%s]", contactID))
+		return "", result
+	}
+
+	if err != nil {
+		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("could not load contact [%s] for user [%s]", contactID, userID))))
+		result.Add("contact_id", fmt.Sprintf("could not validate the contact [%s], please try again later", contactID))
+		return "", result
+	}
+
+	if contact.PhoneNumber() == "" {
+		result.Add("contact_id", fmt.Sprintf("the contact [%s] does not have a phone number", contact.Name))
+	}
+
+	return contact.PhoneNumber(), result
+}
+
+// validateSuppression checks that the recipient has not opted out of receiving messages from the user
+func (validator MessageHandlerValidator) validateSuppression(ctx context.Context, userID entities.UserID, recipient string) url.Values {
+	ctx, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
+	defer span.End()
+
+	result := url.Values{}
+	suppressed, err := validator.suppressionService.IsSuppressed(ctx, userID, recipient)
+	if err != nil {
+		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("could not check if [%s] is suppressed for user [%s]", recipient, userID))))
+		result.Add("to", fmt.Sprintf("could not validate the recipient [%s], please try again later", recipient))
+		return result
+	}
+
+	if suppressed {
+		result.Add("to", fmt.Sprintf("the recipient [%s] opted out of receiving messages from you", recipient))
+	}
+
+	return result
+}
+
+// validateContactTargets checks that the contact IDs and tags resolve to at most 1000 recipients
+func (validator MessageHandlerValidator) validateContactTargets(ctx context.Context, userID entities.UserID, request requests.MessageBulkSend) url.Values {
+	ctx, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
+	defer span.End()
+
+	result := url.Values{}
+	for _, contactID := range request.ContactIDs {
+		if _, err := uuid.Parse(contactID); err != nil {
+			result.Add("contact_ids", fmt.Sprintf("the contact ID [%s] must be a valid UUID", contactID))
+		}
+	}
+	if len(result) != 0 {
+		return result
+	}
+
+	contacts, err := validator.contactService.Targets(ctx, request.ToContactTargetParams(userID))
+	if err != nil {
+		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("could not fetch contact targets for user [%s]", userID))))
+		result.Add("contact_ids", "could not validate the contacts, please try again later")
+		return result
+	}
+
+	found := map[string]bool{}
+	for _, contact := range contacts {
+		found[contact.ID.String()] = true
+	}
+
+	for _, contactID := range request.ContactIDs {
+		if !found[uuid.MustParse(contactID).String()] {
+			result.Add("contact_ids", fmt.Sprintf("no contact found with ID [%s]", contactID))
+		}
+	}
+	if len(result) != 0 {
+		return result
+	}
+
+	request.AddContacts(contacts)
+	if len(request.To) == 0 {
+		result.Add("tags", fmt.Sprintf("no contacts with a phone number found with the tags [%s]", strings.Join(request.Tags, ", ")))
+	}
+
+	if len(request.To) > 1000 {
+		result.Add("to", fmt.Sprintf("the request contains [%d] recipients which is more than the maximum of 1000", len(request.To)))
 	}
 
 	return result
@@ -191,6 +612,9 @@ func (validator MessageHandlerValidator) ValidateMessageIndex(_ context.Context,
 				"numeric",
 				"min:0",
 			},
+			"cursor": []string{
+				cursorRule,
+			},
 			"contact": []string{
 				"required",
 				"min:1",
@@ -207,6 +631,66 @@ func (validator MessageHandlerValidator) ValidateMessageIndex(_ context.Context,
 	return v.ValidateStruct()
 }
 
+// ValidateMessageSearch validates the requests.MessageSearch request
+func (validator MessageHandlerValidator) ValidateMessageSearch(_ context.Context, request requests.MessageSearch) url.Values {
+	rules := govalidator.MapData{
+		"limit": []string{
+			"required",
+			"numeric",
+			"min:1",
+			"max:100",
+		},
+		"query": []string{
+			"max:100",
+		},
+		"status": []string{
+			"in:" + strings.Join([]string{
+				entities.MessageStatusPending,
+				entities.MessageStatusScheduled,
+				entities.MessageStatusSending,
+				entities.MessageStatusSent,
+				entities.MessageStatusReceived,
+				entities.MessageStatusFailed,
+				entities.MessageStatusDelivered,
+				entities.MessageStatusExpired,
+				entities.MessageStatusCancelled,
+			}, ","),
+		},
+		"type": []string{
+			"in:" + strings.Join([]string{
+				entities.MessageTypeMobileTerminated,
+				entities.MessageTypeMobileOriginated,
+				entities.MessageTypeCallMissed,
+			}, ","),
+		},
+		"encrypted": []string{
+			"in:true,false",
+		},
+		"cursor": []string{
+			cursorRule,
+		},
+	}
+
+	if request.Owner != "" {
+		rules["owner"] = []string{phoneNumberRule}
+	}
+
+	v := govalidator.New(govalidator.Options{
+		Data:  &request,
+		Rules: rules,
+	})
+
+	result := v.ValidateStruct()
+
+	validator.validateDateRange(result, request.StartDate, request.EndDate)
+
+	if request.Query != "" && request.Encrypted == "true" {
+		result.Add("query", "encrypted messages cannot be searched by content, remove the query or set encrypted=false")
+	}
+
+	return result
+}
+
 // ValidateMessageEvent validates the requests.MessageEvent request
 func (validator MessageHandlerValidator) ValidateMessageEvent(_ context.Context, request requests.MessageEvent) url.Values {
 	v := govalidator.New(govalidator.Options{
@@ -229,6 +713,51 @@ func (validator MessageHandlerValidator) ValidateMessageEvent(_ context.Context,
 	return v.ValidateStruct()
 }
 
+// ValidateMessageAnalyze validates the requests.MessageAnalyze request
+func (validator MessageHandlerValidator) ValidateMessageAnalyze(_ context.Context, request requests.MessageAnalyze) url.Values {
+	rules := govalidator.MapData{
+		"content": []string{
+			"required",
+			"min:1",
+			"max:2048",
+		},
+	}
+
+	if request.From != "" {
+		rules["from"] = []string{phoneNumberRule}
+	}
+
+	v := govalidator.New(govalidator.Options{
+		Data:  &request,
+		Rules: rules,
+	})
+	return v.ValidateStruct()
+}
+
+// ValidateMessageReschedule validates the requests.MessageReschedule request
+func (validator MessageHandlerValidator) ValidateMessageReschedule(_ context.Context, request requests.MessageReschedule) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"messageID": []string{
+				"required",
+				"uuid",
+			},
+		},
+	})
+
+	result := v.ValidateStruct()
+	if request.SendAt.IsZero() || !request.SendAt.After(time.Now().UTC()) {
+		result.Add("send_at", "the send_at field must be a time in the future")
+	}
+
+	if request.SendAt.After(time.Now().UTC().Add(messageRescheduleMaxDelay)) {
+		result.Add("send_at", fmt.Sprintf("the send_at field must be a time within the next %d days", int(messageRescheduleMaxDelay.Hours()/24)))
+	}
+
+	return result
+}
+
 // ValidateCallMissed validates the requests.MessageCallMissed request
 func (validator MessageHandlerValidator) ValidateCallMissed(_ context.Context, request requests.MessageCallMissed) url.Values {
 	v := govalidator.New(govalidator.Options{
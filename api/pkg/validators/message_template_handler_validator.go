@@ -0,0 +1,97 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/thedevsaddam/govalidator"
+)
+
+// MessageTemplateHandlerValidator validates models used in handlers.MessageTemplateHandler
+type MessageTemplateHandlerValidator struct {
+	validator
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+}
+
+// NewMessageTemplateHandlerValidator creates a new handlers.MessageTemplateHandler validator
+func NewMessageTemplateHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+) (v *MessageTemplateHandlerValidator) {
+	return &MessageTemplateHandlerValidator{
+		logger: logger.WithService(fmt.Sprintf("%T", v)),
+		tracer: tracer,
+	}
+}
+
+// ValidateIndex validates the requests.MessageTemplateIndex request
+func (validator *MessageTemplateHandlerValidator) ValidateIndex(_ context.Context, request requests.MessageTemplateIndex) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"limit": []string{
+				"required",
+				"numeric",
+				"min:1",
+				"max:100",
+			},
+			"skip": []string{
+				"required",
+				"numeric",
+				"min:0",
+			},
+			"query": []string{
+				"max:100",
+			},
+		},
+	})
+	return v.ValidateStruct()
+}
+
+// ValidateStore validates the requests.MessageTemplateStore request
+func (validator *MessageTemplateHandlerValidator) ValidateStore(_ context.Context, request requests.MessageTemplateStore) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"name": []string{
+				"required",
+				"min:1",
+				"max:255",
+			},
+			"content": []string{
+				"required",
+				"min:1",
+				"max:2048",
+			},
+		},
+	})
+	return v.ValidateStruct()
+}
+
+// ValidateUpdate validates the requests.MessageTemplateUpdate request
+func (validator *MessageTemplateHandlerValidator) ValidateUpdate(_ context.Context, request requests.MessageTemplateUpdate) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"name": []string{
+				"required",
+				"min:1",
+				"max:255",
+			},
+			"content": []string{
+				"required",
+				"min:1",
+				"max:2048",
+			},
+			"messageTemplateID": []string{
+				"required",
+				"uuid",
+			},
+		},
+	})
+	return v.ValidateStruct()
+}
@@ -44,6 +44,9 @@ func (validator *MessageThreadHandlerValidator) ValidateMessageThreadIndex(_ con
 				"numeric",
 				"min:0",
 			},
+			"cursor": []string{
+				cursorRule,
+			},
 			"is_archived": []string{
 				"required",
 				"in:true,false",
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
 
@@ -47,6 +48,9 @@ func (validator *PhoneHandlerValidator) ValidateIndex(_ context.Context, request
 				"numeric",
 				"min:0",
 			},
+			"cursor": []string{
+				cursorRule,
+			},
 			"query": []string{
 				"max:100",
 			},
@@ -96,9 +100,100 @@ func (validator *PhoneHandlerValidator) ValidateUpsert(_ context.Context, reques
 		result.Add("message_expiration_seconds", "message_expiration_seconds cannot be 0 when max_send_attempts is greater than 0")
 	}
 
+	validator.validateKeywords(result, "opt_out_keywords", request.OptOutKeywords)
+	validator.validateKeywords(result, "opt_in_keywords", request.OptInKeywords)
+	validator.validateBusinessHours(result, request.BusinessHours)
+	validator.validateHolidays(result, request.Holidays)
+	validator.validateQuietHours(result, request.QuietHoursStart, request.QuietHoursEnd)
+
+	if request.MaxSegments != nil && *request.MaxSegments > 10 {
+		result.Add("max_segments", "the max_segments field must be between 0 and 10")
+	}
+
+	if request.OutOfOfficeReply != nil && len(*request.OutOfOfficeReply) > 1024 {
+		result.Add("out_of_office_reply", "the out_of_office_reply field must be less than 1024 characters")
+	}
+
 	return result
 }
 
+func (validator *PhoneHandlerValidator) validateBusinessHours(result url.Values, businessHours *[]entities.BusinessHours) {
+	if businessHours == nil {
+		return
+	}
+
+	if len(*businessHours) > 21 {
+		result.Add("business_hours", "the business_hours field cannot contain more than 21 entries")
+	}
+
+	for _, hours := range *businessHours {
+		if hours.Day < time.Sunday || hours.Day > time.Saturday {
+			result.Add("business_hours", fmt.Sprintf("the day [%d] must be between 0 (Sunday) and 6 (Saturday)", hours.Day))
+			continue
+		}
+
+		start, startErr := time.Parse("15:04", hours.Start)
+		end, endErr := time.Parse("15:04", hours.End)
+		if startErr != nil || endErr != nil || len(hours.Start) != 5 || len(hours.End) != 5 {
+			result.Add("business_hours", fmt.Sprintf("the business hours on [%s] must be in the HH:MM format e.g 09:00", hours.Day))
+			continue
+		}
+
+		if !start.Before(end) {
+			result.Add("business_hours", fmt.Sprintf("the start [%s] must be before the end [%s] of the business hours on [%s]", hours.Start, hours.End, hours.Day))
+		}
+	}
+}
+
+func (validator *PhoneHandlerValidator) validateHolidays(result url.Values, holidays *[]string) {
+	if holidays == nil {
+		return
+	}
+
+	if len(*holidays) > 100 {
+		result.Add("holidays", "the holidays field cannot contain more than 100 dates")
+	}
+
+	for _, holiday := range *holidays {
+		if _, err := time.Parse(time.DateOnly, holiday); err != nil {
+			result.Add("holidays", fmt.Sprintf("the holiday [%s] must be a date in the YYYY-MM-DD format", holiday))
+		}
+	}
+}
+
+func (validator *PhoneHandlerValidator) validateQuietHours(result url.Values, start *string, end *string) {
+	if start == nil && end == nil {
+		return
+	}
+
+	if start == nil || end == nil {
+		result.Add("quiet_hours_start", "the quiet_hours_start and quiet_hours_end fields must be set together")
+		return
+	}
+
+	for attribute, value := range map[string]string{"quiet_hours_start": *start, "quiet_hours_end": *end} {
+		if _, err := time.Parse("15:04", value); err != nil || len(value) != 5 {
+			result.Add(attribute, fmt.Sprintf("the %s [%s] must be in the HH:MM format e.g 21:00", attribute, value))
+		}
+	}
+}
+
+func (validator *PhoneHandlerValidator) validateKeywords(result url.Values, attribute string, keywords *[]string) {
+	if keywords == nil {
+		return
+	}
+
+	if len(*keywords) > 10 {
+		result.Add(attribute, fmt.Sprintf("the %s field cannot contain more than 10 keywords", attribute))
+	}
+
+	for _, keyword := range *keywords {
+		if len(keyword) > 20 {
+			result.Add(attribute, fmt.Sprintf("the keyword [%s] must be less than 20 characters", keyword))
+		}
+	}
+}
+
 // ValidateDelete ValidateUpsert validates requests.PhoneDelete
 func (validator *PhoneHandlerValidator) ValidateDelete(_ context.Context, request requests.PhoneDelete) url.Values {
 	v := govalidator.New(govalidator.Options{
@@ -0,0 +1,193 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+	"github.com/thedevsaddam/govalidator"
+)
+
+// scheduledMessageMinInterval is the shortest duration allowed between 2 occurrences of an entities.ScheduledMessage
+const scheduledMessageMinInterval = 15 * time.Minute
+
+// ScheduledMessageHandlerValidator validates models used in handlers.ScheduledMessageHandler
+type ScheduledMessageHandlerValidator struct {
+	validator
+	logger            telemetry.Logger
+	tracer            telemetry.Tracer
+	phoneService      *services.PhoneService
+	senderPoolService *services.SenderPoolService
+}
+
+// NewScheduledMessageHandlerValidator creates a new handlers.ScheduledMessageHandler validator
+func NewScheduledMessageHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	phoneService *services.PhoneService,
+	senderPoolService *services.SenderPoolService,
+) (v *ScheduledMessageHandlerValidator) {
+	return &ScheduledMessageHandlerValidator{
+		logger:            logger.WithService(fmt.Sprintf("%T", v)),
+		tracer:            tracer,
+		phoneService:      phoneService,
+		senderPoolService: senderPoolService,
+	}
+}
+
+// ValidateIndex validates the requests.ScheduledMessageIndex request
+func (validator *ScheduledMessageHandlerValidator) ValidateIndex(_ context.Context, request requests.ScheduledMessageIndex) url.Values {
+	rules := govalidator.MapData{
+		"limit": []string{
+			"required",
+			"numeric",
+			"min:1",
+			"max:100",
+		},
+		"skip": []string{
+			"required",
+			"numeric",
+			"min:0",
+		},
+		"query": []string{
+			"max:100",
+		},
+	}
+
+	if request.Owner != "" {
+		rules["owner"] = []string{phoneNumberRule}
+	}
+
+	v := govalidator.New(govalidator.Options{
+		Data:  &request,
+		Rules: rules,
+	})
+	return v.ValidateStruct()
+}
+
+// ValidateStore validates the requests.ScheduledMessageStore request
+func (validator *ScheduledMessageHandlerValidator) ValidateStore(ctx context.Context, userID entities.UserID, request requests.ScheduledMessageStore) url.Values {
+	ctx, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
+	defer span.End()
+
+	rules := govalidator.MapData{
+		"from": []string{
+			"required",
+			phoneNumberRule,
+		},
+		"to": []string{
+			"required",
+			contactPhoneNumberRule,
+		},
+		"content": []string{
+			"required",
+			"min:1",
+			"max:2048",
+		},
+		"format": []string{
+			"required",
+			"in:" + strings.Join([]string{
+				entities.ScheduledMessageFormatCron.String(),
+				entities.ScheduledMessageFormatRRule.String(),
+			}, ","),
+		},
+		"expression": []string{
+			"required",
+			"max:255",
+		},
+	}
+
+	poolID := services.SenderPoolID(request.From)
+	if poolID != nil {
+		rules["from"] = []string{"required", "uuid"}
+	}
+
+	v := govalidator.New(govalidator.Options{
+		Data:  &request,
+		Rules: rules,
+	})
+
+	result := v.ValidateStruct()
+	if len(result) != 0 {
+		return result
+	}
+
+	if validator.validateExpression(result, request); len(result) != 0 {
+		return result
+	}
+
+	if poolID != nil {
+		return validator.validateSenderPool(ctx, userID, *poolID)
+	}
+
+	_, err := validator.phoneService.Load(ctx, userID, request.From)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		result.Add("from", fmt.Sprintf("no phone found with the 'from' number [%s]. install the android app on your phone to start sending messages", request.From))
+		return result
+	}
+
+	if err != nil {
+		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("could not load phone for user [%s] and phone [%s]", userID, request.From))))
+		result.Add("from", fmt.Sprintf("could not validate the 'from' number [%s], please try again later", request.From))
+	}
+
+	return result
+}
+
+// validateSenderPool checks that the sender pool has a registered phone
+func (validator *ScheduledMessageHandlerValidator) validateSenderPool(ctx context.Context, userID entities.UserID, poolID uuid.UUID) url.Values {
+	ctx, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
+	defer span.End()
+
+	result := url.Values{}
+	phones, err := validator.senderPoolService.Phones(ctx, userID, poolID)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		result.Add("from", fmt.Sprintf("no sender pool found with ID [%s]", poolID))
+		return result
+	}
+
+	if err != nil {
+		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("could not load sender pool [%s] for user [%s]", poolID, userID))))
+		result.Add("from", fmt.Sprintf("could not validate the sender pool [%s], please try again later", poolID))
+		return result
+	}
+
+	if len(phones) == 0 {
+		result.Add("from", fmt.Sprintf("the sender pool [%s] doesn't contain any phone which is registered on your account", poolID))
+	}
+
+	return result
+}
+
+func (validator *ScheduledMessageHandlerValidator) validateExpression(result url.Values, request requests.ScheduledMessageStore) {
+	schedule := &entities.ScheduledMessage{
+		Format:     entities.ScheduledMessageFormat(request.Format),
+		Expression: request.Expression,
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	first, err := schedule.NextOccurrence(time.Now().UTC())
+	if err != nil {
+		result.Add("expression", fmt.Sprintf("the expression [%s] is not a valid %s expression", request.Expression, request.Format))
+		return
+	}
+
+	if first == nil {
+		result.Add("expression", fmt.Sprintf("the expression [%s] has no occurrences in the future", request.Expression))
+		return
+	}
+
+	second, err := schedule.NextOccurrence(*first)
+	if err == nil && second != nil && second.Sub(*first) < scheduledMessageMinInterval {
+		result.Add("expression", fmt.Sprintf("the occurrences of the expression [%s] must be at least %s apart", request.Expression, scheduledMessageMinInterval))
+	}
+}
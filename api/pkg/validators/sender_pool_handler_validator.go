@@ -0,0 +1,122 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+	"github.com/thedevsaddam/govalidator"
+)
+
+// senderPoolMaxPhoneNumbers is the maximum number of phones in an entities.SenderPool
+const senderPoolMaxPhoneNumbers = 20
+
+// SenderPoolHandlerValidator validates models used in handlers.SenderPoolHandler
+type SenderPoolHandlerValidator struct {
+	validator
+	logger       telemetry.Logger
+	tracer       telemetry.Tracer
+	phoneService *services.PhoneService
+}
+
+// NewSenderPoolHandlerValidator creates a new handlers.SenderPoolHandler validator
+func NewSenderPoolHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	phoneService *services.PhoneService,
+) (v *SenderPoolHandlerValidator) {
+	return &SenderPoolHandlerValidator{
+		logger:       logger.WithService(fmt.Sprintf("%T", v)),
+		tracer:       tracer,
+		phoneService: phoneService,
+	}
+}
+
+// ValidateIndex validates the requests.SenderPoolIndex request
+func (validator *SenderPoolHandlerValidator) ValidateIndex(_ context.Context, request requests.SenderPoolIndex) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"limit": []string{
+				"required",
+				"numeric",
+				"min:1",
+				"max:100",
+			},
+			"skip": []string{
+				"required",
+				"numeric",
+				"min:0",
+			},
+			"query": []string{
+				"max:100",
+			},
+		},
+	})
+	return v.ValidateStruct()
+}
+
+// ValidateStore validates the requests.SenderPoolStore request
+func (validator *SenderPoolHandlerValidator) ValidateStore(ctx context.Context, userID entities.UserID, request requests.SenderPoolStore) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data:  &request,
+		Rules: validator.storeRules(),
+	})
+
+	result := v.ValidateStruct()
+	if len(result) != 0 {
+		return result
+	}
+
+	validator.validatePhones(ctx, result, userID, request.PhoneNumbers)
+	return result
+}
+
+// ValidateUpdate validates the requests.SenderPoolUpdate request
+func (validator *SenderPoolHandlerValidator) ValidateUpdate(ctx context.Context, userID entities.UserID, request requests.SenderPoolUpdate) url.Values {
+	if result := validator.ValidateUUID(ctx, request.SenderPoolID, "senderPoolID"); len(result) != 0 {
+		return result
+	}
+	return validator.ValidateStore(ctx, userID, request.SenderPoolStore)
+}
+
+func (validator *SenderPoolHandlerValidator) storeRules() govalidator.MapData {
+	return govalidator.MapData{
+		"name": []string{
+			"required",
+			"min:1",
+			"max:255",
+		},
+		"phone_numbers": []string{
+			"required",
+			"min:1",
+			fmt.Sprintf("max:%d", senderPoolMaxPhoneNumbers),
+			multipleContactPhoneNumberRule,
+		},
+	}
+}
+
+// validatePhones checks that every phone number in the pool is a phone which is registered on the account of the user
+func (validator *SenderPoolHandlerValidator) validatePhones(ctx context.Context, result url.Values, userID entities.UserID, phoneNumbers []string) {
+	ctx, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
+	defer span.End()
+
+	for _, phoneNumber := range phoneNumbers {
+		_, err := validator.phoneService.Load(ctx, userID, phoneNumber)
+		if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+			result.Add("phone_numbers", fmt.Sprintf("no phone found with number [%s]. install the android app on the phone before adding it to a sender pool", phoneNumber))
+			continue
+		}
+
+		if err != nil {
+			ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("could not load phone for user [%s] and phone [%s]", userID, phoneNumber))))
+			result.Add("phone_numbers", fmt.Sprintf("could not validate the phone number [%s], please try again later", phoneNumber))
+		}
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"net/url"
 	"regexp"
+	"time"
 
 	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
 
 	"github.com/nyaruka/phonenumbers"
 	"github.com/thedevsaddam/govalidator"
@@ -19,6 +21,7 @@ const (
 	contactPhoneNumberRule         = "contactPhoneNumber"
 	multipleContactPhoneNumberRule = "multipleContactPhoneNumber"
 	webhookEventsRule              = "webhookEvents"
+	cursorRule                     = "cursor"
 )
 
 func init() {
@@ -68,6 +71,19 @@ func init() {
 		return nil
 	})
 
+	govalidator.AddCustomRule(cursorRule, func(field string, rule string, message string, value interface{}) error {
+		cursor, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("The %s field must be a string", field)
+		}
+
+		if _, err := repositories.ParseCursor(cursor); err != nil {
+			return fmt.Errorf("The %s field is not valid, use the next_cursor from the previous response", field)
+		}
+
+		return nil
+	})
+
 	govalidator.AddCustomRule(webhookEventsRule, func(field string, rule string, message string, value interface{}) error {
 		input, ok := value.([]string)
 		if !ok {
@@ -78,19 +94,8 @@ func init() {
 			return fmt.Errorf("The %s field is an empty array", field)
 		}
 
-		validEvents := map[string]bool{
-			events.EventTypeMessagePhoneReceived:  true,
-			events.EventTypeMessagePhoneSent:      true,
-			events.EventTypeMessagePhoneDelivered: true,
-			events.EventTypeMessageSendFailed:     true,
-			events.EventTypeMessageSendExpired:    true,
-			events.EventTypePhoneHeartbeatOnline:  true,
-			events.EventTypePhoneHeartbeatOffline: true,
-			events.MessageCallMissed:              true,
-		}
-
 		for _, event := range input {
-			if _, ok := validEvents[event]; !ok {
+			if !events.IsWebhookEventType(event) {
 				return fmt.Errorf("The %s field has an invalid event with name [%s]", field, event)
 			}
 		}
@@ -117,3 +122,25 @@ func (validator *validator) ValidateUUID(_ context.Context, ID string, name stri
 
 	return v.ValidateStruct()
 }
+
+// validateDateRange checks that the optional start_date and end_date are RFC3339 timestamps in chronological order
+func (validator *validator) validateDateRange(result url.Values, startDate string, endDate string) {
+	dates := map[string]time.Time{}
+	for attribute, value := range map[string]string{"start_date": startDate, "end_date": endDate} {
+		if value == "" {
+			continue
+		}
+		timestamp, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			result.Add(attribute, fmt.Sprintf("the %s [%s] must be an RFC3339 timestamp e.g 2022-06-05T14:26:09+03:00", attribute, value))
+			continue
+		}
+		dates[attribute] = timestamp
+	}
+
+	if start, ok := dates["start_date"]; ok {
+		if end, ok := dates["end_date"]; ok && end.Before(start) {
+			result.Add("end_date", "the end_date must be after the start_date")
+		}
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"strings"
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/events"
 	"github.com/NdoleStudio/httpsms/pkg/repositories"
 	"github.com/NdoleStudio/httpsms/pkg/services"
 	"github.com/palantir/stacktrace"
@@ -53,6 +55,9 @@ func (validator *WebhookHandlerValidator) ValidateIndex(_ context.Context, reque
 				"numeric",
 				"min:0",
 			},
+			"cursor": []string{
+				cursorRule,
+			},
 			"query": []string{
 				"max:100",
 			},
@@ -73,6 +78,23 @@ func (validator *WebhookHandlerValidator) ValidateStore(ctx context.Context, use
 				"min:1",
 				"max:255",
 			},
+			"signing_scheme": []string{
+				"required",
+				"in:" + strings.Join([]string{
+					string(entities.WebhookSigningSchemeJWT),
+					string(entities.WebhookSigningSchemeHMAC),
+				}, ","),
+			},
+			"format": []string{
+				"in:" + strings.Join([]string{
+					string(entities.WebhookFormatCloudEventsStructured),
+					string(entities.WebhookFormatCloudEventsBinary),
+					string(entities.WebhookFormatDiscord),
+					string(entities.WebhookFormatSlack),
+					string(entities.WebhookFormatTeams),
+					string(entities.WebhookFormatGoogleChat),
+				}, ","),
+			},
 			"url": []string{
 				"required",
 				"url",
@@ -83,7 +105,6 @@ func (validator *WebhookHandlerValidator) ValidateStore(ctx context.Context, use
 				webhookEventsRule,
 			},
 			"phone_numbers": []string{
-				"required",
 				multipleContactPhoneNumberRule,
 			},
 		},
@@ -94,6 +115,11 @@ func (validator *WebhookHandlerValidator) ValidateStore(ctx context.Context, use
 		return result
 	}
 
+	if request.SigningScheme == string(entities.WebhookSigningSchemeHMAC) && request.SigningKey == "" {
+		result.Add("signing_key", fmt.Sprintf("The signing_key field is required when the signing_scheme is [%s]", entities.WebhookSigningSchemeHMAC))
+		return result
+	}
+
 	for _, address := range request.PhoneNumbers {
 		_, err := validator.phoneService.Load(ctx, userID, address)
 		if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
@@ -115,6 +141,22 @@ func (validator *WebhookHandlerValidator) ValidateUpdate(ctx context.Context, us
 				"min:1",
 				"max:255",
 			},
+			"signing_scheme": []string{
+				"in:" + strings.Join([]string{
+					string(entities.WebhookSigningSchemeJWT),
+					string(entities.WebhookSigningSchemeHMAC),
+				}, ","),
+			},
+			"format": []string{
+				"in:" + strings.Join([]string{
+					string(entities.WebhookFormatCloudEventsStructured),
+					string(entities.WebhookFormatCloudEventsBinary),
+					string(entities.WebhookFormatDiscord),
+					string(entities.WebhookFormatSlack),
+					string(entities.WebhookFormatTeams),
+					string(entities.WebhookFormatGoogleChat),
+				}, ","),
+			},
 			"webhookID": []string{
 				"required",
 				"uuid",
@@ -129,7 +171,6 @@ func (validator *WebhookHandlerValidator) ValidateUpdate(ctx context.Context, us
 				webhookEventsRule,
 			},
 			"phone_numbers": []string{
-				"required",
 				multipleContactPhoneNumberRule,
 			},
 		},
@@ -140,6 +181,11 @@ func (validator *WebhookHandlerValidator) ValidateUpdate(ctx context.Context, us
 		return result
 	}
 
+	if request.SigningScheme == string(entities.WebhookSigningSchemeHMAC) && request.SigningKey == "" {
+		result.Add("signing_key", fmt.Sprintf("The signing_key field is required when the signing_scheme is [%s]", entities.WebhookSigningSchemeHMAC))
+		return result
+	}
+
 	for _, address := range request.PhoneNumbers {
 		_, err := validator.phoneService.Load(ctx, userID, address)
 		if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
@@ -148,3 +194,95 @@ func (validator *WebhookHandlerValidator) ValidateUpdate(ctx context.Context, us
 	}
 	return result
 }
+
+// ValidateDeadLetterIndex validates the requests.WebhookDeadLetterIndex request
+func (validator *WebhookHandlerValidator) ValidateDeadLetterIndex(_ context.Context, request requests.WebhookDeadLetterIndex) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"webhookID": []string{
+				"required",
+				"uuid",
+			},
+			"limit": []string{
+				"required",
+				"numeric",
+				"min:1",
+				"max:100",
+			},
+			"skip": []string{
+				"required",
+				"numeric",
+				"min:0",
+			},
+			"query": []string{
+				"max:100",
+			},
+		},
+	})
+	return v.ValidateStruct()
+}
+
+// ValidateDeliveryIndex validates the requests.WebhookDeliveryIndex request
+func (validator *WebhookHandlerValidator) ValidateDeliveryIndex(_ context.Context, request requests.WebhookDeliveryIndex) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"webhookID": []string{
+				"required",
+				"uuid",
+			},
+			"limit": []string{
+				"required",
+				"numeric",
+				"min:1",
+				"max:100",
+			},
+			"skip": []string{
+				"required",
+				"numeric",
+				"min:0",
+			},
+			"query": []string{
+				"max:100",
+			},
+			"status": []string{
+				"in:" + strings.Join([]string{
+					string(entities.WebhookDeliveryStatusSucceeded),
+					string(entities.WebhookDeliveryStatusFailed),
+				}, ","),
+			},
+			"event_type": []string{
+				"max:100",
+			},
+		},
+	})
+	return v.ValidateStruct()
+}
+
+// ValidateTest validates the requests.WebhookTest request
+func (validator *WebhookHandlerValidator) ValidateTest(_ context.Context, request requests.WebhookTest) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"webhookID": []string{
+				"required",
+				"uuid",
+			},
+			"event_type": []string{
+				"required",
+				"max:100",
+			},
+		},
+	})
+
+	result := v.ValidateStruct()
+	if len(result) > 0 {
+		return result
+	}
+
+	if _, ok := events.SamplePayload(request.EventType, "", ""); !ok {
+		result.Add("event_type", fmt.Sprintf("The event_type field has an event [%s] which cannot be sent to a webhook", request.EventType))
+	}
+	return result
+}
@@ -0,0 +1,135 @@
+package webhooks
+
+import (
+	"fmt"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/gofiber/fiber/v2"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	// discordFieldValueLimit is the maximum number of characters in the value of a Discord embed field
+	discordFieldValueLimit = 1024
+
+	// slackFieldTextLimit is the maximum number of characters in the text of a Slack section field
+	slackFieldTextLimit = 2000
+)
+
+// discordRenderer creates a Discord webhook message with an embed
+type discordRenderer struct{}
+
+func (renderer *discordRenderer) Render(event cloudevents.Event) (*Payload, error) {
+	summary, err := summarize(event)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot summarize event [%s] for discord", event.ID()))
+	}
+
+	fields := make([]fiber.Map, 0, len(summary.Fields))
+	for _, item := range summary.Fields {
+		fields = append(fields, fiber.Map{"name": item.Name, "value": truncate(item.Value, discordFieldValueLimit), "inline": item.Inline})
+	}
+
+	return newJSONPayload(fiber.Map{
+		"avatar_url": "https://httpsms.com/avatar.png",
+		"username":   "httpsms.com",
+		"content":    summary.Title,
+		"embeds":     []fiber.Map{{"fields": fields}},
+	})
+}
+
+// slackRenderer creates a Slack incoming webhook message using blocks
+type slackRenderer struct{}
+
+func (renderer *slackRenderer) Render(event cloudevents.Event) (*Payload, error) {
+	summary, err := summarize(event)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot summarize event [%s] for slack", event.ID()))
+	}
+
+	fields := make([]fiber.Map, 0, len(summary.Fields))
+	for _, item := range summary.Fields {
+		fields = append(fields, fiber.Map{"type": "mrkdwn", "text": truncate(fmt.Sprintf("*%s*\n%s", item.Name, item.Value), slackFieldTextLimit)})
+	}
+
+	return newJSONPayload(fiber.Map{
+		"text": summary.Title,
+		"blocks": []fiber.Map{
+			{"type": "header", "text": fiber.Map{"type": "plain_text", "text": summary.Title, "emoji": true}},
+			{"type": "section", "fields": fields},
+			{"type": "context", "elements": []fiber.Map{{"type": "mrkdwn", "text": fmt.Sprintf("%s • %s", event.Type(), event.ID())}}},
+		},
+	})
+}
+
+// teamsRenderer creates a Microsoft Teams message containing an adaptive card
+type teamsRenderer struct{}
+
+func (renderer *teamsRenderer) Render(event cloudevents.Event) (*Payload, error) {
+	summary, err := summarize(event)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot summarize event [%s] for teams", event.ID()))
+	}
+
+	facts := make([]fiber.Map, 0, len(summary.Fields))
+	for _, item := range summary.Fields {
+		facts = append(facts, fiber.Map{"title": item.Name, "value": item.Value})
+	}
+
+	return newJSONPayload(fiber.Map{
+		"type": "message",
+		"attachments": []fiber.Map{
+			{
+				"contentType": "application/vnd.microsoft.card.adaptive",
+				"contentUrl":  nil,
+				"content": fiber.Map{
+					"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+					"type":    "AdaptiveCard",
+					"version": "1.4",
+					"body": []fiber.Map{
+						{"type": "TextBlock", "size": "Medium", "weight": "Bolder", "text": summary.Title, "wrap": true},
+						{"type": "FactSet", "facts": facts},
+						{"type": "TextBlock", "isSubtle": true, "size": "Small", "text": fmt.Sprintf("%s • %s", event.Type(), event.ID()), "wrap": true},
+					},
+				},
+			},
+		},
+	})
+}
+
+// googleChatRenderer creates a Google Chat message with a card
+type googleChatRenderer struct{}
+
+func (renderer *googleChatRenderer) Render(event cloudevents.Event) (*Payload, error) {
+	summary, err := summarize(event)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot summarize event [%s] for google chat", event.ID()))
+	}
+
+	widgets := make([]fiber.Map, 0, len(summary.Fields))
+	for _, item := range summary.Fields {
+		widgets = append(widgets, fiber.Map{"decoratedText": fiber.Map{"topLabel": item.Name, "text": item.Value, "wrapText": true}})
+	}
+
+	return newJSONPayload(fiber.Map{
+		"text": summary.Title,
+		"cardsV2": []fiber.Map{
+			{
+				"cardId": event.ID(),
+				"card": fiber.Map{
+					"header":   fiber.Map{"title": summary.Title, "subtitle": event.Type()},
+					"sections": []fiber.Map{{"widgets": widgets}},
+				},
+			},
+		},
+	})
+}
+
+// truncate shortens the value to at most limit characters and marks the cut with an ellipsis
+func truncate(value string, limit int) string {
+	runes := []rune(value)
+	if len(runes) <= limit {
+		return value
+	}
+	return string(runes[:limit-1]) + "…"
+}
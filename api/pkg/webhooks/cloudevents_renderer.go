@@ -0,0 +1,48 @@
+package webhooks
+
+import (
+	"fmt"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// cloudEventsStructuredRenderer sends the whole event as the JSON body
+type cloudEventsStructuredRenderer struct{}
+
+func (renderer *cloudEventsStructuredRenderer) Render(event cloudevents.Event) (*Payload, error) {
+	payload, err := newJSONPayload(event)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot render event [%s] in structured mode", event.ID()))
+	}
+	return payload, nil
+}
+
+// cloudEventsBinaryRenderer sends the event data as the body and the event attributes as ce-* headers
+type cloudEventsBinaryRenderer struct{}
+
+func (renderer *cloudEventsBinaryRenderer) Render(event cloudevents.Event) (*Payload, error) {
+	headers := map[string]string{
+		"Content-Type":   event.DataContentType(),
+		"ce-specversion": event.SpecVersion(),
+		"ce-id":          event.ID(),
+		"ce-type":        event.Type(),
+		"ce-source":      event.Source(),
+		"ce-time":        event.Time().UTC().Format(time.RFC3339Nano),
+	}
+
+	if headers["Content-Type"] == "" {
+		headers["Content-Type"] = cloudevents.ApplicationJSON
+	}
+
+	if event.Subject() != "" {
+		headers["ce-subject"] = event.Subject()
+	}
+
+	for name, value := range event.Extensions() {
+		headers["ce-"+name] = fmt.Sprintf("%v", value)
+	}
+
+	return &Payload{Body: event.Data(), Headers: headers}, nil
+}
@@ -0,0 +1,67 @@
+// Package webhooks renders cloudevents into the request bodies expected by the different webhook receivers.
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// Payload is the rendered body and headers of a webhook request
+type Payload struct {
+	Body    []byte
+	Headers map[string]string
+}
+
+// Renderer converts a cloudevents.Event into a Payload for one entities.WebhookFormat
+type Renderer interface {
+	Render(event cloudevents.Event) (*Payload, error)
+}
+
+// Registry stores the Renderer for each entities.WebhookFormat
+type Registry struct {
+	renderers map[entities.WebhookFormat]Renderer
+}
+
+// NewRegistry creates a Registry with the renderers for all the supported entities.WebhookFormat
+func NewRegistry() *Registry {
+	registry := &Registry{renderers: map[entities.WebhookFormat]Renderer{}}
+
+	registry.Register(entities.WebhookFormatCloudEventsStructured, &cloudEventsStructuredRenderer{})
+	registry.Register(entities.WebhookFormatCloudEventsBinary, &cloudEventsBinaryRenderer{})
+	registry.Register(entities.WebhookFormatDiscord, &discordRenderer{})
+	registry.Register(entities.WebhookFormatSlack, &slackRenderer{})
+	registry.Register(entities.WebhookFormatTeams, &teamsRenderer{})
+	registry.Register(entities.WebhookFormatGoogleChat, &googleChatRenderer{})
+
+	return registry
+}
+
+// Register adds a Renderer for an entities.WebhookFormat, replacing any existing one
+func (registry *Registry) Register(format entities.WebhookFormat, renderer Renderer) {
+	registry.renderers[format] = renderer
+}
+
+// Get returns the Renderer of an entities.WebhookFormat
+func (registry *Registry) Get(format entities.WebhookFormat) (Renderer, error) {
+	renderer, ok := registry.renderers[format]
+	if !ok {
+		return nil, stacktrace.NewError(fmt.Sprintf("no renderer is registered for webhook format [%s]", format))
+	}
+	return renderer, nil
+}
+
+func newJSONPayload(body any) (*Payload, error) {
+	content, err := json.Marshal(body)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot marshal [%T] into JSON", body))
+	}
+
+	return &Payload{
+		Body:    content,
+		Headers: map[string]string{"Content-Type": "application/json"},
+	}, nil
+}
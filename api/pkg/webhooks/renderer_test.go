@@ -0,0 +1,245 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEvent(t *testing.T, eventType string) cloudevents.Event {
+	payload, ok := events.SamplePayload(eventType, entities.UserID("user-id"), "+18005550199")
+	require.True(t, ok, fmt.Sprintf("no sample payload for [%s]", eventType))
+
+	event := cloudevents.NewEvent()
+	event.SetSource("https://api.httpsms.com/v1/webhooks/test")
+	event.SetType(eventType)
+	event.SetTime(time.Now().UTC())
+	event.SetID(uuid.New().String())
+	require.NoError(t, event.SetData(cloudevents.ApplicationJSON, payload))
+
+	return event
+}
+
+// newLongTestEvent creates an event without a custom summary whose payload exceeds the chat field limits
+func newLongTestEvent(t *testing.T) cloudevents.Event {
+	event := cloudevents.NewEvent()
+	event.SetSource("https://api.httpsms.com/v1/webhooks/test")
+	event.SetType("unknown.event")
+	event.SetTime(time.Now().UTC())
+	event.SetID(uuid.New().String())
+	require.NoError(t, event.SetData(cloudevents.ApplicationJSON, map[string]string{"content": strings.Repeat("é", 3000)}))
+
+	return event
+}
+
+func renderJSON(t *testing.T, format entities.WebhookFormat, event cloudevents.Event) (*Payload, map[string]any) {
+	renderer, err := NewRegistry().Get(format)
+	require.NoError(t, err)
+
+	payload, err := renderer.Render(event)
+	require.NoError(t, err)
+
+	body := map[string]any{}
+	require.NoError(t, json.Unmarshal(payload.Body, &body))
+
+	return payload, body
+}
+
+func TestRegistry_Get(t *testing.T) {
+	t.Run("it returns an error for an unknown format", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		registry := NewRegistry()
+
+		// Act
+		renderer, err := registry.Get("unknown")
+
+		// Assert
+		assert.Error(t, err)
+		assert.Nil(t, renderer)
+	})
+}
+
+func TestCloudEventsStructuredRenderer_Render(t *testing.T) {
+	for _, eventType := range events.WebhookEventTypes() {
+		eventType := eventType
+		t.Run(eventType, func(t *testing.T) {
+			// Setup
+			t.Parallel()
+
+			// Arrange
+			event := newTestEvent(t, eventType)
+
+			// Act
+			payload, body := renderJSON(t, entities.WebhookFormatCloudEventsStructured, event)
+
+			// Assert
+			assert.Equal(t, "application/json", payload.Headers["Content-Type"])
+			assert.Equal(t, eventType, body["type"])
+			assert.Equal(t, event.ID(), body["id"])
+			assert.NotEmpty(t, body["data"])
+		})
+	}
+}
+
+func TestCloudEventsBinaryRenderer_Render(t *testing.T) {
+	for _, eventType := range events.WebhookEventTypes() {
+		eventType := eventType
+		t.Run(eventType, func(t *testing.T) {
+			// Setup
+			t.Parallel()
+
+			// Arrange
+			event := newTestEvent(t, eventType)
+
+			// Act
+			payload, _ := renderJSON(t, entities.WebhookFormatCloudEventsBinary, event)
+
+			// Assert
+			assert.Equal(t, event.Data(), payload.Body)
+			assert.Equal(t, cloudevents.ApplicationJSON, payload.Headers["Content-Type"])
+			assert.Equal(t, "1.0", payload.Headers["ce-specversion"])
+			assert.Equal(t, event.ID(), payload.Headers["ce-id"])
+			assert.Equal(t, eventType, payload.Headers["ce-type"])
+			assert.Equal(t, event.Source(), payload.Headers["ce-source"])
+			assert.NotEmpty(t, payload.Headers["ce-time"])
+		})
+	}
+}
+
+func TestDiscordRenderer_Render(t *testing.T) {
+	for _, eventType := range events.WebhookEventTypes() {
+		eventType := eventType
+		t.Run(eventType, func(t *testing.T) {
+			// Setup
+			t.Parallel()
+
+			// Arrange
+			event := newTestEvent(t, eventType)
+
+			// Act
+			_, body := renderJSON(t, entities.WebhookFormatDiscord, event)
+
+			// Assert
+			assert.Equal(t, "httpsms.com", body["username"])
+			assert.NotEmpty(t, body["content"])
+			assert.NotEqual(t, eventType, body["content"])
+			assert.Len(t, body["embeds"], 1)
+		})
+	}
+
+	t.Run("it truncates long field values", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		event := newLongTestEvent(t)
+
+		// Act
+		_, body := renderJSON(t, entities.WebhookFormatDiscord, event)
+
+		// Assert
+		fields := body["embeds"].([]any)[0].(map[string]any)["fields"].([]any)
+		for _, item := range fields {
+			assert.LessOrEqual(t, utf8.RuneCountInString(item.(map[string]any)["value"].(string)), discordFieldValueLimit)
+		}
+	})
+}
+
+func TestSlackRenderer_Render(t *testing.T) {
+	for _, eventType := range events.WebhookEventTypes() {
+		eventType := eventType
+		t.Run(eventType, func(t *testing.T) {
+			// Setup
+			t.Parallel()
+
+			// Arrange
+			event := newTestEvent(t, eventType)
+
+			// Act
+			_, body := renderJSON(t, entities.WebhookFormatSlack, event)
+
+			// Assert
+			assert.NotEmpty(t, body["text"])
+			blocks := body["blocks"].([]any)
+			assert.Len(t, blocks, 3)
+			assert.Equal(t, "header", blocks[0].(map[string]any)["type"])
+			assert.NotEmpty(t, blocks[1].(map[string]any)["fields"])
+		})
+	}
+
+	t.Run("it truncates long field values", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		event := newLongTestEvent(t)
+
+		// Act
+		_, body := renderJSON(t, entities.WebhookFormatSlack, event)
+
+		// Assert
+		fields := body["blocks"].([]any)[1].(map[string]any)["fields"].([]any)
+		for _, item := range fields {
+			assert.LessOrEqual(t, utf8.RuneCountInString(item.(map[string]any)["text"].(string)), slackFieldTextLimit)
+		}
+	})
+}
+
+func TestTeamsRenderer_Render(t *testing.T) {
+	for _, eventType := range events.WebhookEventTypes() {
+		eventType := eventType
+		t.Run(eventType, func(t *testing.T) {
+			// Setup
+			t.Parallel()
+
+			// Arrange
+			event := newTestEvent(t, eventType)
+
+			// Act
+			_, body := renderJSON(t, entities.WebhookFormatTeams, event)
+
+			// Assert
+			assert.Equal(t, "message", body["type"])
+			attachments := body["attachments"].([]any)
+			assert.Len(t, attachments, 1)
+			attachment := attachments[0].(map[string]any)
+			assert.Equal(t, "application/vnd.microsoft.card.adaptive", attachment["contentType"])
+			assert.Equal(t, "AdaptiveCard", attachment["content"].(map[string]any)["type"])
+		})
+	}
+}
+
+func TestGoogleChatRenderer_Render(t *testing.T) {
+	for _, eventType := range events.WebhookEventTypes() {
+		eventType := eventType
+		t.Run(eventType, func(t *testing.T) {
+			// Setup
+			t.Parallel()
+
+			// Arrange
+			event := newTestEvent(t, eventType)
+
+			// Act
+			_, body := renderJSON(t, entities.WebhookFormatGoogleChat, event)
+
+			// Assert
+			assert.NotEmpty(t, body["text"])
+			cards := body["cardsV2"].([]any)
+			assert.Len(t, cards, 1)
+			assert.Equal(t, event.ID(), cards[0].(map[string]any)["cardId"])
+		})
+	}
+}
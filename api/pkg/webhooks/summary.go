@@ -0,0 +1,208 @@
+package webhooks
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+	"github.com/nyaruka/phonenumbers"
+	"github.com/palantir/stacktrace"
+)
+
+// summary is the human-readable version of an event used by the chat renderers
+type summary struct {
+	Title  string
+	Fields []field
+}
+
+type field struct {
+	Name   string
+	Value  string
+	Inline bool
+}
+
+type messageSummary struct {
+	id        uuid.UUID
+	from      string
+	to        string
+	content   string
+	timestamp time.Time
+}
+
+func summarize(event cloudevents.Event) (*summary, error) {
+	switch event.Type() {
+	case events.EventTypeMessagePhoneReceived:
+		payload := new(events.MessagePhoneReceivedPayload)
+		if err := decode(event, payload); err != nil {
+			return nil, err
+		}
+		return newMessageSummary("✉ new message received", &messageSummary{payload.MessageID, payload.Contact, payload.Owner, payload.Content, payload.Timestamp}), nil
+	case events.EventTypeMessagePhoneSent:
+		payload := new(events.MessagePhoneSentPayload)
+		if err := decode(event, payload); err != nil {
+			return nil, err
+		}
+		return newMessageSummary("📤 message sent", &messageSummary{payload.ID, payload.Owner, payload.Contact, payload.Content, payload.Timestamp}), nil
+	case events.EventTypeMessagePhoneDelivered:
+		payload := new(events.MessagePhoneDeliveredPayload)
+		if err := decode(event, payload); err != nil {
+			return nil, err
+		}
+		return newMessageSummary("✅ message delivered", &messageSummary{payload.ID, payload.Owner, payload.Contact, payload.Content, payload.Timestamp}), nil
+	case events.EventTypeMessageSendFailed:
+		payload := new(events.MessageSendFailedPayload)
+		if err := decode(event, payload); err != nil {
+			return nil, err
+		}
+		result := newMessageSummary("❌ message failed", &messageSummary{payload.ID, payload.Owner, payload.Contact, payload.Content, payload.Timestamp})
+		result.Fields = append(result.Fields, field{Name: "Error:", Value: payload.ErrorMessage})
+		return result, nil
+	case events.EventTypeMessageSendExpired:
+		payload := new(events.MessageSendExpiredPayload)
+		if err := decode(event, payload); err != nil {
+			return nil, err
+		}
+		return newMessageSummary("⌛ message expired", &messageSummary{payload.MessageID, payload.Owner, payload.Contact, payload.Content, payload.Timestamp}), nil
+	case events.MessageCallMissed:
+		payload := new(events.MessageCallMissedPayload)
+		if err := decode(event, payload); err != nil {
+			return nil, err
+		}
+		return &summary{
+			Title: "📞 missed phone call",
+			Fields: []field{
+				{Name: "From:", Value: formatPhoneNumber(payload.Contact), Inline: true},
+				{Name: "To:", Value: formatPhoneNumber(payload.Owner), Inline: true},
+				{Name: "Timestamp:", Value: payload.Timestamp.Format(time.RFC1123)},
+			},
+		}, nil
+	case events.EventTypePhoneHeartbeatOnline:
+		payload := new(events.PhoneHeartbeatOnlinePayload)
+		if err := decode(event, payload); err != nil {
+			return nil, err
+		}
+		return newHeartbeatSummary("🟢 phone is online", payload.Owner, payload.LastHeartbeatTimestamp), nil
+	case events.EventTypePhoneHeartbeatOffline:
+		payload := new(events.PhoneHeartbeatOfflinePayload)
+		if err := decode(event, payload); err != nil {
+			return nil, err
+		}
+		return newHeartbeatSummary("🔴 phone is offline", payload.Owner, payload.LastHeartbeatTimestamp), nil
+	case events.EventTypeContactOptOut:
+		payload := new(events.ContactOptOutPayload)
+		if err := decode(event, payload); err != nil {
+			return nil, err
+		}
+		return newOptSummary("🚫 contact opted out", payload.Contact, payload.Owner, payload.Keyword, payload.Timestamp), nil
+	case events.EventTypeContactOptIn:
+		payload := new(events.ContactOptInPayload)
+		if err := decode(event, payload); err != nil {
+			return nil, err
+		}
+		return newOptSummary("🔁 contact opted in", payload.Contact, payload.Owner, payload.Keyword, payload.Timestamp), nil
+	case events.EventTypeMessageSendDeferred:
+		payload := new(events.MessageSendDeferredPayload)
+		if err := decode(event, payload); err != nil {
+			return nil, err
+		}
+		return &summary{
+			Title: "🌙 message deferred",
+			Fields: []field{
+				{Name: "From:", Value: formatPhoneNumber(payload.Owner), Inline: true},
+				{Name: "To:", Value: formatPhoneNumber(payload.Contact), Inline: true},
+				{Name: "Quiet Hours:", Value: payload.QuietHoursStart + " - " + payload.QuietHoursEnd, Inline: true},
+				{Name: "Scheduled At:", Value: payload.ScheduledAt.Format(time.RFC1123)},
+			},
+		}, nil
+	case events.EventTypeAutoReplyTriggered:
+		payload := new(events.AutoReplyTriggeredPayload)
+		if err := decode(event, payload); err != nil {
+			return nil, err
+		}
+		return &summary{
+			Title: "🤖 auto-reply triggered",
+			Fields: []field{
+				{Name: "From:", Value: formatPhoneNumber(payload.Contact), Inline: true},
+				{Name: "To:", Value: formatPhoneNumber(payload.Owner), Inline: true},
+				{Name: "Action:", Value: payload.Action.String(), Inline: true},
+				{Name: "Content:", Value: payload.Content},
+				{Name: "Timestamp:", Value: payload.Timestamp.Format(time.RFC1123)},
+			},
+		}, nil
+	default:
+		return &summary{
+			Title: fmt.Sprintf("🔔 %s", event.Type()),
+			Fields: []field{
+				{Name: "Event ID:", Value: event.ID()},
+				{Name: "Payload:", Value: string(event.Data())},
+			},
+		}, nil
+	}
+}
+
+func newOptSummary(title string, contact string, owner string, keyword *string, timestamp time.Time) *summary {
+	result := &summary{
+		Title: title,
+		Fields: []field{
+			{Name: "Contact:", Value: formatPhoneNumber(contact), Inline: true},
+		},
+	}
+
+	if owner != "" {
+		result.Fields = append(result.Fields, field{Name: "Phone:", Value: formatPhoneNumber(owner), Inline: true})
+	}
+
+	if keyword != nil {
+		result.Fields = append(result.Fields, field{Name: "Keyword:", Value: *keyword, Inline: true})
+	}
+
+	result.Fields = append(result.Fields, field{Name: "Timestamp:", Value: timestamp.Format(time.RFC1123)})
+	return result
+}
+
+func newMessageSummary(title string, message *messageSummary) *summary {
+	return &summary{
+		Title: title,
+		Fields: []field{
+			{Name: "From:", Value: formatPhoneNumber(message.from), Inline: true},
+			{Name: "To:", Value: formatPhoneNumber(message.to), Inline: true},
+			{Name: "Content:", Value: message.content},
+			{Name: "MessageID:", Value: message.id.String()},
+		},
+	}
+}
+
+func newHeartbeatSummary(title string, owner string, lastHeartbeat time.Time) *summary {
+	return &summary{
+		Title: title,
+		Fields: []field{
+			{Name: "Phone:", Value: formatPhoneNumber(owner), Inline: true},
+			{Name: "Last Heartbeat:", Value: lastHeartbeat.Format(time.RFC1123), Inline: true},
+		},
+	}
+}
+
+func decode(event cloudevents.Event, payload any) error {
+	if err := event.DataAs(payload); err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot unmarshal event [%s] with ID [%s] into [%T]", event.Type(), event.ID(), payload))
+	}
+	return nil
+}
+
+var phoneNumberRegex = regexp.MustCompile(`^\+?[1-9]\d{9,14}$`)
+
+func formatPhoneNumber(phoneNumber string) string {
+	if !phoneNumberRegex.MatchString(phoneNumber) {
+		return phoneNumber
+	}
+
+	number, err := phonenumbers.Parse(phoneNumber, phonenumbers.UNKNOWN_REGION)
+	if err != nil {
+		return phoneNumber
+	}
+
+	return phonenumbers.Format(number, phonenumbers.INTERNATIONAL)
+}
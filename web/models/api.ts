@@ -246,6 +246,8 @@ export interface EntitiesWebhook {
   phone_numbers: string[]
   /** @example "DGW8NwQp7mxKaSZ72Xq9v67SLqSbWQvckzzmK8D6rvd7NywSEkdMJtuxKyEkYnCY" */
   signing_key: string
+  /** @example "hmac-sha256" */
+  signing_scheme: string
   /** @example "2022-06-05T14:26:10.303278+03:00" */
   updated_at: string
   /** @example "https://example.com" */
@@ -419,6 +421,8 @@ export interface RequestsWebhookStore {
   /** @example ["+18005550100","+18005550100"] */
   phone_numbers: string[]
   signing_key: string
+  /** @example "hmac-sha256" */
+  signing_scheme: string
   url: string
 }
 
@@ -427,6 +431,8 @@ export interface RequestsWebhookUpdate {
   /** @example ["+18005550100","+18005550100"] */
   phone_numbers: string[]
   signing_key: string
+  /** @example "hmac-sha256" */
+  signing_scheme: string
   url: string
 }
 